@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/alibaba/opensandbox/internal/version"
@@ -62,6 +63,19 @@ func main() {
 
 	ctrl := controller.InitCodeRunner()
 
+	// Restore bash sessions persisted by a previous execd process.
+	if flag.DataDir != "" {
+		sessionDir := filepath.Join(flag.DataDir, "sessions")
+		restored, err := ctrl.EnableBashSessionPersistence(sessionDir)
+		if err != nil {
+			log.Warn("bash session persistence disabled (continuing in memory): %v", err)
+		} else {
+			log.Info("bash session persistence enabled at %s, restored %d session(s)", sessionDir, restored)
+		}
+	}
+
+	controller.InitScheduler(runtime.NewScheduler(ctrl))
+
 	// Always store probe result for capabilities endpoint.
 	controller.InitIsolatedProbe(&isolationProbe)
 
@@ -42,4 +42,8 @@ var (
 	// IsolationConfigPath points to the TOML isolation config file.
 	// Empty means use built-in defaults.
 	IsolationConfigPath string
+
+	// DataDir holds execd state that must survive restarts (e.g. bash
+	// sessions). Empty, the default, disables on-disk persistence.
+	DataDir string
 )
@@ -31,6 +31,7 @@ const (
 	gracefulShutdownTimeoutEnv = "EXECD_API_GRACE_SHUTDOWN"
 	jupyterIdlePollIntervalEnv = "EXECD_JUPYTER_IDLE_POLL_INTERVAL"
 	isolationConfigEnv         = "EXECD_ISOLATION_CONFIG"
+	dataDirEnv                 = "EXECD_DATA_DIR"
 )
 
 // InitFlags registers CLI flags and env overrides.
@@ -42,6 +43,7 @@ func InitFlags() {
 	ApiGracefulShutdownTimeout = time.Second * 1
 	JupyterIdlePollInterval = 100 * time.Millisecond
 	IsolationConfigPath = ""
+	DataDir = ""
 
 	// First, set default values from environment variables
 	if jupyterFromEnv := os.Getenv(jupyterHostEnv); jupyterFromEnv != "" {
@@ -95,6 +97,12 @@ func InitFlags() {
 	}
 	flag.StringVar(&IsolationConfigPath, "isolation-config", IsolationConfigPath, "Path to isolation TOML config file (default: built-in defaults)")
 
+	// Persistent state
+	if v, ok := os.LookupEnv(dataDirEnv); ok {
+		DataDir = v
+	}
+	flag.StringVar(&DataDir, "data-dir", DataDir, "Directory for state persisted across restarts, e.g. /var/lib/execd; bash sessions and their environment are written there (default: empty, persistence disabled)")
+
 	// Parse flags - these will override environment variables if provided
 	flag.Parse()
 	if JupyterIdlePollInterval <= 0 {
@@ -32,6 +32,8 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/alibaba/opensandbox/internal/safego"
+
 	"github.com/alibaba/opensandbox/execd/pkg/jupyter/execute"
 	"github.com/alibaba/opensandbox/execd/pkg/log"
 	"github.com/alibaba/opensandbox/execd/pkg/util/pathutil"
@@ -57,6 +59,7 @@ func (c *Controller) createBashSession(req *CreateContextRequest) (string, error
 	}
 
 	session := newBashSession(resolvedCwd)
+	session.store = c.bashSessionStore
 	if err := session.start(); err != nil {
 		return "", fmt.Errorf("failed to start bash session: %w", err)
 	}
@@ -99,6 +102,57 @@ func (c *Controller) closeBashSession(sessionId string) error {
 	return nil
 }
 
+// EnableBashSessionPersistence stores bash session env/cwd under dir and
+// restores the sessions left there by a previous execd process. It must be
+// called before the HTTP server starts serving requests.
+func (c *Controller) EnableBashSessionPersistence(dir string) (int, error) {
+	store, err := newBashSessionStore(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	records, err := store.load()
+	if err != nil {
+		return 0, err
+	}
+	for _, rec := range records {
+		c.bashSessionClientMap.Store(rec.ID, restoreBashSession(rec, store))
+		log.Info("restored bash session %s (cwd=%s)", rec.ID, rec.Cwd)
+	}
+
+	c.bashSessionStore = store
+	return len(records), nil
+}
+
+// ListBashSessions returns all bash sessions ordered by creation time.
+// Environments are omitted; use GetBashSessionInfo to inspect a single session.
+func (c *Controller) ListBashSessions() []BashSessionInfo {
+	sessions := make([]BashSessionInfo, 0)
+	c.bashSessionClientMap.Range(func(_, v any) bool {
+		if s, ok := v.(*bashSession); ok {
+			sessions = append(sessions, s.info(false))
+		}
+		return true
+	})
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].ID < sessions[j].ID
+		}
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions
+}
+
+// GetBashSessionInfo returns the captured env and cwd of a bash session.
+func (c *Controller) GetBashSessionInfo(sessionID string) (*BashSessionInfo, error) {
+	session := c.getBashSession(sessionID)
+	if session == nil {
+		return nil, ErrContextNotFound
+	}
+	info := session.info(true)
+	return &info, nil
+}
+
 func (c *Controller) CreateBashSession(req *CreateContextRequest) (string, error) {
 	return c.createBashSession(req)
 }
@@ -124,10 +178,29 @@ func newBashSession(cwd string) *bashSession {
 		}
 	}
 
+	now := time.Now()
 	return &bashSession{
-		config: config,
-		env:    env,
-		cwd:    cwd,
+		config:    config,
+		env:       env,
+		cwd:       cwd,
+		createdAt: now,
+		updatedAt: now,
+	}
+}
+
+// restoreBashSession rebuilds a started session from its persisted record.
+func restoreBashSession(rec *bashSessionRecord, store *bashSessionStore) *bashSession {
+	return &bashSession{
+		config: &bashSessionConfig{
+			Session:        rec.ID,
+			StartupTimeout: 5 * time.Second,
+		},
+		started:   true,
+		env:       copyEnvMap(rec.Env),
+		cwd:       rec.Cwd,
+		createdAt: rec.CreatedAt,
+		updatedAt: rec.UpdatedAt,
+		store:     store,
 	}
 }
 
@@ -140,19 +213,59 @@ func (s *bashSession) start() error {
 	}
 
 	s.started = true
+	s.persistLocked()
 	return nil
 }
 
-func (s *bashSession) trackCurrentProcess(pid int) {
+// persistLocked writes the session state to the store, if any. Failures are
+// logged and otherwise ignored so that a full or read-only data dir degrades
+// to in-memory sessions instead of breaking command execution.
+// Caller must hold s.mu.
+func (s *bashSession) persistLocked() {
+	if s.store == nil {
+		return
+	}
+	rec := &bashSessionRecord{
+		ID:        s.config.Session,
+		Cwd:       s.cwd,
+		Env:       s.env,
+		CreatedAt: s.createdAt,
+		UpdatedAt: s.updatedAt,
+	}
+	if err := s.store.save(rec); err != nil {
+		log.Warning("persist bash session %s: %v", s.config.Session, err)
+	}
+}
+
+func (s *bashSession) info(withEnv bool) BashSessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := BashSessionInfo{
+		ID:        s.config.Session,
+		Cwd:       s.cwd,
+		Running:   s.currentProcessPid != 0,
+		CreatedAt: s.createdAt,
+		UpdatedAt: s.updatedAt,
+	}
+	if withEnv {
+		info.Env = copyEnvMap(s.env)
+	}
+	return info
+}
+
+func (s *bashSession) trackCurrentProcess(pid int, terminator *processTerminator) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.currentProcessPid = pid
+	s.currentTerminator = terminator
 }
 
 func (s *bashSession) untrackCurrentProcess() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.currentProcessPid = 0
+	s.currentTerminator = nil
 }
 
 //nolint:gocognit
@@ -205,7 +318,7 @@ func (s *bashSession) run(ctx context.Context, request *ExecuteCodeRequest) erro
 		return fmt.Errorf("close script file: %w", err)
 	}
 
-	cmd := exec.CommandContext(ctx, "bash", "--noprofile", "--norc", scriptPath)
+	cmd := exec.Command("bash", "--noprofile", "--norc", scriptPath)
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	// Do not pass envSnapshot via cmd.Env to avoid "argument list too long" when session env is large.
 	// Child inherits parent env (nil => default in Go). The script file already has "export K=V" for
@@ -220,8 +333,18 @@ func (s *bashSession) run(ctx context.Context, request *ExecuteCodeRequest) erro
 		log.Error("start bash session failed: %v (command: %q)", err, log.SanitizeCommand(request.Code))
 		return fmt.Errorf("start bash: %w", err)
 	}
+	exited := make(chan struct{})
+	terminator := newProcessGroupTerminator(cmd.Process.Pid, request.Termination, exited)
 	defer s.untrackCurrentProcess()
-	s.trackCurrentProcess(cmd.Process.Pid)
+	s.trackCurrentProcess(cmd.Process.Pid, terminator)
+
+	safego.Go(func() {
+		select {
+		case <-exited:
+		case <-ctx.Done():
+			terminator.terminate(terminationReasonFor(ctx))
+		}
+	})
 
 	scanner := bufio.NewScanner(stdout)
 	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
@@ -259,15 +382,34 @@ func (s *bashSession) run(ctx context.Context, request *ExecuteCodeRequest) erro
 
 	scanErr := scanner.Err()
 	waitErr := cmd.Wait()
+	close(exited)
 
 	if scanErr != nil {
 		log.Error("read stdout failed: %v (command: %q)", scanErr, log.SanitizeCommand(request.Code))
 		return fmt.Errorf("read stdout: %w", scanErr)
 	}
 
-	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-		log.Error("timeout after %s while running command: %q", wait, log.SanitizeCommand(request.Code))
-		return fmt.Errorf("timeout after %s", wait)
+	if termination := terminator.termination(); termination != nil {
+		// The run was cut short, so its env/cwd dump is incomplete and the
+		// session state is left as it was before the run.
+		errMsg := fmt.Sprintf("command %s", termination.Reason)
+		if termination.Reason == TerminationTimeout {
+			errMsg = fmt.Sprintf("timeout after %s", wait)
+		}
+		log.Error("%s, sent %s while running command: %q", errMsg, termination.Signal, log.SanitizeCommand(request.Code))
+		exitCode := -1
+		if cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		notifyTerminated(request.Hooks, termination)
+		if request.Hooks.OnExecuteError != nil {
+			request.Hooks.OnExecuteError(&execute.ErrorOutput{
+				EName:     "CommandExecError",
+				EValue:    strconv.Itoa(exitCode),
+				Traceback: []string{errMsg},
+			})
+		}
+		return nil
 	}
 
 	if exitCode == nil && cmd.ProcessState != nil {
@@ -283,6 +425,10 @@ func (s *bashSession) run(ctx context.Context, request *ExecuteCodeRequest) erro
 	if pwdLine != "" {
 		s.cwd = pwdLine
 	}
+	if s.started {
+		s.updatedAt = time.Now()
+		s.persistLocked()
+	}
 	s.mu.Unlock()
 
 	var exitErr *exec.ExitError
@@ -458,19 +604,27 @@ func splitEnvPair(kv string) (string, string, bool) {
 
 func (s *bashSession) close() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	pid := s.currentProcessPid
+	terminator := s.currentTerminator
 	s.currentProcessPid = 0
+	s.currentTerminator = nil
 	s.started = false
 	s.env = nil
 	s.cwd = ""
 
-	if pid != 0 {
-		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil {
-			log.Warning("kill session process group %d: %v (process may have already exited)", pid, err)
+	if s.store != nil {
+		if err := s.store.remove(s.config.Session); err != nil {
+			log.Warning("remove persisted bash session %s: %v", s.config.Session, err)
 		}
 	}
+	s.mu.Unlock()
+
+	// Stop the active run in the background: the grace period may take
+	// seconds and must not hold up the deletion.
+	if pid != 0 && terminator != nil {
+		log.Warning("stopping process group %d of bash session %s", pid, s.config.Session)
+		terminator.terminateAsync(TerminationInterrupted)
+	}
 	return nil
 }
 
@@ -0,0 +1,160 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alibaba/opensandbox/execd/pkg/log"
+)
+
+const bashSessionRecordExt = ".json"
+
+// bashSessionRecord is the on-disk form of a bash session's shell state.
+type bashSessionRecord struct {
+	ID        string            `json:"id"`
+	Cwd       string            `json:"cwd"`
+	Env       map[string]string `json:"env"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// bashSessionStore keeps one JSON file per bash session under dir so that
+// session env/cwd survive execd restarts. Files are written atomically
+// (temp file + rename) and are readable by the owner only, since captured
+// environments routinely contain credentials.
+type bashSessionStore struct {
+	dir string
+}
+
+func newBashSessionStore(dir string) (*bashSessionStore, error) {
+	if dir == "" {
+		return nil, errors.New("session store directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create session store %s: %w", dir, err)
+	}
+	// MkdirAll leaves the mode of an existing directory alone.
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("restrict session store %s: %w", dir, err)
+	}
+	return &bashSessionStore{dir: dir}, nil
+}
+
+func (s *bashSessionStore) path(id string) (string, error) {
+	// Session IDs are always UUIDs; rejecting anything else keeps a crafted
+	// ID from escaping the store directory.
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("invalid session id %q", id)
+	}
+	return filepath.Join(s.dir, id+bashSessionRecordExt), nil
+}
+
+func (s *bashSessionStore) save(rec *bashSessionRecord) error {
+	target, err := s.path(rec.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal session %s: %w", rec.ID, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "."+rec.ID+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file for session %s: %w", rec.ID, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) //nolint:errcheck
+
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("restrict session %s: %w", rec.ID, err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write session %s: %w", rec.ID, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync session %s: %w", rec.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close session %s: %w", rec.ID, err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("commit session %s: %w", rec.ID, err)
+	}
+	return nil
+}
+
+func (s *bashSessionStore) remove(id string) error {
+	target, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove session %s: %w", id, err)
+	}
+	return nil
+}
+
+// load returns every readable record in the store. Corrupt or mismatched
+// files are skipped with a warning rather than failing startup.
+func (s *bashSessionStore) load() ([]*bashSessionRecord, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read session store %s: %w", s.dir, err)
+	}
+
+	records := make([]*bashSessionRecord, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, bashSessionRecordExt) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			log.Warning("skip session record %s: %v", name, err)
+			continue
+		}
+		var rec bashSessionRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			log.Warning("skip corrupt session record %s: %v", name, err)
+			continue
+		}
+		if rec.ID+bashSessionRecordExt != name {
+			log.Warning("skip session record %s: id mismatch %q", name, rec.ID)
+			continue
+		}
+		if _, err := uuid.Parse(rec.ID); err != nil {
+			log.Warning("skip session record %s: invalid id", name)
+			continue
+		}
+		records = append(records, &rec)
+	}
+	return records, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBashSessionStore_RestrictsExistingDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	_, err := newBashSessionStore(dir)
+	require.NoError(t, err)
+
+	fi, err := os.Stat(dir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o700), fi.Mode().Perm())
+}
+
+func TestBashSessionStore_SaveLoadRemove(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	store, err := newBashSessionStore(dir)
+	require.NoError(t, err)
+
+	rec := &bashSessionRecord{
+		ID:        uuid.New().String(),
+		Cwd:       "/workspace",
+		Env:       map[string]string{"FOO": "bar"},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		UpdatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	require.NoError(t, store.save(rec))
+
+	fi, err := os.Stat(filepath.Join(dir, rec.ID+bashSessionRecordExt))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	// Corrupt and foreign files must not break loading.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, uuid.New().String()+bashSessionRecordExt), []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("x"), 0o600))
+
+	records, err := store.load()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, rec, records[0])
+
+	require.NoError(t, store.remove(rec.ID))
+	require.NoError(t, store.remove(rec.ID), "removing twice should be a no-op")
+	records, err = store.load()
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestBashSessionStore_RejectsInvalidID(t *testing.T) {
+	store, err := newBashSessionStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.Error(t, store.save(&bashSessionRecord{ID: "../escape"}))
+	require.Error(t, store.remove("../escape"))
+}
+
+func TestBashSession_PersistedAcrossControllers(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found in PATH")
+	}
+
+	dir := t.TempDir()
+	workDir := t.TempDir()
+
+	c1 := NewController("", "")
+	_, err := c1.EnableBashSessionPersistence(dir)
+	require.NoError(t, err)
+
+	sessionID, err := c1.CreateBashSession(&CreateContextRequest{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, c1.RunInBashSession(ctx, &ExecuteCodeRequest{
+		Language: Bash,
+		Context:  sessionID,
+		Code:     "export PERSISTED_VAR=hello; cd " + workDir,
+		Timeout:  5 * time.Second,
+		Hooks:    ExecuteResultHook{},
+	}))
+
+	// A fresh controller simulates an execd restart.
+	c2 := NewController("", "")
+	restored, err := c2.EnableBashSessionPersistence(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, restored)
+
+	sessions := c2.ListBashSessions()
+	require.Len(t, sessions, 1)
+	require.Equal(t, sessionID, sessions[0].ID)
+	require.Nil(t, sessions[0].Env, "list should not expose env")
+
+	info, err := c2.GetBashSessionInfo(sessionID)
+	require.NoError(t, err)
+	require.Equal(t, workDir, info.Cwd)
+	require.Equal(t, "hello", info.Env["PERSISTED_VAR"])
+
+	var stdout []string
+	require.NoError(t, c2.RunInBashSession(ctx, &ExecuteCodeRequest{
+		Language: Bash,
+		Context:  sessionID,
+		Code:     `echo "$PERSISTED_VAR $(pwd)"`,
+		Timeout:  5 * time.Second,
+		Hooks: ExecuteResultHook{
+			OnExecuteStdout: func(s string) { stdout = append(stdout, s) },
+		},
+	}))
+	require.Contains(t, stdout, "hello "+workDir)
+
+	require.NoError(t, c2.DeleteBashSession(sessionID))
+	_, err = os.Stat(filepath.Join(dir, sessionID+bashSessionRecordExt))
+	require.True(t, os.IsNotExist(err), "deleted session should be removed from the store")
+}
@@ -598,3 +598,43 @@ func TestBashSession_CloseWithNoActiveRun(t *testing.T) {
 		require.Fail(t, "close() did not return within 2s when no run was active")
 	}
 }
+
+// TestBashSession_TimeoutReportsTermination verifies that a timed-out run is
+// stopped per its termination policy and reported through the hooks rather
+// than as a runtime error.
+func TestBashSession_TimeoutReportsTermination(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found in PATH")
+	}
+
+	session := newBashSession("")
+	require.NoError(t, session.start())
+	defer session.close()
+
+	var (
+		termination *Termination
+		execErr     *execute.ErrorOutput
+	)
+	req := &ExecuteCodeRequest{
+		Code:        "export LEAK=1; trap 'exit 3' HUP; sleep 30 & wait",
+		Timeout:     300 * time.Millisecond,
+		Termination: &TerminationPolicy{Signal: "SIGHUP", GracePeriod: 10 * time.Second},
+		Hooks: ExecuteResultHook{
+			OnExecuteError:      func(e *execute.ErrorOutput) { execErr = e },
+			OnExecuteTerminated: func(t Termination) { termination = &t },
+		},
+	}
+
+	start := time.Now()
+	require.NoError(t, session.run(context.Background(), req))
+	require.Less(t, time.Since(start), 3*time.Second)
+
+	require.Equal(t, &Termination{Reason: TerminationTimeout, Signal: "SIGHUP"}, termination)
+	require.NotNil(t, execErr)
+	require.Equal(t, "3", execErr.EValue)
+	require.Contains(t, execErr.Traceback[0], "timeout after")
+
+	info := session.info(true)
+	_, leaked := info.Env["LEAK"]
+	require.False(t, leaked, "a terminated run must not update session env")
+}
@@ -38,3 +38,18 @@ func (c *Controller) RunInBashSession(_ context.Context, _ *ExecuteCodeRequest)
 func (c *Controller) DeleteBashSession(_ string) error { //nolint:revive
 	return errBashSessionNotSupported
 }
+
+// EnableBashSessionPersistence is not supported on Windows.
+func (c *Controller) EnableBashSessionPersistence(_ string) (int, error) { //nolint:revive
+	return 0, errBashSessionNotSupported
+}
+
+// ListBashSessions returns no sessions on Windows.
+func (c *Controller) ListBashSessions() []BashSessionInfo { //nolint:revive
+	return nil
+}
+
+// GetBashSessionInfo is not supported on Windows.
+func (c *Controller) GetBashSessionInfo(_ string) (*BashSessionInfo, error) { //nolint:revive
+	return nil, errBashSessionNotSupported
+}
@@ -117,7 +117,10 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 	startAt := time.Now()
 	log.Info("received command: %v", log.SanitizeCommand(request.Code))
 	shell := getShell()
-	cmd := exec.CommandContext(ctx, shell, "-c", request.Code)
+	// Cancellation is handled by the terminator below rather than
+	// exec.CommandContext, which would SIGKILL the leader immediately and
+	// skip the termination policy's grace period.
+	cmd := exec.Command(shell, "-c", request.Code)
 	extraEnv := mergeExtraEnvs(loadExtraEnvFromFile(), request.Envs)
 	cwd, err := pathutil.ExpandPathWithEnv(request.Cwd, extraEnv)
 	if err != nil {
@@ -140,21 +143,24 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 	cmd.Dir = cwd
 
 	done := make(chan struct{}, 1)
+	onStdout, onStderr, shaper := commandLineSinks(request)
 	var wg sync.WaitGroup
 	wg.Add(2)
 	safego.Go(func() {
 		defer wg.Done()
-		c.tailStdPipe(stdoutPath, request.Hooks.OnExecuteStdout, done)
+		c.tailStdLines(stdoutPath, onStdout, done)
 	})
 	safego.Go(func() {
 		defer wg.Done()
-		c.tailStdPipe(stderrPath, request.Hooks.OnExecuteStderr, done)
+		c.tailStdLines(stderrPath, onStderr, done)
 	})
+	safego.Go(func() { shaper.run(done) })
 
 	err = cmd.Start()
 	if err != nil {
 		close(done)
 		wg.Wait()
+		shaper.close()
 		request.Hooks.OnExecuteInit(session)
 		request.Hooks.OnExecuteError(&execute.ErrorOutput{
 			EName:     "CommandExecError",
@@ -165,6 +171,7 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 		return nil
 	}
 
+	terminator := newProcessGroupTerminator(cmd.Process.Pid, request.Termination, done)
 	kernel := &commandKernel{
 		pid:          cmd.Process.Pid,
 		stdoutPath:   stdoutPath,
@@ -173,6 +180,7 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 		running:      true,
 		content:      request.Code,
 		isBackground: false,
+		terminator:   terminator,
 	}
 	c.storeCommandKernel(session, kernel)
 	request.Hooks.OnExecuteInit(session)
@@ -200,12 +208,10 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 					return
 				default:
 				}
-				// Genuine cancellation (timeout, client disconnect,
-				// Interrupt). Kill the whole process group so children
-				// don't outlive the cancelled context.
-				if cmd.Process != nil {
-					_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
-				}
+				// Genuine cancellation (timeout). Stop the whole process
+				// group per the termination policy so children don't
+				// outlive the cancelled context.
+				terminator.terminate(terminationReasonFor(ctx))
 				return
 			case sig := <-signals:
 				if sig == nil {
@@ -222,6 +228,10 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 	err = cmd.Wait()
 	close(done)
 	wg.Wait()
+	shaper.close()
+	termination := terminator.termination()
+	c.markCommandTerminated(session, termination)
+	notifyTerminated(request.Hooks, termination)
 	if err != nil {
 		var eName, eValue string
 		var eCode int
@@ -261,10 +271,23 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 	session := c.newContextID()
 	request.Hooks.OnExecuteInit(session)
 
+	startAt := time.Now()
+	if _, err := c.startBackgroundCommand(ctx, cancel, session, request); err != nil {
+		return err
+	}
+
+	request.Hooks.OnExecuteComplete(time.Since(startAt))
+	return nil
+}
+
+// startBackgroundCommand launches request detached under session. The
+// returned channel is closed once the command has exited and its final
+// status has been recorded.
+func (c *Controller) startBackgroundCommand(ctx context.Context, cancel context.CancelFunc, session string, request *ExecuteCodeRequest) (<-chan struct{}, error) {
 	pipe, err := c.combinedOutputDescriptor(session)
 	if err != nil {
 		cancel()
-		return fmt.Errorf("failed to get combined output descriptor: %w", err)
+		return nil, fmt.Errorf("failed to get combined output descriptor: %w", err)
 	}
 	stdoutPath := c.combinedOutputFileName(session)
 	stderrPath := c.combinedOutputFileName(session)
@@ -277,19 +300,19 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 	startAt := time.Now()
 	log.Info("received command: %v", log.SanitizeCommand(request.Code))
 	shell := getShell()
-	cmd := exec.CommandContext(ctx, shell, "-c", request.Code)
+	cmd := exec.Command(shell, "-c", request.Code)
 	extraEnv := mergeExtraEnvs(loadExtraEnvFromFile(), request.Envs)
 	cwd, err := pathutil.ExpandPathWithEnv(request.Cwd, extraEnv)
 	if err != nil {
 		cancel()
-		return fmt.Errorf("resolve cwd: %w", err)
+		return nil, fmt.Errorf("resolve cwd: %w", err)
 	}
 	cmd.Dir = cwd
 	// Configure credentials and process group
 	cred, err := buildCredential(request.Uid, request.Gid)
 	if err != nil {
 		cancel()
-		return fmt.Errorf("build credential: %w", err)
+		return nil, fmt.Errorf("build credential: %w", err)
 	}
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid:    true,
@@ -323,18 +346,26 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 		kernel.running = false
 		c.storeCommandKernel(session, kernel)
 		c.markCommandFinished(session, 255, err.Error())
-		return fmt.Errorf("failed to start commands: %w", err)
+		return nil, fmt.Errorf("failed to start commands: %w", err)
 	}
 
+	exited := make(chan struct{})
+	finished := make(chan struct{})
+	terminator := newProcessGroupTerminator(cmd.Process.Pid, request.Termination, exited)
+
 	safego.Go(func() {
+		defer close(finished)
 		defer pipe.Close()
 
 		kernel.running = true
 		kernel.pid = cmd.Process.Pid
+		kernel.terminator = terminator
 		c.storeCommandKernel(session, kernel)
 
 		err = cmd.Wait()
+		close(exited)
 		cancel()
+		c.markCommandTerminated(session, terminator.termination())
 		if err != nil {
 			log.Error("CommandExecError: error running commands: %v", err)
 			exitCode := 1
@@ -348,14 +379,16 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 		c.markCommandFinished(session, 0, "")
 	})
 
-	// ensure we kill the whole process group if the context is cancelled (e.g., timeout).
+	// Stop the whole process group if the context expires (e.g., timeout).
+	// The wait goroutine cancels ctx after the command exits; exited gates
+	// the terminator so that never signals a reaped pid.
 	safego.Go(func() {
-		<-ctx.Done()
-		if cmd.Process != nil {
-			_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) // best-effort
+		select {
+		case <-exited:
+		case <-ctx.Done():
+			terminator.terminate(terminationReasonFor(ctx))
 		}
 	})
 
-	request.Hooks.OnExecuteComplete(time.Since(startAt))
-	return nil
+	return finished, nil
 }
@@ -23,10 +23,18 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/alibaba/opensandbox/execd/pkg/log"
 )
 
 // tailStdPipe streams appended log data until the process finishes.
 func (c *Controller) tailStdPipe(file string, onExecute func(text string), done <-chan struct{}) {
+	c.tailStdLines(file, func(text string, _ bool) { onExecute(text) }, done)
+}
+
+// tailStdLines is tailStdPipe for callers that need to know whether a line
+// was terminated by a bare carriage return (progress-style redraw).
+func (c *Controller) tailStdLines(file string, onLine func(text string, cr bool), done <-chan struct{}) {
 	lastPos := int64(0)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
@@ -36,10 +44,10 @@ func (c *Controller) tailStdPipe(file string, onExecute func(text string), done
 	for {
 		select {
 		case <-done:
-			c.readFromPos(mutex, file, lastPos, onExecute, true, &lastWasCR)
+			c.readLinesFromPos(mutex, file, lastPos, onLine, true, &lastWasCR)
 			return
 		case <-ticker.C:
-			newPos := c.readFromPos(mutex, file, lastPos, onExecute, false, &lastWasCR)
+			newPos := c.readLinesFromPos(mutex, file, lastPos, onLine, false, &lastWasCR)
 			lastPos = newPos
 		}
 	}
@@ -60,6 +68,25 @@ func (c *Controller) storeCommandKernel(sessionID string, kernel *commandKernel)
 	c.commandClientMap.Store(sessionID, kernel)
 }
 
+// removeCommandKernel drops a finished command and deletes its log files.
+// A running command is left alone.
+func (c *Controller) removeCommandKernel(sessionID string) {
+	c.mu.Lock()
+	kernel := c.getCommandKernel(sessionID)
+	if kernel == nil || kernel.running {
+		c.mu.Unlock()
+		return
+	}
+	c.commandClientMap.Delete(sessionID)
+	c.mu.Unlock()
+
+	for _, path := range []string{kernel.stdoutPath, kernel.stderrPath} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Warning("remove command log %s: %v", path, err)
+		}
+	}
+}
+
 // stdLogDescriptor creates temporary files for capturing command output.
 // It ensures the temp directory exists before opening files, so that commands
 // continue to work even after the /tmp directory has been removed and recreated.
@@ -108,6 +135,13 @@ func (c *Controller) combinedOutputFileName(session string) string {
 // lastWasCR persists CRLF detection across calls so a \r\n pair split between
 // two polls does not surface a spurious blank line for the trailing \n.
 func (c *Controller) readFromPos(mutex *sync.Mutex, filepath string, startPos int64, onExecute func(string), flushIncomplete bool, lastWasCR *bool) int64 {
+	return c.readLinesFromPos(mutex, filepath, startPos, func(text string, _ bool) { onExecute(text) }, flushIncomplete, lastWasCR)
+}
+
+// readLinesFromPos is readFromPos reporting, per line, whether it ended with a
+// bare \r. A \r\n pair visible within one read counts as a plain newline;
+// one split across polls is reported as \r since the \n is not yet written.
+func (c *Controller) readLinesFromPos(mutex *sync.Mutex, filepath string, startPos int64, onLine func(text string, cr bool), flushIncomplete bool, lastWasCR *bool) int64 {
 	if !mutex.TryLock() {
 		return -1
 	}
@@ -140,7 +174,7 @@ func (c *Controller) readFromPos(mutex *sync.Mutex, filepath string, startPos in
 			if err == io.EOF {
 				// If buffer has content but no newline, flush if needed, otherwise wait for next read
 				if flushIncomplete && buffer.Len() > 0 {
-					onExecute(buffer.String())
+					onLine(buffer.String(), false)
 					buffer.Reset()
 				}
 			}
@@ -150,18 +184,30 @@ func (c *Controller) readFromPos(mutex *sync.Mutex, filepath string, startPos in
 
 		// Check if it's a line terminator (\n or \r)
 		if b == '\n' || b == '\r' {
+			bareCR := false
+			if b == '\r' {
+				if next, err := reader.Peek(1); err == nil && next[0] == '\n' {
+					// Consume the \n of a complete \r\n pair right away.
+					_, _ = reader.ReadByte()
+					currentPos++
+					b = '\n'
+					cr = false
+				} else {
+					bareCR = true
+				}
+			}
 			switch {
 			case buffer.Len() > 0:
 				// Flush the line content without the terminator
-				onExecute(buffer.String())
+				onLine(buffer.String(), bareCR)
 				buffer.Reset()
 			case b == '\n' && cr:
 				// Second half of a \r\n pair; already emitted on \r
 			default:
 				// Standalone blank line; surface it so callers see the gap
-				onExecute("\n")
+				onLine("\n", bareCR)
 			}
-			cr = (b == '\r')
+			cr = bareCR
 			continue
 		}
 
@@ -244,3 +244,90 @@ func TestKillPid_TerminatesEntireProcessGroup(t *testing.T) {
 	}
 	t.Fatalf("child pid %d still alive 2s after killPid — process leak", childPid)
 }
+
+// TestRunCommand_TimeoutGracePeriodAllowsFlush verifies that a timeout sends
+// the policy signal first so the command can clean up, and that the
+// termination is reported to hooks and command status.
+func TestRunCommand_TimeoutGracePeriodAllowsFlush(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found in PATH")
+	}
+
+	reportFile := filepath.Join(t.TempDir(), "report.txt")
+	c := NewController("", "")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var (
+		session     string
+		termination *Termination
+		completed   bool
+	)
+	req := &ExecuteCodeRequest{
+		Code:        `trap 'echo flushed > "` + reportFile + `"; exit 0' TERM; sleep 30 & wait`,
+		Cwd:         t.TempDir(),
+		Timeout:     time.Second,
+		Termination: &TerminationPolicy{Signal: "SIGTERM", GracePeriod: 10 * time.Second},
+		Hooks: ExecuteResultHook{
+			OnExecuteInit:       func(s string) { session = s },
+			OnExecuteStdout:     func(_ string) {},
+			OnExecuteStderr:     func(_ string) {},
+			OnExecuteError:      func(_ *execute.ErrorOutput) {},
+			OnExecuteComplete:   func(_ time.Duration) { completed = true },
+			OnExecuteTerminated: func(t Termination) { termination = &t },
+		},
+	}
+
+	start := time.Now()
+	require.NoError(t, c.runCommand(ctx, req))
+	require.Less(t, time.Since(start), 5*time.Second, "command should exit on SIGTERM, not wait for SIGKILL")
+
+	data, err := os.ReadFile(reportFile)
+	require.NoError(t, err, "trap should have run before the command exited")
+	require.Equal(t, "flushed\n", string(data))
+
+	require.True(t, completed, "trap exits 0, so the run completes")
+	require.Equal(t, &Termination{Reason: TerminationTimeout, Signal: "SIGTERM"}, termination)
+
+	status, err := c.GetCommandStatus(session)
+	require.NoError(t, err)
+	require.Equal(t, termination, status.Termination)
+}
+
+// TestRunCommand_TimeoutEscalatesToKill verifies that a command ignoring the
+// policy signal is killed once the grace period runs out.
+func TestRunCommand_TimeoutEscalatesToKill(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found in PATH")
+	}
+
+	c := NewController("", "")
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	var (
+		termination *Termination
+		execErr     *execute.ErrorOutput
+	)
+	req := &ExecuteCodeRequest{
+		Code:        `trap '' TERM; sleep 30`,
+		Cwd:         t.TempDir(),
+		Timeout:     300 * time.Millisecond,
+		Termination: &TerminationPolicy{GracePeriod: 200 * time.Millisecond},
+		Hooks: ExecuteResultHook{
+			OnExecuteInit:       func(_ string) {},
+			OnExecuteStdout:     func(_ string) {},
+			OnExecuteStderr:     func(_ string) {},
+			OnExecuteError:      func(e *execute.ErrorOutput) { execErr = e },
+			OnExecuteComplete:   func(_ time.Duration) {},
+			OnExecuteTerminated: func(t Termination) { termination = &t },
+		},
+	}
+
+	start := time.Now()
+	require.NoError(t, c.runCommand(ctx, req))
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	require.NotNil(t, execErr, "killed command must report an error")
+	require.Equal(t, &Termination{Reason: TerminationTimeout, Signal: "SIGKILL"}, termination)
+}
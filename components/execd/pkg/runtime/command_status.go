@@ -30,6 +30,8 @@ type CommandStatus struct {
 	StartedAt  time.Time  `json:"started_at,omitempty"`
 	FinishedAt *time.Time `json:"finished_at,omitempty"`
 	Content    string     `json:"content,omitempty"`
+	// Termination is set when execd stopped the command on timeout or interrupt.
+	Termination *Termination `json:"termination,omitempty"`
 }
 
 // CommandOutput contains non-streamed stdout/stderr plus status.
@@ -71,23 +73,36 @@ func (c *Controller) GetCommandStatus(session string) (*CommandStatus, error) {
 		FinishedAt: kernel.finishedAt,
 		Content:    kernel.content,
 	}
+	if kernel.termination != nil {
+		t := *kernel.termination
+		status.Termination = &t
+	}
 	return status, nil
 }
 
-// SeekBackgroundCommandOutput returns accumulated stdout/stderr and status for a session.
-func (c *Controller) SeekBackgroundCommandOutput(session string, cursor int64) ([]byte, int64, error) {
+// SeekCommandOutput returns the raw on-disk log of a foreground or background
+// command from cursor onwards. Background commands share one combined log, so
+// stream only selects between StreamStdout (default) and StreamStderr for
+// foreground commands. The log is complete even when the streamed output was
+// shaped or truncated by an OutputFormat.
+func (c *Controller) SeekCommandOutput(session string, stream string, cursor int64) ([]byte, int64, error) {
 	kernel := c.commandSnapshot(session)
 	if kernel == nil {
 		return nil, -1, fmt.Errorf("command not found: %s", session)
 	}
 
-	if !kernel.isBackground {
-		return nil, -1, fmt.Errorf("command %s is not running in background", session)
+	path := kernel.stdoutPath
+	switch stream {
+	case "", StreamStdout:
+	case StreamStderr:
+		path = kernel.stderrPath
+	default:
+		return nil, -1, fmt.Errorf("unknown output stream %q", stream)
 	}
 
-	file, err := os.Open(kernel.stdoutPath)
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, -1, fmt.Errorf("error open combined output file for command %s: %w", session, err)
+		return nil, -1, fmt.Errorf("error open output file for command %s: %w", session, err)
 	}
 	defer file.Close()
 
@@ -112,6 +127,24 @@ func (c *Controller) SeekBackgroundCommandOutput(session string, cursor int64) (
 	return data, currentPos, nil
 }
 
+// markCommandTerminated records how execd stopped a command. It must be
+// called before markCommandFinished so pollers never see a finished command
+// without its termination.
+func (c *Controller) markCommandTerminated(session string, termination *Termination) {
+	if termination == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, ok := c.commandClientMap.Load(session); ok {
+		if kernel, _ := v.(*commandKernel); kernel != nil {
+			kernel.termination = termination
+		}
+	}
+}
+
 // markCommandFinished updates bookkeeping when a command exits.
 func (c *Controller) markCommandFinished(session string, exitCode int, errMsg string) {
 	now := time.Now()
@@ -135,4 +168,5 @@ func (c *Controller) markCommandFinished(session string, exitCode int, errMsg st
 	// process. Group-wide kill would otherwise amplify the impact of a
 	// stale-PID hit to every process in the unrelated process group.
 	kernel.pid = 0
+	kernel.terminator = nil
 }
@@ -76,7 +76,7 @@ func TestGetCommandStatus_Running(t *testing.T) {
 	t.Log(status)
 }
 
-func TestSeekBackgroundCommandOutput_Completed(t *testing.T) {
+func TestSeekCommandOutput_BackgroundCompleted(t *testing.T) {
 	c := NewController("", "")
 
 	tmpDir := t.TempDir()
@@ -101,14 +101,14 @@ func TestSeekBackgroundCommandOutput_Completed(t *testing.T) {
 	}
 	c.storeCommandKernel(session, kernel)
 
-	output, cursor, err := c.SeekBackgroundCommandOutput(session, 0)
+	output, cursor, err := c.SeekCommandOutput(session, "", 0)
 	require.NoError(t, err, "GetCommandOutput error")
 
 	require.Greater(t, cursor, int64(0), "expected cursor>=0")
 	require.Equal(t, stdoutContent, string(output))
 }
 
-func TestSeekBackgroundCommandOutput_WithRunBackgroundCommand(t *testing.T) {
+func TestSeekCommandOutput_WithRunBackgroundCommand(t *testing.T) {
 	c := NewController("", "")
 
 	expected := "line1\nline2\n"
@@ -135,19 +135,19 @@ func TestSeekBackgroundCommandOutput_WithRunBackgroundCommand(t *testing.T) {
 
 	deadline := time.Now().Add(5 * time.Second)
 	for time.Now().Before(deadline) {
-		output, cursor, err = c.SeekBackgroundCommandOutput(session, 0)
+		output, cursor, err = c.SeekCommandOutput(session, "", 0)
 		if err == nil && len(output) > 0 {
 			break
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
-	require.NoError(t, err, "SeekBackgroundCommandOutput error")
+	require.NoError(t, err, "SeekCommandOutput error")
 	require.Equal(t, expected, string(output))
 	require.GreaterOrEqual(t, cursor, int64(len(expected)), "cursor should advance to end of file")
 
 	// incremental seek from current cursor should return empty data and same-or-higher cursor
-	output2, cursor2, err := c.SeekBackgroundCommandOutput(session, cursor)
-	require.NoError(t, err, "SeekBackgroundCommandOutput (second call) error")
+	output2, cursor2, err := c.SeekCommandOutput(session, "", cursor)
+	require.NoError(t, err, "SeekCommandOutput (second call) error")
 	require.Empty(t, output2, "expected no new output")
 	require.GreaterOrEqual(t, cursor2, cursor, "cursor should not move backwards")
 }
@@ -16,6 +16,7 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -496,3 +497,46 @@ func TestCombinedOutputDescriptor_AutoCreatesTempDir(t *testing.T) {
 	require.NoError(t, err, "expected temp dir to be created, stat error")
 	require.True(t, info.IsDir(), "expected %s to be a directory", missingDir)
 }
+
+func TestRunCommand_OutputFormatKeepsRawLog(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("bash not available on windows")
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found in PATH")
+	}
+
+	c := NewController("", "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		sessionID string
+		jsonLines []string
+		stdout    []string
+		truncated bool
+	)
+	req := &ExecuteCodeRequest{
+		Code:         `echo '{"a":1}'; echo plain; echo 'this line exceeds the budget'`,
+		Cwd:          t.TempDir(),
+		OutputFormat: &OutputFormat{JSONLines: true, MaxBytes: 16},
+		Hooks: ExecuteResultHook{
+			OnExecuteInit:      func(s string) { sessionID = s },
+			OnExecuteStdout:    func(s string) { stdout = append(stdout, s) },
+			OnExecuteStderr:    func(_ string) {},
+			OnExecuteJSON:      func(_ string, data json.RawMessage) { jsonLines = append(jsonLines, string(data)) },
+			OnExecuteTruncated: func(_ int64) { truncated = true },
+			OnExecuteError:     func(_ *execute.ErrorOutput) {},
+			OnExecuteComplete:  func(_ time.Duration) {},
+		},
+	}
+	require.NoError(t, c.runCommand(ctx, req))
+
+	require.Equal(t, []string{`{"a":1}`}, jsonLines)
+	require.Equal(t, []string{"plain"}, stdout)
+	require.True(t, truncated)
+
+	raw, _, err := c.SeekCommandOutput(sessionID, StreamStdout, 0)
+	require.NoError(t, err)
+	require.Equal(t, "{\"a\":1}\nplain\nthis line exceeds the budget\n", string(raw))
+}
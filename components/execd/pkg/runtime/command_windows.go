@@ -58,21 +58,24 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 	cmd.Env = mergeEnvs(os.Environ(), extraEnv)
 
 	done := make(chan struct{}, 1)
+	onStdout, onStderr, shaper := commandLineSinks(request)
 	var wg sync.WaitGroup
 	wg.Add(2)
 	safego.Go(func() {
 		defer wg.Done()
-		c.tailStdPipe(c.stdoutFileName(session), request.Hooks.OnExecuteStdout, done)
+		c.tailStdLines(c.stdoutFileName(session), onStdout, done)
 	})
 	safego.Go(func() {
 		defer wg.Done()
-		c.tailStdPipe(c.stderrFileName(session), request.Hooks.OnExecuteStderr, done)
+		c.tailStdLines(c.stderrFileName(session), onStderr, done)
 	})
+	safego.Go(func() { shaper.run(done) })
 
 	err = cmd.Start()
 	if err != nil {
 		close(done)
 		wg.Wait()
+		shaper.close()
 		request.Hooks.OnExecuteError(&execute.ErrorOutput{EName: "CommandExecError", EValue: err.Error()})
 		log.Error("CommandExecError: error starting commands: %v", err)
 		return nil
@@ -80,6 +83,8 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 
 	kernel := &commandKernel{
 		pid:          cmd.Process.Pid,
+		stdoutPath:   c.stdoutFileName(session),
+		stderrPath:   c.stderrFileName(session),
 		content:      request.Code,
 		isBackground: false,
 	}
@@ -88,6 +93,7 @@ func (c *Controller) runCommand(ctx context.Context, request *ExecuteCodeRequest
 	err = cmd.Wait()
 	close(done)
 	wg.Wait()
+	shaper.close()
 	if err != nil {
 		var eName, eValue string
 		var traceback []string
@@ -121,9 +127,22 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 	session := c.newContextID()
 	request.Hooks.OnExecuteInit(session)
 
+	startAt := time.Now()
+	if _, err := c.startBackgroundCommand(ctx, cancel, session, request); err != nil {
+		return err
+	}
+
+	request.Hooks.OnExecuteComplete(time.Since(startAt))
+	return nil
+}
+
+// startBackgroundCommand launches request detached under session. The
+// returned channel is closed once the command has exited (or failed to
+// start) and its final status has been recorded.
+func (c *Controller) startBackgroundCommand(ctx context.Context, cancel context.CancelFunc, session string, request *ExecuteCodeRequest) (<-chan struct{}, error) {
 	pipe, err := c.combinedOutputDescriptor(session)
 	if err != nil {
-		return fmt.Errorf("failed to get combined output descriptor: %w", err)
+		return nil, fmt.Errorf("failed to get combined output descriptor: %w", err)
 	}
 	stdoutPath := c.combinedOutputFileName(session)
 	stderrPath := c.combinedOutputFileName(session)
@@ -134,7 +153,7 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 	extraEnv := mergeExtraEnvs(loadExtraEnvFromFile(), request.Envs)
 	cwd, err := pathutil.ExpandPathWithEnv(request.Cwd, extraEnv)
 	if err != nil {
-		return fmt.Errorf("resolve cwd: %w", err)
+		return nil, fmt.Errorf("resolve cwd: %w", err)
 	}
 
 	cmd.Dir = cwd
@@ -145,7 +164,10 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 	devNull, _ := os.OpenFile(os.DevNull, os.O_RDWR, 0) // best-effort, ignore error
 	cmd.Stdin = devNull
 
+	finished := make(chan struct{})
 	safego.Go(func() {
+		defer close(finished)
+
 		err := cmd.Start()
 		if err != nil {
 			log.Error("CommandExecError: error starting commands: %v", err)
@@ -190,6 +212,5 @@ func (c *Controller) runBackgroundCommand(ctx context.Context, cancel context.Ca
 		c.markCommandFinished(session, 0, "")
 	})
 
-	request.Hooks.OnExecuteComplete(time.Since(startAt))
-	return nil
+	return finished, nil
 }
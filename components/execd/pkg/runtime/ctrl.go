@@ -42,6 +42,7 @@ type Controller struct {
 	defaultLanguageSessions sync.Map // map[Language]string
 	commandClientMap        sync.Map // map[sessionID]*commandKernel
 	bashSessionClientMap    sync.Map // map[sessionID]*bashSession
+	bashSessionStore        *bashSessionStore
 	ptySessionMap           sync.Map // map[sessionID]*ptySession
 	isolatedSessionMap      sync.Map // map[sessionID]*isolatedSession
 	db                      *sql.DB
@@ -66,6 +67,9 @@ type commandKernel struct {
 	running      bool
 	isBackground bool
 	content      string
+	// terminator stops the running process group; nil when not running.
+	terminator  *processTerminator
+	termination *Termination
 }
 
 // NewController creates a runtime controller.
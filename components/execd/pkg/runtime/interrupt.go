@@ -43,6 +43,11 @@ func (c *Controller) Interrupt(sessionID string) error {
 		if snapshot == nil || !snapshot.running || snapshot.pid <= 0 {
 			return fmt.Errorf("command session %s is not running", sessionID)
 		}
+		if snapshot.terminator != nil && snapshot.terminator.policy != nil {
+			log.Warning("Interrupting command session %s (process group %d)", sessionID, snapshot.pid)
+			snapshot.terminator.terminateAsync(TerminationInterrupted)
+			return nil
+		}
 		return c.killPid(snapshot.pid)
 	case c.getBashSession(sessionID) != nil:
 		return c.closeBashSession(sessionID)
@@ -51,6 +56,14 @@ func (c *Controller) Interrupt(sessionID string) error {
 	}
 }
 
+// newProcessGroupTerminator returns a terminator that signals the process
+// group pgid until exited is closed.
+func newProcessGroupTerminator(pgid int, policy *TerminationPolicy, exited <-chan struct{}) *processTerminator {
+	return newProcessTerminator(policy, exited, func(sig syscall.Signal) error {
+		return syscall.Kill(-pgid, sig)
+	})
+}
+
 // killPid sends SIGTERM followed by SIGKILL if needed.
 //
 // Commands are launched with Setpgid: true, so pid is also the process group
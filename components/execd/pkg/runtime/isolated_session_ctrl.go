@@ -234,6 +234,17 @@ type StdoutCallback func(line string)
 // Runs are serialized per session via s.runMu.
 // envs are exported in the bash session before code runs.
 func (r *IsolatedRunner) RunInIsolatedSession(ctx context.Context, id string, code string, envs map[string]string, onStdout StdoutCallback) error {
+	return r.RunInIsolatedSessionWithTermination(ctx, id, code, envs, onStdout, nil)
+}
+
+// RunInIsolatedSessionWithTermination is RunInIsolatedSession with a
+// termination policy applied when ctx is done. Without a policy the run is
+// interrupted with SIGINT and never escalated. With one, the policy signal
+// (SIGINT unless set) is sent to the session's process group and SIGKILL
+// follows after the grace period. SIGKILL, like any signal the session shell
+// does not survive, ends the whole session, not just the run. Runs stopped
+// this way return a *TerminationError.
+func (r *IsolatedRunner) RunInIsolatedSessionWithTermination(ctx context.Context, id string, code string, envs map[string]string, onStdout StdoutCallback, policy *TerminationPolicy) error {
 	s := r.lookup(id)
 	if s == nil {
 		return ErrContextNotFound
@@ -283,9 +294,21 @@ func (r *IsolatedRunner) RunInIsolatedSession(ctx context.Context, id string, co
 	// terminate bash entirely.
 	done := make(chan struct{})
 	defer close(done)
+	var terminator *processTerminator
+	if policy != nil && s.cmd != nil && s.cmd.Process != nil {
+		p := *policy
+		if p.Signal == "" {
+			p.Signal = "SIGINT"
+		}
+		terminator = newProcessGroupTerminator(s.cmd.Process.Pid, &p, done)
+	}
 	go func() {
 		select {
 		case <-ctx.Done():
+			if terminator != nil {
+				terminator.terminate(terminationReasonFor(ctx))
+				return
+			}
 			if s.cmd != nil && s.cmd.Process != nil {
 				_ = syscall.Kill(-s.cmd.Process.Pid, syscall.SIGINT)
 			}
@@ -299,6 +322,9 @@ func (r *IsolatedRunner) RunInIsolatedSession(ctx context.Context, id string, co
 
 	exitCode, err := scanUntilMarker(ctx, stdout, runMarker, onStdout)
 	if err != nil {
+		if termination := terminator.termination(); termination != nil {
+			return &TerminationError{Termination: *termination, Err: err}
+		}
 		return err
 	}
 
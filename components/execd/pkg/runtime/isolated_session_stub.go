@@ -71,6 +71,11 @@ func (r *IsolatedRunner) RunInIsolatedSession(_ context.Context, _ string, _ str
 	return ErrContextNotFound
 }
 
+// RunInIsolatedSessionWithTermination returns an error on Windows.
+func (r *IsolatedRunner) RunInIsolatedSessionWithTermination(_ context.Context, _ string, _ string, _ map[string]string, _ StdoutCallback, _ *TerminationPolicy) error {
+	return ErrContextNotFound
+}
+
 // DeleteIsolatedSession returns an error on Windows.
 func (r *IsolatedRunner) DeleteIsolatedSession(_ string) error {
 	return ErrContextNotFound
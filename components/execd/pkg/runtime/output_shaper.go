@@ -0,0 +1,264 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+
+	defaultProgressInterval = 500 * time.Millisecond
+)
+
+// OutputFormat opts a foreground command into structured output events.
+// The zero value streams raw text lines.
+type OutputFormat struct {
+	// JSONLines emits a JSON event for every line holding a valid JSON object or array.
+	JSONLines bool `json:"json_lines,omitempty"`
+	// Progress collapses lines redrawn with a bare carriage return into throttled progress events.
+	Progress bool `json:"progress,omitempty"`
+	// ProgressInterval is the minimum spacing between progress events per stream.
+	ProgressInterval time.Duration `json:"progress_interval,omitempty"`
+	// MaxBytes caps the bytes streamed to the client; 0 means unlimited.
+	// The on-disk log is never truncated.
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+}
+
+func (f *OutputFormat) enabled() bool {
+	return f != nil && (f.JSONLines || f.Progress || f.MaxBytes > 0)
+}
+
+// progressState tracks one stream's carriage-return redraw sequence.
+type progressState struct {
+	active     bool
+	pending    string
+	hasPending bool
+	lastEmit   time.Time
+}
+
+// outputShaper turns raw stdout/stderr lines into json, progress and
+// truncation events according to an OutputFormat.
+type outputShaper struct {
+	format   OutputFormat
+	interval time.Duration
+	hooks    ExecuteResultHook
+	now      func() time.Time
+
+	mu        sync.Mutex
+	streamed  int64
+	truncated bool
+	progress  map[string]*progressState
+}
+
+func newOutputShaper(format OutputFormat, hooks ExecuteResultHook) *outputShaper {
+	interval := format.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	return &outputShaper{
+		format:   format,
+		interval: interval,
+		hooks:    hooks,
+		now:      time.Now,
+		progress: make(map[string]*progressState),
+	}
+}
+
+// commandLineSinks returns the per-stream line callbacks for a foreground
+// command. The shaper is nil unless the request opts into structured output.
+func commandLineSinks(request *ExecuteCodeRequest) (func(string, bool), func(string, bool), *outputShaper) {
+	if !request.OutputFormat.enabled() {
+		onStdout := func(text string, _ bool) { request.Hooks.OnExecuteStdout(text) }
+		onStderr := func(text string, _ bool) { request.Hooks.OnExecuteStderr(text) }
+		return onStdout, onStderr, nil
+	}
+
+	shaper := newOutputShaper(*request.OutputFormat, request.Hooks)
+	onStdout := func(text string, cr bool) { shaper.line(StreamStdout, text, cr) }
+	onStderr := func(text string, cr bool) { shaper.line(StreamStderr, text, cr) }
+	return onStdout, onStderr, shaper
+}
+
+// line handles one line read from stream. cr reports a bare \r terminator.
+func (s *outputShaper) line(stream, text string, cr bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.truncated {
+		return
+	}
+
+	if s.format.Progress {
+		st := s.progressFor(stream)
+		switch {
+		case cr && text == "\n":
+			// A redraw that starts with \r carries no content of its own.
+			return
+		case cr:
+			st.active = true
+			now := s.now()
+			if !st.lastEmit.IsZero() && now.Sub(st.lastEmit) < s.interval {
+				st.pending, st.hasPending = text, true
+				return
+			}
+			st.hasPending = false
+			st.lastEmit = now
+			s.emit(stream, text, s.hooks.OnExecuteProgress)
+			return
+		case st.active:
+			// The newline-terminated line after a redraw sequence is its final
+			// state; it supersedes anything still waiting on the throttle.
+			st.active, st.hasPending = false, false
+			st.lastEmit = time.Time{}
+			if text != "\n" {
+				s.emit(stream, text, s.hooks.OnExecuteProgress)
+			}
+			return
+		}
+	}
+
+	if s.format.JSONLines {
+		if raw, ok := jsonLine(text); ok {
+			s.emitJSON(stream, raw)
+			return
+		}
+	}
+	s.emit(stream, text, nil)
+}
+
+// run flushes throttled progress updates that would otherwise wait for the
+// next line, until done is closed. Safe to call on a nil shaper.
+func (s *outputShaper) run(done <-chan struct{}) {
+	if s == nil || !s.format.Progress {
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.flushProgress(false)
+		}
+	}
+}
+
+// close emits any progress update still held by the throttle. Safe to call
+// on a nil shaper.
+func (s *outputShaper) close() {
+	if s == nil {
+		return
+	}
+	s.flushProgress(true)
+}
+
+func (s *outputShaper) flushProgress(force bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, stream := range []string{StreamStdout, StreamStderr} {
+		st, ok := s.progress[stream]
+		if !ok || !st.hasPending || s.truncated {
+			continue
+		}
+		if !force && now.Sub(st.lastEmit) < s.interval {
+			continue
+		}
+		st.hasPending = false
+		st.lastEmit = now
+		s.emit(stream, st.pending, s.hooks.OnExecuteProgress)
+	}
+}
+
+func (s *outputShaper) progressFor(stream string) *progressState {
+	st, ok := s.progress[stream]
+	if !ok {
+		st = &progressState{}
+		s.progress[stream] = st
+	}
+	return st
+}
+
+// reserve accounts size bytes against MaxBytes, switching the shaper into
+// truncated mode (and announcing it once) when the budget is exhausted.
+// Caller must hold s.mu.
+func (s *outputShaper) reserve(size int) bool {
+	if s.format.MaxBytes <= 0 {
+		return true
+	}
+	if s.streamed+int64(size) > s.format.MaxBytes {
+		s.truncated = true
+		if s.hooks.OnExecuteTruncated != nil {
+			s.hooks.OnExecuteTruncated(s.format.MaxBytes)
+		}
+		return false
+	}
+	s.streamed += int64(size)
+	return true
+}
+
+// emit delivers a text line through hook, falling back to the raw stream hook
+// when hook is nil. Caller must hold s.mu.
+func (s *outputShaper) emit(stream, text string, hook func(stream, text string)) {
+	if !s.reserve(len(text)) {
+		return
+	}
+	if hook != nil {
+		hook(stream, text)
+		return
+	}
+	s.emitRaw(stream, text)
+}
+
+// emitJSON delivers a parsed JSON line. Caller must hold s.mu.
+func (s *outputShaper) emitJSON(stream string, raw json.RawMessage) {
+	if !s.reserve(len(raw)) {
+		return
+	}
+	if s.hooks.OnExecuteJSON != nil {
+		s.hooks.OnExecuteJSON(stream, raw)
+		return
+	}
+	s.emitRaw(stream, string(raw))
+}
+
+func (s *outputShaper) emitRaw(stream, text string) {
+	if stream == StreamStderr {
+		s.hooks.OnExecuteStderr(text)
+		return
+	}
+	s.hooks.OnExecuteStdout(text)
+}
+
+// jsonLine reports whether text is a JSON object or array, returning it
+// without surrounding whitespace.
+func jsonLine(text string) (json.RawMessage, bool) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+	if !json.Valid([]byte(trimmed)) {
+		return nil, false
+	}
+	return json.RawMessage(trimmed), true
+}
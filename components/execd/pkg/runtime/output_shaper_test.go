@@ -0,0 +1,131 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type shapedEvent struct {
+	kind   string
+	stream string
+	text   string
+}
+
+func newRecordingShaper(format OutputFormat) (*outputShaper, *[]shapedEvent, *time.Time) {
+	var events []shapedEvent
+	now := time.Unix(1700000000, 0)
+	hooks := ExecuteResultHook{
+		OnExecuteStdout: func(text string) { events = append(events, shapedEvent{"stdout", StreamStdout, text}) },
+		OnExecuteStderr: func(text string) { events = append(events, shapedEvent{"stderr", StreamStderr, text}) },
+		OnExecuteJSON: func(stream string, data json.RawMessage) {
+			events = append(events, shapedEvent{"json", stream, string(data)})
+		},
+		OnExecuteProgress: func(stream, text string) { events = append(events, shapedEvent{"progress", stream, text}) },
+		OnExecuteTruncated: func(limit int64) {
+			events = append(events, shapedEvent{kind: "truncated"})
+		},
+	}
+	s := newOutputShaper(format, hooks)
+	s.now = func() time.Time { return now }
+	return s, &events, &now
+}
+
+func TestOutputShaper_JSONLines(t *testing.T) {
+	s, events, _ := newRecordingShaper(OutputFormat{JSONLines: true})
+
+	s.line(StreamStdout, `  {"step":1}  `, false)
+	s.line(StreamStdout, `not json {`, false)
+	s.line(StreamStderr, `[1,2]`, false)
+	s.line(StreamStdout, `42`, false)
+
+	require.Equal(t, []shapedEvent{
+		{"json", StreamStdout, `{"step":1}`},
+		{"stdout", StreamStdout, `not json {`},
+		{"json", StreamStderr, `[1,2]`},
+		{"stdout", StreamStdout, `42`},
+	}, *events)
+}
+
+func TestOutputShaper_ProgressThrottledAndFinal(t *testing.T) {
+	s, events, now := newRecordingShaper(OutputFormat{Progress: true, ProgressInterval: time.Second})
+
+	s.line(StreamStdout, "\n", true) // leading \r of a redraw
+	s.line(StreamStdout, "10%", true)
+	*now = now.Add(100 * time.Millisecond)
+	s.line(StreamStdout, "20%", true)
+	*now = now.Add(100 * time.Millisecond)
+	s.line(StreamStdout, "30%", true)
+
+	s.flushProgress(false)
+	require.Equal(t, []shapedEvent{{"progress", StreamStdout, "10%"}}, *events, "updates within the interval are held")
+
+	*now = now.Add(time.Second)
+	s.flushProgress(false)
+	require.Equal(t, shapedEvent{"progress", StreamStdout, "30%"}, (*events)[1], "the latest held update wins")
+
+	s.line(StreamStdout, "40%", true)
+	s.line(StreamStdout, "100%", false)
+	s.line(StreamStdout, "done", false)
+	s.close()
+
+	require.Equal(t, []shapedEvent{
+		{"progress", StreamStdout, "10%"},
+		{"progress", StreamStdout, "30%"},
+		{"progress", StreamStdout, "100%"},
+		{"stdout", StreamStdout, "done"},
+	}, *events)
+}
+
+func TestOutputShaper_MaxBytesTruncates(t *testing.T) {
+	s, events, _ := newRecordingShaper(OutputFormat{MaxBytes: 10})
+
+	s.line(StreamStdout, "12345", false)
+	s.line(StreamStderr, "6789", false)
+	s.line(StreamStdout, "overflow", false)
+	s.line(StreamStdout, "x", false)
+
+	require.Equal(t, []shapedEvent{
+		{"stdout", StreamStdout, "12345"},
+		{"stderr", StreamStderr, "6789"},
+		{kind: "truncated"},
+	}, *events)
+}
+
+func TestReadLinesFromPos_ReportsBareCR(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "stdout.log")
+	require.NoError(t, os.WriteFile(logFile, []byte("a\r\nb\rc\nd\r"), 0o644))
+
+	type line struct {
+		text string
+		cr   bool
+	}
+	var got []line
+	c := &Controller{}
+	var lastWasCR bool
+	c.readLinesFromPos(&sync.Mutex{}, logFile, 0, func(text string, cr bool) {
+		got = append(got, line{text, cr})
+	}, false, &lastWasCR)
+
+	require.Equal(t, []line{{"a", false}, {"b", true}, {"c", false}, {"d", true}}, got)
+	require.True(t, lastWasCR)
+}
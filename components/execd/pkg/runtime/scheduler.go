@@ -0,0 +1,558 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alibaba/opensandbox/internal/safego"
+
+	"github.com/alibaba/opensandbox/execd/pkg/log"
+	"github.com/alibaba/opensandbox/execd/pkg/util/cron"
+)
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// ConcurrencyPolicy decides what happens when a schedule fires while its
+// previous run is still active.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencySkip drops the new run.
+	ConcurrencySkip ConcurrencyPolicy = "skip"
+	// ConcurrencyQueue starts the new run once the active one finishes.
+	ConcurrencyQueue ConcurrencyPolicy = "queue"
+	// ConcurrencyReplace interrupts the active run and starts the new one.
+	ConcurrencyReplace ConcurrencyPolicy = "replace"
+)
+
+// ScheduleRunStatus is the state of one scheduled run.
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunRunning   ScheduleRunStatus = "running"
+	ScheduleRunSucceeded ScheduleRunStatus = "succeeded"
+	ScheduleRunFailed    ScheduleRunStatus = "failed"
+	ScheduleRunSkipped   ScheduleRunStatus = "skipped"
+)
+
+const (
+	defaultScheduleHistoryLimit = 20
+	minScheduleInterval         = time.Second
+	// maxQueuedScheduleRuns bounds the backlog of a queue schedule whose
+	// runs take longer than its period; further fires are skipped.
+	maxQueuedScheduleRuns = 10
+)
+
+// ScheduleSpec describes a recurring background command. Exactly one of
+// Cron and Interval must be set.
+type ScheduleSpec struct {
+	Name    string
+	Command string
+	// Cron is a five-field cron expression evaluated in local time.
+	Cron string
+	// Interval runs the command at a fixed period, starting one period
+	// after the schedule is created or resumed.
+	Interval    time.Duration
+	Concurrency ConcurrencyPolicy
+	// Timeout caps every run; 0 means no limit.
+	Timeout     time.Duration
+	Termination *TerminationPolicy
+	Cwd         string
+	Envs        map[string]string
+	Uid         *uint32
+	Gid         *uint32
+	// HistoryLimit is the number of runs kept; 0 uses the default of 20.
+	HistoryLimit int
+}
+
+// ScheduleRun records one activation of a schedule. Runs are ordinary
+// background commands: CommandID works with the command status and logs APIs.
+type ScheduleRun struct {
+	CommandID   string
+	Status      ScheduleRunStatus
+	ScheduledAt time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	ExitCode    *int
+	Error       string
+	Termination *Termination
+}
+
+// ScheduleInfo is a point-in-time view of a schedule.
+type ScheduleInfo struct {
+	ID        string
+	Spec      ScheduleSpec
+	Paused    bool
+	CreatedAt time.Time
+	NextRunAt *time.Time
+	Queued    int
+	// Runs holds the most recent runs, newest first.
+	Runs []ScheduleRun
+}
+
+// Scheduler runs commands on cron or interval schedules.
+type Scheduler struct {
+	ctrl *Controller
+
+	mu        sync.Mutex
+	schedules map[string]*schedule
+}
+
+// NewScheduler creates a scheduler that launches runs through ctrl.
+func NewScheduler(ctrl *Controller) *Scheduler {
+	return &Scheduler{
+		ctrl:      ctrl,
+		schedules: make(map[string]*schedule),
+	}
+}
+
+// schedule is one registered schedule and its run loop.
+type schedule struct {
+	id        string
+	spec      ScheduleSpec
+	cron      *cron.Schedule
+	ctrl      *Controller
+	createdAt time.Time
+
+	// wake interrupts the loop after pause/resume; stop ends it.
+	wake chan struct{}
+	stop chan struct{}
+
+	mu      sync.Mutex
+	paused  bool
+	deleted bool
+	// anchor is the reference time of the next interval fire.
+	anchor    time.Time
+	nextRunAt *time.Time
+	active    *ScheduleRun
+	// replacing is set while the active run is being interrupted for a
+	// replace.
+	replacing bool
+	// queue holds fire times waiting for the active run to finish.
+	queue []time.Time
+	runs  []*ScheduleRun
+}
+
+// ValidateScheduleSpec reports whether spec can be scheduled.
+func ValidateScheduleSpec(spec ScheduleSpec) error {
+	_, err := parseScheduleTiming(spec)
+	return err
+}
+
+func parseScheduleTiming(spec ScheduleSpec) (*cron.Schedule, error) {
+	switch {
+	case spec.Cron != "" && spec.Interval > 0:
+		return nil, errors.New("cron and interval are mutually exclusive")
+	case spec.Cron != "":
+		sched, err := cron.Parse(spec.Cron)
+		if err != nil {
+			return nil, err
+		}
+		return sched, nil
+	case spec.Interval > 0:
+		if spec.Interval < minScheduleInterval {
+			return nil, fmt.Errorf("interval must be at least %s", minScheduleInterval)
+		}
+		return nil, nil
+	default:
+		return nil, errors.New("one of cron or interval is required")
+	}
+}
+
+// Create registers a schedule and starts its loop.
+func (s *Scheduler) Create(spec ScheduleSpec) (*ScheduleInfo, error) {
+	sched, err := parseScheduleTiming(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch spec.Concurrency {
+	case "":
+		spec.Concurrency = ConcurrencySkip
+	case ConcurrencySkip, ConcurrencyQueue, ConcurrencyReplace:
+	default:
+		return nil, fmt.Errorf("unknown concurrency policy %q", spec.Concurrency)
+	}
+	if spec.HistoryLimit <= 0 {
+		spec.HistoryLimit = defaultScheduleHistoryLimit
+	}
+	spec.Envs = maps.Clone(spec.Envs)
+
+	now := time.Now()
+	sc := &schedule{
+		id:        uuid.New().String(),
+		spec:      spec,
+		cron:      sched,
+		ctrl:      s.ctrl,
+		createdAt: now,
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		anchor:    now,
+	}
+
+	s.mu.Lock()
+	s.schedules[sc.id] = sc
+	s.mu.Unlock()
+
+	safego.Go(sc.loop)
+	log.Info("created schedule %s (%s): %s", sc.id, sc.timing(), log.SanitizeCommand(spec.Command))
+	info := sc.info()
+	return &info, nil
+}
+
+// List returns all schedules ordered by creation time, without run history.
+func (s *Scheduler) List() []ScheduleInfo {
+	s.mu.Lock()
+	schedules := make([]*schedule, 0, len(s.schedules))
+	for _, sc := range s.schedules {
+		schedules = append(schedules, sc)
+	}
+	s.mu.Unlock()
+
+	infos := make([]ScheduleInfo, 0, len(schedules))
+	for _, sc := range schedules {
+		info := sc.info()
+		info.Runs = nil
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+	})
+	return infos
+}
+
+// Get returns a schedule with its run history.
+func (s *Scheduler) Get(id string) (*ScheduleInfo, error) {
+	sc := s.lookup(id)
+	if sc == nil {
+		return nil, ErrScheduleNotFound
+	}
+	info := sc.info()
+	return &info, nil
+}
+
+// Pause stops future runs of a schedule. An active run is left alone.
+func (s *Scheduler) Pause(id string) (*ScheduleInfo, error) {
+	return s.setPaused(id, true)
+}
+
+// Resume restarts a paused schedule. Interval schedules fire one period
+// after resuming.
+func (s *Scheduler) Resume(id string) (*ScheduleInfo, error) {
+	return s.setPaused(id, false)
+}
+
+func (s *Scheduler) setPaused(id string, paused bool) (*ScheduleInfo, error) {
+	sc := s.lookup(id)
+	if sc == nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	sc.mu.Lock()
+	if sc.paused != paused {
+		sc.paused = paused
+		if paused {
+			sc.queue = nil
+		} else {
+			sc.anchor = time.Now()
+		}
+	}
+	sc.mu.Unlock()
+
+	select {
+	case sc.wake <- struct{}{}:
+	default:
+	}
+	info := sc.info()
+	return &info, nil
+}
+
+// Delete removes a schedule and the commands and logs of its finished runs.
+// An active run keeps going and stays available through the command APIs.
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	sc, ok := s.schedules[id]
+	delete(s.schedules, id)
+	s.mu.Unlock()
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	sc.mu.Lock()
+	sc.deleted = true
+	sc.queue = nil
+	for _, run := range sc.runs {
+		if run != sc.active {
+			sc.evictLocked(run)
+		}
+	}
+	sc.runs = nil
+	sc.mu.Unlock()
+	close(sc.stop)
+	log.Info("deleted schedule %s", id)
+	return nil
+}
+
+func (s *Scheduler) lookup(id string) *schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedules[id]
+}
+
+func (sc *schedule) timing() string {
+	if sc.cron != nil {
+		return "cron " + sc.spec.Cron
+	}
+	return "every " + sc.spec.Interval.String()
+}
+
+// loop waits for each fire time and dispatches it until the schedule is deleted.
+func (sc *schedule) loop() {
+	for {
+		next, ok := sc.planNext()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if ok {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+
+		select {
+		case <-sc.stop:
+			stopTimer(timer)
+			return
+		case <-sc.wake:
+			stopTimer(timer)
+		case <-fire:
+			sc.fire(next)
+		}
+	}
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+// planNext computes and publishes the next fire time; ok is false while paused.
+func (sc *schedule) planNext() (time.Time, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.paused {
+		sc.nextRunAt = nil
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	if sc.cron != nil {
+		next = sc.cron.Next(time.Now())
+		if next.IsZero() {
+			sc.nextRunAt = nil
+			return time.Time{}, false
+		}
+	} else {
+		next = sc.anchor.Add(sc.spec.Interval)
+		// Don't replay fires missed while the process was suspended.
+		if now := time.Now(); next.Before(now) {
+			next = now
+		}
+	}
+	sc.nextRunAt = &next
+	return next, true
+}
+
+// fire handles one activation according to the concurrency policy.
+func (sc *schedule) fire(at time.Time) {
+	var replace string
+
+	sc.mu.Lock()
+	sc.anchor = at
+	switch {
+	case sc.paused || sc.deleted:
+	case sc.active == nil:
+		sc.startLocked(at)
+	case sc.spec.Concurrency == ConcurrencyQueue && len(sc.queue) < maxQueuedScheduleRuns:
+		sc.queue = append(sc.queue, at)
+	case sc.spec.Concurrency == ConcurrencyReplace:
+		// Keep only the newest pending fire; the active run is
+		// interrupted and the next run starts once it has exited.
+		sc.queue = []time.Time{at}
+		if !sc.replacing {
+			sc.replacing = true
+			replace = sc.active.CommandID
+		}
+	default:
+		sc.recordLocked(&ScheduleRun{
+			Status:      ScheduleRunSkipped,
+			ScheduledAt: at,
+			Error:       fmt.Sprintf("previous run %s still active", sc.active.CommandID),
+		})
+	}
+	sc.mu.Unlock()
+
+	if replace != "" {
+		log.Info("schedule %s: replacing active run %s", sc.id, replace)
+		// Interrupt waits out the grace period; don't hold up the loop.
+		safego.Go(func() {
+			if err := sc.ctrl.Interrupt(replace); err != nil {
+				log.Warning("schedule %s: interrupt run %s: %v", sc.id, replace, err)
+			}
+		})
+	}
+}
+
+// startLocked launches a run as a background command. Caller must hold sc.mu.
+func (sc *schedule) startLocked(scheduledAt time.Time) {
+	started := time.Now()
+	run := &ScheduleRun{
+		CommandID:   sc.ctrl.newContextID(),
+		Status:      ScheduleRunRunning,
+		ScheduledAt: scheduledAt,
+		StartedAt:   &started,
+	}
+	sc.recordLocked(run)
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if sc.spec.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), sc.spec.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	request := &ExecuteCodeRequest{
+		Language:    BackgroundCommand,
+		Code:        sc.spec.Command,
+		Cwd:         sc.spec.Cwd,
+		Envs:        sc.spec.Envs,
+		Uid:         sc.spec.Uid,
+		Gid:         sc.spec.Gid,
+		Timeout:     sc.spec.Timeout,
+		Termination: sc.spec.Termination,
+	}
+	finished, err := sc.ctrl.startBackgroundCommand(ctx, cancel, run.CommandID, request)
+	if err != nil {
+		log.Error("schedule %s: start run %s: %v", sc.id, run.CommandID, err)
+		now := time.Now()
+		run.Status = ScheduleRunFailed
+		run.Error = err.Error()
+		run.FinishedAt = &now
+		return
+	}
+
+	sc.active = run
+	safego.Go(func() {
+		<-finished
+		sc.finish(run)
+	})
+}
+
+// finish records the outcome of run and starts the next pending one.
+func (sc *schedule) finish(run *ScheduleRun) {
+	status, err := sc.ctrl.GetCommandStatus(run.CommandID)
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = ScheduleRunFailed
+	switch {
+	case err != nil:
+		run.Error = err.Error()
+	default:
+		if status.FinishedAt != nil {
+			run.FinishedAt = status.FinishedAt
+		}
+		run.ExitCode = status.ExitCode
+		run.Error = status.Error
+		run.Termination = status.Termination
+		if status.ExitCode != nil && *status.ExitCode == 0 {
+			run.Status = ScheduleRunSucceeded
+		}
+	}
+
+	sc.active = nil
+	sc.replacing = false
+	if !sc.deleted && !slices.Contains(sc.runs, run) {
+		// Trimmed from the history while active.
+		sc.evictLocked(run)
+	}
+	if sc.paused || sc.deleted || len(sc.queue) == 0 {
+		return
+	}
+	next := sc.queue[0]
+	sc.queue = sc.queue[1:]
+	sc.startLocked(next)
+}
+
+// recordLocked appends run to the history, trimming it to HistoryLimit and
+// evicting the commands of trimmed runs. Caller must hold sc.mu.
+func (sc *schedule) recordLocked(run *ScheduleRun) {
+	sc.runs = append(sc.runs, run)
+	if extra := len(sc.runs) - sc.spec.HistoryLimit; extra > 0 {
+		for _, trimmed := range sc.runs[:extra] {
+			// An active run is evicted by finish once it exits.
+			if trimmed != sc.active {
+				sc.evictLocked(trimmed)
+			}
+		}
+		sc.runs = append([]*ScheduleRun(nil), sc.runs[extra:]...)
+	}
+}
+
+// evictLocked removes the background command of a finished run and its log,
+// so that schedules don't accumulate them. Caller must hold sc.mu.
+func (sc *schedule) evictLocked(run *ScheduleRun) {
+	if run.CommandID != "" {
+		sc.ctrl.removeCommandKernel(run.CommandID)
+	}
+}
+
+func (sc *schedule) info() ScheduleInfo {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	spec := sc.spec
+	spec.Envs = maps.Clone(sc.spec.Envs)
+	info := ScheduleInfo{
+		ID:        sc.id,
+		Spec:      spec,
+		Paused:    sc.paused,
+		CreatedAt: sc.createdAt,
+		Queued:    len(sc.queue),
+		Runs:      make([]ScheduleRun, 0, len(sc.runs)),
+	}
+	if sc.nextRunAt != nil {
+		next := *sc.nextRunAt
+		info.NextRunAt = &next
+	}
+	for i := len(sc.runs) - 1; i >= 0; i-- {
+		info.Runs = append(info.Runs, *sc.runs[i])
+	}
+	return info
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSchedule registers a schedule whose timer never fires during the
+// test, so runs are driven by calling fire directly.
+func newTestSchedule(t *testing.T, s *Scheduler, spec ScheduleSpec) *schedule {
+	t.Helper()
+	spec.Interval = time.Hour
+	info, err := s.Create(spec)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Delete(info.ID) })
+	sc := s.lookup(info.ID)
+	require.NotNil(t, sc)
+	return sc
+}
+
+func waitForRuns(t *testing.T, s *Scheduler, id string, cond func([]ScheduleRun) bool) []ScheduleRun {
+	t.Helper()
+	var runs []ScheduleRun
+	require.Eventually(t, func() bool {
+		info, err := s.Get(id)
+		require.NoError(t, err)
+		runs = info.Runs
+		return cond(runs)
+	}, 10*time.Second, 20*time.Millisecond)
+	return runs
+}
+
+func allFinished(n int) func([]ScheduleRun) bool {
+	return func(runs []ScheduleRun) bool {
+		if len(runs) != n {
+			return false
+		}
+		for _, run := range runs {
+			if run.Status == ScheduleRunRunning {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func TestScheduler_RecordsRunsAsBackgroundCommands(t *testing.T) {
+	c := NewController("", "")
+	s := NewScheduler(c)
+	sc := newTestSchedule(t, s, ScheduleSpec{Command: "echo scheduled; exit 3"})
+
+	sc.fire(time.Now())
+	runs := waitForRuns(t, s, sc.id, allFinished(1))
+
+	run := runs[0]
+	require.Equal(t, ScheduleRunFailed, run.Status)
+	require.NotNil(t, run.ExitCode)
+	require.Equal(t, 3, *run.ExitCode)
+	require.NotNil(t, run.FinishedAt)
+
+	status, err := c.GetCommandStatus(run.CommandID)
+	require.NoError(t, err)
+	require.False(t, status.Running)
+
+	output, _, err := c.SeekCommandOutput(run.CommandID, "", 0)
+	require.NoError(t, err)
+	require.Contains(t, string(output), "scheduled")
+}
+
+func TestScheduler_SkipWhileActive(t *testing.T) {
+	s := NewScheduler(NewController("", ""))
+	sc := newTestSchedule(t, s, ScheduleSpec{Command: "sleep 0.5"})
+
+	sc.fire(time.Now())
+	sc.fire(time.Now())
+	runs := waitForRuns(t, s, sc.id, allFinished(2))
+
+	// Newest first.
+	require.Equal(t, ScheduleRunSkipped, runs[0].Status)
+	require.Empty(t, runs[0].CommandID)
+	require.Equal(t, ScheduleRunSucceeded, runs[1].Status)
+}
+
+func TestScheduler_QueueRunsAfterActive(t *testing.T) {
+	s := NewScheduler(NewController("", ""))
+	sc := newTestSchedule(t, s, ScheduleSpec{Command: "sleep 0.2", Concurrency: ConcurrencyQueue})
+
+	sc.fire(time.Now())
+	sc.fire(time.Now())
+	info, err := s.Get(sc.id)
+	require.NoError(t, err)
+	require.Equal(t, 1, info.Queued)
+
+	runs := waitForRuns(t, s, sc.id, allFinished(2))
+	require.Equal(t, ScheduleRunSucceeded, runs[0].Status)
+	require.Equal(t, ScheduleRunSucceeded, runs[1].Status)
+	require.False(t, runs[0].StartedAt.Before(*runs[1].FinishedAt), "queued run must start after the active one finished")
+}
+
+func TestScheduler_ReplaceInterruptsActive(t *testing.T) {
+	s := NewScheduler(NewController("", ""))
+	sc := newTestSchedule(t, s, ScheduleSpec{
+		Command:     "sleep 30",
+		Concurrency: ConcurrencyReplace,
+		Termination: &TerminationPolicy{Signal: "SIGKILL"},
+	})
+
+	sc.fire(time.Now())
+	waitForRuns(t, s, sc.id, func(runs []ScheduleRun) bool { return len(runs) == 1 })
+	// Let the first run register its process before it is replaced.
+	require.Eventually(t, func() bool {
+		sc.mu.Lock()
+		id := sc.active.CommandID
+		sc.mu.Unlock()
+		status, err := s.ctrl.GetCommandStatus(id)
+		return err == nil && status.Running
+	}, 5*time.Second, 20*time.Millisecond)
+
+	sc.fire(time.Now())
+	runs := waitForRuns(t, s, sc.id, func(runs []ScheduleRun) bool {
+		return len(runs) == 2 && runs[1].Status != ScheduleRunRunning && runs[0].Status == ScheduleRunRunning
+	})
+	require.Equal(t, ScheduleRunFailed, runs[1].Status)
+	require.NotNil(t, runs[1].Termination)
+	require.Equal(t, TerminationInterrupted, runs[1].Termination.Reason)
+
+	require.NoError(t, s.ctrl.Interrupt(runs[0].CommandID))
+}
+
+func TestScheduler_HistoryLimit(t *testing.T) {
+	s := NewScheduler(NewController("", ""))
+	sc := newTestSchedule(t, s, ScheduleSpec{Command: "true", HistoryLimit: 2})
+
+	for i := 0; i < 3; i++ {
+		sc.fire(time.Now())
+		waitForRuns(t, s, sc.id, func(runs []ScheduleRun) bool {
+			return len(runs) > 0 && runs[0].Status != ScheduleRunRunning
+		})
+	}
+
+	info, err := s.Get(sc.id)
+	require.NoError(t, err)
+	require.Len(t, info.Runs, 2)
+}
+
+func commandKernelCount(c *Controller) int {
+	n := 0
+	c.commandClientMap.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestScheduler_EvictsTrimmedRuns(t *testing.T) {
+	c := NewController("", "")
+	s := NewScheduler(c)
+	sc := newTestSchedule(t, s, ScheduleSpec{Command: "echo run", HistoryLimit: 2})
+
+	var commandIDs []string
+	for i := 0; i < 10; i++ {
+		sc.fire(time.Now())
+		runs := waitForRuns(t, s, sc.id, func(runs []ScheduleRun) bool {
+			return len(runs) > 0 && runs[0].Status != ScheduleRunRunning
+		})
+		commandIDs = append(commandIDs, runs[0].CommandID)
+	}
+
+	require.Equal(t, 2, commandKernelCount(c))
+	for _, id := range commandIDs[:8] {
+		_, err := c.GetCommandStatus(id)
+		require.Error(t, err, "trimmed run %s must be evicted", id)
+		require.NoFileExists(t, c.combinedOutputFileName(id))
+	}
+	for _, id := range commandIDs[8:] {
+		require.FileExists(t, c.combinedOutputFileName(id))
+	}
+
+	require.NoError(t, s.Delete(sc.id))
+	require.Zero(t, commandKernelCount(c))
+	for _, id := range commandIDs[8:] {
+		require.NoFileExists(t, c.combinedOutputFileName(id))
+	}
+}
+
+func TestScheduler_PauseResumeDelete(t *testing.T) {
+	s := NewScheduler(NewController("", ""))
+	info, err := s.Create(ScheduleSpec{Command: "true", Cron: "@daily"})
+	require.NoError(t, err)
+	require.Equal(t, ConcurrencySkip, info.Spec.Concurrency)
+	require.Equal(t, defaultScheduleHistoryLimit, info.Spec.HistoryLimit)
+
+	require.Eventually(t, func() bool {
+		got, err := s.Get(info.ID)
+		return err == nil && got.NextRunAt != nil
+	}, time.Second, 10*time.Millisecond)
+
+	paused, err := s.Pause(info.ID)
+	require.NoError(t, err)
+	require.True(t, paused.Paused)
+	require.Eventually(t, func() bool {
+		got, err := s.Get(info.ID)
+		return err == nil && got.NextRunAt == nil
+	}, time.Second, 10*time.Millisecond)
+
+	sc := s.lookup(info.ID)
+	sc.fire(time.Now())
+	got, err := s.Get(info.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.Runs, "paused schedule must not run")
+
+	resumed, err := s.Resume(info.ID)
+	require.NoError(t, err)
+	require.False(t, resumed.Paused)
+	require.Len(t, s.List(), 1)
+
+	require.NoError(t, s.Delete(info.ID))
+	require.ErrorIs(t, s.Delete(info.ID), ErrScheduleNotFound)
+	_, err = s.Get(info.ID)
+	require.ErrorIs(t, err, ErrScheduleNotFound)
+	require.Empty(t, s.List())
+}
+
+func TestValidateScheduleSpec(t *testing.T) {
+	require.NoError(t, ValidateScheduleSpec(ScheduleSpec{Cron: "*/5 * * * *"}))
+	require.NoError(t, ValidateScheduleSpec(ScheduleSpec{Interval: time.Minute}))
+	require.Error(t, ValidateScheduleSpec(ScheduleSpec{}))
+	require.Error(t, ValidateScheduleSpec(ScheduleSpec{Cron: "@daily", Interval: time.Minute}))
+	require.Error(t, ValidateScheduleSpec(ScheduleSpec{Interval: time.Millisecond}))
+	require.Error(t, ValidateScheduleSpec(ScheduleSpec{Cron: "* * *"}))
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alibaba/opensandbox/execd/pkg/log"
+	"github.com/alibaba/opensandbox/internal/safego"
+)
+
+// TerminationReason tells why execd stopped a process.
+type TerminationReason string
+
+const (
+	// TerminationTimeout means the request timeout expired.
+	TerminationTimeout TerminationReason = "timeout"
+	// TerminationInterrupted means the run was interrupted, deleted or abandoned by its client.
+	TerminationInterrupted TerminationReason = "interrupted"
+)
+
+const defaultTerminationGracePeriod = 3 * time.Second
+
+// terminationSignals are the signals a TerminationPolicy may send first.
+var terminationSignals = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+}
+
+// TerminationPolicy controls how execd stops a process group on timeout or
+// interrupt: Signal is sent first and SIGKILL follows once GracePeriod has
+// elapsed without the group leader exiting.
+type TerminationPolicy struct {
+	// Signal is the name of the first signal, e.g. "SIGTERM" (the default).
+	Signal string `json:"signal,omitempty"`
+	// GracePeriod is the time allowed between Signal and SIGKILL; 0 uses the default of 3s.
+	GracePeriod time.Duration `json:"grace_period,omitempty"`
+}
+
+// Termination records how execd stopped a process.
+type Termination struct {
+	// Reason is why execd first signalled the process; escalating to SIGKILL keeps it.
+	Reason TerminationReason `json:"reason"`
+	// Signal is the last signal execd sent, e.g. "SIGTERM", or "SIGKILL" after escalation.
+	Signal string `json:"signal"`
+}
+
+// TerminationError is returned by runs that execd stopped before they finished.
+type TerminationError struct {
+	Termination Termination
+	Err         error
+}
+
+func (e *TerminationError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Termination.Reason, e.Termination.Signal, e.Err)
+}
+
+func (e *TerminationError) Unwrap() error {
+	return e.Err
+}
+
+// terminationReasonFor maps a done context to the reason for stopping its run.
+func terminationReasonFor(ctx context.Context) TerminationReason {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return TerminationTimeout
+	}
+	return TerminationInterrupted
+}
+
+// plan returns the first signal and grace period. Without a policy execd
+// keeps its historical behaviour and sends SIGKILL right away.
+func (p *TerminationPolicy) plan() (syscall.Signal, time.Duration) {
+	if p == nil {
+		return syscall.SIGKILL, 0
+	}
+
+	sig := syscall.SIGTERM
+	if p.Signal != "" {
+		s, ok := terminationSignals[p.Signal]
+		if !ok {
+			log.Warning("unsupported termination signal %q, using SIGTERM", p.Signal)
+		} else {
+			sig = s
+		}
+	}
+	grace := p.GracePeriod
+	if grace <= 0 {
+		grace = defaultTerminationGracePeriod
+	}
+	return sig, grace
+}
+
+// processTerminator stops one run according to its TerminationPolicy.
+type processTerminator struct {
+	policy *TerminationPolicy
+	// exited is closed once the run is over; no signal is sent after that.
+	exited <-chan struct{}
+	// signal delivers sig to the run's process group.
+	signal func(sig syscall.Signal) error
+
+	once   sync.Once
+	mu     sync.Mutex
+	result *Termination
+}
+
+func newProcessTerminator(policy *TerminationPolicy, exited <-chan struct{}, signal func(syscall.Signal) error) *processTerminator {
+	return &processTerminator{
+		policy: policy,
+		exited: exited,
+		signal: signal,
+	}
+}
+
+// terminate sends the policy signal, waits up to the grace period for the
+// run to end and then sends SIGKILL. It returns once the run has ended or
+// SIGKILL was sent. Only the first call acts; later calls wait for it.
+func (t *processTerminator) terminate(reason TerminationReason) {
+	if t == nil {
+		return
+	}
+	t.once.Do(func() { t.escalate(reason) })
+}
+
+// terminateAsync runs terminate in the background, for callers such as
+// session deletion that must not wait out the grace period.
+func (t *processTerminator) terminateAsync(reason TerminationReason) {
+	if t == nil {
+		return
+	}
+	safego.Go(func() { t.terminate(reason) })
+}
+
+func (t *processTerminator) escalate(reason TerminationReason) {
+	if t.isExited() {
+		return
+	}
+
+	sig, grace := t.policy.plan()
+	t.record(reason, sig)
+	if !t.send(sig) {
+		return
+	}
+	if sig == syscall.SIGKILL {
+		return
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-t.exited:
+		return
+	case <-timer.C:
+	}
+
+	log.Warning("process did not exit within %s after %s, sending SIGKILL", grace, signalName(sig))
+	t.record(reason, syscall.SIGKILL)
+	t.send(syscall.SIGKILL)
+}
+
+// send delivers sig unless the run has already ended, reporting whether the
+// target may still be alive.
+func (t *processTerminator) send(sig syscall.Signal) bool {
+	if t.isExited() {
+		return false
+	}
+	if err := t.signal(sig); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return false
+		}
+		log.Warning("send %s: %v", signalName(sig), err)
+	}
+	return true
+}
+
+func (t *processTerminator) isExited() bool {
+	select {
+	case <-t.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *processTerminator) record(reason TerminationReason, sig syscall.Signal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.result = &Termination{Reason: reason, Signal: signalName(sig)}
+}
+
+// termination returns how the run was stopped, or nil if terminate never
+// signalled it. Safe to call on a nil terminator.
+func (t *processTerminator) termination() *Termination {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.result == nil {
+		return nil
+	}
+	cp := *t.result
+	return &cp
+}
+
+// notifyTerminated fires OnExecuteTerminated when the run was stopped by execd.
+func notifyTerminated(hooks ExecuteResultHook, termination *Termination) {
+	if termination != nil && hooks.OnExecuteTerminated != nil {
+		hooks.OnExecuteTerminated(*termination)
+	}
+}
+
+func signalName(sig syscall.Signal) string {
+	for name, s := range terminationSignals {
+		if s == sig {
+			return name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
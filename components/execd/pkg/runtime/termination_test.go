@@ -0,0 +1,150 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTerminator returns a terminator whose signals are recorded.
+// When exitOn is set, delivering that signal ends the run.
+func recordingTerminator(policy *TerminationPolicy, exitOn syscall.Signal) (*processTerminator, func() []syscall.Signal) {
+	var (
+		mu   sync.Mutex
+		sent []syscall.Signal
+		once sync.Once
+	)
+	exited := make(chan struct{})
+	t := newProcessTerminator(policy, exited, func(sig syscall.Signal) error {
+		mu.Lock()
+		sent = append(sent, sig)
+		mu.Unlock()
+		if sig == exitOn || sig == syscall.SIGKILL {
+			once.Do(func() { close(exited) })
+		}
+		return nil
+	})
+	return t, func() []syscall.Signal {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]syscall.Signal(nil), sent...)
+	}
+}
+
+func TestProcessTerminator_DefaultTimeoutKillsImmediately(t *testing.T) {
+	term, sent := recordingTerminator(nil, 0)
+
+	term.terminate(TerminationTimeout)
+
+	require.Equal(t, []syscall.Signal{syscall.SIGKILL}, sent())
+	require.Equal(t, &Termination{Reason: TerminationTimeout, Signal: "SIGKILL"}, term.termination())
+}
+
+func TestProcessTerminator_ExitsWithinGracePeriod(t *testing.T) {
+	policy := &TerminationPolicy{Signal: "SIGINT", GracePeriod: time.Minute}
+	term, sent := recordingTerminator(policy, syscall.SIGINT)
+
+	start := time.Now()
+	term.terminate(TerminationInterrupted)
+
+	require.Less(t, time.Since(start), time.Second, "must not wait out the grace period")
+	require.Equal(t, []syscall.Signal{syscall.SIGINT}, sent())
+	require.Equal(t, &Termination{Reason: TerminationInterrupted, Signal: "SIGINT"}, term.termination())
+}
+
+func TestProcessTerminator_EscalatesAfterGracePeriod(t *testing.T) {
+	policy := &TerminationPolicy{GracePeriod: 50 * time.Millisecond}
+	term, sent := recordingTerminator(policy, 0)
+
+	term.terminate(TerminationTimeout)
+	term.terminate(TerminationInterrupted)
+
+	require.Equal(t, []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL}, sent())
+	require.Equal(t, &Termination{Reason: TerminationTimeout, Signal: "SIGKILL"}, term.termination())
+}
+
+func TestProcessTerminator_DefaultInterruptKillsImmediately(t *testing.T) {
+	term, sent := recordingTerminator(nil, 0)
+
+	term.terminate(TerminationInterrupted)
+
+	require.Equal(t, []syscall.Signal{syscall.SIGKILL}, sent())
+	require.Equal(t, &Termination{Reason: TerminationInterrupted, Signal: "SIGKILL"}, term.termination())
+}
+
+func TestProcessTerminator_TerminateAsyncDoesNotWait(t *testing.T) {
+	policy := &TerminationPolicy{GracePeriod: 100 * time.Millisecond}
+	term, sent := recordingTerminator(policy, 0)
+
+	start := time.Now()
+	term.terminateAsync(TerminationInterrupted)
+	require.Less(t, time.Since(start), 50*time.Millisecond, "must not wait out the grace period")
+
+	require.Eventually(t, func() bool { return len(sent()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	require.Equal(t, []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL}, sent())
+	require.Equal(t, &Termination{Reason: TerminationInterrupted, Signal: "SIGKILL"}, term.termination())
+}
+
+func TestProcessTerminator_NoSignalAfterExit(t *testing.T) {
+	exited := make(chan struct{})
+	close(exited)
+	term := newProcessTerminator(nil, exited, func(syscall.Signal) error {
+		t.Fatal("signal sent to an exited run")
+		return nil
+	})
+
+	term.terminate(TerminationTimeout)
+
+	require.Nil(t, term.termination())
+}
+
+func TestProcessTerminator_GoneProcessStopsEscalation(t *testing.T) {
+	var sent []syscall.Signal
+	term := newProcessTerminator(&TerminationPolicy{GracePeriod: time.Minute}, make(chan struct{}), func(sig syscall.Signal) error {
+		sent = append(sent, sig)
+		return syscall.ESRCH
+	})
+
+	term.terminate(TerminationInterrupted)
+
+	require.Equal(t, []syscall.Signal{syscall.SIGTERM}, sent)
+}
+
+func TestTerminationReasonFor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	require.Equal(t, TerminationTimeout, terminationReasonFor(ctx))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, TerminationInterrupted, terminationReasonFor(ctx))
+}
+
+func TestTerminationError_Unwrap(t *testing.T) {
+	err := &TerminationError{
+		Termination: Termination{Reason: TerminationTimeout, Signal: "SIGINT"},
+		Err:         context.DeadlineExceeded,
+	}
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Equal(t, "timeout (SIGINT): context deadline exceeded", err.Error())
+}
@@ -15,6 +15,7 @@
 package runtime
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -31,6 +32,15 @@ type ExecuteResultHook struct {
 	OnExecuteStderr   func(stderr string) //nolint:predeclared
 	OnExecuteError    func(err *execute.ErrorOutput)
 	OnExecuteComplete func(executionTime time.Duration)
+
+	// Structured output hooks, only fired for requests with an OutputFormat.
+	OnExecuteJSON      func(stream string, data json.RawMessage)
+	OnExecuteProgress  func(stream string, text string)
+	OnExecuteTruncated func(limit int64)
+
+	// OnExecuteTerminated fires before the final error or complete callback
+	// when execd stopped the run on timeout or interrupt.
+	OnExecuteTerminated func(termination Termination)
 }
 
 // ExecuteCodeRequest represents a code execution request with context and hooks.
@@ -43,7 +53,12 @@ type ExecuteCodeRequest struct {
 	Envs     map[string]string `json:"envs"`
 	Uid      *uint32           `json:"uid,omitempty"`
 	Gid      *uint32           `json:"gid,omitempty"`
-	Hooks    ExecuteResultHook
+	// OutputFormat opts foreground commands into structured output events.
+	OutputFormat *OutputFormat `json:"output_format,omitempty"`
+	// Termination controls how commands and bash session runs are stopped;
+	// nil keeps the default behaviour.
+	Termination *TerminationPolicy `json:"termination,omitempty"`
+	Hooks       ExecuteResultHook
 }
 
 // SetDefaultHooks installs stdout logging fallbacks for unset hooks.
@@ -68,6 +83,22 @@ func (req *ExecuteCodeRequest) SetDefaultHooks() {
 			fmt.Printf("OnExecuteComplete: %v\n", executionTime)
 		}
 	}
+	if req.Hooks.OnExecuteJSON == nil {
+		req.Hooks.OnExecuteJSON = func(stream string, data json.RawMessage) {
+			fmt.Printf("OnExecuteJSON: %s %s\n", stream, data)
+		}
+	}
+	if req.Hooks.OnExecuteProgress == nil {
+		req.Hooks.OnExecuteProgress = func(stream, text string) { fmt.Printf("OnExecuteProgress: %s %s\n", stream, text) }
+	}
+	if req.Hooks.OnExecuteTruncated == nil {
+		req.Hooks.OnExecuteTruncated = func(limit int64) { fmt.Printf("OnExecuteTruncated: %d\n", limit) }
+	}
+	if req.Hooks.OnExecuteTerminated == nil {
+		req.Hooks.OnExecuteTerminated = func(termination Termination) {
+			fmt.Printf("OnExecuteTerminated: %s %s\n", termination.Reason, termination.Signal)
+		}
+	}
 	if req.Hooks.OnExecuteInit == nil {
 		req.Hooks.OnExecuteInit = func(session string) { fmt.Printf("OnExecuteInit: %s\n", session) }
 	}
@@ -84,6 +115,16 @@ type CodeContext struct {
 	Language Language `json:"language"`
 }
 
+// BashSessionInfo is a point-in-time view of a bash session's shell state.
+type BashSessionInfo struct {
+	ID        string
+	Cwd       string
+	Env       map[string]string
+	Running   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 // bashSessionConfig holds bash session configuration.
 type bashSessionConfig struct {
 	// StartupSource is a list of scripts sourced on startup.
@@ -104,7 +145,15 @@ type bashSession struct {
 	env     map[string]string
 	cwd     string
 
+	createdAt time.Time
+	updatedAt time.Time
+
+	// store persists env/cwd after every run; nil when persistence is disabled.
+	store *bashSessionStore
+
 	// currentProcessPid is the pid of the active run's process group leader (bash).
-	// Set after cmd.Start(), cleared when run() returns. Used by close() to kill the process group.
+	// Set after cmd.Start(), cleared when run() returns.
 	currentProcessPid int
+	// currentTerminator stops the active run's process group; used by close().
+	currentTerminator *processTerminator
 }
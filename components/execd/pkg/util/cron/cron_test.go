@@ -0,0 +1,91 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse("2006-01-02 15:04", s)
+	require.NoError(t, err)
+	return v
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		expr, from, want string
+	}{
+		{"* * * * *", "2026-03-01 10:00", "2026-03-01 10:01"},
+		{"*/15 * * * *", "2026-03-01 10:07", "2026-03-01 10:15"},
+		{"5/20 * * * *", "2026-03-01 10:26", "2026-03-01 10:45"},
+		{"0 3 * * *", "2026-03-01 03:00", "2026-03-02 03:00"},
+		{"30 9-17/4 * * *", "2026-03-01 13:31", "2026-03-01 17:30"},
+		{"0 0 1 * *", "2026-01-31 12:00", "2026-02-01 00:00"},
+		{"0 12 * * mon-fri", "2026-03-06 12:00", "2026-03-09 12:00"},
+		{"0 0 * * 7", "2026-03-02 00:00", "2026-03-08 00:00"},
+		{"0 0 29 feb *", "2026-01-01 00:00", "2028-02-29 00:00"},
+		// With both day fields restricted, either may match.
+		{"0 0 13 * fri", "2026-03-01 00:00", "2026-03-06 00:00"},
+		{"@hourly", "2026-03-01 10:59", "2026-03-01 11:00"},
+		{"@weekly", "2026-03-01 00:00", "2026-03-08 00:00"},
+		{"@yearly", "2026-03-01 00:00", "2027-01-01 00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, mustTime(t, tt.want), s.Next(mustTime(t, tt.from)))
+		})
+	}
+}
+
+func TestNext_SkipsSeconds(t *testing.T) {
+	s, err := Parse("* * * * *")
+	require.NoError(t, err)
+	from := mustTime(t, "2026-03-01 10:00").Add(30 * time.Second)
+	require.Equal(t, mustTime(t, "2026-03-01 10:01"), s.Next(from))
+}
+
+func TestNext_NeverMatches(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, s.Next(mustTime(t, "2026-01-01 00:00")).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@every 5m",
+	} {
+		_, err := Parse(expr)
+		require.Error(t, err, "expected %q to be rejected", expr)
+	}
+}
@@ -55,8 +55,10 @@ type codeExecutionRunner interface {
 	DeleteContext(session string) error
 	CreateBashSession(req *runtime.CreateContextRequest) (string, error)
 	RunInBashSession(ctx context.Context, req *runtime.ExecuteCodeRequest) error
-	SeekBackgroundCommandOutput(session string, cursor int64) ([]byte, int64, error)
+	SeekCommandOutput(session string, stream string, cursor int64) ([]byte, int64, error)
 	DeleteBashSession(sessionID string) error
+	ListBashSessions() []runtime.BashSessionInfo
+	GetBashSessionInfo(sessionID string) (*runtime.BashSessionInfo, error)
 	Interrupt(sessionID string) error
 	CreatePTYSession(id, cwd, command string) (runtime.PTYSession, error)
 	GetPTYSession(id string) runtime.PTYSession
@@ -323,6 +325,60 @@ func (c *CodeInterpretingController) CreateSession() {
 	c.RespondSuccess(model.CreateSessionResponse{SessionID: sessionID})
 }
 
+// ListSessions returns all bash sessions without their environments (list_sessions API).
+func (c *CodeInterpretingController) ListSessions() {
+	sessions := codeRunner.ListBashSessions()
+	resp := make([]model.SessionInfo, 0, len(sessions))
+	for i := range sessions {
+		resp = append(resp, toSessionInfo(&sessions[i]))
+	}
+	c.RespondSuccess(resp)
+}
+
+// GetSession returns the captured env and cwd of a bash session (get_session API).
+func (c *CodeInterpretingController) GetSession() {
+	sessionID := c.ctx.Param("sessionId")
+	if sessionID == "" {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeMissingQuery,
+			"missing path parameter 'sessionId'",
+		)
+		return
+	}
+
+	info, err := codeRunner.GetBashSessionInfo(sessionID)
+	if err != nil {
+		if errors.Is(err, runtime.ErrContextNotFound) {
+			c.RespondError(
+				http.StatusNotFound,
+				model.ErrorCodeContextNotFound,
+				fmt.Sprintf("session %s not found", sessionID),
+			)
+			return
+		}
+		c.RespondError(
+			http.StatusInternalServerError,
+			model.ErrorCodeRuntimeError,
+			fmt.Sprintf("error getting session %s. %v", sessionID, err),
+		)
+		return
+	}
+
+	c.RespondSuccess(toSessionInfo(info))
+}
+
+func toSessionInfo(info *runtime.BashSessionInfo) model.SessionInfo {
+	return model.SessionInfo{
+		SessionID: info.ID,
+		Cwd:       info.Cwd,
+		Env:       info.Env,
+		Running:   info.Running,
+		CreatedAt: info.CreatedAt,
+		UpdatedAt: info.UpdatedAt,
+	}
+}
+
 // RunInSession runs a command in an existing bash session and streams output via SSE (run_in_session API).
 func (c *CodeInterpretingController) RunInSession() {
 	sessionID := c.ctx.Param("sessionId")
@@ -355,11 +411,12 @@ func (c *CodeInterpretingController) RunInSession() {
 
 	timeout := time.Duration(request.Timeout) * time.Millisecond
 	runReq := &runtime.ExecuteCodeRequest{
-		Language: runtime.Bash,
-		Context:  sessionID,
-		Code:     request.Command,
-		Cwd:      request.Cwd,
-		Timeout:  timeout,
+		Language:    runtime.Bash,
+		Context:     sessionID,
+		Code:        request.Command,
+		Cwd:         request.Cwd,
+		Timeout:     timeout,
+		Termination: request.Termination.ToRuntime(),
 	}
 	ctx, cancel := context.WithCancel(c.ctx.Request.Context())
 	defer cancel()
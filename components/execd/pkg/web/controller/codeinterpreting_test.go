@@ -34,6 +34,7 @@ import (
 type fakeCodeRunner struct {
 	execute          func(request *runtime.ExecuteCodeRequest) error
 	runInBashSession func(_ context.Context, _ *runtime.ExecuteCodeRequest) error
+	sessions         []runtime.BashSessionInfo
 }
 
 func (f *fakeCodeRunner) CreateContext(_ *runtime.CreateContextRequest) (string, error) {
@@ -78,7 +79,7 @@ func (f *fakeCodeRunner) RunInBashSession(ctx context.Context, req *runtime.Exec
 	return nil
 }
 
-func (f *fakeCodeRunner) SeekBackgroundCommandOutput(_ string, _ int64) ([]byte, int64, error) {
+func (f *fakeCodeRunner) SeekCommandOutput(_ string, _ string, _ int64) ([]byte, int64, error) {
 	return nil, 0, nil
 }
 
@@ -86,6 +87,19 @@ func (f *fakeCodeRunner) DeleteBashSession(_ string) error {
 	return nil
 }
 
+func (f *fakeCodeRunner) ListBashSessions() []runtime.BashSessionInfo {
+	return f.sessions
+}
+
+func (f *fakeCodeRunner) GetBashSessionInfo(sessionID string) (*runtime.BashSessionInfo, error) {
+	for i := range f.sessions {
+		if f.sessions[i].ID == sessionID {
+			return &f.sessions[i], nil
+		}
+	}
+	return nil, runtime.ErrContextNotFound
+}
+
 func (f *fakeCodeRunner) Interrupt(_ string) error {
 	return nil
 }
@@ -421,3 +435,48 @@ func TestRunCodeSuccessStillEmitsSSE(t *testing.T) {
 	require.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
 	require.NotEmpty(t, w.Body.Bytes(), "successful run should write SSE events")
 }
+
+func TestListSessions(t *testing.T) {
+	previousRunner := codeRunner
+	codeRunner = &fakeCodeRunner{
+		sessions: []runtime.BashSessionInfo{
+			{ID: "s-1", Cwd: "/workspace"},
+		},
+	}
+	t.Cleanup(func() { codeRunner = previousRunner })
+
+	ctx, w := newTestContext(http.MethodGet, "/session", nil)
+	NewCodeInterpretingController(ctx).ListSessions()
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp []model.SessionInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	require.Equal(t, "s-1", resp[0].SessionID)
+	require.Equal(t, "/workspace", resp[0].Cwd)
+}
+
+func TestGetSession(t *testing.T) {
+	previousRunner := codeRunner
+	codeRunner = &fakeCodeRunner{
+		sessions: []runtime.BashSessionInfo{
+			{ID: "s-1", Cwd: "/workspace", Env: map[string]string{"FOO": "bar"}},
+		},
+	}
+	t.Cleanup(func() { codeRunner = previousRunner })
+
+	ctx, w := newTestContext(http.MethodGet, "/session/s-1", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "sessionId", Value: "s-1"})
+	NewCodeInterpretingController(ctx).GetSession()
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp model.SessionInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "bar", resp.Env["FOO"])
+
+	ctx, w = newTestContext(http.MethodGet, "/session/missing", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "sessionId", Value: "missing"})
+	NewCodeInterpretingController(ctx).GetSession()
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -129,11 +129,13 @@ func (c *CodeInterpretingController) GetCommandStatus() {
 	if status.FinishedAt != nil {
 		resp.FinishedAt = status.FinishedAt
 	}
+	resp.Termination = model.NewTermination(status.Termination)
 
 	c.RespondSuccess(resp)
 }
 
-// GetBackgroundCommandOutput returns accumulated stdout/stderr for a command session as plain text.
+// GetBackgroundCommandOutput returns the raw log of a command session as plain text.
+// Foreground commands keep stdout and stderr apart; pick one with the `stream` query.
 func (c *CodeInterpretingController) GetBackgroundCommandOutput() {
 	id := c.ctx.Param("id")
 	if id == "" {
@@ -142,7 +144,7 @@ func (c *CodeInterpretingController) GetBackgroundCommandOutput() {
 	}
 
 	cursor := c.QueryInt64(c.ctx.Query("cursor"), 0)
-	output, lastCursor, err := codeRunner.SeekBackgroundCommandOutput(id, cursor)
+	output, lastCursor, err := codeRunner.SeekCommandOutput(id, c.ctx.Query("stream"), cursor)
 	if err != nil {
 		c.RespondError(http.StatusBadRequest, model.ErrorCodeInvalidRequest, err.Error())
 		return
@@ -157,23 +159,26 @@ func (c *CodeInterpretingController) buildExecuteCommandRequest(request model.Ru
 	timeout := time.Duration(request.TimeoutMs) * time.Millisecond
 	if request.Background {
 		return &runtime.ExecuteCodeRequest{
-			Language: runtime.BackgroundCommand,
-			Code:     request.Command,
-			Cwd:      request.Cwd,
-			Timeout:  timeout,
-			Gid:      request.Gid,
-			Uid:      request.Uid,
-			Envs:     request.Envs,
+			Language:    runtime.BackgroundCommand,
+			Code:        request.Command,
+			Cwd:         request.Cwd,
+			Timeout:     timeout,
+			Gid:         request.Gid,
+			Uid:         request.Uid,
+			Envs:        request.Envs,
+			Termination: request.Termination.ToRuntime(),
 		}
 	} else {
 		return &runtime.ExecuteCodeRequest{
-			Language: runtime.Command,
-			Code:     request.Command,
-			Cwd:      request.Cwd,
-			Timeout:  timeout,
-			Gid:      request.Gid,
-			Uid:      request.Uid,
-			Envs:     request.Envs,
+			Language:     runtime.Command,
+			Code:         request.Command,
+			Cwd:          request.Cwd,
+			Timeout:      timeout,
+			Gid:          request.Gid,
+			Uid:          request.Uid,
+			Envs:         request.Envs,
+			OutputFormat: request.OutputFormat.ToRuntime(),
+			Termination:  request.Termination.ToRuntime(),
 		}
 	}
 }
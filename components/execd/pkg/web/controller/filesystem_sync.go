@@ -0,0 +1,287 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/opensandbox/execd/pkg/util/pathutil"
+	"github.com/alibaba/opensandbox/execd/pkg/web/model"
+)
+
+// GetSyncManifest returns the manifest of the regular files under a directory,
+// letting clients pull only what changed.
+func (c *FilesystemController) GetSyncManifest() {
+	rec := beginFilesystemMetric("manifest")
+	defer rec.Finish(c.basicController)
+
+	root := c.ctx.Query("path")
+	if root == "" {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeMissingQuery,
+			"missing query parameter 'path'",
+		)
+		return
+	}
+	absRoot, err := pathutil.ExpandAbsPath(root)
+	if err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("invalid path %s. %v", root, err),
+		)
+		return
+	}
+
+	files, err := buildSyncManifest(absRoot)
+	if err != nil {
+		c.handleFileError(err)
+		return
+	}
+
+	rec.MarkSuccess()
+	c.RespondSuccess(model.SyncManifest{Root: absRoot, Files: files})
+}
+
+// SyncFiles compares a client manifest with a directory and reports the
+// files the client must upload. Files whose content already matches only
+// get their mode fixed, and with delete set, files missing from the
+// manifest are removed.
+func (c *FilesystemController) SyncFiles() {
+	rec := beginFilesystemMetric("sync")
+	defer rec.Finish(c.basicController)
+
+	var request model.SyncRequest
+	if err := c.bindJSON(&request); err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("error parsing request, MAYBE invalid body format. %v", err),
+		)
+		return
+	}
+	if err := request.Validate(); err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("invalid request, validation error %v", err),
+		)
+		return
+	}
+	absRoot, err := pathutil.ExpandAbsPath(request.Root)
+	if err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("invalid root %s. %v", request.Root, err),
+		)
+		return
+	}
+
+	resp, err := syncToManifest(absRoot, request.Files, request.Delete)
+	if err != nil {
+		c.RespondError(
+			http.StatusInternalServerError,
+			model.ErrorCodeRuntimeError,
+			fmt.Sprintf("error syncing %s. %v", absRoot, err),
+		)
+		return
+	}
+
+	rec.MarkSuccess()
+	c.RespondSuccess(resp)
+}
+
+// buildSyncManifest hashes every regular file under root. Symlinks and
+// other special files are not followed or listed.
+func buildSyncManifest(root string) ([]model.SyncFileEntry, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", root)
+	}
+
+	files := make([]model.SyncFileEntry, 0)
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, model.SyncFileEntry{
+			Path:   filepath.ToSlash(rel),
+			Size:   fi.Size(),
+			Mode:   syncMode(fi.Mode()),
+			SHA256: sum,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func syncToManifest(root string, files []model.SyncFileEntry, deleteExtra bool) (*model.SyncResponse, error) {
+	resp := &model.SyncResponse{Upload: make([]string, 0)}
+	wanted := make(map[string]struct{}, len(files))
+
+	for _, f := range files {
+		wanted[f.Path] = struct{}{}
+		local := filepath.Join(root, filepath.FromSlash(f.Path))
+
+		info, err := lstatInRoot(root, f.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err != nil || !info.Mode().IsRegular() || info.Size() != f.Size {
+			resp.Upload = append(resp.Upload, f.Path)
+			continue
+		}
+		sum, err := fileSHA256(local)
+		if err != nil {
+			return nil, err
+		}
+		if sum != f.SHA256 {
+			resp.Upload = append(resp.Upload, f.Path)
+			continue
+		}
+		if f.Mode != 0 && syncMode(info.Mode()) != f.Mode {
+			if err := ChmodFile(local, model.Permission{Mode: f.Mode}); err != nil {
+				return nil, fmt.Errorf("chmod %s: %w", f.Path, err)
+			}
+			resp.Chmod = append(resp.Chmod, f.Path)
+		}
+	}
+
+	if deleteExtra {
+		deleted, err := removeUnlisted(root, wanted)
+		if err != nil {
+			return nil, err
+		}
+		resp.Deleted = deleted
+	}
+
+	sort.Strings(resp.Upload)
+	sort.Strings(resp.Chmod)
+	return resp, nil
+}
+
+// lstatInRoot stats the manifest path rel under root without following
+// symlinks in any of its components, so a symlinked directory inside root
+// cannot point the sync at files outside it.
+func lstatInRoot(root, rel string) (fs.FileInfo, error) {
+	p := root
+	parts := strings.Split(rel, "/")
+	for i, part := range parts {
+		p = filepath.Join(p, part)
+		info, err := os.Lstat(p)
+		if err != nil {
+			return nil, err
+		}
+		if i == len(parts)-1 {
+			return info, nil
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return nil, fmt.Errorf("path %s goes through symlink %s", rel, path.Join(parts[:i+1]...))
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("path %s goes through non-directory %s", rel, path.Join(parts[:i+1]...))
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// removeUnlisted removes the files and symlinks under root that are not in
+// wanted. Directories are left in place.
+func removeUnlisted(root string, wanted map[string]struct{}) ([]string, error) {
+	var deleted []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if p == root && errors.Is(walkErr, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := wanted[rel]; ok {
+			return nil
+		}
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+		deleted = append(deleted, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// syncMode renders permission bits as octal digits, matching model.Permission.
+func syncMode(mode fs.FileMode) int {
+	v, _ := strconv.Atoi(strconv.FormatUint(uint64(mode.Perm()), 8))
+	return v
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/opensandbox/execd/pkg/web/model"
+)
+
+func syncEntry(path, content string, mode int) model.SyncFileEntry {
+	sum := sha256.Sum256([]byte(content))
+	return model.SyncFileEntry{
+		Path:   path,
+		Size:   int64(len(content)),
+		Mode:   mode,
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
+
+func writeSyncFile(t *testing.T, root, rel, content string, mode os.FileMode) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), mode))
+	require.NoError(t, os.Chmod(p, mode))
+}
+
+func TestFilesystemControllerGetSyncManifest(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, root, "a.txt", "alpha", 0o644)
+	writeSyncFile(t, root, "bin/run.sh", "#!/bin/sh", 0o755)
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(root, "link")))
+
+	ctrl, rec := newFilesystemController(t, http.MethodGet, "/files/manifest?path="+url.QueryEscape(root), nil)
+	ctrl.GetSyncManifest()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp model.SyncManifest
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, root, resp.Root)
+	require.Equal(t, []model.SyncFileEntry{
+		syncEntry("a.txt", "alpha", 644),
+		syncEntry("bin/run.sh", "#!/bin/sh", 755),
+	}, resp.Files)
+}
+
+func TestFilesystemControllerGetSyncManifestMissingRoot(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	ctrl, rec := newFilesystemController(t, http.MethodGet, "/files/manifest?path="+url.QueryEscape(missing), nil)
+	ctrl.GetSyncManifest()
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFilesystemControllerSyncFiles(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, root, "same.txt", "same", 0o644)
+	writeSyncFile(t, root, "changed.txt", "old", 0o644)
+	writeSyncFile(t, root, "mode.sh", "echo", 0o644)
+	writeSyncFile(t, root, "stale/extra.txt", "extra", 0o644)
+
+	body, err := json.Marshal(model.SyncRequest{
+		Root: root,
+		Files: []model.SyncFileEntry{
+			syncEntry("same.txt", "same", 644),
+			syncEntry("changed.txt", "new", 644),
+			syncEntry("mode.sh", "echo", 755),
+			syncEntry("dir/new.txt", "new", 644),
+		},
+		Delete: true,
+	})
+	require.NoError(t, err)
+
+	ctrl, rec := newFilesystemController(t, http.MethodPost, "/files/sync", body)
+	ctrl.SyncFiles()
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp model.SyncResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, []string{"changed.txt", "dir/new.txt"}, resp.Upload)
+	require.Equal(t, []string{"mode.sh"}, resp.Chmod)
+	require.Equal(t, []string{"stale/extra.txt"}, resp.Deleted)
+
+	info, err := os.Stat(filepath.Join(root, "mode.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	require.NoFileExists(t, filepath.Join(root, "stale/extra.txt"))
+	require.FileExists(t, filepath.Join(root, "same.txt"))
+}
+
+func TestFilesystemControllerSyncFilesKeepsExtraWithoutDelete(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, root, "extra.txt", "extra", 0o644)
+
+	body, err := json.Marshal(model.SyncRequest{Root: root, Files: []model.SyncFileEntry{syncEntry("a.txt", "a", 0)}})
+	require.NoError(t, err)
+
+	ctrl, rec := newFilesystemController(t, http.MethodPost, "/files/sync", body)
+	ctrl.SyncFiles()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp model.SyncResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, []string{"a.txt"}, resp.Upload)
+	require.Empty(t, resp.Deleted)
+	require.FileExists(t, filepath.Join(root, "extra.txt"))
+}
+
+func TestFilesystemControllerSyncFilesRejectsEscapingPaths(t *testing.T) {
+	root := t.TempDir()
+	for _, p := range []string{"../etc/passwd", "/etc/passwd", "a/../../b", "./a", "a//b"} {
+		body, err := json.Marshal(model.SyncRequest{Root: root, Files: []model.SyncFileEntry{syncEntry(p, "x", 0)}})
+		require.NoError(t, err)
+
+		ctrl, rec := newFilesystemController(t, http.MethodPost, "/files/sync", body)
+		ctrl.SyncFiles()
+
+		require.Equal(t, http.StatusBadRequest, rec.Code, p)
+	}
+}
+
+func TestFilesystemControllerSyncFilesRejectsEmptyManifestWithDelete(t *testing.T) {
+	root := t.TempDir()
+	writeSyncFile(t, root, "keep.txt", "keep", 0o644)
+
+	body, err := json.Marshal(model.SyncRequest{Root: root, Files: []model.SyncFileEntry{}, Delete: true})
+	require.NoError(t, err)
+
+	ctrl, rec := newFilesystemController(t, http.MethodPost, "/files/sync", body)
+	ctrl.SyncFiles()
+
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	require.FileExists(t, filepath.Join(root, "keep.txt"))
+}
+
+func TestFilesystemControllerSyncFilesDoesNotFollowSymlinkedDirs(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeSyncFile(t, outside, "x.sh", "echo", 0o644)
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "link")))
+
+	body, err := json.Marshal(model.SyncRequest{Root: root, Files: []model.SyncFileEntry{syncEntry("link/x.sh", "echo", 755)}})
+	require.NoError(t, err)
+
+	ctrl, rec := newFilesystemController(t, http.MethodPost, "/files/sync", body)
+	ctrl.SyncFiles()
+
+	require.NotEqual(t, http.StatusOK, rec.Code, rec.Body.String())
+	info, err := os.Stat(filepath.Join(outside, "x.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+}
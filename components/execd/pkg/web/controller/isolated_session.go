@@ -178,7 +178,7 @@ func (c *IsolatedSessionController) Run() {
 	}
 
 	startTime := time.Now()
-	err := isolatedRunner.RunInIsolatedSession(ctx, sessionID, req.Code, req.Envs, onStdout)
+	err := isolatedRunner.RunInIsolatedSessionWithTermination(ctx, sessionID, req.Code, req.Envs, onStdout, req.Termination.ToRuntime())
 	durationMs := float64(time.Since(startTime)) / float64(time.Millisecond)
 
 	if err != nil {
@@ -202,6 +202,10 @@ func (c *IsolatedSessionController) Run() {
 				EValue: evalue,
 			},
 		}
+		var terminated *runtime.TerminationError
+		if errors.As(err, &terminated) {
+			event.Termination = model.NewTermination(&terminated.Termination)
+		}
 		c.writeSingleEvent("IsolatedError", event.ToJSON(), true, event.Summary())
 		return
 	}
@@ -0,0 +1,168 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alibaba/opensandbox/execd/pkg/runtime"
+	"github.com/alibaba/opensandbox/execd/pkg/web/model"
+)
+
+// scheduler is set by InitScheduler during startup.
+var scheduler commandScheduler
+
+// commandScheduler is the subset of runtime.Scheduler used by the handlers.
+type commandScheduler interface {
+	Create(spec runtime.ScheduleSpec) (*runtime.ScheduleInfo, error)
+	List() []runtime.ScheduleInfo
+	Get(id string) (*runtime.ScheduleInfo, error)
+	Pause(id string) (*runtime.ScheduleInfo, error)
+	Resume(id string) (*runtime.ScheduleInfo, error)
+	Delete(id string) error
+}
+
+// InitScheduler wires the command scheduler.
+func InitScheduler(s *runtime.Scheduler) {
+	scheduler = s
+}
+
+// ScheduleController handles /schedules endpoints.
+type ScheduleController struct {
+	*basicController
+}
+
+// NewScheduleController creates a controller bound to ctx.
+func NewScheduleController(ctx *gin.Context) *ScheduleController {
+	return &ScheduleController{
+		basicController: newBasicController(ctx),
+	}
+}
+
+// CreateSchedule handles POST /schedules.
+func (c *ScheduleController) CreateSchedule() {
+	var request model.CreateScheduleRequest
+	if err := c.bindJSON(&request); err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("error parsing request, MAYBE invalid body format. %v", err),
+		)
+		return
+	}
+	if err := request.Validate(); err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("invalid request, validation error %v", err),
+		)
+		return
+	}
+
+	info, err := scheduler.Create(request.ToRuntime())
+	if err != nil {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeInvalidRequest,
+			fmt.Sprintf("error creating schedule. %v", err),
+		)
+		return
+	}
+	c.RespondSuccess(model.NewScheduleResponse(info))
+}
+
+// ListSchedules handles GET /schedules. Run history is omitted.
+func (c *ScheduleController) ListSchedules() {
+	infos := scheduler.List()
+	resp := make([]model.ScheduleResponse, 0, len(infos))
+	for i := range infos {
+		resp = append(resp, model.NewScheduleResponse(&infos[i]))
+	}
+	c.RespondSuccess(resp)
+}
+
+// GetSchedule handles GET /schedules/:id.
+func (c *ScheduleController) GetSchedule() {
+	c.respondSchedule(scheduler.Get)
+}
+
+// PauseSchedule handles POST /schedules/:id/pause.
+func (c *ScheduleController) PauseSchedule() {
+	c.respondSchedule(scheduler.Pause)
+}
+
+// ResumeSchedule handles POST /schedules/:id/resume.
+func (c *ScheduleController) ResumeSchedule() {
+	c.respondSchedule(scheduler.Resume)
+}
+
+// DeleteSchedule handles DELETE /schedules/:id. An active run is not interrupted.
+func (c *ScheduleController) DeleteSchedule() {
+	id, ok := c.scheduleID()
+	if !ok {
+		return
+	}
+	if err := scheduler.Delete(id); err != nil {
+		c.respondScheduleError(id, err)
+		return
+	}
+	c.RespondSuccess(nil)
+}
+
+func (c *ScheduleController) respondSchedule(op func(id string) (*runtime.ScheduleInfo, error)) {
+	id, ok := c.scheduleID()
+	if !ok {
+		return
+	}
+	info, err := op(id)
+	if err != nil {
+		c.respondScheduleError(id, err)
+		return
+	}
+	c.RespondSuccess(model.NewScheduleResponse(info))
+}
+
+func (c *ScheduleController) scheduleID() (string, bool) {
+	id := c.ctx.Param("id")
+	if id == "" {
+		c.RespondError(
+			http.StatusBadRequest,
+			model.ErrorCodeMissingQuery,
+			"missing path parameter 'id'",
+		)
+		return "", false
+	}
+	return id, true
+}
+
+func (c *ScheduleController) respondScheduleError(id string, err error) {
+	if errors.Is(err, runtime.ErrScheduleNotFound) {
+		c.RespondError(
+			http.StatusNotFound,
+			model.ErrorCodeScheduleNotFound,
+			fmt.Sprintf("schedule %s not found", id),
+		)
+		return
+	}
+	c.RespondError(
+		http.StatusInternalServerError,
+		model.ErrorCodeRuntimeError,
+		fmt.Sprintf("error handling schedule %s. %v", id, err),
+	)
+}
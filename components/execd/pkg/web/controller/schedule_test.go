@@ -0,0 +1,101 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/opensandbox/execd/pkg/runtime"
+	"github.com/alibaba/opensandbox/execd/pkg/web/model"
+)
+
+func useTestScheduler(t *testing.T) {
+	t.Helper()
+	previous := scheduler
+	scheduler = runtime.NewScheduler(runtime.NewController("", ""))
+	t.Cleanup(func() { scheduler = previous })
+}
+
+func TestCreateSchedule_RejectsInvalidTiming(t *testing.T) {
+	useTestScheduler(t)
+
+	for _, body := range []string{
+		`{"command":"true"}`,
+		`{"command":"true","cron":"@daily","interval":60000}`,
+		`{"command":"true","cron":"61 * * * *"}`,
+		`{"command":"true","interval":10}`,
+		`{"command":"true","cron":"@daily","concurrency_policy":"parallel"}`,
+	} {
+		ctx, w := newTestContext(http.MethodPost, "/schedules", []byte(body))
+		NewScheduleController(ctx).CreateSchedule()
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestScheduleLifecycle(t *testing.T) {
+	useTestScheduler(t)
+
+	ctx, w := newTestContext(http.MethodPost, "/schedules", []byte(`{"name":"nightly","command":"true","cron":"0 3 * * *"}`))
+	NewScheduleController(ctx).CreateSchedule()
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var created model.ScheduleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, "nightly", created.Name)
+	require.Equal(t, "skip", created.ConcurrencyPolicy)
+
+	ctx, w = newTestContext(http.MethodPost, "/schedules/"+created.ID+"/pause", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: created.ID})
+	NewScheduleController(ctx).PauseSchedule()
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var paused model.ScheduleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paused))
+	require.True(t, paused.Paused)
+
+	ctx, w = newTestContext(http.MethodGet, "/schedules", nil)
+	NewScheduleController(ctx).ListSchedules()
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list []model.ScheduleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+
+	ctx, w = newTestContext(http.MethodDelete, "/schedules/"+created.ID, nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: created.ID})
+	NewScheduleController(ctx).DeleteSchedule()
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetSchedule_NotFoundReturns404(t *testing.T) {
+	useTestScheduler(t)
+
+	ctx, w := newTestContext(http.MethodGet, "/schedules/missing", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "missing"})
+	NewScheduleController(ctx).GetSchedule()
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp model.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, model.ErrorCodeScheduleNotFound, resp.Code)
+	require.Equal(t, "schedule missing not found", resp.Message)
+}
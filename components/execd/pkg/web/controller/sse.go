@@ -16,8 +16,11 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/alibaba/opensandbox/internal/safego"
@@ -55,6 +58,10 @@ func (c *basicController) setupSSEResponse() {
 
 // setServerEventsHandler adapts runtime callbacks to SSE events.
 func (c *CodeInterpretingController) setServerEventsHandler(ctx context.Context) runtime.ExecuteResultHook {
+	// The runtime reports a termination right before the final error or
+	// complete callback; it is attached to that event.
+	var termination atomic.Pointer[model.Termination]
+
 	return runtime.ExecuteResultHook{
 		OnExecuteInit: func(session string) {
 			event := model.ServerStreamEvent{
@@ -105,6 +112,7 @@ func (c *CodeInterpretingController) setServerEventsHandler(ctx context.Context)
 				Type:          model.StreamEventTypeComplete,
 				ExecutionTime: executionTime.Milliseconds(),
 				Timestamp:     time.Now().UnixMilli(),
+				Termination:   termination.Load(),
 			}
 			payload := event.ToJSON()
 			c.writeSingleEvent("OnExecuteComplete", payload, true, event.Summary())
@@ -115,9 +123,10 @@ func (c *CodeInterpretingController) setServerEventsHandler(ctx context.Context)
 			}
 
 			event := model.ServerStreamEvent{
-				Type:      model.StreamEventTypeError,
-				Error:     err,
-				Timestamp: time.Now().UnixMilli(),
+				Type:        model.StreamEventTypeError,
+				Error:       err,
+				Timestamp:   time.Now().UnixMilli(),
+				Termination: termination.Load(),
 			}
 			payload := event.ToJSON()
 			c.writeSingleEvent("OnExecuteError", payload, true, event.Summary())
@@ -157,6 +166,38 @@ func (c *CodeInterpretingController) setServerEventsHandler(ctx context.Context)
 			payload := event.ToJSON()
 			c.writeSingleEvent("OnExecuteStderr", payload, true, event.Summary())
 		},
+		OnExecuteJSON: func(stream string, data json.RawMessage) {
+			event := model.ServerStreamEvent{
+				Type:      model.StreamEventTypeJSON,
+				Stream:    stream,
+				Data:      data,
+				Timestamp: time.Now().UnixMilli(),
+			}
+			payload := event.ToJSON()
+			c.writeSingleEvent("OnExecuteJSON", payload, true, event.Summary())
+		},
+		OnExecuteProgress: func(stream string, text string) {
+			event := model.ServerStreamEvent{
+				Type:      model.StreamEventTypeProgress,
+				Stream:    stream,
+				Text:      text,
+				Timestamp: time.Now().UnixMilli(),
+			}
+			payload := event.ToJSON()
+			c.writeSingleEvent("OnExecuteProgress", payload, true, event.Summary())
+		},
+		OnExecuteTruncated: func(limit int64) {
+			event := model.ServerStreamEvent{
+				Type:      model.StreamEventTypeTruncated,
+				Text:      fmt.Sprintf("output truncated after %d bytes; full log is available from the command logs API", limit),
+				Timestamp: time.Now().UnixMilli(),
+			}
+			payload := event.ToJSON()
+			c.writeSingleEvent("OnExecuteTruncated", payload, true, event.Summary())
+		},
+		OnExecuteTerminated: func(t runtime.Termination) {
+			termination.Store(model.NewTermination(&t))
+		},
 	}
 }
 
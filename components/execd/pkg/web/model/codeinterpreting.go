@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 
@@ -59,6 +60,36 @@ type RunCommandRequest struct {
 	Uid  *uint32           `json:"uid,omitempty"`
 	Gid  *uint32           `json:"gid,omitempty"`
 	Envs map[string]string `json:"envs,omitempty"`
+
+	// OutputFormat opts a foreground command into structured stream events.
+	OutputFormat *CommandOutputFormat `json:"output_format,omitempty"`
+	// Termination controls how the command is stopped on timeout or interrupt.
+	Termination *TerminationPolicy `json:"termination,omitempty"`
+}
+
+// CommandOutputFormat controls structured output events for a foreground command.
+type CommandOutputFormat struct {
+	// JSONLines emits `json` events for lines that hold a JSON object or array.
+	JSONLines bool `json:"json_lines,omitempty"`
+	// Progress collapses carriage-return redraws into throttled `progress` events.
+	Progress bool `json:"progress,omitempty"`
+	// ProgressIntervalMs is the minimum spacing of progress events per stream; 0 uses the server default.
+	ProgressIntervalMs int64 `json:"progress_interval,omitempty" validate:"omitempty,gte=0"`
+	// MaxBytes caps the total streamed output; 0 means unlimited.
+	MaxBytes int64 `json:"max_bytes,omitempty" validate:"omitempty,gte=0"`
+}
+
+// ToRuntime converts the API format to its runtime form.
+func (f *CommandOutputFormat) ToRuntime() *runtime.OutputFormat {
+	if f == nil {
+		return nil
+	}
+	return &runtime.OutputFormat{
+		JSONLines:        f.JSONLines,
+		Progress:         f.Progress,
+		ProgressInterval: time.Duration(f.ProgressIntervalMs) * time.Millisecond,
+		MaxBytes:         f.MaxBytes,
+	}
 }
 
 func (r *RunCommandRequest) Validate() error {
@@ -69,6 +100,9 @@ func (r *RunCommandRequest) Validate() error {
 	if r.Gid != nil && r.Uid == nil {
 		return errors.New("uid is required when gid is provided")
 	}
+	if r.OutputFormat != nil && r.Background {
+		return errors.New("output_format is not supported for background commands")
+	}
 	return runtime.ValidateWorkingDir(r.Cwd)
 }
 
@@ -84,6 +118,11 @@ const (
 	StreamEventTypeComplete ServerStreamEventType = "execution_complete"
 	StreamEventTypeCount    ServerStreamEventType = "execution_count"
 	StreamEventTypePing     ServerStreamEventType = "ping"
+
+	// Structured output events, emitted only for commands with an output_format.
+	StreamEventTypeJSON      ServerStreamEventType = "json"
+	StreamEventTypeProgress  ServerStreamEventType = "progress"
+	StreamEventTypeTruncated ServerStreamEventType = "truncated"
 )
 
 // ServerStreamEvent is emitted to clients over SSE.
@@ -95,6 +134,13 @@ type ServerStreamEvent struct {
 	Timestamp      int64                 `json:"timestamp,omitempty"`
 	Results        map[string]any        `json:"results,omitempty"`
 	Error          *execute.ErrorOutput  `json:"error,omitempty"`
+	// Stream is stdout or stderr for json and progress events.
+	Stream string `json:"stream,omitempty"`
+	// Data is the parsed line of a json event.
+	Data json.RawMessage `json:"data,omitempty"`
+	// Termination is set on the final error or execution_complete event
+	// when execd stopped the run.
+	Termination *Termination `json:"termination,omitempty"`
 }
 
 // ToJSON serializes the event for streaming.
@@ -106,12 +152,18 @@ func (s ServerStreamEvent) ToJSON() []byte {
 // Summary renders a lightweight, log-friendly string without JSON.
 func (s ServerStreamEvent) Summary() string {
 	parts := []string{fmt.Sprintf("type=%s", s.Type)}
+	if s.Stream != "" {
+		parts = append(parts, fmt.Sprintf("stream=%s", s.Stream))
+	}
 	if s.Text != "" {
 		parts = append(parts, fmt.Sprintf("text=%s", truncateString(s.Text, 100)))
 	}
 	if s.ExecutionTime > 0 {
 		parts = append(parts, fmt.Sprintf("elapsed_ms=%d", s.ExecutionTime))
 	}
+	if len(s.Data) > 0 {
+		parts = append(parts, fmt.Sprintf("data=%s", truncateString(string(s.Data), 100)))
+	}
 	if len(s.Results) > 0 {
 		parts = append(parts, fmt.Sprintf("results=%d", len(s.Results)))
 	}
@@ -122,6 +174,9 @@ func (s ServerStreamEvent) Summary() string {
 		}
 		parts = append(parts, fmt.Sprintf("error=%s: %s", errLabel, truncateString(s.Error.EValue, 80)))
 	}
+	if s.Termination != nil {
+		parts = append(parts, fmt.Sprintf("termination=%s/%s", s.Termination.Reason, s.Termination.Signal))
+	}
 	return strings.Join(parts, " ")
 }
 
@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alibaba/opensandbox/execd/pkg/jupyter/execute"
 	"github.com/stretchr/testify/require"
@@ -132,3 +133,37 @@ func TestServerStreamEventSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestRunCommandRequestValidateOutputFormat(t *testing.T) {
+	req := RunCommandRequest{
+		Command:      "ls",
+		OutputFormat: &CommandOutputFormat{JSONLines: true, MaxBytes: 1024},
+	}
+	require.NoError(t, req.Validate())
+
+	req.OutputFormat.MaxBytes = -1
+	require.Error(t, req.Validate(), "expected validation error when max_bytes is negative")
+
+	req.OutputFormat.MaxBytes = 0
+	req.Background = true
+	require.Error(t, req.Validate(), "output_format is only supported for foreground commands")
+}
+
+func TestRunCommandRequestValidateTermination(t *testing.T) {
+	req := RunCommandRequest{
+		Command:     "make test",
+		Termination: &TerminationPolicy{Signal: "SIGINT", GracePeriodMs: 5000},
+	}
+	require.NoError(t, req.Validate())
+
+	policy := req.Termination.ToRuntime()
+	require.Equal(t, "SIGINT", policy.Signal)
+	require.Equal(t, 5*time.Second, policy.GracePeriod)
+
+	req.Termination.Signal = "SIGSTOP"
+	require.Error(t, req.Validate(), "expected validation error for unsupported signal")
+
+	req.Termination.Signal = ""
+	req.Termination.GracePeriodMs = -1
+	require.Error(t, req.Validate(), "expected validation error when grace_period is negative")
+}
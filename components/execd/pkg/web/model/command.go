@@ -14,15 +14,56 @@
 
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/alibaba/opensandbox/execd/pkg/runtime"
+)
 
 // CommandStatusResponse represents command status for REST APIs.
 type CommandStatusResponse struct {
-	ID         string     `json:"id"`
-	Content    string     `json:"content,omitempty"`
-	Running    bool       `json:"running"`
-	ExitCode   *int       `json:"exit_code,omitempty"`
-	Error      string     `json:"error,omitempty"`
-	StartedAt  time.Time  `json:"started_at,omitempty"`
-	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ID          string       `json:"id"`
+	Content     string       `json:"content,omitempty"`
+	Running     bool         `json:"running"`
+	ExitCode    *int         `json:"exit_code,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	StartedAt   time.Time    `json:"started_at,omitempty"`
+	FinishedAt  *time.Time   `json:"finished_at,omitempty"`
+	Termination *Termination `json:"termination,omitempty"`
+}
+
+// TerminationPolicy controls how a run is stopped on timeout or interrupt:
+// Signal goes to the process group first and SIGKILL follows after the grace period.
+type TerminationPolicy struct {
+	// Signal is sent first; defaults to SIGTERM (SIGINT for isolated sessions).
+	Signal string `json:"signal,omitempty" validate:"omitempty,oneof=SIGTERM SIGINT SIGHUP SIGQUIT SIGKILL"`
+	// GracePeriodMs is the time allowed between Signal and SIGKILL; 0 uses the server default.
+	GracePeriodMs int64 `json:"grace_period,omitempty" validate:"omitempty,gte=0"`
+}
+
+// ToRuntime converts the API policy to its runtime form.
+func (p *TerminationPolicy) ToRuntime() *runtime.TerminationPolicy {
+	if p == nil {
+		return nil
+	}
+	return &runtime.TerminationPolicy{
+		Signal:      p.Signal,
+		GracePeriod: time.Duration(p.GracePeriodMs) * time.Millisecond,
+	}
+}
+
+// Termination reports how execd stopped a run.
+type Termination struct {
+	// Reason is timeout or interrupted; it is kept when the run is escalated to SIGKILL.
+	Reason string `json:"reason"`
+	// Signal is the last signal execd sent.
+	Signal string `json:"signal"`
+}
+
+// NewTermination converts a runtime termination; nil stays nil.
+func NewTermination(t *runtime.Termination) *Termination {
+	if t == nil {
+		return nil
+	}
+	return &Termination{Reason: string(t.Reason), Signal: t.Signal}
 }
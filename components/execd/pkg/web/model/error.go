@@ -29,6 +29,7 @@ const (
 	ErrorCodeNotSupported        ErrorCode = "NOT_SUPPORTED"
 	ErrorCodeServiceUnavailable  ErrorCode = "SERVICE_UNAVAILABLE"
 	ErrorCodeSessionNotFound     ErrorCode = "SESSION_NOT_FOUND"
+	ErrorCodeScheduleNotFound    ErrorCode = "SCHEDULE_NOT_FOUND"
 )
 
 type ErrorResponse struct {
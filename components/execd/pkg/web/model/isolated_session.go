@@ -92,6 +92,8 @@ type IsolatedRunRequest struct {
 	Code           string            `json:"code" validate:"required"`
 	Envs           map[string]string `json:"envs,omitempty"`
 	TimeoutSeconds int               `json:"timeout_seconds,omitempty" validate:"omitempty,gte=0"`
+	// Termination escalates a timed-out run to SIGKILL, which ends the session.
+	Termination *TerminationPolicy `json:"termination,omitempty"`
 }
 
 // Validate checks IsolatedRunRequest fields.
@@ -0,0 +1,145 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/alibaba/opensandbox/execd/pkg/runtime"
+)
+
+// CreateScheduleRequest registers a recurring background command.
+// Exactly one of Cron and IntervalMs must be set.
+type CreateScheduleRequest struct {
+	Name    string `json:"name,omitempty"`
+	Command string `json:"command" validate:"required"`
+	// Cron is a five-field cron expression (or @hourly, @daily, ...) in the sandbox's local time.
+	Cron string `json:"cron,omitempty"`
+	// IntervalMs runs the command at a fixed period, first firing one period after creation.
+	IntervalMs int64 `json:"interval,omitempty" validate:"omitempty,gte=1000"`
+	// ConcurrencyPolicy decides what happens when a run is due while the previous one is active.
+	ConcurrencyPolicy string `json:"concurrency_policy,omitempty" validate:"omitempty,oneof=skip queue replace"`
+	// TimeoutMs caps every run; 0 means no limit.
+	TimeoutMs int64 `json:"timeout,omitempty" validate:"omitempty,gte=1"`
+	// Termination controls how a run is stopped on timeout or replacement.
+	Termination *TerminationPolicy `json:"termination,omitempty"`
+
+	Cwd  string            `json:"cwd,omitempty"`
+	Uid  *uint32           `json:"uid,omitempty"`
+	Gid  *uint32           `json:"gid,omitempty"`
+	Envs map[string]string `json:"envs,omitempty"`
+
+	// HistoryLimit is the number of recent runs kept; 0 uses the server default.
+	HistoryLimit int `json:"history_limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+}
+
+func (r *CreateScheduleRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Gid != nil && r.Uid == nil {
+		return errors.New("uid is required when gid is provided")
+	}
+	if err := runtime.ValidateScheduleSpec(r.ToRuntime()); err != nil {
+		return err
+	}
+	return runtime.ValidateWorkingDir(r.Cwd)
+}
+
+// ToRuntime converts the request to a runtime schedule spec.
+func (r *CreateScheduleRequest) ToRuntime() runtime.ScheduleSpec {
+	return runtime.ScheduleSpec{
+		Name:         r.Name,
+		Command:      r.Command,
+		Cron:         r.Cron,
+		Interval:     time.Duration(r.IntervalMs) * time.Millisecond,
+		Concurrency:  runtime.ConcurrencyPolicy(r.ConcurrencyPolicy),
+		Timeout:      time.Duration(r.TimeoutMs) * time.Millisecond,
+		Termination:  r.Termination.ToRuntime(),
+		Cwd:          r.Cwd,
+		Envs:         r.Envs,
+		Uid:          r.Uid,
+		Gid:          r.Gid,
+		HistoryLimit: r.HistoryLimit,
+	}
+}
+
+// ScheduleResponse describes a schedule and, for single-schedule lookups,
+// its recent runs.
+type ScheduleResponse struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name,omitempty"`
+	Command           string            `json:"command"`
+	Cron              string            `json:"cron,omitempty"`
+	IntervalMs        int64             `json:"interval,omitempty"`
+	ConcurrencyPolicy string            `json:"concurrency_policy"`
+	TimeoutMs         int64             `json:"timeout,omitempty"`
+	Cwd               string            `json:"cwd,omitempty"`
+	HistoryLimit      int               `json:"history_limit"`
+	Paused            bool              `json:"paused"`
+	CreatedAt         time.Time         `json:"created_at"`
+	NextRunAt         *time.Time        `json:"next_run_at,omitempty"`
+	Queued            int               `json:"queued,omitempty"`
+	Runs              []ScheduleRunInfo `json:"runs,omitempty"`
+}
+
+// ScheduleRunInfo describes one run. CommandID is the id to pass to the
+// command status and logs endpoints.
+type ScheduleRunInfo struct {
+	CommandID   string       `json:"command_id,omitempty"`
+	Status      string       `json:"status"`
+	ScheduledAt time.Time    `json:"scheduled_at"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"`
+	FinishedAt  *time.Time   `json:"finished_at,omitempty"`
+	ExitCode    *int         `json:"exit_code,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Termination *Termination `json:"termination,omitempty"`
+}
+
+// NewScheduleResponse converts a runtime schedule view.
+func NewScheduleResponse(info *runtime.ScheduleInfo) ScheduleResponse {
+	resp := ScheduleResponse{
+		ID:                info.ID,
+		Name:              info.Spec.Name,
+		Command:           info.Spec.Command,
+		Cron:              info.Spec.Cron,
+		IntervalMs:        info.Spec.Interval.Milliseconds(),
+		ConcurrencyPolicy: string(info.Spec.Concurrency),
+		TimeoutMs:         info.Spec.Timeout.Milliseconds(),
+		Cwd:               info.Spec.Cwd,
+		HistoryLimit:      info.Spec.HistoryLimit,
+		Paused:            info.Paused,
+		CreatedAt:         info.CreatedAt,
+		NextRunAt:         info.NextRunAt,
+		Queued:            info.Queued,
+	}
+	for _, run := range info.Runs {
+		resp.Runs = append(resp.Runs, ScheduleRunInfo{
+			CommandID:   run.CommandID,
+			Status:      string(run.Status),
+			ScheduledAt: run.ScheduledAt,
+			StartedAt:   run.StartedAt,
+			FinishedAt:  run.FinishedAt,
+			ExitCode:    run.ExitCode,
+			Error:       run.Error,
+			Termination: NewTermination(run.Termination),
+		})
+	}
+	return resp
+}
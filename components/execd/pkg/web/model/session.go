@@ -15,6 +15,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 
 	"github.com/alibaba/opensandbox/execd/pkg/runtime"
@@ -30,11 +32,24 @@ type CreateSessionResponse struct {
 	SessionID string `json:"session_id"`
 }
 
+// SessionInfo describes a bash session for list_sessions and get_session.
+// Env is only populated by get_session.
+type SessionInfo struct {
+	SessionID string            `json:"session_id"`
+	Cwd       string            `json:"cwd"`
+	Env       map[string]string `json:"env,omitempty"`
+	Running   bool              `json:"running"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
 // RunInSessionRequest is the request body for running a command in an existing session.
 type RunInSessionRequest struct {
 	Command string `json:"command" validate:"required"`
 	Cwd     string `json:"cwd,omitempty"`
 	Timeout int64  `json:"timeout,omitempty" validate:"omitempty,gte=0"`
+	// Termination controls how the run is stopped on timeout, interrupt or session deletion.
+	Termination *TerminationPolicy `json:"termination,omitempty"`
 }
 
 // Validate validates RunInSessionRequest.
@@ -0,0 +1,89 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// SyncFileEntry describes one regular file of a directory tree.
+type SyncFileEntry struct {
+	// Path is slash-separated and relative to the manifest root.
+	Path string `json:"path" validate:"required"`
+	Size int64  `json:"size" validate:"gte=0"`
+	// Mode holds the permission bits as octal digits (e.g. 644); 0 skips mode comparison.
+	Mode   int    `json:"mode,omitempty"`
+	SHA256 string `json:"sha256" validate:"required,len=64,hexadecimal"`
+}
+
+// SyncManifest lists the regular files under Root.
+type SyncManifest struct {
+	Root  string          `json:"root"`
+	Files []SyncFileEntry `json:"files"`
+}
+
+// SyncRequest asks execd to compare a client manifest with Root.
+type SyncRequest struct {
+	Root  string          `json:"root" validate:"required"`
+	Files []SyncFileEntry `json:"files" validate:"dive"`
+	// Delete removes files under Root that are not in the manifest. It is
+	// rejected with an empty manifest, which would remove everything.
+	Delete bool `json:"delete,omitempty"`
+}
+
+func (r *SyncRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Delete && len(r.Files) == 0 {
+		return fmt.Errorf("delete requires a non-empty manifest")
+	}
+	seen := make(map[string]struct{}, len(r.Files))
+	for _, f := range r.Files {
+		if err := ValidateSyncPath(f.Path); err != nil {
+			return err
+		}
+		if _, ok := seen[f.Path]; ok {
+			return fmt.Errorf("duplicate manifest path %q", f.Path)
+		}
+		seen[f.Path] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateSyncPath rejects manifest paths that are absolute, unclean or
+// escape the root.
+func ValidateSyncPath(p string) error {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, `\`) || path.Clean(p) != p ||
+		p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Errorf("invalid manifest path %q: must be a clean relative path inside root", p)
+	}
+	return nil
+}
+
+// SyncResponse tells the client what to do to finish the sync.
+type SyncResponse struct {
+	// Upload lists manifest paths that are missing or differ and must be uploaded.
+	Upload []string `json:"upload"`
+	// Chmod lists paths whose content matched and whose mode was updated in place.
+	Chmod []string `json:"chmod,omitempty"`
+	// Deleted lists paths removed because they were not in the manifest.
+	Deleted []string `json:"deleted,omitempty"`
+}
@@ -43,6 +43,8 @@ func NewRouter(accessToken string) *gin.Engine {
 		files.POST("/replace", withFilesystem(func(c *controller.FilesystemController) { c.ReplaceContent() }))
 		files.POST("/upload", withFilesystem(func(c *controller.FilesystemController) { c.UploadFile() }))
 		files.GET("/download", withFilesystem(func(c *controller.FilesystemController) { c.DownloadFile() }))
+		files.GET("/manifest", withFilesystem(func(c *controller.FilesystemController) { c.GetSyncManifest() }))
+		files.POST("/sync", withFilesystem(func(c *controller.FilesystemController) { c.SyncFiles() }))
 	}
 
 	directories := r.Group("/directories")
@@ -66,6 +68,8 @@ func NewRouter(accessToken string) *gin.Engine {
 	session := r.Group("/session")
 	{
 		session.POST("", withCode(func(c *controller.CodeInterpretingController) { c.CreateSession() }))
+		session.GET("", withCode(func(c *controller.CodeInterpretingController) { c.ListSessions() }))
+		session.GET("/:sessionId", withCode(func(c *controller.CodeInterpretingController) { c.GetSession() }))
 		session.POST("/:sessionId/run", withCode(func(c *controller.CodeInterpretingController) { c.RunInSession() }))
 		session.DELETE("/:sessionId", withCode(func(c *controller.CodeInterpretingController) { c.DeleteSession() }))
 	}
@@ -78,6 +82,16 @@ func NewRouter(accessToken string) *gin.Engine {
 		command.GET("/:id/logs", withCode(func(c *controller.CodeInterpretingController) { c.GetBackgroundCommandOutput() }))
 	}
 
+	schedules := r.Group("/schedules")
+	{
+		schedules.POST("", withSchedule(func(c *controller.ScheduleController) { c.CreateSchedule() }))
+		schedules.GET("", withSchedule(func(c *controller.ScheduleController) { c.ListSchedules() }))
+		schedules.GET("/:id", withSchedule(func(c *controller.ScheduleController) { c.GetSchedule() }))
+		schedules.POST("/:id/pause", withSchedule(func(c *controller.ScheduleController) { c.PauseSchedule() }))
+		schedules.POST("/:id/resume", withSchedule(func(c *controller.ScheduleController) { c.ResumeSchedule() }))
+		schedules.DELETE("/:id", withSchedule(func(c *controller.ScheduleController) { c.DeleteSchedule() }))
+	}
+
 	metric := r.Group("/metrics")
 	{
 		metric.GET("", withMetric(func(c *controller.MetricController) { c.GetMetrics() }))
@@ -141,6 +155,12 @@ func withPTY(fn func(*controller.PTYController)) gin.HandlerFunc {
 	}
 }
 
+func withSchedule(fn func(*controller.ScheduleController)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		fn(controller.NewScheduleController(ctx))
+	}
+}
+
 func withIsolated(fn func(*controller.IsolatedSessionController)) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		fn(controller.NewIsolatedSessionController(ctx))
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/signals"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/alibaba/opensandbox/ingress/pkg/renewintent"
 	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
 	"github.com/alibaba/opensandbox/ingress/pkg/signature"
+	"github.com/alibaba/opensandbox/ingress/pkg/telemetry"
 	slogger "github.com/alibaba/opensandbox/internal/logger"
 	"github.com/alibaba/opensandbox/internal/version"
 )
@@ -40,21 +42,43 @@ func main() {
 
 	flag.InitFlags()
 
-	cfg := injection.ParseAndGetRESTConfigOrDie()
-	cfg.ContentType = runtime.ContentTypeProtobuf
-	cfg.UserAgent = "opensandbox-ingress/" + version.GitCommit
+	providerType := sandbox.ProviderType(flag.ProviderType)
+	var cfg *rest.Config
+	if providerType.RequiresKubernetes() {
+		cfg = injection.ParseAndGetRESTConfigOrDie()
+		cfg.ContentType = runtime.ContentTypeProtobuf
+		cfg.UserAgent = "opensandbox-ingress/" + version.GitCommit
+	}
 
 	ctx := signals.NewContext()
 	ctx = withLogger(ctx, flag.LogLevel)
 
+	otelShutdown, err := telemetry.Init(ctx)
+	if err != nil {
+		proxy.Logger.Warnf("OpenTelemetry metrics disabled (continuing without OTLP): %v", err)
+		otelShutdown = nil
+	}
+	if otelShutdown != nil {
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			_ = otelShutdown(shutdownCtx)
+		}()
+	}
+
 	// Create sandbox provider factory
 	providerFactory := sandbox.NewProviderFactory(
 		cfg,
 		time.Second*30, // resync period
+		sandbox.WithFileProviderConfig(sandbox.FileProviderConfig{
+			Path:           flag.ProviderFile,
+			ReloadInterval: flag.ProviderFileReloadInterval,
+			Logger:         proxy.Logger,
+		}),
 	)
 
 	// Create sandbox provider based on provider type
-	sandboxProvider, err := providerFactory.CreateProvider(sandbox.ProviderType(flag.ProviderType))
+	sandboxProvider, err := providerFactory.CreateProvider(providerType)
 	if err != nil {
 		log.Panicf("Failed to create sandbox provider: %v", err)
 	}
@@ -64,6 +88,10 @@ func main() {
 		log.Panicf("Failed to start sandbox provider: %v", err)
 	}
 
+	if fileProvider, ok := sandboxProvider.(*sandbox.FileProvider); ok {
+		startFileProviderPush(fileProvider)
+	}
+
 	var renewPublisher renewintent.Publisher
 	if flag.RenewIntentEnabled {
 		redisClient, err := renewintent.RedisClientFromDSN(flag.RenewIntentRedisDSN)
@@ -88,7 +116,44 @@ func main() {
 	}
 
 	// Create reverse proxy with sandbox provider
-	reverseProxy := proxy.NewProxy(ctx, sandboxProvider, proxy.Mode(flag.Mode), renewPublisher, secure)
+	transportConfig := proxy.DefaultTransportConfig()
+	transportConfig.DialTimeout = flag.UpstreamDialTimeout
+	transportConfig.ResponseHeaderTimeout = flag.UpstreamResponseHeaderTimeout
+	transportConfig.IdleConnTimeout = flag.UpstreamIdleConnTimeout
+	transportConfig.MaxIdleConnsPerHost = flag.UpstreamMaxIdleConnsPerHost
+	transportConfig.MaxConnsPerHost = flag.UpstreamMaxConnsPerHost
+	transportConfig.H2C = flag.UpstreamH2C
+
+	proxyOpts := []proxy.Option{
+		proxy.WithTransportConfig(transportConfig),
+		proxy.WithAccessLog(flag.AccessLog),
+		proxy.WithRateLimit(proxy.RateLimitConfig{
+			Default: sandbox.RateLimit{
+				RPS:           flag.RateLimitRPS,
+				Burst:         flag.RateLimitBurst,
+				MaxConcurrent: flag.RateLimitMaxConcurrent,
+			},
+			PerPort:   flag.RateLimitPerPort,
+			PerClient: flag.RateLimitPerClient,
+		}),
+	}
+	if flag.TCPTunnel {
+		tunnelConfig := proxy.DefaultTunnelConfig()
+		tunnelConfig.IdleTimeout = flag.TCPTunnelIdleTimeout
+		proxyOpts = append(proxyOpts, proxy.WithTCPTunnel(tunnelConfig))
+	}
+
+	if flag.WakeOnRequest {
+		if _, ok := sandboxProvider.(sandbox.Waker); !ok {
+			proxy.Logger.Warnf("--wake-on-request has no effect with provider type %s", providerType)
+		}
+		wakeConfig := proxy.DefaultWakeConfig()
+		wakeConfig.Timeout = flag.WakeTimeout
+		wakeConfig.MaxWaiting = flag.WakeMaxWaiting
+		proxyOpts = append(proxyOpts, proxy.WithWakeOnRequest(wakeConfig))
+	}
+
+	reverseProxy := proxy.NewProxy(ctx, sandboxProvider, proxy.Mode(flag.Mode), renewPublisher, secure, proxyOpts...)
 	http.Handle("/", reverseProxy)
 	http.HandleFunc("/status.ok", proxy.Healthz)
 
@@ -99,6 +164,28 @@ func main() {
 	panic("unreachable")
 }
 
+// startFileProviderPush serves the file provider push API when --provider-file-push-addr is set.
+func startFileProviderPush(fileProvider *sandbox.FileProvider) {
+	if flag.ProviderFilePushAddr == "" {
+		if flag.ProviderFile == "" {
+			log.Panicf("provider type %s needs --provider-file or --provider-file-push-addr", sandbox.ProviderTypeFile)
+		}
+		return
+	}
+	server, err := fileProvider.PushServer(flag.ProviderFilePushAddr, flag.ProviderFilePushToken)
+	if err != nil {
+		log.Panicf("Invalid file provider push API: %v", err)
+	}
+	if flag.ProviderFilePushToken == "" {
+		proxy.Logger.Warnf("file provider push API on %s accepts unauthenticated requests", flag.ProviderFilePushAddr)
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			log.Panicf("Error starting file provider push server: %v", err)
+		}
+	}()
+}
+
 func withLogger(ctx context.Context, logLevel string) context.Context {
 	logger := slogger.MustNew(slogger.Config{Level: logLevel}).Named("opensandbox.ingress")
 	return proxy.WithLogger(ctx, logger)
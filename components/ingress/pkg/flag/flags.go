@@ -14,6 +14,8 @@
 
 package flag
 
+import "time"
+
 var (
 	// LogLevel controls the router log verbosity.
 	LogLevel string
@@ -24,6 +26,12 @@ var (
 	// ProviderType specifies the sandbox provider type (e.g., batchsandbox).
 	ProviderType string
 
+	// ProviderFile* configure the file provider (--provider-type file).
+	ProviderFile               string
+	ProviderFileReloadInterval time.Duration
+	ProviderFilePushAddr       string
+	ProviderFilePushToken      string
+
 	// Mode specifies the sandbox service discovery mode (e.g., header, uri).
 	Mode string
 
@@ -34,4 +42,32 @@ var (
 	RenewIntentMinIntervalSec int
 
 	SecureAccessKeys string
+
+	UpstreamDialTimeout           time.Duration
+	UpstreamResponseHeaderTimeout time.Duration
+	UpstreamIdleConnTimeout       time.Duration
+	UpstreamMaxIdleConnsPerHost   int
+	UpstreamMaxConnsPerHost       int
+	UpstreamH2C                   bool
+
+	// AccessLog enables the structured access log entry written for every proxied request.
+	AccessLog bool
+
+	// TCPTunnel enables raw TCP tunnels to sandbox ports over CONNECT or an HTTP upgrade.
+	TCPTunnel            bool
+	TCPTunnelIdleTimeout time.Duration
+
+	// RateLimit* set the default per-sandbox limits; the opensandbox.io/ingress-rate-limit
+	// annotation overrides them per sandbox.
+	RateLimitRPS           float64
+	RateLimitBurst         int
+	RateLimitMaxConcurrent int
+	RateLimitPerPort       bool
+	RateLimitPerClient     bool
+
+	// WakeOnRequest resumes a paused sandbox when a request arrives for it and holds the request
+	// until the sandbox is ready.
+	WakeOnRequest  bool
+	WakeTimeout    time.Duration
+	WakeMaxWaiting int
 )
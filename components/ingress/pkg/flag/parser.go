@@ -16,6 +16,7 @@ package flag
 
 import (
 	"flag"
+	"time"
 )
 
 var (
@@ -26,7 +27,11 @@ func InitFlags() {
 	flag.StringVar(&LogLevel, "log-level", "info", "Server log level")
 	flag.IntVar(&Port, "port", 28888, "Server listening port (default: 28888)")
 	flag.StringVar(&deprecatedNamespace, "namespace", "opensandbox", "Deprecated compatibility flag (ingress now watches sandbox resources across all namespaces)")
-	flag.StringVar(&ProviderType, "provider-type", "batchsandbox", "The sandbox provider type (default: batchsandbox)")
+	flag.StringVar(&ProviderType, "provider-type", "batchsandbox", "The sandbox provider type: batchsandbox, agent-sandbox or file (default: batchsandbox)")
+	flag.StringVar(&ProviderFile, "provider-file", "", "YAML or JSON file of sandbox endpoints for the file provider")
+	flag.DurationVar(&ProviderFileReloadInterval, "provider-file-reload-interval", 2*time.Second, "How often the file provider checks --provider-file for changes")
+	flag.StringVar(&ProviderFilePushAddr, "provider-file-push-addr", "", "Listen address of the file provider push API, e.g. 127.0.0.1:28889 (empty = disabled)")
+	flag.StringVar(&ProviderFilePushToken, "provider-file-push-token", "", "Bearer token required by the file provider push API (empty = no auth, only on a loopback address)")
 	flag.StringVar(&Mode, "mode", "header", "The sandbox service discovery mode (default: header)")
 
 	flag.BoolVar(&RenewIntentEnabled, "renew-intent-enabled", false, "Enable publishing renew-intent events to Redis (OSEP-0009)")
@@ -37,5 +42,27 @@ func InitFlags() {
 
 	flag.StringVar(&SecureAccessKeys, "secure-access-keys", "", "OSEP-0011 verification keys: a=base64,b=base64 (comma-separated; key_id is 1 char [0-9a-z])")
 
+	flag.DurationVar(&UpstreamDialTimeout, "upstream-dial-timeout", 5*time.Second, "Timeout for connecting to a sandbox endpoint")
+	flag.DurationVar(&UpstreamResponseHeaderTimeout, "upstream-response-header-timeout", 0, "Timeout for a sandbox endpoint to send response headers (0 = no limit)")
+	flag.DurationVar(&UpstreamIdleConnTimeout, "upstream-idle-conn-timeout", 90*time.Second, "How long an idle connection to a sandbox endpoint is kept for reuse")
+	flag.IntVar(&UpstreamMaxIdleConnsPerHost, "upstream-max-idle-conns-per-host", 64, "Max idle connections kept per sandbox endpoint")
+	flag.IntVar(&UpstreamMaxConnsPerHost, "upstream-max-conns-per-host", 0, "Max connections per sandbox endpoint (0 = no limit)")
+	flag.BoolVar(&UpstreamH2C, "upstream-h2c", false, "Speak HTTP/2 cleartext (h2c, prior knowledge) to sandbox endpoints; all endpoints must support it")
+
+	flag.BoolVar(&AccessLog, "access-log", true, "Write a structured access log entry for every proxied request")
+
+	flag.BoolVar(&TCPTunnel, "tcp-tunnel", false, "Allow raw TCP tunnels to sandbox ports via CONNECT or an 'Upgrade: opensandbox-tcp' request")
+	flag.DurationVar(&TCPTunnelIdleTimeout, "tcp-tunnel-idle-timeout", 5*time.Minute, "Close a TCP tunnel after no traffic for this long (0 = never)")
+
+	flag.Float64Var(&RateLimitRPS, "rate-limit-rps", 0, "Default requests per second allowed per sandbox (0 = no limit)")
+	flag.IntVar(&RateLimitBurst, "rate-limit-burst", 0, "Default request burst allowed per sandbox above --rate-limit-rps (0 = the rps rounded up)")
+	flag.IntVar(&RateLimitMaxConcurrent, "rate-limit-max-concurrent", 0, "Default max concurrent requests, WebSockets and tunnels per sandbox (0 = no limit)")
+	flag.BoolVar(&RateLimitPerPort, "rate-limit-per-port", false, "Apply the rate limits to each sandbox port separately")
+	flag.BoolVar(&RateLimitPerClient, "rate-limit-per-client", false, "Apply the rate limits to each client IP of a sandbox separately")
+
+	flag.BoolVar(&WakeOnRequest, "wake-on-request", false, "Resume a paused sandbox when a request arrives for it and hold the request until it is ready (batchsandbox provider)")
+	flag.DurationVar(&WakeTimeout, "wake-timeout", 60*time.Second, "Max time a request waits for a paused sandbox to resume")
+	flag.IntVar(&WakeMaxWaiting, "wake-max-waiting", 100, "Max requests held per resuming sandbox; more get 503")
+
 	flag.Parse()
 }
@@ -0,0 +1,232 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/telemetry"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+const (
+	protocolHTTP      = "http"
+	protocolWebSocket = "websocket"
+
+	upstreamErrorTimeout = "timeout"
+	upstreamErrorConnect = "connect"
+	upstreamErrorOther   = "error"
+
+	// statusClientClosedRequest is logged for requests the client abandoned before the upstream
+	// answered, following the nginx convention.
+	statusClientClosedRequest = 499
+)
+
+// requestStats collects what the access log and the metrics report about one proxied request.
+type requestStats struct {
+	start  time.Time
+	attrs  telemetry.RequestAttrs
+	target string
+	status int
+
+	// bytesIn and bytesOut count body bytes, or message bytes once a WebSocket is upgraded, which
+	// both directions update concurrently.
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+
+	// upstreamDuration is how long the upstream took to send response headers or accept the
+	// WebSocket handshake.
+	upstreamDuration time.Duration
+	upstreamError    string
+
+	// rateLimited is the reason the request was rejected with 429: rate or concurrency.
+	rateLimited string
+
+	// wakeDuration is how long the request waited for a paused sandbox to resume.
+	wakeDuration time.Duration
+}
+
+type requestStatsKey struct{}
+
+func withRequestStats(r *http.Request, stats *requestStats) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestStatsKey{}, stats))
+}
+
+// requestStatsFrom returns the stats of the request, or nil outside of Proxy. All methods of
+// requestStats accept a nil receiver.
+func requestStatsFrom(ctx context.Context) *requestStats {
+	stats, _ := ctx.Value(requestStatsKey{}).(*requestStats)
+	return stats
+}
+
+func (s *requestStats) addBytesIn(n int) {
+	if s != nil {
+		s.bytesIn.Add(int64(n))
+	}
+}
+
+func (s *requestStats) addBytesOut(n int) {
+	if s != nil {
+		s.bytesOut.Add(int64(n))
+	}
+}
+
+func (s *requestStats) setRoute(sandboxID string, port int) {
+	if s != nil {
+		s.attrs.SandboxID = sandboxID
+		s.attrs.Port = port
+	}
+}
+
+func (s *requestStats) setStatus(status int) {
+	if s != nil {
+		s.status = status
+	}
+}
+
+func (s *requestStats) setUpstreamDuration(d time.Duration) {
+	if s != nil {
+		s.upstreamDuration = d
+	}
+}
+
+func (s *requestStats) setUpstreamError(err error) {
+	if s != nil {
+		s.upstreamError = upstreamErrorReason(err)
+	}
+}
+
+func (s *requestStats) setRateLimited(reason string) {
+	if s != nil {
+		s.rateLimited = reason
+	}
+}
+
+func (s *requestStats) setWakeDuration(d time.Duration) {
+	if s != nil {
+		s.wakeDuration = d
+	}
+}
+
+// upstreamErrorReason classifies why an upstream did not answer.
+func upstreamErrorReason(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return upstreamErrorTimeout
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return upstreamErrorConnect
+	}
+	return upstreamErrorOther
+}
+
+// finishRequest writes the access log entry of a request and records its metrics.
+func (p *Proxy) finishRequest(r *http.Request, stats *requestStats) {
+	duration := time.Since(stats.start)
+	if stats.upstreamError != "" {
+		telemetry.RecordUpstreamError(r.Context(), stats.attrs, stats.upstreamError)
+	}
+	if stats.rateLimited != "" {
+		telemetry.RecordRateLimited(r.Context(), stats.attrs, stats.rateLimited)
+	}
+	telemetry.RecordRequest(r.Context(), stats.attrs, stats.status, toMillis(duration), toMillis(stats.upstreamDuration))
+
+	if !p.accessLog {
+		return
+	}
+	fields := []slogger.Field{
+		{Key: "sandbox_id", Value: stats.attrs.SandboxID},
+		{Key: "port", Value: stats.attrs.Port},
+		{Key: "route_mode", Value: stats.attrs.RouteMode},
+		{Key: "protocol", Value: stats.attrs.Protocol},
+		{Key: "method", Value: r.Method},
+		{Key: "uri", Value: r.RequestURI},
+		{Key: "target", Value: stats.target},
+		{Key: "client", Value: p.getClientIP(r)},
+		{Key: "status", Value: stats.status},
+		{Key: "bytes_in", Value: stats.bytesIn.Load()},
+		{Key: "bytes_out", Value: stats.bytesOut.Load()},
+		{Key: "duration_ms", Value: toMillis(duration)},
+		{Key: "upstream_ms", Value: toMillis(stats.upstreamDuration)},
+	}
+	if stats.upstreamError != "" {
+		fields = append(fields, slogger.Field{Key: "upstream_error", Value: stats.upstreamError})
+	}
+	if stats.rateLimited != "" {
+		fields = append(fields, slogger.Field{Key: "rate_limited", Value: stats.rateLimited})
+	}
+	if stats.wakeDuration > 0 {
+		fields = append(fields, slogger.Field{Key: "wake_ms", Value: toMillis(stats.wakeDuration)})
+	}
+	Logger.With(fields...).Infof("ingress access")
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// accessLogWriter records the status and the body bytes of a response.
+type accessLogWriter struct {
+	http.ResponseWriter
+	stats       *requestStats
+	wroteHeader bool
+}
+
+func newAccessLogWriter(w http.ResponseWriter, stats *requestStats) *accessLogWriter {
+	return &accessLogWriter{ResponseWriter: w, stats: stats}
+}
+
+func (w *accessLogWriter) WriteHeader(code int) {
+	// Informational responses other than a protocol switch precede the final one.
+	if !w.wroteHeader && (code >= http.StatusOK || code == http.StatusSwitchingProtocols) {
+		w.wroteHeader = true
+		w.stats.setStatus(code)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.stats.setStatus(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.stats.addBytesOut(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the flushing and hijacking of the connection.
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// countingBody counts the request body bytes read by the proxy.
+type countingBody struct {
+	io.ReadCloser
+	stats *requestStats
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.stats.addBytesIn(n)
+	return n, err
+}
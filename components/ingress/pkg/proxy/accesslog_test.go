@@ -0,0 +1,217 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+// recordingLogger keeps the fields of every Infof entry with the given message.
+type recordingLogger struct {
+	fields  []slogger.Field
+	message string
+
+	mu      *sync.Mutex
+	entries *[]map[string]any
+}
+
+func newRecordingLogger(message string) *recordingLogger {
+	return &recordingLogger{message: message, mu: &sync.Mutex{}, entries: &[]map[string]any{}}
+}
+
+func (l *recordingLogger) Debugf(string, ...any) {}
+func (l *recordingLogger) Warnf(string, ...any)  {}
+func (l *recordingLogger) Errorf(string, ...any) {}
+func (l *recordingLogger) Sync() error           { return nil }
+
+func (l *recordingLogger) Infof(template string, _ ...any) {
+	if template != l.message {
+		return
+	}
+	entry := map[string]any{}
+	for _, f := range l.fields {
+		entry[f.Key] = f.Value
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, entry)
+}
+
+func (l *recordingLogger) With(fields ...slogger.Field) slogger.Logger {
+	cp := *l
+	cp.fields = append(append([]slogger.Field{}, l.fields...), fields...)
+	return &cp
+}
+
+func (l *recordingLogger) Named(string) slogger.Logger { return l }
+
+func (l *recordingLogger) Entries() []map[string]any {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]map[string]any{}, *l.entries...)
+}
+
+func TestProxy_AccessLog(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(append(body, '!'))
+	}))
+	defer backend.Close()
+	backendPort := backend.URL[len("http://127.0.0.1:"):]
+
+	provider := &mockProvider{endpoints: map[string]string{"test-sandbox": "127.0.0.1"}}
+	cfg := DefaultTransportConfig()
+	cfg.ResponseHeaderTimeout = 50 * time.Millisecond
+
+	send := func(p *Proxy, method, path, sandboxName, body string) int {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set(SandboxIngress, fmt.Sprintf("%s-%s", sandboxName, backendPort))
+		w := httptest.NewRecorder()
+		p.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("proxied request", func(t *testing.T) {
+		logger := newRecordingLogger("ingress access")
+		Logger = logger
+		p := NewProxy(context.Background(), provider, ModeHeader, nil, nil, WithTransportConfig(cfg))
+
+		assert.Equal(t, http.StatusOK, send(p, http.MethodPost, "/echo", "test-sandbox", "hello"))
+		entries := logger.Entries()
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		assert.Equal(t, "test-sandbox", entry["sandbox_id"])
+		assert.Equal(t, backendPort, fmt.Sprint(entry["port"]))
+		assert.Equal(t, "header", entry["route_mode"])
+		assert.Equal(t, "http", entry["protocol"])
+		assert.Equal(t, http.MethodPost, entry["method"])
+		assert.Equal(t, "127.0.0.1:"+backendPort, entry["target"])
+		assert.Equal(t, http.StatusOK, entry["status"])
+		assert.Equal(t, int64(5), entry["bytes_in"])
+		assert.Equal(t, int64(6), entry["bytes_out"])
+		assert.Greater(t, entry["upstream_ms"], float64(0))
+		assert.GreaterOrEqual(t, entry["duration_ms"], entry["upstream_ms"])
+		assert.NotContains(t, entry, "upstream_error")
+	})
+
+	t.Run("unknown sandbox", func(t *testing.T) {
+		logger := newRecordingLogger("ingress access")
+		Logger = logger
+		p := NewProxy(context.Background(), provider, ModeHeader, nil, nil, WithTransportConfig(cfg))
+
+		assert.Equal(t, http.StatusNotFound, send(p, http.MethodGet, "/", "missing", ""))
+		entries := logger.Entries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "missing", entries[0]["sandbox_id"])
+		assert.Equal(t, http.StatusNotFound, entries[0]["status"])
+		assert.Equal(t, float64(0), entries[0]["upstream_ms"])
+	})
+
+	t.Run("upstream timeout", func(t *testing.T) {
+		logger := newRecordingLogger("ingress access")
+		Logger = logger
+		p := NewProxy(context.Background(), provider, ModeHeader, nil, nil, WithTransportConfig(cfg))
+
+		assert.Equal(t, http.StatusGatewayTimeout, send(p, http.MethodGet, "/slow", "test-sandbox", ""))
+		entries := logger.Entries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, http.StatusGatewayTimeout, entries[0]["status"])
+		assert.Equal(t, upstreamErrorTimeout, entries[0]["upstream_error"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		logger := newRecordingLogger("ingress access")
+		Logger = logger
+		p := NewProxy(context.Background(), provider, ModeHeader, nil, nil, WithAccessLog(false))
+
+		assert.Equal(t, http.StatusOK, send(p, http.MethodGet, "/", "test-sandbox", ""))
+		assert.Empty(t, logger.Entries())
+	})
+}
+
+func TestProxy_AccessLogWebSocket(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := defaultUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msgType, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(msgType, append(msg, '!')); err != nil {
+				return
+			}
+		}
+	}))
+	defer backend.Close()
+	backendPort := backend.URL[len("http://127.0.0.1:"):]
+
+	logger := newRecordingLogger("ingress access")
+	Logger = logger
+	provider := &mockProvider{endpoints: map[string]string{"test-sandbox": "127.0.0.1"}}
+	front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil))
+	defer front.Close()
+
+	url := "ws" + strings.TrimPrefix(front.URL, "http") + "/test-sandbox/" + backendPort + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "hello!", string(msg))
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool { return len(logger.Entries()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	entry := logger.Entries()[0]
+	assert.Equal(t, "websocket", entry["protocol"])
+	assert.Equal(t, "uri", entry["route_mode"])
+	assert.Equal(t, http.StatusSwitchingProtocols, entry["status"])
+	assert.Equal(t, int64(5), entry["bytes_in"])
+	assert.Equal(t, int64(6), entry["bytes_out"])
+	assert.Greater(t, entry["upstream_ms"], float64(0))
+}
+
+func TestUpstreamErrorReason(t *testing.T) {
+	assert.Equal(t, upstreamErrorTimeout, upstreamErrorReason(context.DeadlineExceeded))
+	assert.Equal(t, upstreamErrorOther, upstreamErrorReason(io.ErrUnexpectedEOF))
+
+	_, err := http.Get("http://127.0.0.1:1")
+	require.Error(t, err)
+	assert.Equal(t, upstreamErrorConnect, upstreamErrorReason(err))
+}
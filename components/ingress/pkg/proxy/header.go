@@ -37,6 +37,7 @@ var (
 	SetCookie              = http.CanonicalHeaderKey("Set-Cookie")
 	Host                   = http.CanonicalHeaderKey("Host")
 	Origin                 = http.CanonicalHeaderKey("Origin")
+	RetryAfter             = http.CanonicalHeaderKey("Retry-After")
 
 	// Hop-by-hop headers per RFC 7230 §6.1 — must not be forwarded by proxies.
 	HopByHopConnection       = http.CanonicalHeaderKey("Connection")
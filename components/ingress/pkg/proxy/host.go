@@ -18,7 +18,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
 	"github.com/alibaba/opensandbox/ingress/pkg/signature"
 )
 
@@ -52,9 +54,13 @@ func (p *Proxy) getSandboxHostDefinition(r *http.Request) (*sandboxHost, int, er
 	if err != nil || pr.sandboxID == "" || pr.port == 0 {
 		return nil, http.StatusBadRequest, fmt.Errorf("invalid ingress route: %w", err)
 	}
+	requestStatsFrom(r.Context()).setRoute(pr.sandboxID, pr.port)
 
 	endpoint, err := p.sandboxProvider.GetEndpoint(pr.sandboxID)
-	if err != nil {
+	// A paused sandbox is woken by wakeSandbox, only for requests that pass the access checks
+	// below and the rate limits.
+	paused := err != nil && errors.Is(err, sandbox.ErrSandboxPaused) && p.waker != nil && endpoint != nil
+	if err != nil && !paused {
 		return nil, providerErrHTTPStatus(err), err
 	}
 
@@ -87,9 +93,24 @@ func (p *Proxy) getSandboxHostDefinition(r *http.Request) (*sandboxHost, int, er
 		port:       pr.port,
 		endpoint:   endpoint.Endpoint,
 		requestURI: pr.requestURI,
+		rateLimit:  endpoint.RateLimit,
+		paused:     paused,
 	}, 0, nil
 }
 
+// wakeSandbox resumes the paused sandbox of host and waits until it has an endpoint.
+func (p *Proxy) wakeSandbox(r *http.Request, host *sandboxHost) (int, error) {
+	start := time.Now()
+	endpoint, status, err := p.waker.wait(r.Context(), host.ingressKey)
+	requestStatsFrom(r.Context()).setWakeDuration(time.Since(start))
+	if err != nil {
+		return status, err
+	}
+	host.endpoint = endpoint.Endpoint
+	host.paused = false
+	return 0, nil
+}
+
 func (p *Proxy) parseTargetHostByHeader(r *http.Request) string {
 	targetHost := r.Header.Get(SandboxIngress)
 	if targetHost != "" {
@@ -108,4 +129,8 @@ type sandboxHost struct {
 	port       int
 	endpoint   string
 	requestURI string
+	// rateLimit is the opensandbox.io/ingress-rate-limit annotation of the sandbox.
+	rateLimit string
+	// paused is set when the sandbox has to be woken before the request can be proxied.
+	paused bool
 }
@@ -37,7 +37,8 @@ func parseHostRoute(s string) (parsedRoute, error) {
 	if len(domain) < 1 {
 		return parsedRoute{}, fmt.Errorf("invalid host: %s", s)
 	}
-	label := domain[0]
+	// A CONNECT authority or a Host header may carry the ingress port.
+	label, _, _ := strings.Cut(domain[0], ":")
 
 	sandboxID, port, expires, routeSig, parseErr := signature.ParseRouteToken(label)
 	if parseErr == nil {
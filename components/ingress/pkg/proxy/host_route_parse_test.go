@@ -46,3 +46,10 @@ func TestParseURIRoute_OSEPFourSegmentsWithSig(t *testing.T) {
 	assert.Equal(t, "/extra/path", pr.requestURI)
 	assert.True(t, pr.uriParsedAsOSEP)
 }
+
+func TestParseHostRoute_AuthorityWithPort(t *testing.T) {
+	pr, err := parseHostRoute("my-sandbox-5432:443")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-sandbox", pr.sandboxID)
+	assert.Equal(t, 5432, pr.port)
+}
@@ -15,45 +15,77 @@
 package proxy
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
+	"time"
+
+	slogger "github.com/alibaba/opensandbox/internal/logger"
 )
 
-type HTTPProxy struct{}
+// HTTPProxy forwards a request to the upstream in its URL. It is safe for concurrent use, and all
+// requests share its transport and so its upstream connections.
+type HTTPProxy struct {
+	proxy *httputil.ReverseProxy
+}
 
-func NewHTTPProxy() *HTTPProxy {
-	return &HTTPProxy{}
+func NewHTTPProxy(transport http.RoundTripper) *HTTPProxy {
+	if transport == nil {
+		transport = NewTransport(DefaultTransportConfig())
+	}
+	return &HTTPProxy{
+		proxy: &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				// Keep the X-Forwarded-For chain of earlier proxies.
+				pr.Out.Header[XForwardedFor] = pr.In.Header[XForwardedFor]
+				pr.SetXForwarded()
+				pr.Out.Header.Del(SandboxIngress)
+			},
+			Transport: &timedTransport{next: transport},
+			ModifyResponse: func(response *http.Response) error {
+				response.Header.Add(ReverseProxyServerPowerBy, "OpenSandbox-ingress")
+				return nil
+			},
+			ErrorHandler: handleUpstreamError,
+		},
+	}
 }
 
 func (hp *HTTPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	targetURL := r.URL.String()
-
-	proxy, err := hp.newReverseProxy(targetURL)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadGateway)
-		return
-	}
+	hp.proxy.ServeHTTP(w, r)
+}
 
-	proxy.ServeHTTP(w, r)
+// timedTransport records how long the upstream takes to send response headers.
+type timedTransport struct {
+	next http.RoundTripper
 }
 
-func (hp *HTTPProxy) newReverseProxy(targetHost string) (*httputil.ReverseProxy, error) {
-	url, err := url.Parse(targetHost)
-	if err != nil {
-		return nil, err
+func (t *timedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(r)
+	if err == nil {
+		requestStatsFrom(r.Context()).setUpstreamDuration(time.Since(start))
 	}
+	return resp, err
+}
 
-	proxy := httputil.NewSingleHostReverseProxy(url)
-	proxy.Director = func(req *http.Request) {
-		req.URL.Scheme = url.Scheme
-		req.URL.Host = url.Host
-		req.Host = url.Host
-		req.Header.Del(SandboxIngress)
+// handleUpstreamError answers a request the upstream did not: 504 if it timed out, 502 otherwise.
+func handleUpstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.Canceled) {
+		// The client went away; there is no one to answer.
+		requestStatsFrom(r.Context()).setStatus(statusClientClosedRequest)
+		return
 	}
-	proxy.ModifyResponse = func(response *http.Response) error {
-		response.Header.Add(ReverseProxyServerPowerBy, "OpenSandbox-ingress")
-		return nil
+	requestStatsFrom(r.Context()).setUpstreamError(err)
+	status := http.StatusBadGateway
+	if upstreamErrorReason(err) == upstreamErrorTimeout {
+		status = http.StatusGatewayTimeout
 	}
-	return proxy, nil
+	Logger.With(
+		slogger.Field{Key: "error", Value: err},
+		slogger.Field{Key: "target", Value: r.URL.Host},
+		slogger.Field{Key: "status", Value: status},
+	).Errorf("HTTPProxy: upstream request failed")
+	w.WriteHeader(status)
 }
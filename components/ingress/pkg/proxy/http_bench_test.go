@@ -0,0 +1,85 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+// Benchmarks proxy to a local backend, so they measure the proxy and connection handling rather
+// than the network. The "conns" metric counts the upstream connections dialed; it shows pooling
+// best with several CPUs: go test -run '^$' -bench HTTPProxy -cpu 1,4 ./pkg/proxy/
+
+func benchmarkProxy(b *testing.B, h2c bool, newHandler func() http.Handler) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	server, conns := newCountingBackend(b, h2c, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// Many concurrent clients per CPU, as an ingress under load sees.
+	b.SetParallelism(16)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r := httptest.NewRequest(http.MethodGet, "/hello", nil)
+			r.URL.Scheme = target.Scheme
+			r.URL.Host = target.Host
+			r.Host = target.Host
+			w := httptest.NewRecorder()
+			newHandler().ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				b.Errorf("unexpected status %d", w.Code)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(conns.Load()), "conns")
+}
+
+// BenchmarkHTTPProxy_PerRequest is the former data path: a single-host reverse proxy built for
+// every request on http.DefaultTransport, which keeps only two idle connections per upstream.
+func BenchmarkHTTPProxy_PerRequest(b *testing.B) {
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	benchmarkProxy(b, false, func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target := &url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host}
+			httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+		})
+	})
+}
+
+func BenchmarkHTTPProxy_SharedTransport(b *testing.B) {
+	hp := NewHTTPProxy(NewTransport(DefaultTransportConfig()))
+	benchmarkProxy(b, false, func() http.Handler { return hp })
+}
+
+func BenchmarkHTTPProxy_SharedTransportH2C(b *testing.B) {
+	cfg := DefaultTransportConfig()
+	cfg.H2C = true
+	hp := NewHTTPProxy(NewTransport(cfg))
+	benchmarkProxy(b, true, func() http.Handler { return hp })
+}
@@ -35,6 +35,7 @@ type mockProvider struct {
 	endpoints   map[string]string // sandboxName -> IP
 	notReady    map[string]bool   // sandboxName -> notReady flag
 	accessToken map[string]string // sandboxName -> opensandbox.io/secure-access-token value (non-empty => verification required)
+	rateLimit   map[string]string // sandboxName -> opensandbox.io/ingress-rate-limit value
 }
 
 func (m *mockProvider) sandboxExists(sandboxId string) bool {
@@ -63,6 +64,7 @@ func (m *mockProvider) GetEndpoint(sandboxId string) (*sandbox.EndpointInfo, err
 	return &sandbox.EndpointInfo{
 		Endpoint:          ip,
 		SecureAccessToken: token,
+		RateLimit:         m.rateLimit[sandboxId],
 	}, nil
 }
 
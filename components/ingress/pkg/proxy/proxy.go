@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alibaba/opensandbox/ingress/pkg/renewintent"
 	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
 	"github.com/alibaba/opensandbox/ingress/pkg/signature"
+	"github.com/alibaba/opensandbox/ingress/pkg/telemetry"
 	slogger "github.com/alibaba/opensandbox/internal/logger"
 )
 
@@ -34,18 +37,77 @@ type Proxy struct {
 	renewIntentPublisher renewintent.Publisher
 
 	secure *signature.Verifier
+
+	transportConfig TransportConfig
+	httpProxy       *HTTPProxy
+	webSocketProxy  *WebSocketProxy
+
+	accessLog bool
+	tunnel    *TunnelConfig
+	limiter   *rateLimiter
+	wake      *WakeConfig
+	waker     *sandboxWaker
+}
+
+// Option customizes a Proxy.
+type Option func(*Proxy)
+
+// WithTransportConfig sets how the proxy connects to sandbox endpoints.
+func WithTransportConfig(cfg TransportConfig) Option {
+	return func(p *Proxy) {
+		p.transportConfig = cfg
+	}
 }
 
-func NewProxy(_ context.Context, sandboxProvider sandbox.Provider, mode Mode, renewIntentPublisher renewintent.Publisher, secure *signature.Verifier) *Proxy {
-	return &Proxy{
+// WithAccessLog turns the access log entry written for every request on or off. It is on by
+// default.
+func WithAccessLog(enabled bool) Option {
+	return func(p *Proxy) {
+		p.accessLog = enabled
+	}
+}
+
+func NewProxy(_ context.Context, sandboxProvider sandbox.Provider, mode Mode, renewIntentPublisher renewintent.Publisher, secure *signature.Verifier, opts ...Option) *Proxy {
+	p := &Proxy{
 		sandboxProvider:      sandboxProvider,
 		mode:                 mode,
 		renewIntentPublisher: renewIntentPublisher,
 		secure:               secure,
+		transportConfig:      DefaultTransportConfig(),
+		accessLog:            true,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.limiter == nil {
+		p.limiter = newRateLimiter(RateLimitConfig{})
+	}
+	if waker, ok := sandboxProvider.(sandbox.Waker); ok && p.wake != nil {
+		p.waker = newSandboxWaker(*p.wake, sandboxProvider, waker)
 	}
+	p.httpProxy = NewHTTPProxy(NewTransport(p.transportConfig))
+	p.webSocketProxy = newWebSocketProxy(newWebSocketDialer(p.transportConfig))
+	return p
 }
 
 func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stats := &requestStats{
+		start: time.Now(),
+		attrs: telemetry.RequestAttrs{RouteMode: string(p.mode), Protocol: protocolHTTP},
+	}
+	switch {
+	case p.isTunnelRequest(r):
+		stats.attrs.Protocol = protocolTCP
+	case p.isWebSocketRequest(r):
+		stats.attrs.Protocol = protocolWebSocket
+	}
+	r = withRequestStats(r, stats)
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = &countingBody{ReadCloser: r.Body, stats: stats}
+	}
+	w = newAccessLogWriter(w, stats)
+	defer p.finishRequest(r, stats)
+
 	defer func() {
 		if rcv := recover(); rcv != nil {
 			panicErr := fmt.Sprintf("%v", rcv)
@@ -64,12 +126,24 @@ func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	host, status, err := p.getSandboxHostDefinition(r)
 	if err != nil {
-		if status == 0 {
-			status = http.StatusBadRequest
-		}
-		http.Error(w, fmt.Sprintf("OpenSandbox Ingress: %v", err), status)
+		writeRouteError(w, status, err)
+		return
+	}
+
+	// Held until the proxied request, WebSocket or tunnel ends. Admission comes before waking, so
+	// rejected requests neither resume a sandbox nor take a waiting slot.
+	release, ok := p.admit(w, r, host)
+	if !ok {
 		return
 	}
+	defer release()
+
+	if host.paused {
+		if status, err := p.wakeSandbox(r, host); err != nil {
+			writeRouteError(w, status, err)
+			return
+		}
+	}
 
 	targetHost, err, code := p.resolveRealHost(host)
 	if err != nil {
@@ -86,21 +160,32 @@ func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r.URL.Path = host.requestURI
 	}
 
+	stats.target = targetHost
 	r.Host = targetHost
 	r.URL.Host = targetHost
 	r.Header.Del(SandboxIngress)
 	r.Header.Del(signature.OpenSandboxSecureAccessCanonical)
 
-	Logger.With(
-		slogger.Field{Key: "target", Value: targetHost},
-		slogger.Field{Key: "client", Value: p.getClientIP(r)},
-		slogger.Field{Key: "uri", Value: r.RequestURI},
-		slogger.Field{Key: "method", Value: r.Method},
-	).Infof("ingress requested")
 	p.serve(w, r)
 }
 
+// writeRouteError answers a request that could not be routed to its sandbox.
+func writeRouteError(w http.ResponseWriter, status int, err error) {
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	var retry *retryAfterError
+	if errors.As(err, &retry) {
+		w.Header().Set(RetryAfter, strconv.Itoa(retryAfterSeconds(retry.after)))
+	}
+	http.Error(w, fmt.Sprintf("OpenSandbox Ingress: %v", err), status)
+}
+
 func (p *Proxy) serve(w http.ResponseWriter, r *http.Request) {
+	if p.isTunnelRequest(r) {
+		p.serveTunnel(w, r, r.URL.Host)
+		return
+	}
 	if p.isWebSocketRequest(r) {
 		if r.URL == nil {
 			http.Error(w, "invalid request URL", http.StatusBadRequest)
@@ -114,7 +199,7 @@ func (p *Proxy) serve(w http.ResponseWriter, r *http.Request) {
 				r.URL.Scheme = "ws"
 			}
 		}
-		NewWebSocketProxy(r.URL).ServeHTTP(w, r)
+		p.webSocketProxy.ServeHTTP(w, r)
 	} else {
 		if r.URL.Scheme == "" {
 			if r.TLS != nil {
@@ -123,7 +208,7 @@ func (p *Proxy) serve(w http.ResponseWriter, r *http.Request) {
 				r.URL.Scheme = "http"
 			}
 		}
-		NewHTTPProxy().ServeHTTP(w, r)
+		p.httpProxy.ServeHTTP(w, r)
 	}
 }
 
@@ -0,0 +1,207 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+const (
+	rateLimitedRate        = "rate"
+	rateLimitedConcurrency = "concurrency"
+
+	// rateLimitIdleTTL is how long an unused limiter is kept. It outlasts the refill of any
+	// practical bucket, so dropping it loses no state.
+	rateLimitIdleTTL       = 10 * time.Minute
+	rateLimitSweepInterval = time.Minute
+)
+
+// RateLimitConfig configures the token-bucket rate limit and the concurrency cap applied to the
+// traffic of each sandbox.
+type RateLimitConfig struct {
+	// Default applies to sandboxes without the opensandbox.io/ingress-rate-limit annotation and
+	// fills in the keys the annotation leaves out. The zero value sets no limit.
+	Default sandbox.RateLimit
+	// PerPort gives each port of a sandbox its own limits.
+	PerPort bool
+	// PerClient gives each client IP its own limits for a sandbox.
+	PerClient bool
+}
+
+// WithRateLimit sets the default per-sandbox limits and how traffic is keyed. Without it, only
+// sandboxes annotated with opensandbox.io/ingress-rate-limit are limited, per sandbox.
+func WithRateLimit(cfg RateLimitConfig) Option {
+	return func(p *Proxy) {
+		p.limiter = newRateLimiter(cfg)
+	}
+}
+
+// rateLimiter keeps one limitBucket per sandbox key.
+type rateLimiter struct {
+	cfg RateLimitConfig
+
+	mu        sync.Mutex
+	buckets   map[string]*limitBucket
+	lastSweep time.Time
+}
+
+type limitBucket struct {
+	// spec is the annotation value limit was parsed from.
+	spec   string
+	limit  sandbox.RateLimit
+	tokens *rate.Limiter
+
+	active   int
+	lastUsed time.Time
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		cfg:       cfg,
+		buckets:   make(map[string]*limitBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *rateLimiter) key(sandboxID string, port int, clientIP string) string {
+	key := sandboxID
+	if l.cfg.PerPort {
+		key += ":" + strconv.Itoa(port)
+	}
+	if l.cfg.PerClient {
+		key += "|" + clientIP
+	}
+	return key
+}
+
+// acquire admits a request for key, limited by spec over the defaults. On success the returned
+// release frees the concurrency slot and must be called once the request is done. Otherwise the
+// reason is rate or concurrency, with a hint of when to retry.
+func (l *rateLimiter) acquire(key, spec string) (release func(), reason string, retryAfter time.Duration) {
+	if spec == "" && l.cfg.Default.Unlimited() {
+		return func() {}, "", 0
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b := l.buckets[key]
+	if b == nil {
+		b = &limitBucket{}
+		l.buckets[key] = b
+		l.configure(b, key, spec, now)
+	} else if b.spec != spec {
+		l.configure(b, key, spec, now)
+	}
+	b.lastUsed = now
+
+	if b.limit.MaxConcurrent > 0 && b.active >= b.limit.MaxConcurrent {
+		return nil, rateLimitedConcurrency, time.Second
+	}
+	if b.tokens != nil {
+		r := b.tokens.ReserveN(now, 1)
+		if delay := r.DelayFrom(now); delay > 0 {
+			r.CancelAt(now)
+			return nil, rateLimitedRate, delay
+		}
+	}
+	if b.limit.MaxConcurrent <= 0 {
+		return func() {}, "", 0
+	}
+
+	b.active++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			b.active--
+			b.lastUsed = time.Now()
+			l.mu.Unlock()
+		})
+	}, "", 0
+}
+
+// configure applies spec to b, keeping the tokens and the open requests it already counts.
+func (l *rateLimiter) configure(b *limitBucket, key, spec string, now time.Time) {
+	limit, err := sandbox.ParseRateLimit(spec, l.cfg.Default)
+	if err != nil {
+		Logger.With(
+			slogger.Field{Key: "error", Value: err},
+			slogger.Field{Key: "key", Value: key},
+		).Warnf("ingress: invalid %s annotation, using the default limits", sandbox.AnnotationIngressRateLimit)
+	}
+	b.spec = spec
+	b.limit = limit
+
+	switch {
+	case limit.RPS <= 0:
+		b.tokens = nil
+	case b.tokens == nil:
+		b.tokens = rate.NewLimiter(rate.Limit(limit.RPS), limit.EffectiveBurst())
+	default:
+		b.tokens.SetLimitAt(now, rate.Limit(limit.RPS))
+		b.tokens.SetBurstAt(now, limit.EffectiveBurst())
+	}
+}
+
+// sweep drops the buckets not used for rateLimitIdleTTL. It runs at most once per
+// rateLimitSweepInterval and must be called with l.mu held.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.active == 0 && now.Sub(b.lastUsed) > rateLimitIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// admit applies the limits of the sandbox host to r. When r is rejected, admit answers 429 with
+// Retry-After and returns false; otherwise the caller must call release when done with r.
+func (p *Proxy) admit(w http.ResponseWriter, r *http.Request, host *sandboxHost) (release func(), ok bool) {
+	clientIP := ""
+	if p.limiter.cfg.PerClient {
+		clientIP = p.getClientIP(r)
+	}
+	release, reason, retryAfter := p.limiter.acquire(p.limiter.key(host.ingressKey, host.port, clientIP), host.rateLimit)
+	if reason == "" {
+		return release, true
+	}
+
+	requestStatsFrom(r.Context()).setRateLimited(reason)
+	w.Header().Set(RetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+	http.Error(w, fmt.Sprintf("OpenSandbox Ingress: %s limit exceeded for sandbox %s", reason, host.ingressKey),
+		http.StatusTooManyRequests)
+	return nil, false
+}
+
+// retryAfterSeconds rounds d up to whole seconds, as Retry-After requires, and to at least one.
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+func TestProxy_RateLimit(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	blocked, unblock := make(chan struct{}), make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			blocked <- struct{}{}
+			<-unblock
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	defer close(unblock)
+	backendPort := backend.URL[len("http://127.0.0.1:"):]
+
+	provider := &mockProvider{
+		endpoints: map[string]string{
+			"sandbox-a": "127.0.0.1", "sandbox-b": "127.0.0.1", "annotated": "127.0.0.1", "invalid": "127.0.0.1",
+		},
+		rateLimit: map[string]string{"annotated": "rps=0,concurrency=1", "invalid": "rps=lots"},
+	}
+	get := func(front, sandboxName, path string, header http.Header) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, front+"/"+sandboxName+"/"+backendPort+path, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	t.Run("token bucket per sandbox", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithRateLimit(RateLimitConfig{Default: sandbox.RateLimit{RPS: 0.5, Burst: 2}})))
+		defer front.Close()
+
+		assert.Equal(t, http.StatusOK, get(front.URL, "sandbox-a", "/", nil).StatusCode)
+		assert.Equal(t, http.StatusOK, get(front.URL, "sandbox-a", "/", nil).StatusCode)
+		resp := get(front.URL, "sandbox-a", "/", nil)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(RetryAfter))
+
+		// Other sandboxes have their own bucket.
+		assert.Equal(t, http.StatusOK, get(front.URL, "sandbox-b", "/", nil).StatusCode)
+		// An unparsable annotation falls back to the defaults.
+		assert.Equal(t, http.StatusOK, get(front.URL, "invalid", "/", nil).StatusCode)
+		assert.Equal(t, http.StatusOK, get(front.URL, "invalid", "/", nil).StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, get(front.URL, "invalid", "/", nil).StatusCode)
+	})
+
+	t.Run("per client", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithRateLimit(RateLimitConfig{Default: sandbox.RateLimit{RPS: 0.5, Burst: 1}, PerClient: true})))
+		defer front.Close()
+
+		clientA := http.Header{XForwardedFor: {"10.0.0.1"}}
+		clientB := http.Header{XForwardedFor: {"10.0.0.2"}}
+		assert.Equal(t, http.StatusOK, get(front.URL, "sandbox-a", "/", clientA).StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, get(front.URL, "sandbox-a", "/", clientA).StatusCode)
+		assert.Equal(t, http.StatusOK, get(front.URL, "sandbox-a", "/", clientB).StatusCode)
+	})
+
+	t.Run("annotated concurrency cap", func(t *testing.T) {
+		// No defaults: only the annotated sandbox is limited.
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil))
+		defer front.Close()
+
+		held := make(chan *http.Response)
+		go func() { held <- get(front.URL, "annotated", "/block", nil) }()
+		<-blocked
+		resp := get(front.URL, "annotated", "/", nil)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get(RetryAfter))
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, get(front.URL, "sandbox-a", "/", nil).StatusCode)
+		}
+
+		unblock <- struct{}{}
+		assert.Equal(t, http.StatusOK, (<-held).StatusCode)
+		// The slot is released once the proxy handler returns, just after the response.
+		assert.Eventually(t, func() bool {
+			return get(front.URL, "annotated", "/", nil).StatusCode == http.StatusOK
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestProxy_RateLimitAccessLog(t *testing.T) {
+	logger := newRecordingLogger("ingress access")
+	Logger = logger
+	provider := &mockProvider{
+		endpoints: map[string]string{"test-sandbox": "127.0.0.1"},
+		rateLimit: map[string]string{"test-sandbox": "rps=0.001,burst=1"},
+	}
+	p := NewProxy(context.Background(), provider, ModeURI, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/test-sandbox/1/", nil)
+		p.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	entries := logger.Entries()
+	require.Len(t, entries, 2)
+	assert.NotContains(t, entries[0], "rate_limited")
+	assert.Equal(t, http.StatusTooManyRequests, entries[1]["status"])
+	assert.Equal(t, rateLimitedRate, entries[1]["rate_limited"])
+}
+
+func TestRateLimiter(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+
+	t.Run("key", func(t *testing.T) {
+		assert.Equal(t, "sbx", newRateLimiter(RateLimitConfig{}).key("sbx", 80, "10.0.0.1"))
+		assert.Equal(t, "sbx:80|10.0.0.1",
+			newRateLimiter(RateLimitConfig{PerPort: true, PerClient: true}).key("sbx", 80, "10.0.0.1"))
+	})
+
+	t.Run("release frees the slot once", func(t *testing.T) {
+		l := newRateLimiter(RateLimitConfig{Default: sandbox.RateLimit{MaxConcurrent: 2}})
+		first, reason, _ := l.acquire("sbx", "")
+		require.Empty(t, reason)
+		second, reason, _ := l.acquire("sbx", "")
+		require.Empty(t, reason)
+		_, reason, retryAfter := l.acquire("sbx", "")
+		assert.Equal(t, rateLimitedConcurrency, reason)
+		assert.Equal(t, time.Second, retryAfter)
+
+		first()
+		first()
+		_, reason, _ = l.acquire("sbx", "")
+		assert.Empty(t, reason)
+		_, reason, _ = l.acquire("sbx", "")
+		assert.Equal(t, rateLimitedConcurrency, reason)
+		second()
+	})
+
+	t.Run("annotation change keeps open requests", func(t *testing.T) {
+		l := newRateLimiter(RateLimitConfig{})
+		release, reason, _ := l.acquire("sbx", "concurrency=1")
+		require.Empty(t, reason)
+		_, reason, _ = l.acquire("sbx", "concurrency=1, rps=100")
+		assert.Equal(t, rateLimitedConcurrency, reason)
+		release()
+		_, reason, _ = l.acquire("sbx", "concurrency=1, rps=100")
+		assert.Empty(t, reason)
+	})
+
+	t.Run("sweep drops idle buckets", func(t *testing.T) {
+		l := newRateLimiter(RateLimitConfig{Default: sandbox.RateLimit{RPS: 1, MaxConcurrent: 5}})
+		idle, _, _ := l.acquire("idle", "")
+		idle()
+		busy, _, _ := l.acquire("busy", "")
+		defer busy()
+
+		stale := time.Now().Add(-2 * rateLimitIdleTTL)
+		l.mu.Lock()
+		l.buckets["idle"].lastUsed = stale
+		l.buckets["busy"].lastUsed = stale
+		l.lastSweep = stale
+		l.mu.Unlock()
+
+		_, _, _ = l.acquire("other", "")
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		assert.NotContains(t, l.buckets, "idle")
+		assert.Contains(t, l.buckets, "busy")
+	})
+
+	t.Run("unlimited skips bookkeeping", func(t *testing.T) {
+		l := newRateLimiter(RateLimitConfig{})
+		release, reason, _ := l.acquire("sbx", "")
+		assert.Empty(t, reason)
+		release()
+		assert.Empty(t, l.buckets)
+	})
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, 1, retryAfterSeconds(0))
+	assert.Equal(t, 1, retryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, 3, retryAfterSeconds(2001*time.Millisecond))
+}
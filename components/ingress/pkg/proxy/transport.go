@@ -0,0 +1,110 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultDialTimeout         = 5 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultMaxIdleConns        = 1024
+	defaultMaxIdleConnsPerHost = 64
+	defaultKeepAlive           = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultHandshakeTimeout    = 45 * time.Second
+)
+
+// TransportConfig tunes the connections from the ingress to sandbox endpoints. All proxied
+// requests share one transport, which keeps a pool of idle connections per upstream.
+type TransportConfig struct {
+	// DialTimeout bounds establishing a connection to an upstream.
+	DialTimeout time.Duration
+	// ResponseHeaderTimeout bounds waiting for the response headers of an upstream once the
+	// request is written. Zero means no limit.
+	ResponseHeaderTimeout time.Duration
+	// IdleConnTimeout is how long an idle upstream connection stays in the pool.
+	IdleConnTimeout time.Duration
+	// MaxIdleConns caps the idle connections across all upstreams.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps the idle connections kept per upstream.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps the connections per upstream. Zero means no limit.
+	MaxConnsPerHost int
+	// H2C speaks HTTP/2 with prior knowledge to plain HTTP upstreams, multiplexing requests on
+	// one connection per upstream. Every upstream must then support h2c.
+	H2C bool
+}
+
+// DefaultTransportConfig returns the transport settings used when none are given.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		DialTimeout:         defaultDialTimeout,
+		IdleConnTimeout:     defaultIdleConnTimeout,
+		MaxIdleConns:        defaultMaxIdleConns,
+		MaxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+	}
+}
+
+// NewTransport returns the transport for proxied HTTP requests.
+func NewTransport(cfg TransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: defaultKeepAlive,
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	if cfg.H2C {
+		// Without HTTP/1 in the protocols, http:// upstreams are spoken to in h2c.
+		protocols := new(http.Protocols)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		transport.Protocols = protocols
+	}
+	return transport
+}
+
+// newWebSocketDialer returns the dialer for proxied WebSocket connections. Each WebSocket holds
+// its own upstream connection, so only the dial settings apply.
+func newWebSocketDialer(cfg TransportConfig) *websocket.Dialer {
+	handshakeTimeout := defaultHandshakeTimeout
+	if cfg.ResponseHeaderTimeout > 0 {
+		handshakeTimeout = cfg.ResponseHeaderTimeout
+	}
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: defaultKeepAlive,
+	}
+	return &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		NetDialContext:   dialer.DialContext,
+		HandshakeTimeout: handshakeTimeout,
+	}
+}
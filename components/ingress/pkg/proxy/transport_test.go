@@ -0,0 +1,139 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+// newCountingBackend starts a backend that counts the connections opened to it.
+func newCountingBackend(t testing.TB, h2c bool, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	conns := &atomic.Int32{}
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	if h2c {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		server.Config.Protocols = protocols
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, conns
+}
+
+// proxyGet sends a GET through hp to the backend and returns the response.
+func proxyGet(t testing.TB, hp *HTTPProxy, backend string) *httptest.ResponseRecorder {
+	target, err := url.Parse(backend)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	r.URL.Scheme = target.Scheme
+	r.URL.Host = target.Host
+	r.Host = target.Host
+	w := httptest.NewRecorder()
+	hp.ServeHTTP(w, r)
+	return w
+}
+
+func TestHTTPProxy_ReusesConnections(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	server, conns := newCountingBackend(t, false, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	hp := NewHTTPProxy(NewTransport(DefaultTransportConfig()))
+
+	for range 10 {
+		w := proxyGet(t, hp, server.URL)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "ok", w.Body.String())
+		assert.Equal(t, "OpenSandbox-ingress", w.Header().Get(ReverseProxyServerPowerBy))
+	}
+	assert.Equal(t, int32(1), conns.Load(), "sequential requests share one upstream connection")
+}
+
+func TestHTTPProxy_H2C(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	server, conns := newCountingBackend(t, true, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})
+	cfg := DefaultTransportConfig()
+	cfg.H2C = true
+	hp := NewHTTPProxy(NewTransport(cfg))
+
+	for range 3 {
+		w := proxyGet(t, hp, server.URL)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "HTTP/2.0", w.Body.String())
+	}
+	assert.Equal(t, int32(1), conns.Load())
+}
+
+func TestHTTPProxy_UpstreamErrors(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	slow, _ := newCountingBackend(t, false, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	cfg := DefaultTransportConfig()
+	cfg.ResponseHeaderTimeout = 50 * time.Millisecond
+	hp := NewHTTPProxy(NewTransport(cfg))
+	assert.Equal(t, http.StatusGatewayTimeout, proxyGet(t, hp, slow.URL).Code)
+
+	// Nothing listens on a closed server's address.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	assert.Equal(t, http.StatusBadGateway, proxyGet(t, hp, closed.URL).Code)
+}
+
+func TestHTTPProxy_ForwardedHeaders(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	var got http.Header
+	server, _ := newCountingBackend(t, false, func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	})
+	hp := NewHTTPProxy(nil)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	r.URL.Scheme = target.Scheme
+	r.URL.Host = target.Host
+	r.Host = target.Host
+	r.RemoteAddr = "10.0.0.2:1234"
+	r.Header.Set(XForwardedFor, "10.0.0.1")
+	r.Header.Set(SandboxIngress, "sandbox-8080")
+	hp.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, "10.0.0.1, 10.0.0.2", got.Get(XForwardedFor))
+	assert.Empty(t, got.Get(SandboxIngress))
+}
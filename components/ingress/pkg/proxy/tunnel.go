@@ -0,0 +1,202 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/telemetry"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+const (
+	// TunnelUpgradeProtocol is the Upgrade token of a request asking for a raw TCP tunnel to the
+	// sandbox port it is routed to. Unlike CONNECT, such a request can be routed in uri mode.
+	TunnelUpgradeProtocol = "opensandbox-tcp"
+
+	protocolTCP = "tcp"
+
+	defaultTunnelIdleTimeout = 5 * time.Minute
+	tunnelBufferSize         = 32 * 1024
+)
+
+// TunnelConfig configures raw TCP tunnels to sandbox ports.
+type TunnelConfig struct {
+	// IdleTimeout closes a tunnel after no bytes went either way for this long. Zero means no
+	// limit.
+	IdleTimeout time.Duration
+}
+
+// DefaultTunnelConfig returns the tunnel settings used when none are given.
+func DefaultTunnelConfig() TunnelConfig {
+	return TunnelConfig{IdleTimeout: defaultTunnelIdleTimeout}
+}
+
+// WithTCPTunnel enables raw TCP tunnels, opened by CONNECT or by an upgrade to
+// TunnelUpgradeProtocol. Tunnels are disabled by default.
+func WithTCPTunnel(cfg TunnelConfig) Option {
+	return func(p *Proxy) {
+		p.tunnel = &cfg
+	}
+}
+
+func (p *Proxy) isTunnelRequest(r *http.Request) bool {
+	if p.tunnel == nil {
+		return false
+	}
+	if r.Method == http.MethodConnect {
+		return true
+	}
+	return r.Method == http.MethodGet &&
+		strings.EqualFold(r.Header.Get(HopByHopUpgrade), TunnelUpgradeProtocol) &&
+		headerHasToken(r.Header, HopByHopConnection, "upgrade")
+}
+
+// serveTunnel connects the client to targetHost and copies bytes both ways until either side
+// closes or the tunnel idles out.
+func (p *Proxy) serveTunnel(w http.ResponseWriter, r *http.Request, targetHost string) {
+	stats := requestStatsFrom(r.Context())
+	dialer := &net.Dialer{
+		Timeout:   p.transportConfig.DialTimeout,
+		KeepAlive: defaultKeepAlive,
+	}
+	dialStart := time.Now()
+	upstream, err := dialer.DialContext(r.Context(), "tcp", targetHost)
+	if err != nil {
+		stats.setUpstreamError(err)
+		status := http.StatusBadGateway
+		if upstreamErrorReason(err) == upstreamErrorTimeout {
+			status = http.StatusGatewayTimeout
+		}
+		Logger.With(
+			slogger.Field{Key: "error", Value: err},
+			slogger.Field{Key: "target", Value: targetHost},
+		).Errorf("TCPTunnel: couldn't dial to sandbox")
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	defer upstream.Close()
+	stats.setUpstreamDuration(time.Since(dialStart))
+
+	client, buffered, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		// HTTP/2 connections cannot be hijacked.
+		http.Error(w, "OpenSandbox Ingress: TCP tunnel requires HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	defer client.Close()
+	_ = client.SetDeadline(time.Time{})
+
+	if r.Method == http.MethodConnect {
+		stats.setStatus(http.StatusOK)
+		_, err = io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n")
+	} else {
+		stats.setStatus(http.StatusSwitchingProtocols)
+		_, err = io.WriteString(client, "HTTP/1.1 101 Switching Protocols\r\n"+
+			"Connection: Upgrade\r\nUpgrade: "+TunnelUpgradeProtocol+"\r\n\r\n")
+	}
+	if err != nil {
+		return
+	}
+	// Bytes the client sent right after the request are already read into the buffer.
+	if n := buffered.Reader.Buffered(); n > 0 {
+		pending, _ := buffered.Reader.Peek(n)
+		if _, err := upstream.Write(pending); err != nil {
+			return
+		}
+		stats.addBytesIn(n)
+	}
+
+	if stats != nil {
+		telemetry.AddActiveTunnel(r.Context(), stats.attrs, 1)
+		defer telemetry.AddActiveTunnel(r.Context(), stats.attrs, -1)
+	}
+
+	var lastActive atomic.Int64
+	lastActive.Store(time.Now().UnixNano())
+	idle := p.tunnel.IdleTimeout
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		pipeTunnel(upstream, client, idle, &lastActive, stats.addBytesIn)
+	}()
+	go func() {
+		defer wg.Done()
+		pipeTunnel(client, upstream, idle, &lastActive, stats.addBytesOut)
+	}()
+	wg.Wait()
+}
+
+// pipeTunnel copies src to dst. When src ends, the end is passed on as a half close so the other
+// direction can finish; on an error or once both directions idled out, both connections close.
+func pipeTunnel(dst, src net.Conn, idle time.Duration, lastActive *atomic.Int64, count func(int)) {
+	buf := make([]byte, tunnelBufferSize)
+	for {
+		if idle > 0 {
+			_ = src.SetReadDeadline(time.Now().Add(idle))
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			lastActive.Store(time.Now().UnixNano())
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				closeBoth(dst, src)
+				return
+			}
+			count(n)
+		}
+		if err == nil {
+			continue
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() &&
+			time.Since(time.Unix(0, lastActive.Load())) < idle {
+			// Only this direction is quiet.
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+				_ = cw.CloseWrite()
+				return
+			}
+		}
+		closeBoth(dst, src)
+		return
+	}
+}
+
+func closeBoth(a, b net.Conn) {
+	_ = a.Close()
+	_ = b.Close()
+}
+
+// headerHasToken reports whether the comma-separated values of header contain token.
+func headerHasToken(h http.Header, header, token string) bool {
+	for _, v := range h.Values(header) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/signature"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+// startTCPEcho starts a TCP server that upper-cases what it reads until EOF, then closes.
+func startTCPEcho(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 1024)
+				for {
+					n, err := conn.Read(buf)
+					if n > 0 {
+						_, _ = conn.Write([]byte(strings.ToUpper(string(buf[:n]))))
+					}
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// openTunnel sends a raw tunnel request with an early payload and returns the connection and
+// the handshake response.
+func openTunnel(t *testing.T, front, request, payload string) (net.Conn, *bufio.Reader, *http.Response) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(front, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = io.WriteString(conn, request+payload)
+	require.NoError(t, err)
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	return conn, br, resp
+}
+
+func TestProxy_TCPTunnel(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	port := startTCPEcho(t)
+	provider := &mockProvider{
+		endpoints:   map[string]string{"test-sandbox": "127.0.0.1", "secure-sandbox": "127.0.0.1"},
+		accessToken: map[string]string{"secure-sandbox": "s3cret"},
+	}
+
+	t.Run("upgrade in uri mode", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithTCPTunnel(DefaultTunnelConfig())))
+		defer front.Close()
+
+		request := fmt.Sprintf("GET /test-sandbox/%d HTTP/1.1\r\nHost: ingress\r\n"+
+			"Connection: Upgrade\r\nUpgrade: %s\r\n\r\n", port, TunnelUpgradeProtocol)
+		conn, br, resp := openTunnel(t, front.URL, request, "early ")
+		require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+		assert.Equal(t, TunnelUpgradeProtocol, resp.Header.Get("Upgrade"))
+
+		_, err := io.WriteString(conn, "bytes")
+		require.NoError(t, err)
+		require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+		out, err := io.ReadAll(br)
+		require.NoError(t, err)
+		assert.Equal(t, "EARLY BYTES", string(out))
+	})
+
+	t.Run("connect in header mode", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeHeader, nil, nil,
+			WithTCPTunnel(DefaultTunnelConfig())))
+		defer front.Close()
+
+		authority := fmt.Sprintf("test-sandbox-%d.sandbox.example.com:443", port)
+		request := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", authority, authority)
+		conn, br, resp := openTunnel(t, front.URL, request, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, err := io.WriteString(conn, "ssh")
+		require.NoError(t, err)
+		require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+		out, err := io.ReadAll(br)
+		require.NoError(t, err)
+		assert.Equal(t, "SSH", string(out))
+	})
+
+	t.Run("secure access", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithTCPTunnel(DefaultTunnelConfig())))
+		defer front.Close()
+
+		request := fmt.Sprintf("GET /secure-sandbox/%d HTTP/1.1\r\nHost: ingress\r\n"+
+			"Connection: Upgrade\r\nUpgrade: %s\r\n", port, TunnelUpgradeProtocol)
+		_, _, resp := openTunnel(t, front.URL, request+"\r\n", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		_, _, resp = openTunnel(t, front.URL,
+			request+signature.OpenSandboxSecureAccessCanonical+": s3cret\r\n\r\n", "")
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	})
+
+	t.Run("idle timeout", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithTCPTunnel(TunnelConfig{IdleTimeout: 100 * time.Millisecond})))
+		defer front.Close()
+
+		request := fmt.Sprintf("GET /test-sandbox/%d HTTP/1.1\r\nHost: ingress\r\n"+
+			"Connection: Upgrade\r\nUpgrade: %s\r\n\r\n", port, TunnelUpgradeProtocol)
+		conn, br, resp := openTunnel(t, front.URL, request, "")
+		require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, err := br.ReadByte()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("unreachable port", func(t *testing.T) {
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithTCPTunnel(DefaultTunnelConfig())))
+		defer front.Close()
+
+		request := fmt.Sprintf("GET /test-sandbox/1 HTTP/1.1\r\nHost: ingress\r\n"+
+			"Connection: Upgrade\r\nUpgrade: %s\r\n\r\n", TunnelUpgradeProtocol)
+		_, _, resp := openTunnel(t, front.URL, request, "")
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+}
+
+func TestProxy_IsTunnelRequest(t *testing.T) {
+	upgrade := httptest.NewRequest(http.MethodGet, "/sandbox/22", nil)
+	upgrade.Header.Set("Connection", "keep-alive, Upgrade")
+	upgrade.Header.Set("Upgrade", TunnelUpgradeProtocol)
+	connect := httptest.NewRequest(http.MethodConnect, "/", nil)
+	websocketUpgrade := httptest.NewRequest(http.MethodGet, "/sandbox/22", nil)
+	websocketUpgrade.Header.Set("Connection", "Upgrade")
+	websocketUpgrade.Header.Set("Upgrade", "websocket")
+
+	enabled := &Proxy{tunnel: &TunnelConfig{}}
+	assert.True(t, enabled.isTunnelRequest(upgrade))
+	assert.True(t, enabled.isTunnelRequest(connect))
+	assert.False(t, enabled.isTunnelRequest(websocketUpgrade))
+
+	disabled := &Proxy{}
+	assert.False(t, disabled.isTunnelRequest(upgrade))
+	assert.False(t, disabled.isTunnelRequest(connect))
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+const (
+	defaultWakeTimeout      = 60 * time.Second
+	defaultWakeMaxWaiting   = 100
+	defaultWakePollInterval = 250 * time.Millisecond
+
+	// wakeRetryInterval spaces the resume requests of waiters, in case the sandbox was still
+	// pausing or the provider cache had not seen the previous request yet.
+	wakeRetryInterval = 5 * time.Second
+)
+
+// WakeConfig configures how requests for a paused sandbox resume it and wait.
+type WakeConfig struct {
+	// Timeout is how long a request waits for the sandbox to become ready.
+	Timeout time.Duration
+	// MaxWaiting caps the requests waiting for one sandbox; more are rejected with 503.
+	MaxWaiting int
+	// PollInterval is how often a waiting request checks whether the sandbox is ready.
+	PollInterval time.Duration
+}
+
+// DefaultWakeConfig returns the wake settings used when none are given.
+func DefaultWakeConfig() WakeConfig {
+	return WakeConfig{
+		Timeout:      defaultWakeTimeout,
+		MaxWaiting:   defaultWakeMaxWaiting,
+		PollInterval: defaultWakePollInterval,
+	}
+}
+
+// WithWakeOnRequest makes a request for a paused sandbox resume it and wait until it is ready,
+// when the sandbox provider implements sandbox.Waker. Otherwise such requests get 503 right away.
+func WithWakeOnRequest(cfg WakeConfig) Option {
+	return func(p *Proxy) {
+		p.wake = &cfg
+	}
+}
+
+// retryAfterError is a rejection that tells the client when to retry.
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// sandboxWaker resumes paused sandboxes and tracks the requests waiting for them.
+type sandboxWaker struct {
+	cfg      WakeConfig
+	provider sandbox.Provider
+	waker    sandbox.Waker
+
+	mu      sync.Mutex
+	waiting map[string]*wakeState
+}
+
+type wakeState struct {
+	waiting  int
+	lastWake time.Time
+}
+
+func newSandboxWaker(cfg WakeConfig, provider sandbox.Provider, waker sandbox.Waker) *sandboxWaker {
+	def := DefaultWakeConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = def.Timeout
+	}
+	if cfg.MaxWaiting <= 0 {
+		cfg.MaxWaiting = def.MaxWaiting
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = def.PollInterval
+	}
+	return &sandboxWaker{
+		cfg:      cfg,
+		provider: provider,
+		waker:    waker,
+		waiting:  make(map[string]*wakeState),
+	}
+}
+
+// wait resumes the sandbox and returns its endpoint once ready, or an error with the HTTP status
+// to answer.
+func (w *sandboxWaker) wait(ctx context.Context, sandboxID string) (*sandbox.EndpointInfo, int, error) {
+	st, ok := w.enter(sandboxID)
+	if !ok {
+		return nil, http.StatusServiceUnavailable, &retryAfterError{
+			err:   fmt.Errorf("%w: %s is resuming and too many requests are waiting", sandbox.ErrSandboxNotReady, sandboxID),
+			after: w.cfg.PollInterval,
+		}
+	}
+	defer w.leave(sandboxID)
+
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if w.dueForWake(st) {
+			if err := w.waker.Wake(ctx, sandboxID); err != nil {
+				return nil, providerErrHTTPStatus(err), err
+			}
+		}
+		info, err := w.provider.GetEndpoint(sandboxID)
+		if err == nil {
+			return info, 0, nil
+		}
+		if !errors.Is(err, sandbox.ErrSandboxNotReady) {
+			return nil, providerErrHTTPStatus(err), err
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, http.StatusServiceUnavailable, &retryAfterError{
+					err:   fmt.Errorf("%w: %s did not resume within %s", sandbox.ErrSandboxNotReady, sandboxID, w.cfg.Timeout),
+					after: time.Second,
+				}
+			}
+			return nil, statusClientClosedRequest, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *sandboxWaker) enter(sandboxID string) (*wakeState, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	st := w.waiting[sandboxID]
+	if st == nil {
+		st = &wakeState{}
+		w.waiting[sandboxID] = st
+		Logger.With(slogger.Field{Key: "sandbox_id", Value: sandboxID}).
+			Infof("ingress: resuming paused sandbox for incoming requests")
+	}
+	if st.waiting >= w.cfg.MaxWaiting {
+		return nil, false
+	}
+	st.waiting++
+	return st, true
+}
+
+func (w *sandboxWaker) leave(sandboxID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	st := w.waiting[sandboxID]
+	st.waiting--
+	if st.waiting == 0 {
+		delete(w.waiting, sandboxID)
+	}
+}
+
+// dueForWake reports whether a waiter should ask for the resume now, so that the waiters of a
+// sandbox ask once per wakeRetryInterval.
+func (w *sandboxWaker) dueForWake(st *wakeState) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if now := time.Now(); now.Sub(st.lastWake) >= wakeRetryInterval {
+		st.lastWake = now
+		return true
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alibaba/opensandbox/ingress/pkg/sandbox"
+	"github.com/alibaba/opensandbox/ingress/pkg/signature"
+	slogger "github.com/alibaba/opensandbox/internal/logger"
+)
+
+// pausingProvider reports sandboxes as paused until the test resumes them, counting wake calls.
+type pausingProvider struct {
+	mockProvider
+
+	mu      sync.Mutex
+	paused  map[string]bool
+	wakes   map[string]int
+	wakeErr error
+}
+
+func newPausingProvider(base mockProvider, paused ...string) *pausingProvider {
+	p := &pausingProvider{mockProvider: base, paused: map[string]bool{}, wakes: map[string]int{}}
+	for _, id := range paused {
+		p.paused[id] = true
+	}
+	return p
+}
+
+func (m *pausingProvider) GetEndpoint(sandboxId string) (*sandbox.EndpointInfo, error) {
+	info, err := m.mockProvider.GetEndpoint(sandboxId)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil || !m.paused[sandboxId] {
+		return info, err
+	}
+	return &sandbox.EndpointInfo{SecureAccessToken: info.SecureAccessToken},
+		fmt.Errorf("%w: %w: %s", sandbox.ErrSandboxNotReady, sandbox.ErrSandboxPaused, sandboxId)
+}
+
+func (m *pausingProvider) Wake(_ context.Context, sandboxId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.wakes[sandboxId]++
+	return m.wakeErr
+}
+
+func (m *pausingProvider) resume(sandboxId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.paused, sandboxId)
+}
+
+func (m *pausingProvider) wakeCount(sandboxId string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.wakes[sandboxId]
+}
+
+func TestProxy_WakeOnRequest(t *testing.T) {
+	Logger = slogger.MustNew(slogger.Config{Level: "error"})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	backendPort := backend.URL[len("http://127.0.0.1:"):]
+
+	base := mockProvider{
+		endpoints:   map[string]string{"paused": "127.0.0.1", "secure": "127.0.0.1"},
+		accessToken: map[string]string{"secure": "s3cret"},
+	}
+	fast := WakeConfig{Timeout: 5 * time.Second, PollInterval: 10 * time.Millisecond}
+	get := func(front, sandboxName string, header http.Header) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, front+"/"+sandboxName+"/"+backendPort+"/", nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	t.Run("holds requests until resumed", func(t *testing.T) {
+		provider := newPausingProvider(base, "paused")
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithWakeOnRequest(fast)))
+		defer front.Close()
+
+		const n = 3
+		statuses := make(chan int, n)
+		for i := 0; i < n; i++ {
+			go func() { statuses <- get(front.URL, "paused", nil).StatusCode }()
+		}
+		require.Eventually(t, func() bool { return provider.wakeCount("paused") == 1 },
+			2*time.Second, 10*time.Millisecond)
+		provider.resume("paused")
+		for i := 0; i < n; i++ {
+			assert.Equal(t, http.StatusOK, <-statuses)
+		}
+		assert.Equal(t, 1, provider.wakeCount("paused"), "waiters share one resume request")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		provider := newPausingProvider(base, "paused")
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil))
+		defer front.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, get(front.URL, "paused", nil).StatusCode)
+		assert.Zero(t, provider.wakeCount("paused"))
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		provider := newPausingProvider(base, "paused")
+		cfg := fast
+		cfg.MaxWaiting = 1
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithWakeOnRequest(cfg)))
+		defer front.Close()
+
+		held := make(chan int)
+		go func() { held <- get(front.URL, "paused", nil).StatusCode }()
+		require.Eventually(t, func() bool { return provider.wakeCount("paused") == 1 },
+			2*time.Second, 10*time.Millisecond)
+		resp := get(front.URL, "paused", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get(RetryAfter))
+
+		provider.resume("paused")
+		assert.Equal(t, http.StatusOK, <-held)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		provider := newPausingProvider(base, "paused")
+		cfg := fast
+		cfg.Timeout = 50 * time.Millisecond
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithWakeOnRequest(cfg)))
+		defer front.Close()
+
+		resp := get(front.URL, "paused", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get(RetryAfter))
+	})
+
+	t.Run("wake failure", func(t *testing.T) {
+		provider := newPausingProvider(base, "paused")
+		provider.wakeErr = fmt.Errorf("%w: last resume failed", sandbox.ErrSandboxNotReady)
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithWakeOnRequest(fast)))
+		defer front.Close()
+
+		resp := get(front.URL, "paused", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(RetryAfter))
+	})
+
+	t.Run("rate limited requests do not wake", func(t *testing.T) {
+		provider := newPausingProvider(base, "paused")
+		cfg := fast
+		cfg.Timeout = 50 * time.Millisecond
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithWakeOnRequest(cfg),
+			WithRateLimit(RateLimitConfig{Default: sandbox.RateLimit{RPS: 0.01, Burst: 1}})))
+		defer front.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, get(front.URL, "paused", nil).StatusCode)
+		require.Equal(t, 1, provider.wakeCount("paused"))
+
+		resp := get(front.URL, "paused", nil)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, 1, provider.wakeCount("paused"), "a rejected request must not wake the sandbox")
+	})
+
+	t.Run("secure sandbox needs access before waking", func(t *testing.T) {
+		provider := newPausingProvider(base, "secure")
+		front := httptest.NewServer(NewProxy(context.Background(), provider, ModeURI, nil, nil,
+			WithWakeOnRequest(fast)))
+		defer front.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, get(front.URL, "secure", nil).StatusCode)
+		assert.Zero(t, provider.wakeCount("secure"))
+
+		provider.resume("secure")
+		authorized := http.Header{signature.OpenSandboxSecureAccessCanonical: {"s3cret"}}
+		assert.Equal(t, http.StatusOK, get(front.URL, "secure", authorized).StatusCode)
+	})
+}
+
+func TestProxy_WakeAccessLog(t *testing.T) {
+	logger := newRecordingLogger("ingress access")
+	Logger = logger
+	provider := newPausingProvider(mockProvider{endpoints: map[string]string{"paused": "127.0.0.1"}}, "paused")
+	p := NewProxy(context.Background(), provider, ModeURI, nil, nil,
+		WithWakeOnRequest(WakeConfig{Timeout: 30 * time.Millisecond, PollInterval: 10 * time.Millisecond}))
+
+	p.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/paused/1/", nil))
+	entries := logger.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, http.StatusServiceUnavailable, entries[0]["status"])
+	assert.Contains(t, entries[0], "wake_ms")
+}
@@ -21,7 +21,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/alibaba/opensandbox/ingress/pkg/telemetry"
 	slogger "github.com/alibaba/opensandbox/internal/logger"
 	"github.com/gorilla/websocket"
 )
@@ -82,6 +84,17 @@ func NewWebSocketProxy(target *url.URL) *WebSocketProxy {
 	return &WebSocketProxy{backend: backend}
 }
 
+// newWebSocketProxy returns a WebSocket reverse proxy to the backend in the URL of each request,
+// so that one proxy serves all sandboxes.
+func newWebSocketProxy(dialer *websocket.Dialer) *WebSocketProxy {
+	backend := func(r *http.Request) *url.URL {
+		// Shallow copy
+		u := *r.URL
+		return &u
+	}
+	return &WebSocketProxy{backend: backend, dialer: dialer}
+}
+
 //nolint:gocognit
 func (w *WebSocketProxy) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	if w.backend == nil {
@@ -159,8 +172,16 @@ func (w *WebSocketProxy) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 
 	// Connect to the backend URL, also pass the headers we get from the requst
 	// together with the Forwarded headers we prepared above.
-	connBackend, resp, err := dialer.Dial(backendURL.String(), requestHeader)
+	stats := requestStatsFrom(r.Context())
+	dialStart := time.Now()
+	connBackend, resp, err := dialer.DialContext(r.Context(), backendURL.String(), requestHeader)
+	if resp != nil {
+		stats.setUpstreamDuration(time.Since(dialStart))
+	}
 	if err != nil {
+		if resp == nil {
+			stats.setUpstreamError(err)
+		}
 		Logger.With(slogger.Field{Key: "error", Value: err}).Errorf("WebSocketProxy: couldn't dial to remote backend")
 		if resp != nil {
 			// If the WebSocket handshake fails, ErrBadHandshake is returned
@@ -199,9 +220,16 @@ func (w *WebSocketProxy) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	}
 	defer connPub.Close()
 
+	// The upgrade hijacked the connection, so the response is not seen by the writer.
+	stats.setStatus(http.StatusSwitchingProtocols)
+	if stats != nil {
+		telemetry.AddActiveWebSocket(r.Context(), stats.attrs, 1)
+		defer telemetry.AddActiveWebSocket(r.Context(), stats.attrs, -1)
+	}
+
 	errClient := make(chan error, 1)
 	errBackend := make(chan error, 1)
-	replicateWebsocketConn := func(dst, src *websocket.Conn, errc chan error) {
+	replicateWebsocketConn := func(dst, src *websocket.Conn, errc chan error, count func(int)) {
 		for {
 			msgType, msg, err := src.ReadMessage()
 			if err != nil {
@@ -220,11 +248,12 @@ func (w *WebSocketProxy) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 				errc <- err
 				break
 			}
+			count(len(msg))
 		}
 	}
 
-	go replicateWebsocketConn(connPub, connBackend, errClient)
-	go replicateWebsocketConn(connBackend, connPub, errBackend)
+	go replicateWebsocketConn(connPub, connBackend, errClient, stats.addBytesOut)
+	go replicateWebsocketConn(connBackend, connPub, errBackend, stats.addBytesIn)
 
 	var message string
 	select {
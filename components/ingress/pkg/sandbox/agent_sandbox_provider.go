@@ -200,14 +200,16 @@ func (a *AgentSandboxProvider) GetEndpoint(sandboxId string) (*EndpointInfo, err
 	if err != nil {
 		return nil, err
 	}
-	accessToken := ""
+	accessToken, rateLimit := "", ""
 	ann := u.GetAnnotations()
 	if ann != nil {
 		accessToken = strings.TrimSpace(ann[AnnotationAccessToken])
+		rateLimit = strings.TrimSpace(ann[AnnotationIngressRateLimit])
 	}
 	return &EndpointInfo{
 		Endpoint:          endpoint,
 		SecureAccessToken: accessToken,
+		RateLimit:         rateLimit,
 	}, nil
 }
 
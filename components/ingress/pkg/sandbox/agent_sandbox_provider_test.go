@@ -111,6 +111,7 @@ func TestAgentSandboxProvider_Start_ContextCancelled(t *testing.T) {
 func TestAgentSandboxProvider_GetEndpoint_ServiceFQDN(t *testing.T) {
 	namespace := "test-ns"
 	obj := buildUnstructuredSandbox("demo", namespace)
+	obj.SetAnnotations(map[string]string{AnnotationIngressRateLimit: "rps=5"})
 	obj.Object["status"] = map[string]any{
 		"serviceFQDN": "sandbox.demo.svc.cluster.local",
 		"conditions": []any{
@@ -148,6 +149,7 @@ func TestAgentSandboxProvider_GetEndpoint_ServiceFQDN(t *testing.T) {
 	endpoint, err := provider.GetEndpoint("demo")
 	assert.NoError(t, err)
 	assert.Equal(t, "sandbox.demo.svc.cluster.local", endpoint.Endpoint)
+	assert.Equal(t, "rps=5", endpoint.RateLimit)
 }
 
 func TestAgentSandboxProvider_GetEndpoint_NotFound(t *testing.T) {
@@ -28,11 +28,13 @@ import (
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 )
 
 type BatchSandboxProvider struct {
+	client          clientset.Interface
 	informerFactory informers.SharedInformerFactory
 	lister          listers.BatchSandboxLister
 	informer        cache.SharedIndexInformer
@@ -68,6 +70,7 @@ func NewBatchSandboxProvider(
 	}
 
 	return &BatchSandboxProvider{
+		client:          clientset,
 		informerFactory: informerFactory,
 		lister:          batchSandboxInformer.Lister(),
 		informer:        batchSandboxInformer.Informer(),
@@ -136,9 +139,18 @@ func (p *BatchSandboxProvider) GetEndpoint(sandboxId string) (*EndpointInfo, err
 	if err != nil {
 		return nil, err
 	}
-	accessToken := ""
+	accessToken, rateLimit := "", ""
 	if batchSandbox.Annotations != nil {
 		accessToken = strings.TrimSpace(batchSandbox.Annotations[AnnotationAccessToken])
+		rateLimit = strings.TrimSpace(batchSandbox.Annotations[AnnotationIngressRateLimit])
+	}
+
+	switch batchSandbox.Status.Phase {
+	case sandboxv1alpha1.BatchSandboxPhasePaused, sandboxv1alpha1.BatchSandboxPhaseResuming:
+		// The access metadata lets callers authorize a request before they wake the sandbox.
+		return &EndpointInfo{SecureAccessToken: accessToken, RateLimit: rateLimit},
+			fmt.Errorf("%w: %w: %s/%s (phase: %s)",
+				ErrSandboxNotReady, ErrSandboxPaused, batchSandbox.Namespace, sandboxId, batchSandbox.Status.Phase)
 	}
 
 	// Check if BatchSandbox is ready
@@ -157,7 +169,43 @@ func (p *BatchSandboxProvider) GetEndpoint(sandboxId string) (*EndpointInfo, err
 	return &EndpointInfo{
 		Endpoint:          endpoints[0],
 		SecureAccessToken: accessToken,
+		RateLimit:         rateLimit,
 	}, nil
 }
 
-var _ Provider = (*BatchSandboxProvider)(nil)
+// resumePatch is the merge patch the server also uses to resume a BatchSandbox.
+var resumePatch = []byte(`{"spec":{"pause":false}}`)
+
+// Wake resumes a paused BatchSandbox by setting spec.pause=false. A resume that failed is not
+// retried, as that needs the server's retry bridge.
+func (p *BatchSandboxProvider) Wake(ctx context.Context, sandboxId string) error {
+	batchSandbox, err := p.findBatchSandbox(sandboxId)
+	if err != nil {
+		return err
+	}
+	if batchSandbox.Status.Phase != sandboxv1alpha1.BatchSandboxPhasePaused {
+		return nil
+	}
+	for _, cond := range batchSandbox.Status.Conditions {
+		if cond.Type == sandboxv1alpha1.BatchSandboxConditionResumeFailed && cond.Status == sandboxv1alpha1.ConditionTrue {
+			return fmt.Errorf("%w: %s/%s: last resume failed: %s",
+				ErrSandboxNotReady, batchSandbox.Namespace, sandboxId, cond.Message)
+		}
+	}
+	if batchSandbox.Spec.Pause != nil && !*batchSandbox.Spec.Pause {
+		// Resume requested, not yet picked up by the controller.
+		return nil
+	}
+
+	_, err = p.client.SandboxV1alpha1().BatchSandboxes(batchSandbox.Namespace).
+		Patch(ctx, batchSandbox.Name, types.MergePatchType, resumePatch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("resume BatchSandbox %s/%s: %w", batchSandbox.Namespace, sandboxId, err)
+	}
+	return nil
+}
+
+var (
+	_ Provider = (*BatchSandboxProvider)(nil)
+	_ Waker    = (*BatchSandboxProvider)(nil)
+)
@@ -132,8 +132,9 @@ func TestBatchSandboxProvider_WithFakeInformer(t *testing.T) {
 				Name:      "secure-sb",
 				Namespace: namespace,
 				Annotations: map[string]string{
-					AnnotationAccessToken:     "opaque",
-					utils.AnnotationEndpoints: `["10.0.0.1"]`,
+					AnnotationAccessToken:      "opaque",
+					AnnotationIngressRateLimit: " rps=5,concurrency=2 ",
+					utils.AnnotationEndpoints:  `["10.0.0.1"]`,
 				},
 			},
 			Spec: sandboxv1alpha1.BatchSandboxSpec{
@@ -150,6 +151,7 @@ func TestBatchSandboxProvider_WithFakeInformer(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "10.0.0.1", info.Endpoint)
 		assert.Equal(t, "opaque", info.SecureAccessToken)
+		assert.Equal(t, "rps=5,concurrency=2", info.RateLimit)
 	})
 }
 
@@ -450,6 +452,84 @@ func TestBatchSandboxProvider_GetEndpoint_AmbiguousAcrossNamespaces(t *testing.T
 	assert.True(t, strings.Contains(err.Error(), "ambiguous sandbox id"))
 }
 
+func TestBatchSandboxProvider_PausedSandbox(t *testing.T) {
+	namespace := "test-namespace"
+	newBatchSandbox := func(name string, phase sandboxv1alpha1.BatchSandboxPhase, pause bool, conditions ...sandboxv1alpha1.BatchSandboxCondition) *sandboxv1alpha1.BatchSandbox {
+		return &sandboxv1alpha1.BatchSandbox{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					utils.AnnotationEndpoints: `["10.0.0.1"]`,
+					AnnotationAccessToken:     "tok",
+				},
+			},
+			Spec:   sandboxv1alpha1.BatchSandboxSpec{Replicas: ptr(int32(1)), Pause: &pause},
+			Status: sandboxv1alpha1.BatchSandboxStatus{Replicas: 1, Phase: phase, Conditions: conditions},
+		}
+	}
+	paused := newBatchSandbox("paused", sandboxv1alpha1.BatchSandboxPhasePaused, true)
+	resuming := newBatchSandbox("resuming", sandboxv1alpha1.BatchSandboxPhaseResuming, false)
+	failed := newBatchSandbox("failed", sandboxv1alpha1.BatchSandboxPhasePaused, true, sandboxv1alpha1.BatchSandboxCondition{
+		Type:    sandboxv1alpha1.BatchSandboxConditionResumeFailed,
+		Status:  sandboxv1alpha1.ConditionTrue,
+		Message: "snapshot not found",
+	})
+
+	// Created through the typed client: seeding NewSimpleClientset would store them under a
+	// guessed resource name that Patch does not find.
+	fakeClient := fakeclientset.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(
+		fakeClient,
+		time.Second*30,
+		informers.WithNamespace(namespace),
+	)
+	batchSandboxInformer := informerFactory.Sandbox().V1alpha1().BatchSandboxes()
+	provider := &BatchSandboxProvider{
+		client:          fakeClient,
+		informerFactory: informerFactory,
+		lister:          batchSandboxInformer.Lister(),
+		informerSynced:  batchSandboxInformer.Informer().HasSynced,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, provider.Start(ctx))
+	for _, bs := range []*sandboxv1alpha1.BatchSandbox{paused, resuming, failed} {
+		_, err := fakeClient.SandboxV1alpha1().BatchSandboxes(namespace).Create(ctx, bs, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, batchSandboxInformer.Informer().GetStore().Add(bs))
+	}
+
+	info, err := provider.GetEndpoint("paused")
+	assert.ErrorIs(t, err, ErrSandboxPaused)
+	assert.ErrorIs(t, err, ErrSandboxNotReady)
+	if assert.NotNil(t, info) {
+		assert.Empty(t, info.Endpoint)
+		assert.Equal(t, "tok", info.SecureAccessToken, "access metadata is kept to authorize the wake")
+	}
+	_, err = provider.GetEndpoint("resuming")
+	assert.ErrorIs(t, err, ErrSandboxPaused)
+
+	assert.NoError(t, provider.Wake(ctx, "paused"))
+	updated, err := fakeClient.SandboxV1alpha1().BatchSandboxes(namespace).Get(ctx, "paused", metav1.GetOptions{})
+	assert.NoError(t, err)
+	if assert.NotNil(t, updated.Spec.Pause) {
+		assert.False(t, *updated.Spec.Pause)
+	}
+
+	fakeClient.ClearActions()
+	assert.NoError(t, provider.Wake(ctx, "resuming"))
+	assert.Empty(t, fakeClient.Actions(), "a resuming sandbox is not patched again")
+
+	err = provider.Wake(ctx, "failed")
+	assert.ErrorIs(t, err, ErrSandboxNotReady)
+	assert.Contains(t, err.Error(), "snapshot not found")
+	assert.Empty(t, fakeClient.Actions())
+
+	assert.ErrorIs(t, provider.Wake(ctx, "missing"), ErrSandboxNotFound)
+}
+
 // ptr is a helper function to create int32 pointer
 func ptr(i int32) *int32 {
 	return &i
@@ -8,6 +8,10 @@ type EndpointInfo struct {
 	// SecureAccessToken is the trimmed annotation opensandbox.io/secure-access-token value.
 	// Empty means secure access is not required.
 	SecureAccessToken string
+
+	// RateLimit is the trimmed annotation opensandbox.io/ingress-rate-limit value, parsed by the
+	// ingress with ParseRateLimit. Empty means the ingress defaults apply.
+	RateLimit string
 }
 
 func (i EndpointInfo) AccessVerificationRequired() bool {
@@ -24,22 +24,44 @@ import (
 type DefaultProviderFactory struct {
 	config       *rest.Config
 	resyncPeriod time.Duration
+	fileConfig   FileProviderConfig
 }
 
-func NewProviderFactory(config *rest.Config, resyncPeriod time.Duration) *DefaultProviderFactory {
-	return &DefaultProviderFactory{
+// FactoryOption customizes a DefaultProviderFactory.
+type FactoryOption func(*DefaultProviderFactory)
+
+// WithFileProviderConfig sets the configuration of the file provider.
+func WithFileProviderConfig(cfg FileProviderConfig) FactoryOption {
+	return func(f *DefaultProviderFactory) {
+		f.fileConfig = cfg
+	}
+}
+
+// NewProviderFactory creates a factory. config may be nil when only providers that do not
+// require Kubernetes are created.
+func NewProviderFactory(config *rest.Config, resyncPeriod time.Duration, opts ...FactoryOption) *DefaultProviderFactory {
+	f := &DefaultProviderFactory{
 		config:       config,
 		resyncPeriod: resyncPeriod,
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // CreateProvider creates a Provider instance based on the provider type
 func (f *DefaultProviderFactory) CreateProvider(providerType ProviderType) (Provider, error) {
+	if providerType.RequiresKubernetes() && f.config == nil {
+		return nil, fmt.Errorf("provider type %s requires a Kubernetes config", providerType)
+	}
 	switch providerType {
 	case ProviderTypeBatchSandbox:
 		return NewBatchSandboxProvider(f.config, f.resyncPeriod), nil
 	case ProviderTypeAgentSandbox:
 		return NewAgentSandboxProvider(f.config, f.resyncPeriod), nil
+	case ProviderTypeFile:
+		return NewFileProvider(f.fileConfig), nil
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %s", providerType)
 	}
@@ -0,0 +1,240 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/alibaba/opensandbox/internal/logger"
+)
+
+const defaultFileReloadInterval = 2 * time.Second
+
+// FileSandbox is one sandbox entry of the file provider.
+type FileSandbox struct {
+	// Endpoint is the upstream IP or host name of the sandbox. Empty means not ready.
+	Endpoint string `json:"endpoint"`
+	// SecureAccessToken has the meaning of the opensandbox.io/secure-access-token annotation.
+	SecureAccessToken string `json:"secureAccessToken,omitempty"`
+	// RateLimit has the meaning of the opensandbox.io/ingress-rate-limit annotation.
+	RateLimit string `json:"rateLimit,omitempty"`
+}
+
+// FileSandboxes is the document read by the file provider, in YAML or JSON:
+//
+//	sandboxes:
+//	  my-sandbox:
+//	    endpoint: 172.17.0.5
+type FileSandboxes struct {
+	Sandboxes map[string]FileSandbox `json:"sandboxes"`
+}
+
+// FileProviderConfig configures the file provider.
+type FileProviderConfig struct {
+	// Path of the sandbox document. Empty means sandboxes are only pushed over HTTP.
+	Path string
+	// ReloadInterval is how often the file is checked for changes.
+	ReloadInterval time.Duration
+	// Logger reports reload failures. Optional.
+	Logger logger.Logger
+}
+
+// FileProvider serves sandbox endpoints from a static file, reloaded when it changes, and from
+// entries pushed through PushHandler. It needs no Kubernetes API, e.g. for the Docker runtime or
+// local development.
+type FileProvider struct {
+	cfg FileProviderConfig
+
+	mu       sync.RWMutex
+	fromFile map[string]FileSandbox
+	// pushed entries take precedence over the file, except for its secure access tokens, and
+	// survive reloads.
+	pushed map[string]FileSandbox
+	// content is the last file content loaded, to skip reloads of an unchanged file.
+	content []byte
+}
+
+func NewFileProvider(cfg FileProviderConfig) *FileProvider {
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = defaultFileReloadInterval
+	}
+	return &FileProvider{
+		cfg:      cfg,
+		fromFile: map[string]FileSandbox{},
+		pushed:   map[string]FileSandbox{},
+	}
+}
+
+// Start loads the file and keeps reloading it until ctx is done. A file that cannot be loaded at
+// start is an error; later failures keep the last good content.
+func (p *FileProvider) Start(ctx context.Context) error {
+	if p.cfg.Path == "" {
+		return nil
+	}
+	if err := p.reload(); err != nil {
+		return err
+	}
+	go p.watch(ctx)
+	return nil
+}
+
+func (p *FileProvider) watch(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.reload(); err != nil && p.cfg.Logger != nil {
+				p.cfg.Logger.With(
+					logger.Field{Key: "path", Value: p.cfg.Path},
+					logger.Field{Key: "error", Value: err},
+				).Errorf("file provider: reload failed, keeping the previous sandboxes")
+			}
+		}
+	}
+}
+
+// reload reads the file, replacing the file entries if its content changed.
+func (p *FileProvider) reload() error {
+	content, err := os.ReadFile(p.cfg.Path)
+	if err != nil {
+		return fmt.Errorf("read sandbox file: %w", err)
+	}
+	p.mu.RLock()
+	unchanged := p.content != nil && bytes.Equal(p.content, content)
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	sandboxes, err := ParseFileSandboxes(content)
+	if err != nil {
+		return fmt.Errorf("parse sandbox file %s: %w", p.cfg.Path, err)
+	}
+	p.mu.Lock()
+	p.fromFile = sandboxes
+	p.content = content
+	p.mu.Unlock()
+	return nil
+}
+
+// ParseFileSandboxes parses a YAML or JSON sandbox document.
+func ParseFileSandboxes(content []byte) (map[string]FileSandbox, error) {
+	var doc FileSandboxes
+	if err := yaml.UnmarshalStrict(content, &doc); err != nil {
+		return nil, err
+	}
+	sandboxes := make(map[string]FileSandbox, len(doc.Sandboxes))
+	for id, sbx := range doc.Sandboxes {
+		if strings.TrimSpace(id) == "" {
+			return nil, errors.New("empty sandbox id")
+		}
+		sandboxes[id] = sbx.trimmed()
+	}
+	return sandboxes, nil
+}
+
+func (s FileSandbox) trimmed() FileSandbox {
+	return FileSandbox{
+		Endpoint:          strings.TrimSpace(s.Endpoint),
+		SecureAccessToken: strings.TrimSpace(s.SecureAccessToken),
+		RateLimit:         strings.TrimSpace(s.RateLimit),
+	}
+}
+
+// GetEndpoint returns the endpoint of a pushed or file sandbox.
+func (p *FileProvider) GetEndpoint(sandboxId string) (*EndpointInfo, error) {
+	p.mu.RLock()
+	sbx, ok := p.lookupLocked(sandboxId)
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSandboxNotFound, sandboxId)
+	}
+	if sbx.Endpoint == "" {
+		return nil, fmt.Errorf("%w: %s has no endpoint", ErrSandboxNotReady, sandboxId)
+	}
+	return &EndpointInfo{
+		Endpoint:          sbx.Endpoint,
+		SecureAccessToken: sbx.SecureAccessToken,
+		RateLimit:         sbx.RateLimit,
+	}, nil
+}
+
+// Put adds or replaces a pushed sandbox.
+func (p *FileProvider) Put(sandboxId string, sbx FileSandbox) {
+	p.mu.Lock()
+	p.pushed[sandboxId] = sbx.trimmed()
+	p.mu.Unlock()
+}
+
+// Delete removes a pushed sandbox, reporting whether it existed. Sandboxes from the file are not
+// affected.
+func (p *FileProvider) Delete(sandboxId string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.pushed[sandboxId]
+	delete(p.pushed, sandboxId)
+	return ok
+}
+
+// Sandboxes returns all sandboxes, pushed entries replacing file entries of the same id as in
+// GetEndpoint.
+func (p *FileProvider) Sandboxes() map[string]FileSandbox {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	all := make(map[string]FileSandbox, len(p.fromFile)+len(p.pushed))
+	for id := range p.fromFile {
+		all[id], _ = p.lookupLocked(id)
+	}
+	for id := range p.pushed {
+		all[id], _ = p.lookupLocked(id)
+	}
+	return all
+}
+
+// lookupLocked returns the pushed or file entry of a sandbox. A pushed entry replaces the file
+// entry, except for a secure access token defined in the file, which a push cannot override or
+// clear. The caller holds p.mu.
+func (p *FileProvider) lookupLocked(sandboxId string) (FileSandbox, bool) {
+	fromFile, inFile := p.fromFile[sandboxId]
+	sbx, ok := p.pushed[sandboxId]
+	if !ok {
+		return fromFile, inFile
+	}
+	if inFile && fromFile.SecureAccessToken != "" {
+		sbx.SecureAccessToken = fromFile.SecureAccessToken
+	}
+	return sbx, true
+}
+
+// fileAccessToken returns the secure access token the file defines for a sandbox.
+func (p *FileProvider) fileAccessToken(sandboxId string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.fromFile[sandboxId].SecureAccessToken
+}
+
+var _ Provider = (*FileProvider)(nil)
@@ -0,0 +1,128 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	maxPushBodyBytes = 64 * 1024
+
+	pushReadHeaderTimeout = 5 * time.Second
+	pushReadTimeout       = 10 * time.Second
+	pushWriteTimeout      = 10 * time.Second
+)
+
+// PushServer returns the server of the push API on addr. Without a token, addr must be a
+// loopback address, since anyone who can reach the API can re-route sandboxes.
+func (p *FileProvider) PushServer(addr, token string) (*http.Server, error) {
+	if token == "" && !isLoopbackAddr(addr) {
+		return nil, fmt.Errorf("file provider push API on non-loopback address %q requires a token", addr)
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           p.PushHandler(token),
+		ReadHeaderTimeout: pushReadHeaderTimeout,
+		ReadTimeout:       pushReadTimeout,
+		WriteTimeout:      pushWriteTimeout,
+	}, nil
+}
+
+// isLoopbackAddr reports whether a listen address only binds loopback interfaces.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// PushHandler serves the HTTP API that adds and removes sandboxes of the file provider at
+// runtime:
+//
+//	GET    /sandboxes       all sandboxes, as a FileSandboxes document without access tokens
+//	PUT    /sandboxes/{id}  add or replace a sandbox from a FileSandbox body
+//	DELETE /sandboxes/{id}  remove a pushed sandbox
+//
+// A PUT keeps the secure access token the file defines for the sandbox and is rejected if it
+// carries a different one. A non-empty token must be sent as "Authorization: Bearer <token>".
+func (p *FileProvider) PushHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sandboxes", func(w http.ResponseWriter, _ *http.Request) {
+		sandboxes := p.Sandboxes()
+		for id, sbx := range sandboxes {
+			sbx.SecureAccessToken = ""
+			sandboxes[id] = sbx
+		}
+		writeJSON(w, http.StatusOK, FileSandboxes{Sandboxes: sandboxes})
+	})
+	mux.HandleFunc("PUT /sandboxes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var sbx FileSandbox
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushBodyBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&sbx); err != nil {
+			http.Error(w, "invalid sandbox: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(sbx.Endpoint) == "" {
+			http.Error(w, "invalid sandbox: endpoint is required", http.StatusBadRequest)
+			return
+		}
+		pushedToken := strings.TrimSpace(sbx.SecureAccessToken)
+		if fileToken := p.fileAccessToken(r.PathValue("id")); fileToken != "" && pushedToken != "" &&
+			subtle.ConstantTimeCompare([]byte(pushedToken), []byte(fileToken)) != 1 {
+			http.Error(w, "secureAccessToken is defined by the sandbox file", http.StatusConflict)
+			return
+		}
+		p.Put(r.PathValue("id"), sbx)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /sandboxes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !p.Delete(r.PathValue("id")) {
+			http.Error(w, "sandbox not pushed", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if token == "" {
+		return mux
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
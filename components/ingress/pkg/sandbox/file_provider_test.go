@@ -0,0 +1,227 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSandboxFile(t *testing.T, path, content string) {
+	t.Helper()
+	// Write and rename, as a ConfigMap update or an editor does.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestParseFileSandboxes(t *testing.T) {
+	yamlDoc := `
+sandboxes:
+  web:
+    endpoint: " 172.17.0.5 "
+    secureAccessToken: tok
+    rateLimit: rps=10
+  starting: {}
+`
+	sandboxes, err := ParseFileSandboxes([]byte(yamlDoc))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]FileSandbox{
+		"web":      {Endpoint: "172.17.0.5", SecureAccessToken: "tok", RateLimit: "rps=10"},
+		"starting": {},
+	}, sandboxes)
+
+	jsonDoc := `{"sandboxes": {"web": {"endpoint": "10.0.0.1"}}}`
+	sandboxes, err = ParseFileSandboxes([]byte(jsonDoc))
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", sandboxes["web"].Endpoint)
+
+	_, err = ParseFileSandboxes([]byte("sandboxes:\n  web:\n    endpiont: 10.0.0.1\n"))
+	assert.Error(t, err, "unknown fields are rejected")
+	_, err = ParseFileSandboxes([]byte("sandboxes: [web]"))
+	assert.Error(t, err)
+}
+
+func TestFileProvider_GetEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sandboxes.yaml")
+	writeSandboxFile(t, path, `
+sandboxes:
+  web:
+    endpoint: 172.17.0.5
+    secureAccessToken: tok
+  starting:
+    endpoint: ""
+`)
+	provider := NewFileProvider(FileProviderConfig{Path: path})
+	require.NoError(t, provider.Start(context.Background()))
+
+	info, err := provider.GetEndpoint("web")
+	require.NoError(t, err)
+	assert.Equal(t, "172.17.0.5", info.Endpoint)
+	assert.True(t, info.AccessVerificationRequired())
+
+	_, err = provider.GetEndpoint("starting")
+	assert.ErrorIs(t, err, ErrSandboxNotReady)
+	_, err = provider.GetEndpoint("missing")
+	assert.ErrorIs(t, err, ErrSandboxNotFound)
+}
+
+func TestFileProvider_Start_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	provider := NewFileProvider(FileProviderConfig{Path: filepath.Join(dir, "missing.yaml")})
+	assert.Error(t, provider.Start(context.Background()))
+
+	path := filepath.Join(dir, "bad.yaml")
+	writeSandboxFile(t, path, "sandboxes: [")
+	provider = NewFileProvider(FileProviderConfig{Path: path})
+	assert.Error(t, provider.Start(context.Background()))
+}
+
+func TestFileProvider_HotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sandboxes.yaml")
+	writeSandboxFile(t, path, "sandboxes:\n  web:\n    endpoint: 10.0.0.1\n")
+	provider := NewFileProvider(FileProviderConfig{Path: path, ReloadInterval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, provider.Start(ctx))
+
+	endpointOf := func(id string) string {
+		info, err := provider.GetEndpoint(id)
+		if err != nil {
+			return err.Error()
+		}
+		return info.Endpoint
+	}
+
+	writeSandboxFile(t, path, "sandboxes:\n  web:\n    endpoint: 10.0.0.2\n  api:\n    endpoint: 10.0.0.3\n")
+	assert.Eventually(t, func() bool {
+		return endpointOf("web") == "10.0.0.2" && endpointOf("api") == "10.0.0.3"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// A broken edit keeps the last good content.
+	writeSandboxFile(t, path, "sandboxes: [")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "10.0.0.2", endpointOf("web"))
+
+	writeSandboxFile(t, path, "sandboxes: {}\n")
+	assert.Eventually(t, func() bool {
+		_, err := provider.GetEndpoint("web")
+		return err != nil
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestFileProvider_PushHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sandboxes.yaml")
+	writeSandboxFile(t, path, "sandboxes:\n  web:\n    endpoint: 10.0.0.1\n    secureAccessToken: t0k\n")
+	provider := NewFileProvider(FileProviderConfig{Path: path})
+	require.NoError(t, provider.Start(context.Background()))
+	srv := httptest.NewServer(provider.PushHandler("s3cret"))
+	defer srv.Close()
+
+	do := func(method, path, body string, auth bool) *http.Response {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if auth {
+			req.Header.Set("Authorization", "Bearer s3cret")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodPut, "/sandboxes/api", `{"endpoint":"10.0.0.9"}`, false).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/sandboxes/api", `{"endpoint":""}`, true).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/sandboxes/api", `{"endpiont":"10.0.0.9"}`, true).StatusCode)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodPut, "/sandboxes/api", `{"endpoint":"10.0.0.9","rateLimit":"rps=1"}`, true).StatusCode)
+	info, err := provider.GetEndpoint("api")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.9", info.Endpoint)
+	assert.Equal(t, "rps=1", info.RateLimit)
+
+	// Pushed entries override the file, but not its secure access token.
+	assert.Equal(t, http.StatusConflict, do(http.MethodPut, "/sandboxes/web", `{"endpoint":"10.0.0.8","secureAccessToken":"other"}`, true).StatusCode)
+	assert.Equal(t, http.StatusNoContent, do(http.MethodPut, "/sandboxes/web", `{"endpoint":"10.0.0.8"}`, true).StatusCode)
+	info, err = provider.GetEndpoint("web")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.8", info.Endpoint)
+	assert.Equal(t, "t0k", info.SecureAccessToken)
+	resp := do(http.MethodGet, "/sandboxes", "", true)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var doc FileSandboxes
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, map[string]FileSandbox{
+		"web": {Endpoint: "10.0.0.8"},
+		"api": {Endpoint: "10.0.0.9", RateLimit: "rps=1"},
+	}, doc.Sandboxes, "secure access tokens are not listed")
+
+	// Deleting the pushed entry uncovers the file entry again.
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/sandboxes/web", "", true).StatusCode)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/sandboxes/web", "", true).StatusCode)
+	info, err = provider.GetEndpoint("web")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", info.Endpoint)
+}
+
+func TestFileProvider_PushServer(t *testing.T) {
+	provider := NewFileProvider(FileProviderConfig{})
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:28889": true,
+		"localhost:28889": true,
+		"[::1]:28889":     true,
+		":28889":          false,
+		"0.0.0.0:28889":   false,
+		"10.0.0.1:28889":  false,
+	} {
+		server, err := provider.PushServer(addr, "")
+		if !ok {
+			assert.Error(t, err, addr)
+			continue
+		}
+		require.NoError(t, err, addr)
+		assert.NotZero(t, server.ReadHeaderTimeout)
+		assert.NotZero(t, server.ReadTimeout)
+		assert.NotZero(t, server.WriteTimeout)
+	}
+
+	// A token allows any address.
+	server, err := provider.PushServer(":28889", "s3cret")
+	require.NoError(t, err)
+	assert.Equal(t, ":28889", server.Addr)
+}
+
+func TestProviderFactory_File(t *testing.T) {
+	factory := NewProviderFactory(nil, time.Minute, WithFileProviderConfig(FileProviderConfig{}))
+	provider, err := factory.CreateProvider(ProviderTypeFile)
+	require.NoError(t, err)
+	assert.IsType(t, &FileProvider{}, provider)
+	assert.NoError(t, provider.Start(context.Background()), "push-only provider needs no file")
+
+	_, err = factory.CreateProvider(ProviderTypeBatchSandbox)
+	assert.ErrorContains(t, err, "requires a Kubernetes config")
+	_, err = factory.CreateProvider("unknown")
+	assert.ErrorContains(t, err, "unsupported provider type")
+}
@@ -24,15 +24,25 @@ type ProviderType string
 const (
 	ProviderTypeBatchSandbox ProviderType = "batchsandbox"
 	ProviderTypeAgentSandbox ProviderType = "agent-sandbox"
+	ProviderTypeFile         ProviderType = "file"
 
 	sandboxNameIndex string = "sandbox-name"
 
 	// AnnotationAccessToken marks a sandbox that requires signed ingress routes when non-empty.
 	AnnotationAccessToken = "opensandbox.io/secure-access-token"
+
+	// AnnotationIngressRateLimit overrides the ingress rate limit defaults for a sandbox, e.g.
+	// "rps=10,burst=20,concurrency=50". See ParseRateLimit.
+	AnnotationIngressRateLimit = "opensandbox.io/ingress-rate-limit"
 )
 
 func (tpy ProviderType) String() string { return string(tpy) }
 
+// RequiresKubernetes reports whether the provider watches the Kubernetes API.
+func (tpy ProviderType) RequiresKubernetes() bool {
+	return tpy == ProviderTypeBatchSandbox || tpy == ProviderTypeAgentSandbox
+}
+
 var (
 	// ErrSandboxNotFound indicates the sandbox resource does not exist
 	ErrSandboxNotFound = errors.New("sandbox not found")
@@ -40,6 +50,10 @@ var (
 	// ErrSandboxNotReady indicates the sandbox exists but is not ready
 	// This includes: not enough ready replicas, missing endpoints, invalid configuration
 	ErrSandboxNotReady = errors.New("sandbox not ready")
+
+	// ErrSandboxPaused indicates the sandbox is paused or resuming. It always comes wrapped
+	// together with ErrSandboxNotReady.
+	ErrSandboxPaused = errors.New("sandbox paused")
 )
 
 // Provider defines the interface for sandbox resource providers
@@ -49,6 +63,7 @@ type Provider interface {
 	// Providers run in global-watch mode across all namespaces.
 	// Returns the first available endpoint from provider status/annotations.
 	// Returns error if sandbox not found or endpoint unavailable.
+	// With ErrSandboxPaused, the returned info may carry the access metadata without an Endpoint.
 	// Note: This is a local cache query, no network I/O involved
 	GetEndpoint(sandboxId string) (*EndpointInfo, error)
 
@@ -58,6 +73,13 @@ type Provider interface {
 	Start(ctx context.Context) error
 }
 
+// Waker is implemented by providers that can resume a sandbox reported with ErrSandboxPaused.
+type Waker interface {
+	// Wake asks for the sandbox to be resumed. It does nothing when a resume is already
+	// requested or running; the endpoint becomes available through GetEndpoint once it is ready.
+	Wake(ctx context.Context, sandboxId string) error
+}
+
 // ProviderFactory creates a Provider instance based on the provider type
 type ProviderFactory interface {
 	CreateProvider(providerType ProviderType) (Provider, error)
@@ -0,0 +1,84 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RateLimit bounds the ingress traffic to a sandbox. Zero fields mean no limit.
+type RateLimit struct {
+	// RPS is the sustained number of requests per second.
+	RPS float64
+	// Burst is how many requests may arrive at once above RPS.
+	Burst int
+	// MaxConcurrent caps open requests, WebSockets and tunnels.
+	MaxConcurrent int
+}
+
+// Unlimited reports whether r sets no limit at all.
+func (r RateLimit) Unlimited() bool {
+	return r.RPS <= 0 && r.MaxConcurrent <= 0
+}
+
+// EffectiveBurst returns Burst, or RPS rounded up when no burst is set.
+func (r RateLimit) EffectiveBurst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return max(1, int(math.Ceil(r.RPS)))
+}
+
+// ParseRateLimit applies an opensandbox.io/ingress-rate-limit annotation value such as
+// "rps=10,burst=20,concurrency=50" to defaults. Keys left out keep their default, and 0 lifts
+// the limit.
+func ParseRateLimit(spec string, defaults RateLimit) (RateLimit, error) {
+	out := defaults
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return defaults, fmt.Errorf("invalid rate limit %q: expected key=value", part)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "rps":
+			rps, err := strconv.ParseFloat(value, 64)
+			if err != nil || rps < 0 || math.IsInf(rps, 0) || math.IsNaN(rps) {
+				return defaults, fmt.Errorf("invalid rate limit rps %q", value)
+			}
+			out.RPS = rps
+		case "burst", "concurrency":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return defaults, fmt.Errorf("invalid rate limit %s %q", key, value)
+			}
+			if key == "burst" {
+				out.Burst = n
+			} else {
+				out.MaxConcurrent = n
+			}
+		default:
+			return defaults, fmt.Errorf("unknown rate limit key %q", key)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	defaults := RateLimit{RPS: 100, Burst: 200, MaxConcurrent: 10}
+	tests := []struct {
+		name    string
+		spec    string
+		want    RateLimit
+		wantErr bool
+	}{
+		{name: "empty keeps defaults", spec: "", want: defaults},
+		{name: "all keys", spec: "rps=2.5, burst=5, concurrency=3", want: RateLimit{RPS: 2.5, Burst: 5, MaxConcurrent: 3}},
+		{name: "partial override", spec: "concurrency=1", want: RateLimit{RPS: 100, Burst: 200, MaxConcurrent: 1}},
+		{name: "zero lifts limit", spec: "rps=0,concurrency=0", want: RateLimit{Burst: 200}},
+		{name: "missing value", spec: "rps", wantErr: true},
+		{name: "negative", spec: "burst=-1", wantErr: true},
+		{name: "not a number", spec: "rps=fast", wantErr: true},
+		{name: "unknown key", spec: "rpm=10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRateLimit(tt.spec, defaults)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, defaults, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRateLimit_EffectiveBurst(t *testing.T) {
+	assert.Equal(t, 7, RateLimit{RPS: 1, Burst: 7}.EffectiveBurst())
+	assert.Equal(t, 3, RateLimit{RPS: 2.5}.EffectiveBurst())
+	assert.Equal(t, 1, RateLimit{RPS: 0.1}.EffectiveBurst())
+	assert.True(t, RateLimit{Burst: 5}.Unlimited())
+	assert.False(t, RateLimit{MaxConcurrent: 1}.Unlimited())
+}
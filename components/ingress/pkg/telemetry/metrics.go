@@ -0,0 +1,184 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	inttelemetry "github.com/alibaba/opensandbox/internal/telemetry"
+)
+
+var (
+	requestCount     metric.Int64Counter
+	requestDuration  metric.Float64Histogram
+	upstreamDuration metric.Float64Histogram
+	upstreamErrors   metric.Int64Counter
+	activeWebSockets metric.Int64UpDownCounter
+	activeTunnels    metric.Int64UpDownCounter
+	rateLimited      metric.Int64Counter
+)
+
+var ingressSharedAttrs = sync.OnceValue(func() []attribute.KeyValue {
+	return inttelemetry.AppendAttrsFromKeyValuePairs(nil, os.Getenv(envMetricsExtraAttrs))
+})
+
+// RequestAttrs identifies the traffic a measurement belongs to.
+type RequestAttrs struct {
+	SandboxID string
+	Port      int
+	// RouteMode is how the sandbox was addressed: header or uri.
+	RouteMode string
+	// Protocol is http, websocket or tcp.
+	Protocol string
+}
+
+func (a RequestAttrs) attributes(extra ...attribute.KeyValue) []attribute.KeyValue {
+	attrs := append([]attribute.KeyValue{}, ingressSharedAttrs()...)
+	attrs = append(attrs,
+		attribute.String("sandbox_id", a.SandboxID),
+		attribute.Int("port", a.Port),
+		attribute.String("route_mode", a.RouteMode),
+		attribute.String("protocol", a.Protocol),
+	)
+	return append(attrs, extra...)
+}
+
+func registerIngressMetrics() error {
+	meter := otel.Meter("opensandbox/ingress")
+
+	var err error
+	requestCount, err = meter.Int64Counter(
+		"ingress.http.requests.count",
+		metric.WithDescription("Proxied requests by sandbox, port, status class and route mode"),
+	)
+	if err != nil {
+		return err
+	}
+	requestDuration, err = meter.Float64Histogram(
+		"ingress.http.request.duration",
+		metric.WithDescription("Proxied request duration, until the response is written or the WebSocket closes"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return err
+	}
+	upstreamDuration, err = meter.Float64Histogram(
+		"ingress.upstream.response.duration",
+		metric.WithDescription("Time for the sandbox to answer with response headers or a WebSocket handshake"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return err
+	}
+	upstreamErrors, err = meter.Int64Counter(
+		"ingress.upstream.errors.count",
+		metric.WithDescription("Requests the sandbox did not answer, by reason"),
+	)
+	if err != nil {
+		return err
+	}
+	activeWebSockets, err = meter.Int64UpDownCounter(
+		"ingress.websocket.connections.active",
+		metric.WithDescription("Open proxied WebSocket connections"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	activeTunnels, err = meter.Int64UpDownCounter(
+		"ingress.tcp.tunnels.active",
+		metric.WithDescription("Open TCP tunnels to sandbox ports"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	rateLimited, err = meter.Int64Counter(
+		"ingress.ratelimit.rejected.count",
+		metric.WithDescription("Requests rejected with 429 by the per-sandbox limits, by reason"),
+	)
+	return err
+}
+
+// RecordRequest records a finished request. A zero upstreamMillis means the upstream was not
+// reached and is not recorded.
+func RecordRequest(ctx context.Context, a RequestAttrs, statusCode int, durationMillis, upstreamMillis float64) {
+	if requestCount == nil {
+		return
+	}
+	opt := metric.WithAttributes(a.attributes(attribute.String("status_class", StatusClass(statusCode)))...)
+	requestCount.Add(ctx, 1, opt)
+	requestDuration.Record(ctx, durationMillis, opt)
+	if upstreamMillis > 0 {
+		upstreamDuration.Record(ctx, upstreamMillis, metric.WithAttributes(a.attributes()...))
+	}
+}
+
+// RecordUpstreamError records a request the sandbox did not answer, such as on timeout.
+func RecordUpstreamError(ctx context.Context, a RequestAttrs, reason string) {
+	if upstreamErrors == nil {
+		return
+	}
+	upstreamErrors.Add(ctx, 1, metric.WithAttributes(a.attributes(attribute.String("reason", reason))...))
+}
+
+// RecordRateLimited records a request rejected by a rate limit or concurrency cap; reason is
+// rate or concurrency.
+func RecordRateLimited(ctx context.Context, a RequestAttrs, reason string) {
+	if rateLimited == nil {
+		return
+	}
+	rateLimited.Add(ctx, 1, metric.WithAttributes(a.attributes(attribute.String("reason", reason))...))
+}
+
+// AddActiveWebSocket adjusts the open WebSocket count by delta.
+func AddActiveWebSocket(ctx context.Context, a RequestAttrs, delta int64) {
+	if activeWebSockets == nil {
+		return
+	}
+	activeWebSockets.Add(ctx, delta, metric.WithAttributes(a.attributes()...))
+}
+
+// AddActiveTunnel adjusts the open TCP tunnel count by delta.
+func AddActiveTunnel(ctx context.Context, a RequestAttrs, delta int64) {
+	if activeTunnels == nil {
+		return
+	}
+	activeTunnels.Add(ctx, delta, metric.WithAttributes(a.attributes()...))
+}
+
+// StatusClass groups an HTTP status code as 1xx to 5xx, or unknown.
+func StatusClass(code int) string {
+	switch {
+	case code >= 100 && code < 200:
+		return "1xx"
+	case code >= 200 && code < 300:
+		return "2xx"
+	case code >= 300 && code < 400:
+		return "3xx"
+	case code >= 400 && code < 500:
+		return "4xx"
+	case code >= 500 && code < 600:
+		return "5xx"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestStatusClass(t *testing.T) {
+	cases := map[int]string{
+		101: "1xx",
+		200: "2xx",
+		304: "3xx",
+		404: "4xx",
+		499: "4xx",
+		504: "5xx",
+		0:   "unknown",
+		600: "unknown",
+	}
+	for code, want := range cases {
+		assert.Equal(t, want, StatusClass(code), "status %d", code)
+	}
+}
+
+func TestRecordMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() {
+		otel.SetMeterProvider(prev)
+		_ = provider.Shutdown(context.Background())
+	})
+	require.NoError(t, registerIngressMetrics())
+
+	ctx := context.Background()
+	attrs := RequestAttrs{SandboxID: "sbx", Port: 8080, RouteMode: "header", Protocol: "http"}
+	RecordRequest(ctx, attrs, 200, 12, 10)
+	RecordRequest(ctx, attrs, 201, 5, 0)
+	RecordRequest(ctx, attrs, 502, 1, 0)
+	RecordUpstreamError(ctx, attrs, "connect")
+	RecordRateLimited(ctx, attrs, "rate")
+	RecordRateLimited(ctx, attrs, "rate")
+	ws := RequestAttrs{SandboxID: "sbx", Port: 8080, RouteMode: "uri", Protocol: "websocket"}
+	AddActiveWebSocket(ctx, ws, 1)
+	AddActiveWebSocket(ctx, ws, 1)
+	AddActiveWebSocket(ctx, ws, -1)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	got := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m.Data
+		}
+	}
+
+	requests, ok := got["ingress.http.requests.count"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	byClass := map[string]int64{}
+	for _, dp := range requests.DataPoints {
+		class, _ := dp.Attributes.Value("status_class")
+		byClass[class.AsString()] = dp.Value
+		sandboxID, _ := dp.Attributes.Value("sandbox_id")
+		assert.Equal(t, "sbx", sandboxID.AsString())
+		port, _ := dp.Attributes.Value("port")
+		assert.Equal(t, int64(8080), port.AsInt64())
+		mode, _ := dp.Attributes.Value("route_mode")
+		assert.Equal(t, "header", mode.AsString())
+	}
+	assert.Equal(t, map[string]int64{"2xx": 2, "5xx": 1}, byClass)
+
+	upstream, ok := got["ingress.upstream.response.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, upstream.DataPoints, 1)
+	assert.Equal(t, uint64(1), upstream.DataPoints[0].Count)
+
+	errs, ok := got["ingress.upstream.errors.count"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, errs.DataPoints, 1)
+	reason, _ := errs.DataPoints[0].Attributes.Value(attribute.Key("reason"))
+	assert.Equal(t, "connect", reason.AsString())
+
+	limited, ok := got["ingress.ratelimit.rejected.count"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, limited.DataPoints, 1)
+	assert.Equal(t, int64(2), limited.DataPoints[0].Value)
+	reason, _ = limited.DataPoints[0].Attributes.Value(attribute.Key("reason"))
+	assert.Equal(t, "rate", reason.AsString())
+
+	active, ok := got["ingress.websocket.connections.active"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, active.DataPoints, 1)
+	assert.Equal(t, int64(1), active.DataPoints[0].Value)
+}
@@ -41,7 +41,7 @@ const (
 )
 
 // BatchSandboxConditionType represents the type of BatchSandbox condition.
-// +kubebuilder:validation:Enum=Ready;Progressing;Paused;PauseFailed;ResumeFailed;PodFailed
+// +kubebuilder:validation:Enum=Ready;Progressing;Paused;PauseFailed;ResumeFailed;PodFailed;QuotaExceeded;TaskFailed;InvalidSpec
 type BatchSandboxConditionType string
 
 const (
@@ -57,6 +57,15 @@ const (
 	BatchSandboxConditionResumeFailed BatchSandboxConditionType = "ResumeFailed"
 	// BatchSandboxConditionPodFailed is set when the sandbox pod enters a failed state.
 	BatchSandboxConditionPodFailed BatchSandboxConditionType = "PodFailed"
+	// BatchSandboxConditionQuotaExceeded is set while the sandbox does not fit into a SandboxQuota
+	// of its namespace; no pods are created or allocated for it until it does.
+	BatchSandboxConditionQuotaExceeded BatchSandboxConditionType = "QuotaExceeded"
+	// BatchSandboxConditionTaskFailed is set while a task has failed. Its message holds the termination
+	// message of a failed task, such as the tail of its stderr.
+	BatchSandboxConditionTaskFailed BatchSandboxConditionType = "TaskFailed"
+	// BatchSandboxConditionInvalidSpec is set while the spec combines fields that cannot be used
+	// together, such as snapshotRef with poolRef. No pods are created or allocated for it.
+	BatchSandboxConditionInvalidSpec BatchSandboxConditionType = "InvalidSpec"
 )
 
 // BatchSandboxCondition represents a condition of a BatchSandbox
@@ -91,6 +100,13 @@ type BatchSandboxSpec struct {
 	// +optional
 	// +kubebuilder:validation:Optional
 	PoolRef string `json:"poolRef,omitempty"`
+	// SnapshotRef references a SandboxSnapshot (same namespace) to start the sandbox from.
+	// Controller writes the snapshot's container images into Template before creating Pods,
+	// so one prepared environment can be forked into any number of replicas.
+	// Mutually exclusive with PoolRef.
+	// +optional
+	// +kubebuilder:validation:Optional
+	SnapshotRef string `json:"snapshotRef,omitempty"`
 	// +optional
 	// Template describes the pods that will be created.
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -137,8 +153,30 @@ type BatchSandboxSpec struct {
 	// Controller never clears this field; Server may temporarily patch nil to force a new generation for retries.
 	// +optional
 	Pause *bool `json:"pause,omitempty"`
+
+	// Priority orders pooled BatchSandboxes when their Pool cannot satisfy every request.
+	// Sandboxes with a higher priority are allocated pods first. Default is 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+	// PreemptionPolicy controls whether this sandbox may take pods from lower-priority
+	// BatchSandboxes of the same Pool when the Pool is exhausted. The Pool must enable preemption.
+	// - Never: Wait for pods to become available.
+	// - PreemptLowerPriority: Release pods of lower-priority sandboxes.
+	// +optional
+	// +kubebuilder:default=Never
+	// +kubebuilder:validation:Optional
+	PreemptionPolicy *PreemptionPolicy `json:"preemptionPolicy,omitempty"`
 }
 
+// PreemptionPolicy describes whether a BatchSandbox may preempt lower-priority sandboxes.
+// +kubebuilder:validation:Enum=Never;PreemptLowerPriority
+type PreemptionPolicy string
+
+const (
+	PreemptionPolicyNever                PreemptionPolicy = "Never"
+	PreemptionPolicyPreemptLowerPriority PreemptionPolicy = "PreemptLowerPriority"
+)
+
 type TaskResourcePolicy string
 
 const (
@@ -146,6 +184,23 @@ const (
 	TaskResourcePolicyRelease TaskResourcePolicy = "Release"
 )
 
+// BatchSandboxReplicaPauseStatus tracks the pause snapshot of a single replica.
+type BatchSandboxReplicaPauseStatus struct {
+	// Index is the replica index.
+	Index int32 `json:"index"`
+	// SnapshotName is the internal SandboxSnapshot capturing this replica.
+	SnapshotName string `json:"snapshotName"`
+	// Phase mirrors the phase of the replica's SandboxSnapshot.
+	// +optional
+	Phase SandboxSnapshotPhase `json:"phase,omitempty"`
+	// Retries is the number of times the snapshot was retaken after a failure.
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+	// Message holds the last snapshot failure message, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // BatchSandboxStatus defines the observed state of BatchSandbox.
 type BatchSandboxStatus struct {
 	// ObservedGeneration is the most recent generation observed for this BatchSandbox. It corresponds to the
@@ -167,6 +222,11 @@ type BatchSandboxStatus struct {
 	TaskPending int32 `json:"taskPending"`
 	// TaskUnknown is the number of Unknown task
 	TaskUnknown int32 `json:"taskUnknown"`
+	// TaskSteps counts the tasks by state for every step of a task template with steps.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	TaskSteps []TaskStepStatus `json:"taskSteps,omitempty"`
 
 	// Phase is the overall phase of the BatchSandbox, aggregated and written by Controller.
 	// Server reads this field directly without combining multiple fields.
@@ -178,6 +238,13 @@ type BatchSandboxStatus struct {
 	// +optional
 	PauseObservedGeneration int64 `json:"pauseObservedGeneration,omitempty"`
 
+	// PauseReplicas records the per-replica snapshot progress of the most recent pause.
+	// Resume restores each listed replica from its own snapshot.
+	// +optional
+	// +listType=map
+	// +listMapKey=index
+	PauseReplicas []BatchSandboxReplicaPauseStatus `json:"pauseReplicas,omitempty"`
+
 	// Conditions records operation failure context
 	// +optional
 	// +listType=map
@@ -235,8 +302,49 @@ type TaskSpec struct {
 	// If exceeded, the task executor should terminate the task.
 	// +optional
 	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+	// RestartPolicy specifies whether the task executor restarts the task after it terminates.
+	// Restarts are delayed by an exponential backoff. Defaults to Never.
+	// +optional
+	RestartPolicy TaskRestartPolicy `json:"restartPolicy,omitempty"`
+	// MaxRetries is the maximum number of times the task is restarted. If unset, the task is
+	// restarted without limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// Steps is a DAG of named tasks that run in each sandbox instead of Process. A step starts once
+	// all the steps it depends on have succeeded; when a step fails, the steps that depend on it
+	// fail without running. TimeoutSeconds, RestartPolicy and MaxRetries apply to every step.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Steps []TaskStep `json:"steps,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
 }
 
+// TaskStep is a named task in the DAG of steps of a task template.
+type TaskStep struct {
+	// Name of the step, unique within the task template.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// DependsOn lists the names of the steps that must succeed before this step starts.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Process is the process run by the step.
+	// +kubebuilder:validation:Required
+	Process *ProcessTask `json:"process"`
+}
+
+// TaskRestartPolicy describes how a terminated task is restarted.
+// +kubebuilder:validation:Enum=Never;OnFailure;Always
+type TaskRestartPolicy string
+
+const (
+	TaskRestartPolicyNever     TaskRestartPolicy = "Never"
+	TaskRestartPolicyOnFailure TaskRestartPolicy = "OnFailure"
+	TaskRestartPolicyAlways    TaskRestartPolicy = "Always"
+)
+
 type ProcessTask struct {
 	// Command command
 	// +kubebuilder:validation:Required
@@ -254,6 +362,20 @@ type ProcessTask struct {
 	WorkingDir string `json:"workingDir,omitempty"`
 }
 
+// TaskStepStatus counts the tasks by the state of one step.
+type TaskStepStatus struct {
+	// Name of the step.
+	Name string `json:"name"`
+	// Pending is the number of tasks in which the step has not started yet.
+	Pending int32 `json:"pending"`
+	// Running is the number of tasks in which the step is running.
+	Running int32 `json:"running"`
+	// Succeed is the number of tasks in which the step succeeded.
+	Succeed int32 `json:"succeed"`
+	// Failed is the number of tasks in which the step failed.
+	Failed int32 `json:"failed"`
+}
+
 // TaskStatus task status
 type TaskStatus struct {
 	// Details about the task's current condition.
@@ -33,13 +33,16 @@ const (
 	RecycleTypeDelete RecycleType = "Delete"
 	// RecycleTypeRestart restarts the pod containers when it is returned to the pool.
 	RecycleTypeRestart RecycleType = "Restart"
+	// RecycleTypeReset runs a cleanup procedure inside the pod when it is returned to the pool,
+	// deleting the pod if the result cannot be verified.
+	RecycleTypeReset RecycleType = "Reset"
 )
 
 // RecycleStrategy controls how pods are handled when returned to the pool.
 type RecycleStrategy struct {
 	// Type specifies the recycle policy type.
 	// Default is Delete.
-	// +kubebuilder:validation:Enum=Delete;Restart;Noop
+	// +kubebuilder:validation:Enum=Delete;Restart;Reset;Noop
 	// +kubebuilder:default=Delete
 	// +optional
 	Type RecycleType `json:"type,omitempty"`
@@ -64,8 +67,76 @@ type PoolSpec struct {
 	// RecycleStrategy controls how pods are handled when returned to the pool.
 	// Default is Delete, which deletes the pod.
 	// Restart strategy restarts the pod containers instead of deleting.
+	// Reset strategy cleans the pod in place and falls back to Delete when verification fails.
 	// +optional
 	RecycleStrategy *RecycleStrategy `json:"recycleStrategy,omitempty"`
+	// Autoscaling adjusts the warm buffer target to observed demand instead of keeping
+	// it anywhere between bufferMin and bufferMax. The target is always bounded by CapacitySpec.
+	// +optional
+	Autoscaling *PoolAutoscaling `json:"autoscaling,omitempty"`
+	// Preemption allows BatchSandboxes with preemptionPolicy PreemptLowerPriority to release
+	// pods of lower-priority BatchSandboxes when the pool is exhausted. Unset disables preemption.
+	// +optional
+	Preemption *PoolPreemption `json:"preemption,omitempty"`
+}
+
+// PoolPreemption configures preemption between BatchSandboxes sharing a pool.
+type PoolPreemption struct {
+	// MinRuntimeSeconds protects a sandbox's pods from preemption for this long after
+	// pods were last allocated to it.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinRuntimeSeconds int32 `json:"minRuntimeSeconds,omitempty"`
+}
+
+// PoolAutoscaling derives the warm buffer target from allocation demand.
+// The demand target is allocationRate * leadSeconds, scaled up by averageWait / targetWaitSeconds
+// when sandboxes wait longer than targetWaitSeconds for a pod.
+type PoolAutoscaling struct {
+	// WindowSeconds is the sliding window over which allocation rate and wait time are observed.
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	WindowSeconds int32 `json:"windowSeconds,omitempty"`
+	// LeadSeconds is how many seconds of allocation demand the buffer should absorb,
+	// roughly the time a new pool pod needs to become ready.
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LeadSeconds int32 `json:"leadSeconds,omitempty"`
+	// TargetWaitSeconds is the acceptable average time from BatchSandbox creation to pod allocation.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetWaitSeconds int32 `json:"targetWaitSeconds,omitempty"`
+	// Schedules override the demand target during fixed times of day. The first active schedule wins.
+	// +optional
+	Schedules []PoolAutoscalingSchedule `json:"schedules,omitempty"`
+}
+
+// PoolAutoscalingSchedule pins the buffer target during a daily time window.
+type PoolAutoscalingSchedule struct {
+	// Name identifies the schedule in status.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Start is the window start time of day, HH:MM in 24h format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +kubebuilder:validation:Required
+	Start string `json:"start"`
+	// End is the window end time of day, HH:MM in 24h format. A window with End before Start spans midnight.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +kubebuilder:validation:Required
+	End string `json:"end"`
+	// Days restricts the schedule to these weekdays (Mon, Tue, ...). Empty means every day.
+	// +optional
+	Days []string `json:"days,omitempty"`
+	// TimeZone is the IANA time zone the window is evaluated in. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// BufferTarget is the warm buffer target while the schedule is active.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Required
+	BufferTarget int32 `json:"bufferTarget"`
 }
 
 type CapacitySpec struct {
@@ -120,6 +191,23 @@ type PoolStatus struct {
 	Available int32 `json:"available"`
 	// Updated is the number of nodes that have been updated to the latest revision.
 	Updated int32 `json:"updated,omitempty"`
+	// Autoscaling reports the last autoscaling decision when spec.autoscaling is set.
+	// +optional
+	Autoscaling *PoolAutoscalingStatus `json:"autoscaling,omitempty"`
+}
+
+// PoolAutoscalingStatus is the observed demand and the buffer target derived from it.
+type PoolAutoscalingStatus struct {
+	// DesiredBuffer is the effective warm buffer target, bounded by CapacitySpec.
+	DesiredBuffer int32 `json:"desiredBuffer"`
+	// AllocationsInWindow is the number of pods allocated during the observation window.
+	AllocationsInWindow int32 `json:"allocationsInWindow"`
+	// AverageWaitMilliseconds is the average time from BatchSandbox creation to pod allocation
+	// during the observation window.
+	AverageWaitMilliseconds int64 `json:"averageWaitMilliseconds"`
+	// ActiveSchedule is the name of the schedule overriding the demand target, if any.
+	// +optional
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,119 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SandboxQuotaSpec caps what the BatchSandboxes of a namespace may consume.
+// Unset limits are not enforced. When several SandboxQuotas exist in a namespace, all of them apply.
+type SandboxQuotaSpec struct {
+	// MaxBatchSandboxes is the maximum number of BatchSandboxes admitted in the namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBatchSandboxes *int32 `json:"maxBatchSandboxes,omitempty"`
+	// MaxReplicas is the maximum sum of spec.replicas over all admitted BatchSandboxes.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// MaxPoolPods is the maximum number of pods the namespace may have allocated from each Pool.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPoolPods *int32 `json:"maxPoolPods,omitempty"`
+	// Pools overrides MaxPoolPods for individual Pools.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Pools []PoolQuota `json:"pools,omitempty"`
+	// MaxSnapshots is the maximum number of SandboxSnapshots in the namespace,
+	// including the ones taken to pause BatchSandboxes.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSnapshots *int32 `json:"maxSnapshots,omitempty"`
+}
+
+// PoolQuota limits the pods allocated from a single Pool.
+type PoolQuota struct {
+	// Name of the Pool.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// MaxPods is the maximum number of pods allocated from the Pool.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Required
+	MaxPods int32 `json:"maxPods"`
+}
+
+// SandboxQuotaStatus reports the current usage of the namespace.
+type SandboxQuotaStatus struct {
+	// ObservedGeneration is the most recent generation observed for this SandboxQuota.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// BatchSandboxes is the number of admitted BatchSandboxes.
+	// +optional
+	BatchSandboxes int32 `json:"batchSandboxes"`
+	// Replicas is the sum of spec.replicas over admitted BatchSandboxes.
+	// +optional
+	Replicas int32 `json:"replicas"`
+	// Rejected is the number of BatchSandboxes that do not fit into the quotas of the namespace.
+	// +optional
+	Rejected int32 `json:"rejected"`
+	// Snapshots is the number of SandboxSnapshots.
+	// +optional
+	Snapshots int32 `json:"snapshots"`
+	// Pools lists the pods allocated from each Pool.
+	// +optional
+	Pools []PoolQuotaUsage `json:"pools,omitempty"`
+}
+
+// PoolQuotaUsage is the number of pods allocated from a Pool.
+type PoolQuotaUsage struct {
+	// Name of the Pool.
+	Name string `json:"name"`
+	// Allocated is the number of pods currently allocated from the Pool.
+	Allocated int32 `json:"allocated"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=sbxquota
+// +kubebuilder:printcolumn:name="SANDBOXES",type="integer",JSONPath=".status.batchSandboxes"
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="REJECTED",type="integer",JSONPath=".status.rejected"
+// +kubebuilder:printcolumn:name="SNAPSHOTS",type="integer",JSONPath=".status.snapshots"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// SandboxQuota is the Schema for the sandboxquotas API.
+type SandboxQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SandboxQuotaSpec   `json:"spec,omitempty"`
+	Status SandboxQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SandboxQuotaList contains a list of SandboxQuota.
+type SandboxQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SandboxQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SandboxQuota{}, &SandboxQuotaList{})
+}
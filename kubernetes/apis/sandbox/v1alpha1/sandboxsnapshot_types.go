@@ -30,7 +30,7 @@ const (
 )
 
 // SandboxSnapshotConditionType represents the type of SandboxSnapshot condition.
-// +kubebuilder:validation:Enum=Ready;Failed
+// +kubebuilder:validation:Enum=Ready;Failed;QuotaExceeded
 type SandboxSnapshotConditionType string
 
 const (
@@ -38,6 +38,9 @@ const (
 	SandboxSnapshotConditionReady SandboxSnapshotConditionType = "Ready"
 	// SandboxSnapshotConditionFailed indicates the snapshot has failed.
 	SandboxSnapshotConditionFailed SandboxSnapshotConditionType = "Failed"
+	// SandboxSnapshotConditionQuotaExceeded is set while the snapshot waits in Pending because
+	// it does not fit into the MaxSnapshots of a SandboxQuota.
+	SandboxSnapshotConditionQuotaExceeded SandboxSnapshotConditionType = "QuotaExceeded"
 )
 
 // ContainerSnapshot records the snapshot result for a single container.
@@ -79,6 +82,53 @@ type SandboxSnapshotSpec struct {
 	// Controller uses this to find BatchSandbox -> find Pod -> dispatch commit Job.
 	// +kubebuilder:validation:Required
 	SandboxName string `json:"sandboxName"`
+
+	// ReplicaIndex pins the snapshot to a single replica of a multi-replica BatchSandbox.
+	// When unset, Controller snapshots the first running Pod of the BatchSandbox.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ReplicaIndex *int32 `json:"replicaIndex,omitempty"`
+
+	// Retention controls when Controller deletes the snapshot and its pushed images.
+	// When unset, the snapshot lives until it is deleted explicitly and images are retained.
+	// +optional
+	Retention *SandboxSnapshotRetention `json:"retention,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Retain;Delete
+// SnapshotImagePolicy decides what happens to pushed snapshot images when the snapshot is deleted.
+type SnapshotImagePolicy string
+
+const (
+	// SnapshotImagePolicyRetain keeps the pushed image tags in the registry.
+	SnapshotImagePolicyRetain SnapshotImagePolicy = "Retain"
+	// SnapshotImagePolicyDelete deletes the pushed image tags from the registry.
+	SnapshotImagePolicyDelete SnapshotImagePolicy = "Delete"
+)
+
+// SandboxSnapshotRetention defines the lifecycle of a SandboxSnapshot.
+// Rules are evaluated once the snapshot is Succeed or Failed; the first rule that matches deletes it.
+type SandboxSnapshotRetention struct {
+	// TTLSecondsAfterCreation deletes the snapshot this many seconds after it was created.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TTLSecondsAfterCreation *int32 `json:"ttlSecondsAfterCreation,omitempty"`
+
+	// KeepLast keeps only the newest N Succeed snapshots of the same source sandbox.
+	// Older Succeed snapshots that also set keepLast are deleted; snapshots without it are counted but kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	KeepLast *int32 `json:"keepLast,omitempty"`
+
+	// DeleteWithSandbox deletes the snapshot once its source BatchSandbox is deleted.
+	// +optional
+	DeleteWithSandbox bool `json:"deleteWithSandbox,omitempty"`
+
+	// ImagePolicy decides whether pushed image tags are deleted from the registry together
+	// with the snapshot. Defaults to Delete.
+	// +optional
+	// +kubebuilder:default=Delete
+	ImagePolicy SnapshotImagePolicy `json:"imagePolicy,omitempty"`
 }
 
 // SandboxSnapshotStatus defines the observed state of SandboxSnapshot.
@@ -102,6 +102,21 @@ func (in *BatchSandboxList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchSandboxReplicaPauseStatus) DeepCopyInto(out *BatchSandboxReplicaPauseStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSandboxReplicaPauseStatus.
+func (in *BatchSandboxReplicaPauseStatus) DeepCopy() *BatchSandboxReplicaPauseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchSandboxReplicaPauseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BatchSandboxSpec) DeepCopyInto(out *BatchSandboxSpec) {
 	*out = *in
@@ -148,6 +163,16 @@ func (in *BatchSandboxSpec) DeepCopyInto(out *BatchSandboxSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(PreemptionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSandboxSpec.
@@ -163,6 +188,16 @@ func (in *BatchSandboxSpec) DeepCopy() *BatchSandboxSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BatchSandboxStatus) DeepCopyInto(out *BatchSandboxStatus) {
 	*out = *in
+	if in.TaskSteps != nil {
+		in, out := &in.TaskSteps, &out.TaskSteps
+		*out = make([]TaskStepStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PauseReplicas != nil {
+		in, out := &in.PauseReplicas, &out.PauseReplicas
+		*out = make([]BatchSandboxReplicaPauseStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]BatchSandboxCondition, len(*in))
@@ -218,7 +253,7 @@ func (in *Pool) DeepCopyInto(out *Pool) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pool.
@@ -239,6 +274,63 @@ func (in *Pool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolAutoscaling) DeepCopyInto(out *PoolAutoscaling) {
+	*out = *in
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]PoolAutoscalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolAutoscaling.
+func (in *PoolAutoscaling) DeepCopy() *PoolAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolAutoscalingSchedule) DeepCopyInto(out *PoolAutoscalingSchedule) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolAutoscalingSchedule.
+func (in *PoolAutoscalingSchedule) DeepCopy() *PoolAutoscalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolAutoscalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolAutoscalingStatus) DeepCopyInto(out *PoolAutoscalingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolAutoscalingStatus.
+func (in *PoolAutoscalingStatus) DeepCopy() *PoolAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolList) DeepCopyInto(out *PoolList) {
 	*out = *in
@@ -271,6 +363,51 @@ func (in *PoolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolPreemption) DeepCopyInto(out *PoolPreemption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolPreemption.
+func (in *PoolPreemption) DeepCopy() *PoolPreemption {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolPreemption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolQuota) DeepCopyInto(out *PoolQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolQuota.
+func (in *PoolQuota) DeepCopy() *PoolQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolQuotaUsage) DeepCopyInto(out *PoolQuotaUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolQuotaUsage.
+func (in *PoolQuotaUsage) DeepCopy() *PoolQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 	*out = *in
@@ -295,6 +432,16 @@ func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 		*out = new(RecycleStrategy)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preemption != nil {
+		in, out := &in.Preemption, &out.Preemption
+		*out = new(PoolPreemption)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
@@ -310,6 +457,11 @@ func (in *PoolSpec) DeepCopy() *PoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolAutoscalingStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
@@ -369,12 +521,131 @@ func (in *RecycleStrategy) DeepCopy() *RecycleStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxQuota) DeepCopyInto(out *SandboxQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxQuota.
+func (in *SandboxQuota) DeepCopy() *SandboxQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SandboxQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxQuotaList) DeepCopyInto(out *SandboxQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SandboxQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxQuotaList.
+func (in *SandboxQuotaList) DeepCopy() *SandboxQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SandboxQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxQuotaSpec) DeepCopyInto(out *SandboxQuotaSpec) {
+	*out = *in
+	if in.MaxBatchSandboxes != nil {
+		in, out := &in.MaxBatchSandboxes, &out.MaxBatchSandboxes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPoolPods != nil {
+		in, out := &in.MaxPoolPods, &out.MaxPoolPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxSnapshots != nil {
+		in, out := &in.MaxSnapshots, &out.MaxSnapshots
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxQuotaSpec.
+func (in *SandboxQuotaSpec) DeepCopy() *SandboxQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxQuotaStatus) DeepCopyInto(out *SandboxQuotaStatus) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolQuotaUsage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxQuotaStatus.
+func (in *SandboxQuotaStatus) DeepCopy() *SandboxQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SandboxSnapshot) DeepCopyInto(out *SandboxSnapshot) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -447,9 +718,44 @@ func (in *SandboxSnapshotList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxSnapshotRetention) DeepCopyInto(out *SandboxSnapshotRetention) {
+	*out = *in
+	if in.TTLSecondsAfterCreation != nil {
+		in, out := &in.TTLSecondsAfterCreation, &out.TTLSecondsAfterCreation
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxSnapshotRetention.
+func (in *SandboxSnapshotRetention) DeepCopy() *SandboxSnapshotRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxSnapshotRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SandboxSnapshotSpec) DeepCopyInto(out *SandboxSnapshotSpec) {
 	*out = *in
+	if in.ReplicaIndex != nil {
+		in, out := &in.ReplicaIndex, &out.ReplicaIndex
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(SandboxSnapshotRetention)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxSnapshotSpec.
@@ -522,6 +828,18 @@ func (in *TaskSpec) DeepCopyInto(out *TaskSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TaskStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSpec.
@@ -629,6 +947,46 @@ func (in *TaskStatus) DeepCopy() *TaskStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskStep) DeepCopyInto(out *TaskStep) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Process != nil {
+		in, out := &in.Process, &out.Process
+		*out = new(ProcessTask)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStep.
+func (in *TaskStep) DeepCopy() *TaskStep {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskStepStatus) DeepCopyInto(out *TaskStepStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStepStatus.
+func (in *TaskStepStatus) DeepCopy() *TaskStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskTemplateSpec) DeepCopyInto(out *TaskTemplateSpec) {
 	*out = *in
@@ -42,10 +42,12 @@ import (
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller"
 	poolassign "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/poolassign"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/registry"
 	cryptoutil "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/crypto"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/expectations"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/fieldindex"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/logging"
+	webhookv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -223,6 +225,17 @@ func main() {
 	var resumePullSecret string
 	flag.StringVar(&resumePullSecret, "resume-pull-secret", "", "K8s Secret name for pulling snapshot images during resume.")
 
+	var pauseSnapshotRetries int
+	flag.IntVar(&pauseSnapshotRetries, "pause-snapshot-retries", 2, "How many times a failed replica snapshot is retaken before a pause fails.")
+
+	var allocationStoreType string
+	flag.StringVar(&allocationStoreType, "allocation-store", controller.AllocationStoreMemory,
+		"Where pool allocations are kept: 'memory' rebuilds them from BatchSandbox annotations on start, "+
+			"'configmap' persists them in a ConfigMap per Pool.")
+	var allocationCheckInterval time.Duration
+	flag.DurationVar(&allocationCheckInterval, "allocation-check-interval", controller.DefaultAllocationCheckInterval,
+		"How often each pool's allocation is checked against BatchSandbox annotations. 0 disables the check.")
+
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 
@@ -431,21 +444,32 @@ func main() {
 	}
 
 	if err := (&controller.BatchSandboxReconciler{
-		Client:              mgr.GetClient(),
-		Scheme:              mgr.GetScheme(),
-		Recorder:            mgr.GetEventRecorderFor("batchsandbox-controller"),
-		ResumePullSecret:    resumePullSecret,
-		ProfileStore:        profileStore,
-		StatusRVExpectation: expectations.NewResourceVersionExpectation(),
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		Recorder:             mgr.GetEventRecorderFor("batchsandbox-controller"),
+		ResumePullSecret:     resumePullSecret,
+		PauseSnapshotRetries: int32(pauseSnapshotRetries),
+		ProfileStore:         profileStore,
+		StatusRVExpectation:  expectations.NewResourceVersionExpectation(),
 	}).SetupWithManager(mgr, batchSandboxConcurrency); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "BatchSandbox")
 		os.Exit(1)
 	}
+	var allocationStore controller.AllocationStore
+	switch allocationStoreType {
+	case controller.AllocationStoreMemory:
+		allocationStore = controller.NewInMemoryAllocationStore()
+	case controller.AllocationStoreConfigMap:
+		allocationStore = controller.NewConfigMapAllocationStore(mgr.GetClient(), mgr.GetAPIReader())
+	default:
+		setupLog.Error(fmt.Errorf("unknown allocation store %q", allocationStoreType), "invalid --allocation-store")
+		os.Exit(1)
+	}
 	if err := (&controller.PoolReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
 		Recorder:   mgr.GetEventRecorderFor("pool-controller"),
-		Allocator:  controller.NewDefaultAllocator(mgr.GetClient()),
+		Allocator:  controller.NewAllocator(mgr.GetClient(), allocationStore, allocationCheckInterval),
 		RestConfig: mgr.GetConfig(),
 	}).SetupWithManager(mgr, poolConcurrency); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pool")
@@ -461,10 +485,38 @@ func main() {
 		SnapshotRegistry:         snapshotRegistry,
 		SnapshotRegistryInsecure: snapshotRegistryInsecure,
 		SnapshotPushSecret:       snapshotPushSecret,
+		RegistryClient:           registry.NewHTTPClient(snapshotRegistryInsecure),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SandboxSnapshot")
 		os.Exit(1)
 	}
+	if err := (&controller.SandboxQuotaReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SandboxQuota")
+		os.Exit(1)
+	}
+	// Webhooks need a serving certificate and webhook configurations, so they are opt-in
+	// like the chart's webhook.enabled.
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "true" {
+		setupLog.Info("Admission webhooks disabled; Pool and SandboxSnapshot defaults are not applied",
+			"hint", "set ENABLE_WEBHOOKS=true to enable them")
+	} else {
+		if err := webhookv1alpha1.SetupBatchSandboxWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BatchSandbox")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPoolWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pool")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupSandboxSnapshotWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SandboxSnapshot")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -27,6 +27,12 @@ type SandboxRequest struct {
 	CurReleased   []string
 	PodSupplement int32
 	ToRelease     []string
+	// Priority orders requests when the pool cannot satisfy all of them; higher is served first.
+	Priority int32
+	// CanPreempt allows the request to take pods from lower-priority sandboxes.
+	CanPreempt bool
+	// Preemptible lists the allocated pods that may be taken from this sandbox, in preemption order.
+	Preemptible []string
 }
 
 // AllocAction represents the result of a scheduling decision.
@@ -37,4 +43,13 @@ type AllocAction struct {
 	ToRelease map[string][]string
 	// pod request count
 	PodSupplement int32
+	// pods taken from lower-priority sandboxes
+	Preemptions []Preemption
+}
+
+// Preemption releases pods of a victim sandbox in favour of a higher-priority preemptor.
+type Preemption struct {
+	Preemptor string
+	Victim    string
+	Pods      []string
 }
@@ -15,7 +15,8 @@
 package algorithm
 
 // PackedSchedule allocates pods to each sandbox in order, fully satisfying one before moving to the next.
-// This is the default algorithm and provides the simplest packing strategy.
+// This is the default algorithm and provides the simplest packing strategy. Sandboxes are served
+// from the highest to the lowest priority.
 type PackedSchedule struct{}
 
 func (p *PackedSchedule) Schedule(availablePods []string, allRequest []*SandboxRequest) *AllocAction {
@@ -25,7 +26,7 @@ func (p *PackedSchedule) Schedule(availablePods []string, allRequest []*SandboxR
 		PodSupplement: int32(0),
 	}
 
-	for _, req := range allRequest {
+	for _, req := range byPriority(allRequest) {
 		if len(req.ToRelease) > 0 {
 			action.ToRelease[req.SandboxName] = req.ToRelease
 		}
@@ -103,6 +103,17 @@ func TestPackedSchedule(t *testing.T) {
 			wantRelease:    map[string][]string{},
 			wantSupplement: 3, // 1 remaining for sbx2 + 2 for sbx3
 		},
+		{
+			name:          "HigherPriorityFirst",
+			availablePods: []string{"pod1", "pod2"},
+			allRequest: []*SandboxRequest{
+				{SandboxName: "batch", PodSupplement: 2},
+				{SandboxName: "agent", PodSupplement: 1, Priority: 100},
+			},
+			wantAllocate:   map[string][]string{"agent": {"pod1"}, "batch": {"pod2"}},
+			wantRelease:    map[string][]string{},
+			wantSupplement: 1, // 1 remaining for batch
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,68 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package algorithm
+
+import (
+	"cmp"
+	"slices"
+)
+
+// byPriority returns requests ordered from the highest to the lowest priority,
+// keeping the original order among requests of equal priority.
+func byPriority(allRequest []*SandboxRequest) []*SandboxRequest {
+	sorted := slices.Clone(allRequest)
+	slices.SortStableFunc(sorted, func(a, b *SandboxRequest) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+	return sorted
+}
+
+// Preempt plans preemptions for requests that may preempt but were left unfilled by action.
+// inFlight is the number of pods already being released back to the pool; they are counted
+// against the unmet need first so that a preemptor does not take more pods while earlier
+// preemptions are still recycling. Victims are chosen from the lowest priority upwards and
+// only among sandboxes with a strictly lower priority than the preemptor.
+func Preempt(action *AllocAction, allRequest []*SandboxRequest, inFlight int32) {
+	sorted := byPriority(allRequest)
+	for _, req := range sorted {
+		unmet := req.PodSupplement - int32(len(action.ToAllocate[req.SandboxName]))
+		if unmet <= 0 {
+			continue
+		}
+		covered := min(unmet, inFlight)
+		unmet -= covered
+		inFlight -= covered
+		if unmet <= 0 || !req.CanPreempt {
+			continue
+		}
+		for i := len(sorted) - 1; i >= 0 && unmet > 0; i-- {
+			victim := sorted[i]
+			if victim.Priority >= req.Priority {
+				break
+			}
+			take := min(unmet, int32(len(victim.Preemptible)))
+			if take == 0 {
+				continue
+			}
+			action.Preemptions = append(action.Preemptions, Preemption{
+				Preemptor: req.SandboxName,
+				Victim:    victim.SandboxName,
+				Pods:      slices.Clone(victim.Preemptible[:take]),
+			})
+			victim.Preemptible = victim.Preemptible[take:]
+			unmet -= take
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package algorithm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreempt(t *testing.T) {
+	tests := []struct {
+		name            string
+		allocate        map[string][]string
+		allRequest      []*SandboxRequest
+		inFlight        int32
+		wantPreemptions []Preemption
+	}{
+		{
+			name: "LowestPriorityVictimFirst",
+			allRequest: []*SandboxRequest{
+				{SandboxName: "mid", Priority: 5, Preemptible: []string{"m1"}},
+				{SandboxName: "low", Priority: 0, Preemptible: []string{"l1"}},
+				{SandboxName: "agent", Priority: 10, PodSupplement: 2, CanPreempt: true},
+			},
+			wantPreemptions: []Preemption{
+				{Preemptor: "agent", Victim: "low", Pods: []string{"l1"}},
+				{Preemptor: "agent", Victim: "mid", Pods: []string{"m1"}},
+			},
+		},
+		{
+			name:     "OnlyUnmetNeed",
+			allocate: map[string][]string{"agent": {"pod1"}},
+			allRequest: []*SandboxRequest{
+				{SandboxName: "low", Preemptible: []string{"l1", "l2", "l3"}},
+				{SandboxName: "agent", Priority: 10, PodSupplement: 2, CanPreempt: true},
+			},
+			wantPreemptions: []Preemption{
+				{Preemptor: "agent", Victim: "low", Pods: []string{"l1"}},
+			},
+		},
+		{
+			name: "InFlightReleasesCoverNeed",
+			allRequest: []*SandboxRequest{
+				{SandboxName: "low", Preemptible: []string{"l1", "l2"}},
+				{SandboxName: "agent", Priority: 10, PodSupplement: 2, CanPreempt: true},
+			},
+			inFlight: 2,
+		},
+		{
+			name: "NeverPolicy",
+			allRequest: []*SandboxRequest{
+				{SandboxName: "low", Preemptible: []string{"l1"}},
+				{SandboxName: "agent", Priority: 10, PodSupplement: 1},
+			},
+		},
+		{
+			name: "EqualPriorityIsNotAVictim",
+			allRequest: []*SandboxRequest{
+				{SandboxName: "peer", Priority: 10, Preemptible: []string{"p1"}},
+				{SandboxName: "agent", Priority: 10, PodSupplement: 1, CanPreempt: true},
+			},
+		},
+		{
+			name: "VictimPodsAreNotTakenTwice",
+			allRequest: []*SandboxRequest{
+				{SandboxName: "low", Preemptible: []string{"l1"}},
+				{SandboxName: "agent1", Priority: 10, PodSupplement: 1, CanPreempt: true},
+				{SandboxName: "agent2", Priority: 5, PodSupplement: 1, CanPreempt: true},
+			},
+			wantPreemptions: []Preemption{
+				{Preemptor: "agent1", Victim: "low", Pods: []string{"l1"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := &AllocAction{ToAllocate: map[string][]string{}, ToRelease: map[string][]string{}}
+			for name, pods := range tt.allocate {
+				action.ToAllocate[name] = pods
+			}
+			Preempt(action, tt.allRequest, tt.inFlight)
+			assert.Equal(t, tt.wantPreemptions, action.Preemptions)
+		})
+	}
+}
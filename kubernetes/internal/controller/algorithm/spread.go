@@ -15,7 +15,8 @@
 package algorithm
 
 // SpreadSchedule distributes pods evenly across sandboxes in a round-robin fashion,
-// like water filling — each sandbox gets one pod per round until its need is met.
+// like water filling — each sandbox gets one pod per round until its need is met. Higher-priority
+// sandboxes are filled before lower-priority ones are considered.
 type SpreadSchedule struct{}
 
 func (s *SpreadSchedule) Schedule(availablePods []string, allRequest []*SandboxRequest) *AllocAction {
@@ -32,39 +33,51 @@ func (s *SpreadSchedule) Schedule(availablePods []string, allRequest []*SandboxR
 		}
 	}
 
-	// Build a list of sandboxes that still need pods, with their remaining counts.
+	// Serve priority tiers from highest to lowest; within a tier pods are spread evenly.
 	type needEntry struct {
 		sandboxName string
 		remaining   int32
 	}
-	var needs []needEntry
-	for _, req := range allRequest {
-		if req.PodSupplement > 0 {
-			needs = append(needs, needEntry{sandboxName: req.SandboxName, remaining: req.PodSupplement})
+	podIdx := 0
+	sorted := byPriority(allRequest)
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sorted[end].Priority == sorted[start].Priority {
+			end++
 		}
-	}
 
-	// Round-robin: each round give one pod to each sandbox that still needs pods.
-	podIdx := 0
-	for podIdx < len(availablePods) && len(needs) > 0 {
-		var nextRound []needEntry
-		for _, n := range needs {
-			if podIdx >= len(availablePods) {
-				break
+		// Build a list of sandboxes in this tier that still need pods, with their remaining counts.
+		var needs []needEntry
+		for _, req := range sorted[start:end] {
+			if req.PodSupplement > 0 {
+				needs = append(needs, needEntry{sandboxName: req.SandboxName, remaining: req.PodSupplement})
 			}
-			action.ToAllocate[n.sandboxName] = append(action.ToAllocate[n.sandboxName], availablePods[podIdx])
-			podIdx++
-			n.remaining--
-			if n.remaining > 0 {
-				nextRound = append(nextRound, n)
+		}
+		start = end
+
+		// Round-robin: each round give one pod to each sandbox that still needs pods.
+		for podIdx < len(availablePods) && len(needs) > 0 {
+			var nextRound []needEntry
+			for i, n := range needs {
+				if podIdx >= len(availablePods) {
+					// Out of pods mid-round: carry the rest over as unmet need.
+					nextRound = append(nextRound, needs[i:]...)
+					break
+				}
+				action.ToAllocate[n.sandboxName] = append(action.ToAllocate[n.sandboxName], availablePods[podIdx])
+				podIdx++
+				n.remaining--
+				if n.remaining > 0 {
+					nextRound = append(nextRound, n)
+				}
 			}
+			needs = nextRound
 		}
-		needs = nextRound
-	}
 
-	// Calculate PodSupplement: any remaining unmet need across all sandboxes.
-	for _, n := range needs {
-		action.PodSupplement += n.remaining
+		// Calculate PodSupplement: any remaining unmet need across the tier.
+		for _, n := range needs {
+			action.PodSupplement += n.remaining
+		}
 	}
 
 	return action
@@ -104,6 +104,31 @@ func TestSpreadSchedule(t *testing.T) {
 			wantRelease:    map[string][]string{},
 			wantSupplement: 1, // 1 remaining for sbx1
 		},
+		{
+			name:          "RunsOutMidRound",
+			availablePods: []string{"pod1", "pod2", "pod3", "pod4"},
+			allRequest: []*SandboxRequest{
+				{SandboxName: "sbx1", PodSupplement: 2},
+				{SandboxName: "sbx2", PodSupplement: 2},
+				{SandboxName: "sbx3", PodSupplement: 2},
+			},
+			wantAllocate:   map[string][]string{"sbx1": {"pod1", "pod4"}, "sbx2": {"pod2"}, "sbx3": {"pod3"}},
+			wantRelease:    map[string][]string{},
+			wantSupplement: 2, // 1 remaining for sbx2 + 1 for sbx3
+		},
+		{
+			name:          "PriorityTiers",
+			availablePods: []string{"pod1", "pod2", "pod3", "pod4"},
+			allRequest: []*SandboxRequest{
+				{SandboxName: "batch", PodSupplement: 3},
+				{SandboxName: "agent1", PodSupplement: 1, Priority: 10},
+				{SandboxName: "agent2", PodSupplement: 2, Priority: 10},
+			},
+			// Tier 10: agent1→pod1, agent2→pod2, agent2→pod3; tier 0: batch→pod4
+			wantAllocate:   map[string][]string{"agent1": {"pod1"}, "agent2": {"pod2", "pod3"}, "batch": {"pod4"}},
+			wantRelease:    map[string][]string{},
+			wantSupplement: 2, // 2 remaining for batch
+		},
 	}
 
 	for _, tt := range tests {
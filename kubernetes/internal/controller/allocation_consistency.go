@@ -0,0 +1,188 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+const (
+	// DefaultAllocationCheckInterval is how often a pool's stored allocation is compared with the
+	// BatchSandbox annotations.
+	DefaultAllocationCheckInterval = 5 * time.Minute
+	// allocationDriftConfirmDelay is how long after detecting drift the pool is checked again to
+	// confirm it before repairing.
+	allocationDriftConfirmDelay = 10 * time.Second
+)
+
+// AllocationDrift is a pod whose allocation in the AllocationStore disagrees with the BatchSandbox annotations.
+type AllocationDrift struct {
+	Pod string
+	// Stored is the sandbox the store allocates the pod to, empty if none.
+	Stored string
+	// Expected is the sandbox whose annotations hold the pod, empty if none.
+	Expected string
+}
+
+func (d AllocationDrift) String() string {
+	return fmt.Sprintf("%s(store=%q, annotations=%q)", d.Pod, d.Stored, d.Expected)
+}
+
+// diffPoolAllocation compares the stored allocation with the pods held by the annotations of
+// sandboxes. Pods stored for sandboxes that no longer exist are skipped: the allocator already
+// releases them through the recycle path.
+func diffPoolAllocation(ctx context.Context, syncer AllocationSyncer, stored map[string]string, sandboxes []*sandboxv1alpha1.BatchSandbox) ([]AllocationDrift, error) {
+	existing := make(map[string]struct{}, len(sandboxes))
+	expected := make(map[string]string)
+	for _, sbx := range sandboxes {
+		existing[sbx.Name] = struct{}{}
+		pods, err := sandboxHeldPods(ctx, syncer, sbx)
+		if err != nil {
+			return nil, fmt.Errorf("sandbox %s: %w", sbx.Name, err)
+		}
+		for _, podName := range pods {
+			expected[podName] = sbx.Name
+		}
+	}
+
+	var drift []AllocationDrift
+	for podName, sandboxName := range stored {
+		if _, ok := existing[sandboxName]; !ok && expected[podName] == "" {
+			continue
+		}
+		if expected[podName] != sandboxName {
+			drift = append(drift, AllocationDrift{Pod: podName, Stored: sandboxName, Expected: expected[podName]})
+		}
+	}
+	for podName, sandboxName := range expected {
+		if _, ok := stored[podName]; !ok {
+			drift = append(drift, AllocationDrift{Pod: podName, Expected: sandboxName})
+		}
+	}
+	slices.SortFunc(drift, func(a, b AllocationDrift) int { return strings.Compare(a.Pod, b.Pod) })
+	return drift, nil
+}
+
+// poolConsistency is the consistency check state of one pool.
+type poolConsistency struct {
+	lastCheck time.Time
+	// pending holds drift seen by the last check that is repaired if the next check sees it again.
+	pending []AllocationDrift
+}
+
+// allocationChecker tracks when pools are due for a consistency check.
+type allocationChecker struct {
+	interval time.Duration
+	mu       sync.Mutex
+	pools    map[string]*poolConsistency
+}
+
+// due reports whether the pool should be checked now and returns its state.
+func (c *allocationChecker) due(key string, now time.Time) (*poolConsistency, bool) {
+	if c == nil || c.interval <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pools == nil {
+		c.pools = make(map[string]*poolConsistency)
+	}
+	state, ok := c.pools[key]
+	if !ok {
+		state = &poolConsistency{}
+		c.pools[key] = state
+	}
+	wait := c.interval
+	if len(state.pending) > 0 {
+		wait = allocationDriftConfirmDelay
+	}
+	return state, now.Sub(state.lastCheck) >= wait
+}
+
+func (c *allocationChecker) forget(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pools, key)
+}
+
+// CheckPoolAllocation compares the pool allocation held by the store with the BatchSandbox
+// annotations at most once per check interval. Drift must be seen by two consecutive checks
+// before it is repaired, so that annotation updates not yet visible in the informer cache are not
+// mistaken for drift. The store is then corrected to match the annotations and the repaired drift
+// is returned. It must be called from the pool's reconcile, before Schedule.
+func (allocator *defaultAllocator) CheckPoolAllocation(ctx context.Context, pool *sandboxv1alpha1.Pool, sandboxes []*sandboxv1alpha1.BatchSandbox) ([]AllocationDrift, error) {
+	now := time.Now()
+	state, due := allocator.checker.due(poolKey(pool.Namespace, pool.Name), now)
+	if !due {
+		return nil, nil
+	}
+	if err := allocator.checkRecovery(ctx); err != nil {
+		return nil, err
+	}
+	stored, err := allocator.GetPoolAllocation(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	drift, err := diffPoolAllocation(ctx, allocator.syncer, stored, sandboxes)
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmed, pending []AllocationDrift
+	for _, d := range drift {
+		if slices.Contains(state.pending, d) {
+			confirmed = append(confirmed, d)
+		} else {
+			pending = append(pending, d)
+		}
+	}
+	if len(pending) > 0 {
+		logf.FromContext(ctx).Info("Detected allocation drift, will confirm on next check", "pool", pool.Name, "drift", pending)
+	}
+	if len(confirmed) > 0 {
+		repaired := make(map[string]string, len(stored))
+		for podName, sandboxName := range stored {
+			repaired[podName] = sandboxName
+		}
+		for _, d := range confirmed {
+			if d.Expected == "" {
+				delete(repaired, d.Pod)
+			} else {
+				repaired[d.Pod] = d.Expected
+			}
+		}
+		if err := allocator.store.SetAllocation(ctx, pool, &PoolAllocation{PodAllocation: repaired}); err != nil {
+			return nil, fmt.Errorf("failed to repair pool allocation: %w", err)
+		}
+	}
+
+	allocator.checker.mu.Lock()
+	state.lastCheck = now
+	state.pending = pending
+	allocator.checker.mu.Unlock()
+	return confirmed, nil
+}
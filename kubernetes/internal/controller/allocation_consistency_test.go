@@ -0,0 +1,110 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func driftTestSandbox(name string, allocated, released string) *sandboxv1alpha1.BatchSandbox {
+	sbx := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: map[string]string{}},
+		Spec:       sandboxv1alpha1.BatchSandboxSpec{PoolRef: "pool"},
+	}
+	if allocated != "" {
+		sbx.Annotations[AnnoAllocStatusKey] = allocated
+	}
+	if released != "" {
+		sbx.Annotations[AnnoAllocReleasedKey] = released
+	}
+	return sbx
+}
+
+func TestDiffPoolAllocation(t *testing.T) {
+	sandboxes := []*sandboxv1alpha1.BatchSandbox{
+		driftTestSandbox("sbx1", `{"pods":["pod1","pod2"]}`, `{"pods":["pod2"]}`),
+		driftTestSandbox("sbx2", `{"pods":["pod3","pod4"]}`, ""),
+	}
+	stored := map[string]string{
+		"pod1": "sbx1",   // consistent
+		"pod2": "sbx1",   // released in annotations
+		"pod3": "sbx1",   // held by another sandbox
+		"pod5": "gone",   // orphan, left to the recycle path
+		"pod6": "sbx2",   // not in annotations
+		"pod7": "absent", // orphan
+	}
+	drift, err := diffPoolAllocation(context.Background(), &annoAllocationSyncer{}, stored, sandboxes)
+	require.NoError(t, err)
+	assert.Equal(t, []AllocationDrift{
+		{Pod: "pod2", Stored: "sbx1"},
+		{Pod: "pod3", Stored: "sbx1", Expected: "sbx2"},
+		{Pod: "pod4", Expected: "sbx2"},
+		{Pod: "pod6", Stored: "sbx2"},
+	}, drift)
+}
+
+func TestCheckPoolAllocation(t *testing.T) {
+	ctx := context.Background()
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"}}
+	store := NewInMemoryAllocationStore()
+	allocator := &defaultAllocator{
+		store:   store,
+		syncer:  &annoAllocationSyncer{},
+		checker: &allocationChecker{interval: time.Hour},
+	}
+	allocator.recoverOnce.Do(func() {})
+	require.NoError(t, store.SetAllocation(ctx, pool, &PoolAllocation{PodAllocation: map[string]string{"pod1": "sbx1", "pod9": "sbx1"}}))
+	sandboxes := []*sandboxv1alpha1.BatchSandbox{driftTestSandbox("sbx1", `{"pods":["pod1","pod2"]}`, "")}
+
+	// The first check only records the drift.
+	drift, err := allocator.CheckPoolAllocation(ctx, pool, sandboxes)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	// The pool is not due again until the confirm delay has passed.
+	drift, err = allocator.CheckPoolAllocation(ctx, pool, sandboxes)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	state := allocator.checker.pools[poolKey("default", "pool")]
+	state.lastCheck = state.lastCheck.Add(-allocationDriftConfirmDelay)
+	drift, err = allocator.CheckPoolAllocation(ctx, pool, sandboxes)
+	require.NoError(t, err)
+	assert.Equal(t, []AllocationDrift{{Pod: "pod2", Expected: "sbx1"}, {Pod: "pod9", Stored: "sbx1"}}, drift)
+
+	alloc, err := store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod1": "sbx1", "pod2": "sbx1"}, alloc.PodAllocation)
+
+	// Drift that disappears before it is confirmed is not repaired.
+	store.UpdateAllocation(ctx, "default", "pool", "sbx1", []string{"pod1"})
+	state.lastCheck = state.lastCheck.Add(-time.Hour)
+	_, err = allocator.CheckPoolAllocation(ctx, pool, sandboxes)
+	require.NoError(t, err)
+	store.UpdateAllocation(ctx, "default", "pool", "sbx1", []string{"pod1", "pod2"})
+	state.lastCheck = state.lastCheck.Add(-allocationDriftConfirmDelay)
+	drift, err = allocator.CheckPoolAllocation(ctx, pool, sandboxes)
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+	assert.Empty(t, state.pending)
+}
@@ -0,0 +1,438 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+// Allocation store implementations selectable with the --allocation-store flag.
+const (
+	AllocationStoreMemory    = "memory"
+	AllocationStoreConfigMap = "configmap"
+)
+
+const (
+	allocationConfigMapSuffix = "-allocation"
+	allocationConfigMapKey    = "allocation"
+)
+
+// allocationConfigMapName returns the name of the ConfigMap that persists the allocation of a Pool.
+func allocationConfigMapName(poolName string) string {
+	return poolName + allocationConfigMapSuffix
+}
+
+// durablePoolEntry is the in-memory copy of one pool's persisted allocation.
+type durablePoolEntry struct {
+	namespace string
+	name      string
+
+	mu   sync.Mutex
+	data map[string]string // podName -> sandboxName
+	uid  types.UID
+	// gen is incremented on every change of data.
+	gen uint64
+
+	// persistMu serializes ConfigMap writes and guards the fields below. A write always persists
+	// the latest data, so callers whose change is already covered by persistedGen return early.
+	persistMu    sync.Mutex
+	persistedGen uint64
+	// persisted is the allocation stored at resourceVersion, the base that in-memory changes
+	// are merged from when another writer changed the ConfigMap.
+	persisted       map[string]string
+	resourceVersion string
+}
+
+// checkAllocationConfigMap refuses a ConfigMap that was not written by the store for poolName:
+// it must carry the pool label and a Pool owner reference, matching uid when it is known.
+func checkAllocationConfigMap(cm *corev1.ConfigMap, poolName string, uid types.UID) error {
+	if cm.Labels[LabelAllocationPoolKey] != poolName {
+		return fmt.Errorf("configmap %s/%s is not labeled %s=%s, refusing to use it", cm.Namespace, cm.Name, LabelAllocationPoolKey, poolName)
+	}
+	for _, ref := range cm.OwnerReferences {
+		if ref.Kind == "Pool" && ref.Name == poolName && (uid == "" || ref.UID == uid) {
+			return nil
+		}
+	}
+	return fmt.Errorf("configmap %s/%s is not owned by Pool %s, refusing to use it", cm.Namespace, cm.Name, poolName)
+}
+
+// ConfigMapAllocationStore keeps each Pool's pod-to-sandbox allocation in a ConfigMap owned by the Pool.
+// Reads are served from memory; every change is written through with the ConfigMap's resourceVersion
+// so that a concurrent writer, such as a previous leader, is detected instead of silently overwritten.
+// Recover loads the ConfigMaps instead of listing every BatchSandbox, and only falls back to the
+// annotations for pools that do not have a ConfigMap yet.
+type ConfigMapAllocationStore struct {
+	client client.Client
+	// reader reads ConfigMaps from the API server so that neither a stale cache nor a cluster-wide
+	// ConfigMap informer is involved.
+	reader  client.Reader
+	syncer  AllocationSyncer
+	poolsMu sync.RWMutex
+	pools   map[string]*durablePoolEntry
+}
+
+func NewConfigMapAllocationStore(c client.Client, reader client.Reader) AllocationStore {
+	return &ConfigMapAllocationStore{
+		client: c,
+		reader: reader,
+		syncer: &annoAllocationSyncer{},
+		pools:  make(map[string]*durablePoolEntry),
+	}
+}
+
+// Recover loads the allocation ConfigMaps. Pools without one, e.g. after switching from the
+// in-memory store, are rebuilt from BatchSandbox annotations and persisted on their next schedule.
+// This should be called once during controller initialization before reconcile starts.
+func (store *ConfigMapAllocationStore) Recover(ctx context.Context, c client.Client) error {
+	log := logf.FromContext(ctx)
+	log.Info("Starting allocation recovery from ConfigMaps")
+
+	cmList := &corev1.ConfigMapList{}
+	if err := store.reader.List(ctx, cmList, client.HasLabels{LabelAllocationPoolKey}); err != nil {
+		return fmt.Errorf("failed to list allocation configmaps for recovery: %w", err)
+	}
+	newPools := make(map[string]*durablePoolEntry, len(cmList.Items))
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		poolName := cm.Labels[LabelAllocationPoolKey]
+		if err := checkAllocationConfigMap(cm, poolName, ""); err != nil {
+			log.Info("Ignoring allocation ConfigMap", "reason", err.Error())
+			continue
+		}
+		data := make(map[string]string)
+		if raw := cm.Data[allocationConfigMapKey]; raw != "" {
+			if err := json.Unmarshal([]byte(raw), &data); err != nil {
+				return fmt.Errorf("failed to unmarshal allocation configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+			}
+		}
+		entry := &durablePoolEntry{
+			namespace:       cm.Namespace,
+			name:            poolName,
+			data:            data,
+			persisted:       maps.Clone(data),
+			resourceVersion: cm.ResourceVersion,
+		}
+		for _, ref := range cm.OwnerReferences {
+			if ref.Kind == "Pool" && ref.Name == poolName {
+				entry.uid = ref.UID
+			}
+		}
+		newPools[poolKey(cm.Namespace, poolName)] = entry
+	}
+
+	poolList := &sandboxv1alpha1.PoolList{}
+	if err := c.List(ctx, poolList); err != nil {
+		return fmt.Errorf("failed to list pools for recovery: %w", err)
+	}
+	missing := make(map[string]*durablePoolEntry)
+	for i := range poolList.Items {
+		pool := &poolList.Items[i]
+		key := poolKey(pool.Namespace, pool.Name)
+		if _, ok := newPools[key]; ok {
+			continue
+		}
+		// gen > persistedGen marks the entry dirty so it is persisted on first use.
+		entry := &durablePoolEntry{namespace: pool.Namespace, name: pool.Name, uid: pool.UID, data: make(map[string]string), gen: 1}
+		newPools[key] = entry
+		missing[key] = entry
+	}
+	if len(missing) > 0 {
+		log.Info("Rebuilding allocation from BatchSandboxes for pools without a ConfigMap", "pools", len(missing))
+		batchSandboxList := &sandboxv1alpha1.BatchSandboxList{}
+		if err := c.List(ctx, batchSandboxList); err != nil {
+			return fmt.Errorf("failed to list batch sandboxes for recovery: %w", err)
+		}
+		for i := range batchSandboxList.Items {
+			sbx := &batchSandboxList.Items[i]
+			entry, ok := missing[poolKey(sbx.Namespace, sbx.Spec.PoolRef)]
+			if sbx.Spec.PoolRef == "" || !ok {
+				continue
+			}
+			pods, err := sandboxHeldPods(ctx, store.syncer, sbx)
+			if err != nil {
+				log.Error(err, "Failed to read sandbox allocation during recovery", "sandbox", sbx.Name)
+				return err
+			}
+			for _, podName := range pods {
+				entry.data[podName] = sbx.Name
+			}
+		}
+	}
+
+	store.poolsMu.Lock()
+	store.pools = newPools
+	store.poolsMu.Unlock()
+
+	log.Info("Allocation recovery completed", "totalPools", len(newPools), "rebuiltPools", len(missing))
+	return nil
+}
+
+func (store *ConfigMapAllocationStore) ClearAllocation(ctx context.Context, ns string, poolName string) error {
+	log := logf.FromContext(ctx)
+	log.Info("Clearing pool allocation", "namespace", ns, "pool", poolName)
+	store.poolsMu.Lock()
+	delete(store.pools, poolKey(ns, poolName))
+	store.poolsMu.Unlock()
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: allocationConfigMapName(poolName)}}
+	if err := store.client.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete allocation configmap: %w", err)
+	}
+	return nil
+}
+
+// GetAllocation returns the in-memory allocation. Changes whose write failed earlier are retried first.
+// A conflict is returned so that the caller schedules again from the merged allocation.
+func (store *ConfigMapAllocationStore) GetAllocation(ctx context.Context, pool *sandboxv1alpha1.Pool) (*PoolAllocation, error) {
+	entry := store.getOrCreatePool(pool.Namespace, pool.Name)
+	entry.mu.Lock()
+	entry.uid = pool.UID
+	entry.mu.Unlock()
+
+	if err := store.persist(ctx, entry); err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, err
+		}
+		logf.FromContext(ctx).Error(err, "Failed to persist pool allocation", "pool", pool.Name)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return &PoolAllocation{PodAllocation: maps.Clone(entry.data)}, nil
+}
+
+func (store *ConfigMapAllocationStore) SetAllocation(ctx context.Context, pool *sandboxv1alpha1.Pool, alloc *PoolAllocation) error {
+	entry := store.getOrCreatePool(pool.Namespace, pool.Name)
+	store.mutate(entry, func(data map[string]string) {
+		entry.uid = pool.UID
+		clear(data)
+		for podName, sandboxName := range alloc.PodAllocation {
+			data[podName] = sandboxName
+		}
+	})
+	return store.persist(ctx, entry)
+}
+
+func (store *ConfigMapAllocationStore) ReleaseAllocation(ctx context.Context, ns string, poolName string, pods []string) {
+	entry := store.getOrCreatePool(ns, poolName)
+	store.mutate(entry, func(data map[string]string) {
+		for _, podName := range pods {
+			delete(data, podName)
+		}
+	})
+	store.persistOrLog(ctx, entry)
+}
+
+func (store *ConfigMapAllocationStore) UpdateAllocation(ctx context.Context, ns string, poolName string, sandboxName string, pods []string) {
+	entry := store.getOrCreatePool(ns, poolName)
+	store.mutate(entry, func(data map[string]string) {
+		for podName, sbxName := range data {
+			if sbxName == sandboxName {
+				delete(data, podName)
+			}
+		}
+		for _, podName := range pods {
+			data[podName] = sandboxName
+		}
+	})
+	store.persistOrLog(ctx, entry)
+}
+
+func (store *ConfigMapAllocationStore) ReleaseSandboxAllocation(ctx context.Context, ns string, poolName string, sandboxName string) {
+	store.poolsMu.RLock()
+	entry, exists := store.pools[poolKey(ns, poolName)]
+	store.poolsMu.RUnlock()
+	if !exists {
+		return
+	}
+	store.mutate(entry, func(data map[string]string) {
+		for podName, sbxName := range data {
+			if sbxName == sandboxName {
+				delete(data, podName)
+			}
+		}
+	})
+	store.persistOrLog(ctx, entry)
+}
+
+func (store *ConfigMapAllocationStore) mutate(entry *durablePoolEntry, fn func(data map[string]string)) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	fn(entry.data)
+	entry.gen++
+}
+
+// persistOrLog persists the entry for the AllocationStore methods that cannot return an error.
+// The entry stays dirty on failure and is written again by the next GetAllocation.
+func (store *ConfigMapAllocationStore) persistOrLog(ctx context.Context, entry *durablePoolEntry) {
+	if err := store.persist(ctx, entry); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to persist pool allocation, will retry", "namespace", entry.namespace, "pool", entry.name)
+	}
+}
+
+// persist writes the latest data of entry to its ConfigMap unless that generation is already persisted.
+// If another writer changed or deleted the ConfigMap, the in-memory changes are merged onto the stored
+// allocation and a conflict is returned; the merged allocation is written by the next persist.
+func (store *ConfigMapAllocationStore) persist(ctx context.Context, entry *durablePoolEntry) error {
+	entry.persistMu.Lock()
+	defer entry.persistMu.Unlock()
+
+	entry.mu.Lock()
+	gen, uid := entry.gen, entry.uid
+	if gen == entry.persistedGen {
+		entry.mu.Unlock()
+		return nil
+	}
+	data := maps.Clone(entry.data)
+	entry.mu.Unlock()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pool allocation: %w", err)
+	}
+
+	name := allocationConfigMapName(entry.name)
+	err = store.writeConfigMap(ctx, entry, string(raw), uid)
+	if err == nil {
+		entry.persisted = data
+		entry.persistedGen = gen
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to write allocation configmap %s/%s: %w", entry.namespace, name, err)
+	}
+	logf.FromContext(ctx).Info("Allocation ConfigMap was changed by another writer, merging with the stored allocation",
+		"namespace", entry.namespace, "pool", entry.name, "reason", err.Error())
+	if rebaseErr := store.rebase(ctx, entry, uid); rebaseErr != nil {
+		return fmt.Errorf("failed to merge allocation configmap %s/%s: %w", entry.namespace, name, rebaseErr)
+	}
+	return apierrors.NewConflict(corev1.Resource("configmaps"), name, err)
+}
+
+// rebase reloads the ConfigMap of entry and applies the in-memory changes made since the last
+// successful write on top of it: pods released in memory are dropped unless the stored allocation
+// reassigned them, and pods allocated in memory override the stored ones. If the ConfigMap is gone,
+// the in-memory allocation is kept and recreated. The caller must hold entry.persistMu.
+func (store *ConfigMapAllocationStore) rebase(ctx context.Context, entry *durablePoolEntry, uid types.UID) error {
+	current := &corev1.ConfigMap{}
+	err := store.reader.Get(ctx, client.ObjectKey{Namespace: entry.namespace, Name: allocationConfigMapName(entry.name)}, current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if apierrors.IsNotFound(err) {
+		entry.persisted = nil
+		entry.resourceVersion = ""
+		entry.gen++
+		return nil
+	}
+	if err := checkAllocationConfigMap(current, entry.name, uid); err != nil {
+		return err
+	}
+	stored := make(map[string]string)
+	if raw := current.Data[allocationConfigMapKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			return fmt.Errorf("failed to unmarshal allocation configmap %s/%s: %w", current.Namespace, current.Name, err)
+		}
+	}
+	merged := maps.Clone(stored)
+	for podName, sandboxName := range entry.persisted {
+		if _, ok := entry.data[podName]; !ok && merged[podName] == sandboxName {
+			delete(merged, podName)
+		}
+	}
+	for podName, sandboxName := range entry.data {
+		if entry.persisted[podName] != sandboxName {
+			merged[podName] = sandboxName
+		}
+	}
+	entry.data = merged
+	entry.persisted = stored
+	entry.resourceVersion = current.ResourceVersion
+	entry.gen++
+	return nil
+}
+
+// writeConfigMap creates or updates the ConfigMap of entry with the last resourceVersion it wrote.
+// The caller must hold entry.persistMu.
+func (store *ConfigMapAllocationStore) writeConfigMap(ctx context.Context, entry *durablePoolEntry, raw string, uid types.UID) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       entry.namespace,
+			Name:            allocationConfigMapName(entry.name),
+			ResourceVersion: entry.resourceVersion,
+			Labels:          map[string]string{LabelAllocationPoolKey: entry.name},
+		},
+		Data: map[string]string{allocationConfigMapKey: raw},
+	}
+	if uid != "" {
+		cm.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: sandboxv1alpha1.GroupVersion.String(),
+			Kind:       "Pool",
+			Name:       entry.name,
+			UID:        uid,
+		}}
+	}
+
+	var err error
+	if entry.resourceVersion == "" {
+		err = store.client.Create(ctx, cm)
+	} else {
+		err = store.client.Update(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+	entry.resourceVersion = cm.ResourceVersion
+	return nil
+}
+
+func (store *ConfigMapAllocationStore) getOrCreatePool(ns string, poolName string) *durablePoolEntry {
+	key := poolKey(ns, poolName)
+	store.poolsMu.RLock()
+	entry, exists := store.pools[key]
+	store.poolsMu.RUnlock()
+	if exists {
+		return entry
+	}
+
+	store.poolsMu.Lock()
+	defer store.poolsMu.Unlock()
+	if entry, exists := store.pools[key]; exists {
+		return entry
+	}
+	entry = &durablePoolEntry{namespace: ns, name: poolName, data: make(map[string]string)}
+	store.pools[key] = entry
+	return entry
+}
+
+func poolKey(ns, name string) string {
+	return ns + "/" + name
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func newAllocationStoreTestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, sandboxv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func getAllocationConfigMap(t *testing.T, c client.Client, ns, pool string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: allocationConfigMapName(pool)}, cm))
+	return cm
+}
+
+func TestConfigMapAllocationStore_PersistAndRecover(t *testing.T) {
+	ctx := context.Background()
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"}}
+	c := newAllocationStoreTestClient(t, pool)
+	store := NewConfigMapAllocationStore(c, c)
+	require.NoError(t, store.Recover(ctx, c))
+
+	alloc, err := store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	assert.Empty(t, alloc.PodAllocation)
+
+	store.UpdateAllocation(ctx, "default", "pool", "sbx1", []string{"pod1", "pod2"})
+	store.UpdateAllocation(ctx, "default", "pool", "sbx2", []string{"pod3"})
+	store.ReleaseAllocation(ctx, "default", "pool", []string{"pod2"})
+
+	cm := getAllocationConfigMap(t, c, "default", "pool")
+	assert.Equal(t, "pool", cm.Labels[LabelAllocationPoolKey])
+	require.Len(t, cm.OwnerReferences, 1)
+	assert.Equal(t, pool.UID, cm.OwnerReferences[0].UID)
+	assert.JSONEq(t, `{"pod1":"sbx1","pod3":"sbx2"}`, cm.Data[allocationConfigMapKey])
+
+	store.ReleaseSandboxAllocation(ctx, "default", "pool", "sbx2")
+	cm = getAllocationConfigMap(t, c, "default", "pool")
+	assert.JSONEq(t, `{"pod1":"sbx1"}`, cm.Data[allocationConfigMapKey])
+
+	// A new store recovers from the ConfigMap without reading BatchSandboxes.
+	recovered := NewConfigMapAllocationStore(c, c)
+	require.NoError(t, recovered.Recover(ctx, c))
+	alloc, err = recovered.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod1": "sbx1"}, alloc.PodAllocation)
+
+	require.NoError(t, recovered.ClearAllocation(ctx, "default", "pool"))
+	err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: allocationConfigMapName("pool")}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestConfigMapAllocationStore_RecoverFromAnnotations(t *testing.T) {
+	ctx := context.Background()
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"}}
+	sbx := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sbx1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnoAllocStatusKey:   `{"pods":["pod1","pod2"]}`,
+				AnnoAllocReleasedKey: `{"pods":["pod2"]}`,
+			},
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{PoolRef: "pool"},
+	}
+	c := newAllocationStoreTestClient(t, pool, sbx)
+	store := NewConfigMapAllocationStore(c, c)
+	require.NoError(t, store.Recover(ctx, c))
+
+	alloc, err := store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod1": "sbx1"}, alloc.PodAllocation)
+
+	// The rebuilt allocation is persisted on first use.
+	cm := getAllocationConfigMap(t, c, "default", "pool")
+	assert.JSONEq(t, `{"pod1":"sbx1"}`, cm.Data[allocationConfigMapKey])
+}
+
+func TestConfigMapAllocationStore_ConcurrentWriter(t *testing.T) {
+	ctx := context.Background()
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"}}
+	c := newAllocationStoreTestClient(t, pool)
+	store := NewConfigMapAllocationStore(c, c)
+	require.NoError(t, store.SetAllocation(ctx, pool, &PoolAllocation{PodAllocation: map[string]string{"pod1": "sbx1", "pod3": "sbx3"}}))
+
+	// Another writer changes the ConfigMap. The store's next write conflicts and is not retried
+	// blindly: the other writer's change survives and is merged with the in-memory one.
+	cm := getAllocationConfigMap(t, c, "default", "pool")
+	cm.Data[allocationConfigMapKey] = `{"pod1":"sbx1","pod3":"sbx3","pod9":"other"}`
+	require.NoError(t, c.Update(ctx, cm))
+
+	store.UpdateAllocation(ctx, "default", "pool", "sbx2", []string{"pod2"})
+	cm = getAllocationConfigMap(t, c, "default", "pool")
+	assert.JSONEq(t, `{"pod1":"sbx1","pod3":"sbx3","pod9":"other"}`, cm.Data[allocationConfigMapKey])
+
+	store.ReleaseAllocation(ctx, "default", "pool", []string{"pod3"})
+	alloc, err := store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod1": "sbx1", "pod2": "sbx2", "pod9": "other"}, alloc.PodAllocation)
+	cm = getAllocationConfigMap(t, c, "default", "pool")
+	assert.JSONEq(t, `{"pod1":"sbx1","pod2":"sbx2","pod9":"other"}`, cm.Data[allocationConfigMapKey])
+
+	// A pod released in memory is dropped from the merge; pods the other writer removed stay removed.
+	cm.Data[allocationConfigMapKey] = `{"pod1":"sbx1","pod2":"sbx2"}`
+	require.NoError(t, c.Update(ctx, cm))
+	store.ReleaseAllocation(ctx, "default", "pool", []string{"pod1"})
+	store.UpdateAllocation(ctx, "default", "pool", "sbx4", []string{"pod4"})
+	_, err = store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	cm = getAllocationConfigMap(t, c, "default", "pool")
+	assert.JSONEq(t, `{"pod2":"sbx2","pod4":"sbx4"}`, cm.Data[allocationConfigMapKey])
+
+	// The ConfigMap is recreated from memory if it was deleted.
+	require.NoError(t, c.Delete(ctx, cm))
+	store.ReleaseAllocation(ctx, "default", "pool", []string{"pod4"})
+	_, err = store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	cm = getAllocationConfigMap(t, c, "default", "pool")
+	assert.JSONEq(t, `{"pod2":"sbx2"}`, cm.Data[allocationConfigMapKey])
+}
+
+func TestConfigMapAllocationStore_GetAllocationReturnsConflict(t *testing.T) {
+	ctx := context.Background()
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"}}
+	c := newAllocationStoreTestClient(t, pool)
+	store := NewConfigMapAllocationStore(c, c).(*ConfigMapAllocationStore)
+	require.NoError(t, store.SetAllocation(ctx, pool, &PoolAllocation{PodAllocation: map[string]string{"pod1": "sbx1"}}))
+
+	cm := getAllocationConfigMap(t, c, "default", "pool")
+	cm.Data[allocationConfigMapKey] = `{"pod1":"sbx1","pod9":"other"}`
+	require.NoError(t, c.Update(ctx, cm))
+	// Change memory without writing, as a write that failed for another reason would.
+	entry := store.getOrCreatePool("default", "pool")
+	store.mutate(entry, func(data map[string]string) { data["pod2"] = "sbx2" })
+
+	_, err := store.GetAllocation(ctx, pool)
+	require.True(t, apierrors.IsConflict(err), "got %v", err)
+	alloc, err := store.GetAllocation(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod1": "sbx1", "pod2": "sbx2", "pod9": "other"}, alloc.PodAllocation)
+}
+
+func TestConfigMapAllocationStore_RefusesForeignConfigMap(t *testing.T) {
+	ctx := context.Background()
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"}}
+	for name, foreign := range map[string]*corev1.ConfigMap{
+		"no label": {ObjectMeta: metav1.ObjectMeta{Name: allocationConfigMapName("pool"), Namespace: "default"}},
+		"no owner": {ObjectMeta: metav1.ObjectMeta{Name: allocationConfigMapName("pool"), Namespace: "default",
+			Labels: map[string]string{LabelAllocationPoolKey: "pool"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			foreign.Data = map[string]string{allocationConfigMapKey: `{"pod9":"other"}`, "app": "config"}
+			c := newAllocationStoreTestClient(t, pool, foreign)
+			store := NewConfigMapAllocationStore(c, c)
+			require.NoError(t, store.Recover(ctx, c))
+
+			store.UpdateAllocation(ctx, "default", "pool", "sbx1", []string{"pod1"})
+			alloc, err := store.GetAllocation(ctx, pool)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"pod1": "sbx1"}, alloc.PodAllocation)
+
+			cm := getAllocationConfigMap(t, c, "default", "pool")
+			assert.Equal(t, foreign.Data, cm.Data, "a ConfigMap the store does not own is never overwritten")
+		})
+	}
+}
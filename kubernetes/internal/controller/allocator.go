@@ -15,14 +15,19 @@
 package controller
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -78,14 +83,15 @@ func (store *InMemoryAllocationStore) Recover(ctx context.Context, c client.Clie
 	// Build new pools map first without holding the lock
 	newPools := make(map[string]*poolEntry)
 
-	for _, sbx := range batchSandboxList.Items {
+	for i := range batchSandboxList.Items {
+		sbx := &batchSandboxList.Items[i]
 		poolRef := sbx.Spec.PoolRef
 		if poolRef == "" {
 			continue
 		}
-		allocation, err := store.syncer.GetAllocation(ctx, &sbx)
+		pods, err := sandboxHeldPods(ctx, store.syncer, sbx)
 		if err != nil {
-			log.Error(err, "Failed to unmarshal sandbox allocation during recovery", "sandbox", sbx.Name)
+			log.Error(err, "Failed to read sandbox allocation during recovery", "sandbox", sbx.Name)
 			return err
 		}
 		key := store.poolKey(sbx.Namespace, poolRef)
@@ -96,25 +102,11 @@ func (store *InMemoryAllocationStore) Recover(ctx context.Context, c client.Clie
 			}
 			newPools[key] = entry
 		}
-
-		for _, podName := range allocation.Pods {
+		for _, podName := range pods {
 			entry.data[podName] = sbx.Name
 		}
-		// Filter pods that have already been released (alloc-released records completed recycle).
-		// alloc-release (in-progress) pods must NOT be filtered: the recycle handler is still
-		// processing them and they are still "in use" from the pool's perspective.
-		allocReleased, err := store.syncer.GetReleased(ctx, &sbx)
-		if err != nil {
-			log.Error(err, "Failed to unmarshal sandbox released during recovery", "sandbox", sbx.Name)
-			return err
-		}
-		for _, podName := range allocReleased.Pods {
-			if entry.data[podName] == sbx.Name {
-				delete(entry.data, podName)
-			}
-		}
 
-		log.Info("Recovered sandbox allocation", "pool", poolRef, "sandbox", sbx.Name, "pods", len(allocation.Pods))
+		log.Info("Recovered sandbox allocation", "pool", poolRef, "sandbox", sbx.Name, "pods", len(pods))
 	}
 
 	store.poolsMu.Lock()
@@ -125,6 +117,32 @@ func (store *InMemoryAllocationStore) Recover(ctx context.Context, c client.Clie
 	return nil
 }
 
+// sandboxHeldPods returns the pods the annotations of sandbox hold from its pool.
+// Pods that have already been released (alloc-released records completed recycle) are filtered.
+// alloc-release (in-progress) pods must NOT be filtered: the recycle handler is still
+// processing them and they are still "in use" from the pool's perspective.
+func sandboxHeldPods(ctx context.Context, syncer AllocationSyncer, sbx *sandboxv1alpha1.BatchSandbox) ([]string, error) {
+	allocation, err := syncer.GetAllocation(ctx, sbx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sandbox allocation: %w", err)
+	}
+	released, err := syncer.GetReleased(ctx, sbx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sandbox released: %w", err)
+	}
+	releasedSet := make(map[string]struct{}, len(released.Pods))
+	for _, podName := range released.Pods {
+		releasedSet[podName] = struct{}{}
+	}
+	held := make([]string, 0, len(allocation.Pods))
+	for _, podName := range allocation.Pods {
+		if _, ok := releasedSet[podName]; !ok {
+			held = append(held, podName)
+		}
+	}
+	return held, nil
+}
+
 func (store *InMemoryAllocationStore) ClearAllocation(ctx context.Context, ns string, poolName string) error {
 	log := logf.FromContext(ctx)
 	store.poolsMu.Lock()
@@ -421,6 +439,11 @@ type AllocSpec struct {
 	Pool      *sandboxv1alpha1.Pool
 	// Pods contains all candidate pods owned by the pool.
 	Pods []*corev1.Pod
+	// QuotaRejected holds the sandboxes that do not fit into the SandboxQuotas of the namespace.
+	// They keep their pods but receive no new ones.
+	QuotaRejected map[string]string
+	// MaxPods caps the pods allocated from the pool by SandboxQuota. Nil means unlimited.
+	MaxPods *int32
 }
 
 type Allocator interface {
@@ -434,6 +457,9 @@ type Allocator interface {
 	SyncSandboxReleased(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox, pods []string) error
 	GetSandboxAllocation(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox) ([]string, error)
 	GetSandboxReleased(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox) ([]string, error)
+	// CheckPoolAllocation periodically compares the pool allocation held by the store with the
+	// BatchSandbox annotations, repairs confirmed drift and returns it.
+	CheckPoolAllocation(ctx context.Context, pool *sandboxv1alpha1.Pool, sandboxes []*sandboxv1alpha1.BatchSandbox) ([]AllocationDrift, error)
 }
 
 type defaultAllocator struct {
@@ -442,14 +468,22 @@ type defaultAllocator struct {
 	client      client.Client
 	algorithm   algorithm.Algorithm
 	recoverOnce sync.Once
+	checker     *allocationChecker
 }
 
 func NewDefaultAllocator(client client.Client) Allocator {
+	return NewAllocator(client, NewInMemoryAllocationStore(), DefaultAllocationCheckInterval)
+}
+
+// NewAllocator creates an Allocator backed by the given store. checkInterval is how often each
+// pool's stored allocation is checked against the BatchSandbox annotations; zero disables the check.
+func NewAllocator(client client.Client, store AllocationStore, checkInterval time.Duration) Allocator {
 	return &defaultAllocator{
-		store:     NewInMemoryAllocationStore(),
+		store:     store,
 		syncer:    NewAnnoAllocationSyncer(client),
 		client:    client,
 		algorithm: &algorithm.PackedSchedule{},
+		checker:   &allocationChecker{interval: checkInterval},
 	}
 }
 
@@ -473,11 +507,13 @@ func (allocator *defaultAllocator) Schedule(ctx context.Context, spec *AllocSpec
 	// handles them without any special-casing outside this function.
 	// Terminating sandboxes are handled inside getSandboxRequest: they receive no new supplement and
 	// all unreleased pods are queued for release.
-	allRequest, err := allocator.getAllRequest(ctx, spec.Sandboxes, podAllocation)
+	allRequest, err := allocator.getAllRequest(ctx, spec.Pool, spec.Sandboxes, podAllocation)
 	if err != nil {
 		return nil, err
 	}
 
+	applySandboxQuota(allRequest, spec, int32(len(podAllocation)))
+
 	// Build available pod list using the already-fetched allocation to avoid an extra store read.
 	availablePods, err := allocator.getAvailablePodsFromAlloc(ctx, podAllocation, spec.Pods)
 	if err != nil {
@@ -487,20 +523,54 @@ func (allocator *defaultAllocator) Schedule(ctx context.Context, spec *AllocSpec
 	// Run the allocation algorithm.
 	action := allocator.algorithm.Schedule(availablePods, allRequest)
 
+	// Preempt lower-priority sandboxes for requests the pool could not fill. Pods that are
+	// already being released count towards the unmet need so preemption does not cascade
+	// while earlier victims are still recycling.
+	if spec.Pool.Spec.Preemption != nil {
+		inFlight := int32(0)
+		for _, req := range allRequest {
+			inFlight += int32(len(req.ToRelease))
+		}
+		algorithm.Preempt(action, allRequest, inFlight)
+	}
+
 	return action, nil
 }
 
+// applySandboxQuota drops the supplement of sandboxes rejected by quota and trims the remaining
+// supplements, highest priority first, so that allocated pods stay within the pool's quota.
+// Pods still being released count as allocated until they are recycled.
+func applySandboxQuota(requests []*algorithm.SandboxRequest, spec *AllocSpec, allocated int32) {
+	for _, req := range requests {
+		if _, rejected := spec.QuotaRejected[req.SandboxName]; rejected {
+			req.PodSupplement = 0
+		}
+	}
+	if spec.MaxPods == nil {
+		return
+	}
+	budget := max(*spec.MaxPods-allocated, 0)
+	byPriority := slices.Clone(requests)
+	slices.SortStableFunc(byPriority, func(a, b *algorithm.SandboxRequest) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+	for _, req := range byPriority {
+		req.PodSupplement = min(req.PodSupplement, budget)
+		budget -= req.PodSupplement
+	}
+}
+
 // getAllRequest builds per-sandbox allocation requests for all existing sandboxes and appends
 // orphan entries for pods in podAllocation whose sandbox is no longer in the sandboxes list
 // (e.g. force-deleted). Orphan entries carry PodSupplement=0 and ToRelease set to the orphan
 // pods so the normal recycle path handles them without special-casing in the caller.
-func (allocator *defaultAllocator) getAllRequest(ctx context.Context, sandboxes []*sandboxv1alpha1.BatchSandbox, podAllocation map[string]string) ([]*algorithm.SandboxRequest, error) {
+func (allocator *defaultAllocator) getAllRequest(ctx context.Context, pool *sandboxv1alpha1.Pool, sandboxes []*sandboxv1alpha1.BatchSandbox, podAllocation map[string]string) ([]*algorithm.SandboxRequest, error) {
 	log := logf.FromContext(ctx)
 	existingSandboxes := make(map[string]struct{}, len(sandboxes))
 	allRequest := make([]*algorithm.SandboxRequest, 0, len(sandboxes))
 	for _, sandbox := range sandboxes {
 		existingSandboxes[sandbox.Name] = struct{}{}
-		request, err := allocator.getSandboxRequest(ctx, sandbox)
+		request, err := allocator.getSandboxRequest(ctx, pool, sandbox)
 		if err != nil {
 			return nil, err
 		}
@@ -523,12 +593,13 @@ func (allocator *defaultAllocator) getAllRequest(ctx context.Context, sandboxes
 	return allRequest, nil
 }
 
-func (allocator *defaultAllocator) getSandboxRequest(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox) (*algorithm.SandboxRequest, error) {
+func (allocator *defaultAllocator) getSandboxRequest(ctx context.Context, pool *sandboxv1alpha1.Pool, sandbox *sandboxv1alpha1.BatchSandbox) (*algorithm.SandboxRequest, error) {
 	log := logf.FromContext(ctx)
-	allocated, err := allocator.GetSandboxAllocation(ctx, sandbox)
+	allocation, err := allocator.syncer.GetAllocation(ctx, sandbox)
 	if err != nil {
 		return nil, err
 	}
+	allocated := allocation.Pods
 	released, err := allocator.GetSandboxReleased(ctx, sandbox)
 	if err != nil {
 		return nil, err
@@ -572,23 +643,58 @@ func (allocator *defaultAllocator) getSandboxRequest(ctx context.Context, sandbo
 		}
 	}
 
+	// Preempted pods were taken by a higher-priority sandbox and are requested again.
+	preempted, err := parseSandboxPreempted(sandbox)
+	if err != nil {
+		return nil, err
+	}
+
 	replica := int32(0)
 	if sandbox.Spec.Replicas != nil {
 		replica = *sandbox.Spec.Replicas
 	}
 
 	supplement := int32(0)
-	if replica-int32(len(allocated)) > 0 {
-		supplement = replica - int32(len(allocated))
+	if replica-int32(len(allocated)-len(preempted.Pods)) > 0 {
+		supplement = replica - int32(len(allocated)-len(preempted.Pods))
 	}
 
-	return &algorithm.SandboxRequest{
+	request := &algorithm.SandboxRequest{
 		SandboxName:   sandbox.Name,
 		CurAllocation: allocated,
 		CurReleased:   released,
 		PodSupplement: supplement,
 		ToRelease:     toRelease,
-	}, nil
+	}
+	if sandbox.Spec.Priority != nil {
+		request.Priority = *sandbox.Spec.Priority
+	}
+	if preemption := pool.Spec.Preemption; preemption != nil {
+		request.CanPreempt = sandbox.Spec.PreemptionPolicy != nil &&
+			*sandbox.Spec.PreemptionPolicy == sandboxv1alpha1.PreemptionPolicyPreemptLowerPriority
+		request.Preemptible = getPreemptiblePods(allocation, release, preemption, time.Now())
+	}
+	return request, nil
+}
+
+// getPreemptiblePods returns the allocated pods that a higher-priority sandbox may take, most
+// recently allocated first. Pods are protected until MinRuntimeSeconds after the last allocation.
+func getPreemptiblePods(allocation *SandboxAllocation, release []string, preemption *sandboxv1alpha1.PoolPreemption, now time.Time) []string {
+	minRuntime := time.Duration(preemption.MinRuntimeSeconds) * time.Second
+	if allocation.AllocatedAt != nil && now.Sub(allocation.AllocatedAt.Time) < minRuntime {
+		return nil
+	}
+	releaseSet := make(map[string]struct{}, len(release))
+	for _, r := range release {
+		releaseSet[r] = struct{}{}
+	}
+	preemptible := make([]string, 0, len(allocation.Pods))
+	for i := len(allocation.Pods) - 1; i >= 0; i-- {
+		if _, ok := releaseSet[allocation.Pods[i]]; !ok {
+			preemptible = append(preemptible, allocation.Pods[i])
+		}
+	}
+	return preemptible
 }
 
 func (allocator *defaultAllocator) GetSandboxAllocation(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox) ([]string, error) {
@@ -627,6 +733,7 @@ func (allocator *defaultAllocator) GetPoolAllocation(ctx context.Context, pool *
 }
 
 func (allocator *defaultAllocator) ClearPoolAllocation(ctx context.Context, ns string, poolName string) error {
+	allocator.checker.forget(poolKey(ns, poolName))
 	return allocator.store.ClearAllocation(ctx, ns, poolName)
 }
 
@@ -651,7 +758,7 @@ func (allocator *defaultAllocator) SyncSandboxAllocation(ctx context.Context, sa
 	allocator.store.UpdateAllocation(ctx, sandbox.Namespace, poolRef, sandbox.Name, pods)
 
 	// Phase 2: persist to sandbox annotation.
-	allocation := &SandboxAllocation{Pods: pods}
+	allocation := &SandboxAllocation{Pods: pods, AllocatedAt: ptr.To(metav1.Now())}
 	if err := allocator.syncer.SetAllocation(ctx, sandbox, allocation); err != nil {
 		// Rollback in-memory store to the previous state.
 		log.Error(err, "Rollback sandbox allocation", "sandbox", sandbox.Name, "pods", oldState.Pods)
@@ -249,6 +249,21 @@ func (m *MockAllocator) EXPECT() *MockAllocatorMockRecorder {
 	return m.recorder
 }
 
+// CheckPoolAllocation mocks base method.
+func (m *MockAllocator) CheckPoolAllocation(ctx context.Context, pool *v1alpha1.Pool, sandboxes []*v1alpha1.BatchSandbox) ([]AllocationDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPoolAllocation", ctx, pool, sandboxes)
+	ret0, _ := ret[0].([]AllocationDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPoolAllocation indicates an expected call of CheckPoolAllocation.
+func (mr *MockAllocatorMockRecorder) CheckPoolAllocation(ctx, pool, sandboxes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPoolAllocation", reflect.TypeOf((*MockAllocator)(nil).CheckPoolAllocation), ctx, pool, sandboxes)
+}
+
 // ClearPoolAllocation mocks base method.
 func (m *MockAllocator) ClearPoolAllocation(ctx context.Context, ns, poolName string) error {
 	m.ctrl.T.Helper()
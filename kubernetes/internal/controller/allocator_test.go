@@ -44,6 +44,9 @@ func newTestAllocator(ctrl *gomock.Controller) (Allocator, *MockAllocationStore,
 func TestSchedule(t *testing.T) {
 	replica1 := int32(1)
 	replica2 := int32(2)
+	priority10 := int32(10)
+	preemptLower := sandboxv1alpha1.PreemptionPolicyPreemptLowerPriority
+	allocatedAt := metav1.NewTime(time.Now().Add(-time.Minute))
 
 	tests := []struct {
 		name          string
@@ -257,6 +260,78 @@ func TestSchedule(t *testing.T) {
 				PodSupplement: 0,
 			},
 		},
+		{
+			name: "preempt lower priority - pool exhausted",
+			spec: &AllocSpec{
+				Pods: []*corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}},
+				},
+				Pool: &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool1"}, Spec: sandboxv1alpha1.PoolSpec{Preemption: &sandboxv1alpha1.PoolPreemption{}}},
+				Sandboxes: []*sandboxv1alpha1.BatchSandbox{
+					{ObjectMeta: metav1.ObjectMeta{Name: "batch"}, Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: &replica2}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "agent"}, Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: &replica1, Priority: &priority10, PreemptionPolicy: &preemptLower}},
+				},
+			},
+			poolAlloc:     &PoolAllocation{PodAllocation: map[string]string{"pod1": "batch", "pod2": "batch"}},
+			sandboxAllocs: map[string]*SandboxAllocation{"batch": {Pods: []string{"pod1", "pod2"}}, "agent": {Pods: []string{}}},
+			releases:      map[string]*AllocationRelease{"batch": {Pods: []string{}}, "agent": {Pods: []string{}}},
+			released:      map[string]*AllocationReleased{"batch": {Pods: []string{}}, "agent": {Pods: []string{}}},
+			wantAction: &algorithm.AllocAction{
+				ToAllocate:    map[string][]string{},
+				ToRelease:     map[string][]string{},
+				PodSupplement: 1,
+				Preemptions:   []algorithm.Preemption{{Preemptor: "agent", Victim: "batch", Pods: []string{"pod2"}}},
+			},
+		},
+		{
+			name: "preempted pods in flight - no further preemption, victim requests a replacement",
+			spec: &AllocSpec{
+				Pods: []*corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}},
+				},
+				Pool: &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool1"}, Spec: sandboxv1alpha1.PoolSpec{Preemption: &sandboxv1alpha1.PoolPreemption{}}},
+				Sandboxes: []*sandboxv1alpha1.BatchSandbox{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "batch", Annotations: map[string]string{AnnoAllocPreemptedKey: `{"pods":["pod2"]}`}},
+						Spec:       sandboxv1alpha1.BatchSandboxSpec{Replicas: &replica2},
+					},
+					{ObjectMeta: metav1.ObjectMeta{Name: "agent"}, Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: &replica1, Priority: &priority10, PreemptionPolicy: &preemptLower}},
+				},
+			},
+			poolAlloc:     &PoolAllocation{PodAllocation: map[string]string{"pod1": "batch", "pod2": "batch"}},
+			sandboxAllocs: map[string]*SandboxAllocation{"batch": {Pods: []string{"pod1", "pod2"}}, "agent": {Pods: []string{}}},
+			releases:      map[string]*AllocationRelease{"batch": {Pods: []string{"pod2"}}, "agent": {Pods: []string{}}},
+			released:      map[string]*AllocationReleased{"batch": {Pods: []string{}}, "agent": {Pods: []string{}}},
+			wantAction: &algorithm.AllocAction{
+				ToAllocate:    map[string][]string{},
+				ToRelease:     map[string][]string{"batch": {"pod2"}},
+				PodSupplement: 2, // 1 for agent + 1 replacement for batch
+			},
+		},
+		{
+			name: "min runtime protects recently allocated victim",
+			spec: &AllocSpec{
+				Pods: []*corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}},
+				},
+				Pool: &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool1"}, Spec: sandboxv1alpha1.PoolSpec{Preemption: &sandboxv1alpha1.PoolPreemption{MinRuntimeSeconds: 600}}},
+				Sandboxes: []*sandboxv1alpha1.BatchSandbox{
+					{ObjectMeta: metav1.ObjectMeta{Name: "batch"}, Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: &replica1}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "agent"}, Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: &replica1, Priority: &priority10, PreemptionPolicy: &preemptLower}},
+				},
+			},
+			poolAlloc:     &PoolAllocation{PodAllocation: map[string]string{"pod1": "batch"}},
+			sandboxAllocs: map[string]*SandboxAllocation{"batch": {Pods: []string{"pod1"}, AllocatedAt: &allocatedAt}, "agent": {Pods: []string{}}},
+			releases:      map[string]*AllocationRelease{"batch": {Pods: []string{}}, "agent": {Pods: []string{}}},
+			released:      map[string]*AllocationReleased{"batch": {Pods: []string{}}, "agent": {Pods: []string{}}},
+			wantAction: &algorithm.AllocAction{
+				ToAllocate:    map[string][]string{},
+				ToRelease:     map[string][]string{},
+				PodSupplement: 1,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -282,6 +357,7 @@ func TestSchedule(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, tt.wantAction.ToAllocate, action.ToAllocate)
 			assert.Equal(t, tt.wantAction.PodSupplement, action.PodSupplement)
+			assert.Equal(t, tt.wantAction.Preemptions, action.Preemptions)
 			// ToRelease values may be in any order when built from a map (e.g. orphan sandbox GC).
 			assert.Equal(t, len(tt.wantAction.ToRelease), len(action.ToRelease))
 			for sandboxName, wantPods := range tt.wantAction.ToRelease {
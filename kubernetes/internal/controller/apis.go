@@ -27,9 +27,13 @@ const (
 	AnnoAllocStatusKey           = "sandbox.opensandbox.io/alloc-status"
 	AnnoAllocReleaseKey          = "sandbox.opensandbox.io/alloc-release"
 	AnnoAllocReleasedKey         = "sandbox.opensandbox.io/alloc-released"
+	AnnoAllocPreemptedKey        = "sandbox.opensandbox.io/alloc-preempted"
+	AnnoSourceSnapshotKey        = "sandbox.opensandbox.io/source-snapshot"
+	AnnoSupersededImagesKey      = "sandbox.opensandbox.io/superseded-images"
 	LabelBatchSandboxPodIndexKey = "batch-sandbox.sandbox.opensandbox.io/pod-index"
 	LabelBatchSandboxNameKey     = "batch-sandbox.sandbox.opensandbox.io/name"
 	LabelPrivilegedNodeAccess    = "sandbox.opensandbox.io/privileged-node-access"
+	LabelAllocationPoolKey       = "sandbox.opensandbox.io/allocation-pool"
 
 	FinalizerTaskCleanup    = "batch-sandbox.sandbox.opensandbox.io/task-cleanup"
 	FinalizerPoolAllocation = "pool.sandbox.opensandbox.io/pool-allocation"
@@ -40,6 +44,8 @@ var AnnotationSandboxEndpoints = pkgutils.AnnotationEndpoints
 
 type SandboxAllocation struct {
 	Pods []string `json:"pods"`
+	// AllocatedAt is when pods were last added to the allocation.
+	AllocatedAt *metav1.Time `json:"allocatedAt,omitempty"`
 }
 
 type AllocationRelease struct {
@@ -50,6 +56,12 @@ type AllocationReleased struct {
 	Pods []string `json:"pods"`
 }
 
+// AllocationPreempted lists allocated pods that were taken by a higher-priority sandbox.
+// They are requested for release and no longer count towards the sandbox's replicas.
+type AllocationPreempted struct {
+	Pods []string `json:"pods"`
+}
+
 type PoolAllocation struct {
 	PodAllocation map[string]string `json:"podAllocation"`
 }
@@ -80,3 +92,13 @@ func parseSandboxReleased(obj metav1.Object) (AllocationRelease, error) {
 	}
 	return ret, nil
 }
+
+func parseSandboxPreempted(obj metav1.Object) (AllocationPreempted, error) {
+	ret := AllocationPreempted{}
+	if raw := obj.GetAnnotations()[AnnoAllocPreemptedKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &ret); err != nil {
+			return ret, err
+		}
+	}
+	return ret, nil
+}
@@ -38,6 +38,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -61,6 +62,10 @@ const batchSandboxFirstPodIndex = 0
 
 type taskScheduleResult struct {
 	Running, Failed, Succeed, Unknown, Pending int32
+	// Steps counts the tasks by the state of each step, for tasks that run steps.
+	Steps []sandboxv1alpha1.TaskStepStatus
+	// FailureMessage summarizes the failed tasks, empty if none failed.
+	FailureMessage string
 }
 
 // BatchSandboxReconciler reconciles a BatchSandbox object
@@ -73,14 +78,19 @@ type BatchSandboxReconciler struct {
 	StatusRVExpectation expectations.ResourceVersionExpectation
 	// ResumePullSecret is the K8s Secret name for pulling snapshot images during resume.
 	ResumePullSecret string
+	// PauseSnapshotRetries is how many times a failed replica snapshot is retaken
+	// before the pause is reported as failed.
+	PauseSnapshotRetries int32
 }
 
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=batchsandboxes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=batchsandboxes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=batchsandboxes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxquotas,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -127,6 +137,12 @@ func (r *BatchSandboxReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
+	if batchSbx.DeletionTimestamp == nil {
+		if msg := specConflict(batchSbx); msg != "" {
+			return ctrl.Result{}, r.rejectInvalidSpec(ctx, batchSbx, msg)
+		}
+	}
+
 	// task schedule
 	taskStrategy := strategy.NewTaskSchedulingStrategy(batchSbx)
 
@@ -162,6 +178,15 @@ func (r *BatchSandboxReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
+	// Materialize spec.snapshotRef into the template before any pod is created from it.
+	if needsSnapshotRef(batchSbx) {
+		updated, result, err := r.applySnapshotRef(ctx, batchSbx)
+		if err != nil || updated {
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
 	// Pause/Resume dispatch: handles pause/resume intent before normal scaling.
 	if result, handled, err := r.dispatchPauseResume(ctx, batchSbx); handled {
 		return result, err
@@ -172,6 +197,17 @@ func (r *BatchSandboxReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// normal reconciliation does not keep using a stale pre-dispatch view.
 	taskStrategy = strategy.NewTaskSchedulingStrategy(batchSbx)
 
+	// A sandbox that does not fit into the SandboxQuotas of its namespace keeps its existing pods
+	// but is not scaled up until it is admitted.
+	var quotaRejection string
+	if batchSbx.DeletionTimestamp == nil {
+		admission, err := admitSandboxQuotas(ctx, r.Client, batchSbx.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		quotaRejection = admission.Rejected[batchSbx.Name]
+	}
+
 	pods, err := r.listPods(ctx, poolStrategy, batchSbx)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to list pods %w", err)
@@ -188,13 +224,18 @@ func (r *BatchSandboxReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// snapshot-backed runtime is quiesced and pods must stay absent until resume rewrites the
 	// template images and transitions back through Resuming.
 	if !poolStrategy.IsPooledMode() && batchSbx.Status.Phase != sandboxv1alpha1.BatchSandboxPhasePaused {
-		err := r.scaleBatchSandbox(ctx, batchSbx, batchSbx.Spec.Template, pods)
+		err := r.scaleBatchSandbox(ctx, batchSbx, batchSbx.Spec.Template, pods, quotaRejection == "")
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to scale batch sandbox %w", err)
 		}
 	}
 
 	runtimeView := buildRuntimeView(batchSbx, pods)
+	r.applyQuotaCondition(batchSbx, runtimeView.status, quotaRejection)
+	setConditionInStatus(runtimeView.status, sandboxv1alpha1.BatchSandboxConditionInvalidSpec, sandboxv1alpha1.ConditionFalse, "", "")
+	if quotaRejection != "" {
+		DurationStore.Push(types.NamespacedName{Namespace: batchSbx.Namespace, Name: batchSbx.Name}.String(), sandboxQuotaRecheckInterval)
+	}
 	// Ensure PauseObservedGeneration is up-to-date so the status patch ACKs the
 	// current generation without requiring a dedicated API call.
 	// Skip during Resuming: a newer generation may carry a queued pause request
@@ -218,6 +259,8 @@ func (r *BatchSandboxReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			runtimeView.status.TaskSucceed = ts.Succeed
 			runtimeView.status.TaskUnknown = ts.Unknown
 			runtimeView.status.TaskPending = ts.Pending
+			runtimeView.status.TaskSteps = ts.Steps
+			applyTaskFailedCondition(runtimeView.status, ts)
 		}
 	}
 
@@ -421,9 +464,12 @@ func (r *BatchSandboxReconciler) scheduleTasks(ctx context.Context, tSch tasksch
 	var (
 		running, failed, succeed, unknown int32
 		pending                           int32
+		sampleFailure                     string
+		steps                             stepCounter
 	)
 	for i := range len(tasks) {
 		task := tasks[i]
+		steps.add(task)
 		if task.GetPodName() == "" {
 			pending++
 		} else {
@@ -438,6 +484,12 @@ func (r *BatchSandboxReconciler) scheduleTasks(ctx context.Context, tSch tasksch
 				succeed++
 			case taskscheduler.FailedTaskState:
 				failed++
+				if sampleFailure == "" {
+					sampleFailure = "sample task=" + task.GetName()
+					if message := task.GetTerminationMessage(); message != "" {
+						sampleFailure += "; message=" + message
+					}
+				}
 			case taskscheduler.UnknownTaskState:
 				unknown++
 			}
@@ -450,13 +502,51 @@ func (r *BatchSandboxReconciler) scheduleTasks(ctx context.Context, tSch tasksch
 		}
 		log.Info("successfully released Pods", "count", len(toReleasedPods))
 	}
-	return &taskScheduleResult{
+	result := &taskScheduleResult{
 		Running: running,
 		Failed:  failed,
 		Succeed: succeed,
 		Unknown: unknown,
 		Pending: pending,
-	}, nil
+		Steps:   steps.steps,
+	}
+	if failed > 0 {
+		result.FailureMessage = fmt.Sprintf("%d/%d tasks failed; %s", failed, len(tasks), sampleFailure)
+	}
+	return result, nil
+}
+
+// stepCounter counts tasks by the state of each of their steps, in the order steps are first seen.
+type stepCounter struct {
+	steps []sandboxv1alpha1.TaskStepStatus
+	index map[string]int
+}
+
+func (c *stepCounter) add(task taskscheduler.Task) {
+	for _, step := range task.GetStepStates() {
+		i, ok := c.index[step.Name]
+		if !ok {
+			if c.index == nil {
+				c.index = make(map[string]int)
+			}
+			i = len(c.steps)
+			c.index[step.Name] = i
+			c.steps = append(c.steps, sandboxv1alpha1.TaskStepStatus{Name: step.Name})
+		}
+		count := &c.steps[i]
+		switch {
+		case task.GetPodName() == "":
+			count.Pending++
+		case step.State == taskscheduler.RunningTaskState:
+			count.Running++
+		case step.State == taskscheduler.SucceedTaskState:
+			count.Succeed++
+		case step.State == taskscheduler.FailedTaskState:
+			count.Failed++
+		default:
+			count.Pending++
+		}
+	}
 }
 
 func (r *BatchSandboxReconciler) getTasksCleanupUnfinished(batchSbx *sandboxv1alpha1.BatchSandbox, tSch taskscheduler.TaskScheduler) []taskscheduler.Task {
@@ -476,6 +566,9 @@ func (r *BatchSandboxReconciler) releasePods(ctx context.Context, batchSbx *sand
 		return err
 	}
 	releasedSet.Insert(released.Pods...)
+	if releasedSet.HasAll(toReleasePods...) {
+		return nil
+	}
 	releasedSet.Insert(toReleasePods...)
 	newRelease := AllocationRelease{
 		Pods: sets.List(releasedSet),
@@ -484,10 +577,13 @@ func (r *BatchSandboxReconciler) releasePods(ctx context.Context, batchSbx *sand
 	if err != nil {
 		return fmt.Errorf("Failed to marshal released pod names: %v", err)
 	}
+	// The patch carries the observed resourceVersion so pods the pool controller added to
+	// alloc-release in the meantime (preemption) are not overwritten; a conflict requeues.
 	body := utils.DumpJSON(struct {
 		MetaData metav1.ObjectMeta `json:"metadata"`
 	}{
 		MetaData: metav1.ObjectMeta{
+			ResourceVersion: batchSbx.ResourceVersion,
 			Annotations: map[string]string{
 				AnnoAllocReleaseKey: string(raw),
 			},
@@ -500,7 +596,9 @@ func (r *BatchSandboxReconciler) releasePods(ctx context.Context, batchSbx *sand
 		},
 	}
 	if err := r.Client.Patch(ctx, b, client.RawPatch(types.MergePatchType, []byte(body))); err != nil {
-		r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonFailedRelease, "Failed to release pods: %v", err)
+		if !errors.IsConflict(err) {
+			r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonFailedRelease, "Failed to release pods: %v", err)
+		}
 		return err
 	}
 	if len(toReleasePods) > 0 {
@@ -510,7 +608,9 @@ func (r *BatchSandboxReconciler) releasePods(ctx context.Context, batchSbx *sand
 }
 
 // Normal Mode
-func (r *BatchSandboxReconciler) scaleBatchSandbox(ctx context.Context, batchSandbox *sandboxv1alpha1.BatchSandbox, podTemplateSpec *corev1.PodTemplateSpec, pods []*corev1.Pod) error {
+// scaleBatchSandbox reconciles the pods of a non-pooled sandbox with its replicas. Missing pods
+// are only created when scaleUp is set, i.e. the sandbox fits into its SandboxQuotas.
+func (r *BatchSandboxReconciler) scaleBatchSandbox(ctx context.Context, batchSandbox *sandboxv1alpha1.BatchSandbox, podTemplateSpec *corev1.PodTemplateSpec, pods []*corev1.Pod, scaleUp bool) error {
 	log := logf.FromContext(ctx)
 	indexedPodMap := map[int]*corev1.Pod{}
 	for i := range pods {
@@ -537,6 +637,10 @@ func (r *BatchSandboxReconciler) scaleBatchSandbox(ctx context.Context, batchSan
 		}
 	}
 	// scale
+	if len(needCreateIndex) > 0 && !scaleUp {
+		log.Info("skip creating Pods, sandbox exceeds its SandboxQuota", "count", len(needCreateIndex), "indexes", needCreateIndex)
+		needCreateIndex = nil
+	}
 	if len(needCreateIndex) > 0 {
 		log.Info("try to create Pods", "count", len(needCreateIndex), "indexes", needCreateIndex)
 	}
@@ -606,7 +710,7 @@ func (r *BatchSandboxReconciler) assignPool(ctx context.Context, batchSbx *sandb
 	}
 
 	profile := r.ProfileStore.GetProfile(profileName)
-	assigner := poolassign.NewDefaultAssigner(profile)
+	assigner := poolassign.NewDefaultAssignerWithReader(profile, r.Client)
 
 	poolName, err := assigner.AssignPool(ctx, batchSbx, pools)
 	if err != nil {
@@ -633,6 +737,22 @@ func (r *BatchSandboxReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurren
 		Named("batchsandbox").
 		Owns(&corev1.Pod{}).
 		Owns(&sandboxv1alpha1.SandboxSnapshot{}).
+		Watches(&sandboxv1alpha1.SandboxQuota{}, handler.EnqueueRequestsFromMapFunc(r.sandboxesForQuota)).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		Complete(r)
 }
+
+// sandboxesForQuota enqueues every BatchSandbox in the namespace of a SandboxQuota, since a quota
+// change or a change in its usage may admit or reject any of them.
+func (r *BatchSandboxReconciler) sandboxesForQuota(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &sandboxv1alpha1.BatchSandboxList{}
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list batch sandboxes for SandboxQuota", "sandboxQuota", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name}})
+	}
+	return requests
+}
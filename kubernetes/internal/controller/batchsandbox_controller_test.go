@@ -653,6 +653,7 @@ func TestBatchSandboxReconciler_scheduleTasks(t *testing.T) {
 					mockSche := mock_scheduler.NewMockTaskScheduler(ctrl)
 					mockSche.EXPECT().Schedule().Return(nil).Times(1)
 					mockTask := mock_scheduler.NewMockTask(ctrl)
+					mockTask.EXPECT().GetStepStates().Return(nil).AnyTimes()
 					mockTask.EXPECT().GetState().Return(taskscheduler.SucceedTaskState).Times(1)
 					mockTask.EXPECT().IsResourceReleased().Return(true).Times(1)
 					mockTask.EXPECT().GetPodName().Return("pod-0").AnyTimes()
@@ -673,6 +674,69 @@ func TestBatchSandboxReconciler_scheduleTasks(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "tasks, failed=1 running=1; failure message",
+			fields: fields{
+				Client:   fake.NewClientBuilder().WithScheme(testscheme).WithObjects(fakeBatchSandbox).WithStatusSubresource(fakeBatchSandbox).Build(),
+				Recorder: record.NewFakeRecorder(10),
+			},
+			args: args{
+				tSch: func() taskscheduler.TaskScheduler {
+					mockSche := mock_scheduler.NewMockTaskScheduler(ctrl)
+					mockSche.EXPECT().Schedule().Return(nil).Times(1)
+					failedTask := mock_scheduler.NewMockTask(ctrl)
+					failedTask.EXPECT().GetStepStates().Return(nil).AnyTimes()
+					failedTask.EXPECT().GetState().Return(taskscheduler.FailedTaskState).Times(1)
+					failedTask.EXPECT().IsResourceReleased().Return(false).Times(1)
+					failedTask.EXPECT().GetPodName().Return("pod-0").AnyTimes()
+					failedTask.EXPECT().GetName().Return("task-0").AnyTimes()
+					failedTask.EXPECT().GetTerminationMessage().Return("no such file").Times(1)
+					runningTask := mock_scheduler.NewMockTask(ctrl)
+					runningTask.EXPECT().GetStepStates().Return(nil).AnyTimes()
+					runningTask.EXPECT().GetState().Return(taskscheduler.RunningTaskState).Times(1)
+					runningTask.EXPECT().IsResourceReleased().Return(false).Times(1)
+					runningTask.EXPECT().GetPodName().Return("pod-1").AnyTimes()
+					mockSche.EXPECT().ListTask().Return([]taskscheduler.Task{failedTask, runningTask}).Times(1)
+					return mockSche
+				}(),
+				batchSbx: fakeBatchSandbox.DeepCopy(),
+			},
+			wantTaskStatus: &taskScheduleResult{Failed: 1, Running: 1, FailureMessage: "1/2 tasks failed; sample task=task-0; message=no such file"},
+		},
+		{
+			name: "tasks with steps; step counts",
+			fields: fields{
+				Client:   fake.NewClientBuilder().WithScheme(testscheme).WithObjects(fakeBatchSandbox).WithStatusSubresource(fakeBatchSandbox).Build(),
+				Recorder: record.NewFakeRecorder(10),
+			},
+			args: args{
+				tSch: func() taskscheduler.TaskScheduler {
+					mockSche := mock_scheduler.NewMockTaskScheduler(ctrl)
+					mockSche.EXPECT().Schedule().Return(nil).Times(1)
+					assignedTask := mock_scheduler.NewMockTask(ctrl)
+					assignedTask.EXPECT().GetStepStates().Return([]taskscheduler.StepState{
+						{Name: "build", State: taskscheduler.SucceedTaskState},
+						{Name: "test", State: taskscheduler.RunningTaskState},
+					}).Times(1)
+					assignedTask.EXPECT().GetState().Return(taskscheduler.RunningTaskState).Times(1)
+					assignedTask.EXPECT().IsResourceReleased().Return(false).Times(1)
+					assignedTask.EXPECT().GetPodName().Return("pod-0").AnyTimes()
+					pendingTask := mock_scheduler.NewMockTask(ctrl)
+					pendingTask.EXPECT().GetStepStates().Return([]taskscheduler.StepState{
+						{Name: "build", State: taskscheduler.UnknownTaskState},
+						{Name: "test", State: taskscheduler.UnknownTaskState},
+					}).Times(1)
+					pendingTask.EXPECT().GetPodName().Return("").AnyTimes()
+					mockSche.EXPECT().ListTask().Return([]taskscheduler.Task{assignedTask, pendingTask}).Times(1)
+					return mockSche
+				}(),
+				batchSbx: fakeBatchSandbox.DeepCopy(),
+			},
+			wantTaskStatus: &taskScheduleResult{Running: 1, Pending: 1, Steps: []sandboxv1alpha1.TaskStepStatus{
+				{Name: "build", Succeed: 1, Pending: 1},
+				{Name: "test", Running: 1, Pending: 1},
+			}},
+		},
 	}
 	for i := range tests {
 		tt := &tests[i]
@@ -1089,3 +1153,19 @@ func Test_calPodIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyTaskFailedCondition(t *testing.T) {
+	status := &sandboxv1alpha1.BatchSandboxStatus{}
+	applyTaskFailedCondition(status, &taskScheduleResult{Failed: 1, FailureMessage: "1/1 tasks failed; sample task=task-0; message=boom"})
+	if !hasConditionTrue(status, sandboxv1alpha1.BatchSandboxConditionTaskFailed) {
+		t.Fatalf("expect TaskFailed condition, got %v", status.Conditions)
+	}
+	if msg := status.Conditions[0].Message; msg != "1/1 tasks failed; sample task=task-0; message=boom" {
+		t.Errorf("unexpected condition message %q", msg)
+	}
+
+	applyTaskFailedCondition(status, &taskScheduleResult{Succeed: 1})
+	if len(status.Conditions) != 0 {
+		t.Errorf("expect TaskFailed condition to be cleared, got %v", status.Conditions)
+	}
+}
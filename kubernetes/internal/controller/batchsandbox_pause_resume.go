@@ -16,13 +16,20 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,18 +44,167 @@ import (
 )
 
 const internalPauseSnapshotSuffix = "-pause"
-const supportedPauseReplicas int32 = 1
 
-func internalPauseSnapshotName(batchSandboxName string) string {
+// internalPauseSnapshotName returns the internal SandboxSnapshot name for one replica.
+// Replica 0 keeps the single-replica name so that sandboxes paused before multi-replica
+// support can still be resumed.
+func internalPauseSnapshotName(batchSandboxName string, index int32) string {
 	// TODO: handle Kubernetes resource name length limits for long BatchSandbox names.
-	return batchSandboxName + internalPauseSnapshotSuffix
+	if index == 0 {
+		return batchSandboxName + internalPauseSnapshotSuffix
+	}
+	return fmt.Sprintf("%s%s-%d", batchSandboxName, internalPauseSnapshotSuffix, index)
 }
 
 func unsupportedPauseReplicasMessage(replicas *int32) string {
 	if replicas == nil {
-		return "pause/resume currently supports only BatchSandbox spec.replicas=1; spec.replicas is unset"
+		return "pause/resume requires BatchSandbox spec.replicas>=1; spec.replicas is unset"
+	}
+	return fmt.Sprintf("pause/resume requires BatchSandbox spec.replicas>=1; got spec.replicas=%d", *replicas)
+}
+
+// pauseSnapshotCount returns the number of replica snapshots taken by the current or
+// most recent pause. Sandboxes paused before per-replica tracking have a single one.
+func pauseSnapshotCount(bs *sandboxv1alpha1.BatchSandbox) int32 {
+	if n := len(bs.Status.PauseReplicas); n > 0 {
+		return int32(n)
+	}
+	return 1
+}
+
+func newPauseReplicaStatuses(batchSandboxName string, replicas int32) []sandboxv1alpha1.BatchSandboxReplicaPauseStatus {
+	statuses := make([]sandboxv1alpha1.BatchSandboxReplicaPauseStatus, 0, replicas)
+	for idx := int32(0); idx < replicas; idx++ {
+		statuses = append(statuses, sandboxv1alpha1.BatchSandboxReplicaPauseStatus{
+			Index:        idx,
+			SnapshotName: internalPauseSnapshotName(batchSandboxName, idx),
+			Phase:        sandboxv1alpha1.SandboxSnapshotPhasePending,
+		})
+	}
+	return statuses
+}
+
+func pauseReplicaStatus(bs *sandboxv1alpha1.BatchSandbox, index int32) sandboxv1alpha1.BatchSandboxReplicaPauseStatus {
+	for _, status := range bs.Status.PauseReplicas {
+		if status.Index == index {
+			return status
+		}
+	}
+	return sandboxv1alpha1.BatchSandboxReplicaPauseStatus{
+		Index:        index,
+		SnapshotName: internalPauseSnapshotName(bs.Name, index),
+	}
+}
+
+// replicaPodName returns the Pod serving a replica. Pooled sandboxes order their active
+// allocated Pods by name; other sandboxes name their Pods "<batchSandbox>-<index>".
+func replicaPodName(bs *sandboxv1alpha1.BatchSandbox, index int32) (string, error) {
+	alloc, err := parseSandboxAllocation(bs)
+	if err != nil {
+		return "", err
+	}
+	if len(alloc.Pods) == 0 {
+		return fmt.Sprintf("%s-%d", bs.Name, index), nil
+	}
+	released, err := parseSandboxReleased(bs)
+	if err != nil {
+		return "", err
+	}
+	active := sets.List(sets.New(alloc.Pods...).Difference(sets.New(released.Pods...)))
+	if int(index) >= len(active) {
+		return "", fmt.Errorf("replica %d has no allocated pod: %d active pods", index, len(active))
+	}
+	return active[index], nil
+}
+
+// setShardPatchImages points one replica at its snapshot images by merging a container
+// image override into the replica's shard patch, padding the patch list as needed.
+func setShardPatchImages(bs *sandboxv1alpha1.BatchSandbox, index int, images map[string]string) error {
+	var containers []map[string]string
+	for _, c := range bs.Spec.Template.Spec.Containers {
+		if img, ok := images[c.Name]; ok {
+			containers = append(containers, map[string]string{"name": c.Name, "image": img})
+		}
 	}
-	return fmt.Sprintf("pause/resume currently supports only BatchSandbox spec.replicas=1; got spec.replicas=%d", *replicas)
+	if len(containers) == 0 {
+		return nil
+	}
+	imagePatch, err := json.Marshal(map[string]any{"spec": map[string]any{"containers": containers}})
+	if err != nil {
+		return err
+	}
+	for len(bs.Spec.ShardPatches) <= index {
+		bs.Spec.ShardPatches = append(bs.Spec.ShardPatches, runtime.RawExtension{Raw: []byte("{}")})
+	}
+	existing := bs.Spec.ShardPatches[index].Raw
+	if len(existing) == 0 {
+		existing = []byte("{}")
+	}
+	merged, err := strategicpatch.StrategicMergePatch(existing, imagePatch, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to merge snapshot images into shard patch %d: %w", index, err)
+	}
+	bs.Spec.ShardPatches[index] = runtime.RawExtension{Raw: merged}
+	return nil
+}
+
+// pauseImageTagPattern matches the tags snapshotImageTag gives internal pause snapshots.
+var pauseImageTagPattern = regexp.MustCompile(`^snap-gen[0-9]+(-r[0-9]+)?$`)
+
+// replicaImages returns the container images one replica currently runs from: the
+// template images with the replica's shard patch applied.
+func replicaImages(bs *sandboxv1alpha1.BatchSandbox, index int) ([]string, error) {
+	containers, err := replicaContainers(bs, index)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]string, 0, len(containers))
+	for _, c := range containers {
+		images = append(images, c.Image)
+	}
+	return images, nil
+}
+
+// pausePushedImages returns the images of one replica that an earlier pause of bs pushed.
+// Template and shard patch images the user set are never included, even when they live in
+// the snapshot registry.
+func pausePushedImages(bs *sandboxv1alpha1.BatchSandbox, index int) ([]string, error) {
+	containers, err := replicaContainers(bs, index)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, c := range containers {
+		repo, tag, ok := strings.Cut(c.Image[strings.LastIndex(c.Image, "/")+1:], ":")
+		if ok && repo == bs.Name+"-"+c.Name && pauseImageTagPattern.MatchString(tag) {
+			images = append(images, c.Image)
+		}
+	}
+	return images, nil
+}
+
+// replicaContainers returns the containers of one replica: the template containers with the
+// replica's shard patch applied.
+func replicaContainers(bs *sandboxv1alpha1.BatchSandbox, index int) ([]corev1.Container, error) {
+	if bs.Spec.Template == nil {
+		return nil, nil
+	}
+	pod := &corev1.Pod{Spec: *bs.Spec.Template.Spec.DeepCopy()}
+	if index < len(bs.Spec.ShardPatches) && len(bs.Spec.ShardPatches[index].Raw) > 0 {
+		podBytes, err := json.Marshal(pod)
+		if err != nil {
+			return nil, err
+		}
+		patched, err := strategicpatch.StrategicMergePatch(podBytes, bs.Spec.ShardPatches[index].Raw, &corev1.Pod{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply shard patch %d: %w", index, err)
+		}
+		pod = &corev1.Pod{}
+		if err := json.Unmarshal(patched, pod); err != nil {
+			return nil, err
+		}
+	}
+	return pod.Spec.Containers, nil
 }
 
 func ensureImagePullSecret(template *corev1.PodTemplateSpec, secretName string) {
@@ -108,22 +264,24 @@ func sourcePodTemplateForPause(pod *corev1.Pod) *corev1.PodTemplateSpec {
 	}
 }
 
-func (r *BatchSandboxReconciler) deleteInternalPauseSnapshot(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox) error {
+func (r *BatchSandboxReconciler) deleteInternalPauseSnapshots(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox) error {
 	log := logf.FromContext(ctx)
-	snapshot := &sandboxv1alpha1.SandboxSnapshot{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: internalPauseSnapshotName(bs.Name)}, snapshot); err != nil {
-		if errors.IsNotFound(err) {
-			return nil
+	for idx := int32(0); idx < pauseSnapshotCount(bs); idx++ {
+		snapshot := &sandboxv1alpha1.SandboxSnapshot{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: internalPauseSnapshotName(bs.Name, idx)}, snapshot); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
 		}
-		return err
-	}
-	if snapshot.Status.Phase != sandboxv1alpha1.SandboxSnapshotPhaseSucceed {
-		return nil
-	}
-	if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
-		return err
+		if snapshot.Status.Phase != sandboxv1alpha1.SandboxSnapshotPhaseSucceed {
+			continue
+		}
+		if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Deleted SandboxSnapshot after successful resume", "snapshot", snapshot.Name)
 	}
-	log.Info("Deleted SandboxSnapshot after successful resume", "snapshot", snapshot.Name)
 	return nil
 }
 
@@ -219,15 +377,13 @@ func (r *BatchSandboxReconciler) dispatchPauseResume(ctx context.Context, bs *sa
 }
 
 // handlePause implements the pause flow:
-// 1. ACK (pauseObservedGeneration + phase=Pausing)
-// 2. Stop task-executor tasks, if any, while keeping the source Pod allocated
-// 3. Create SandboxSnapshot child resource
+// 1. ACK (pauseObservedGeneration + phase=Pausing) and reset per-replica progress
+// 2. Stop task-executor tasks, if any, while keeping the source Pods allocated
+// 3. Create one SandboxSnapshot child resource per replica
 func (r *BatchSandboxReconciler) handlePause(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// The pause snapshot format records one source pod's container images, so this
-	// controller only supports rootfs pause/resume for single-replica sandboxes.
-	if bs.Spec.Replicas == nil || *bs.Spec.Replicas != supportedPauseReplicas {
+	if bs.Spec.Replicas == nil || *bs.Spec.Replicas < 1 {
 		msg := unsupportedPauseReplicasMessage(bs.Spec.Replicas)
 		log.Info("Rejecting pause for unsupported replica count", "message", msg)
 		phase := bs.Status.Phase
@@ -242,44 +398,82 @@ func (r *BatchSandboxReconciler) handlePause(ctx context.Context, bs *sandboxv1a
 		}
 		return ctrl.Result{}, nil
 	}
-
-	snapshot := &sandboxv1alpha1.SandboxSnapshot{}
-	snapshotName := internalPauseSnapshotName(bs.Name)
-	err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: snapshotName}, snapshot)
-	if err == nil && snapshot.DeletionTimestamp != nil {
-		log.Info("Waiting for stale SandboxSnapshot deletion before retrying pause", "snapshot", snapshotName)
-		return ctrl.Result{RequeueAfter: time.Second}, nil
-	}
-	if err == nil && (snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseFailed || snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseSucceed) {
-		log.Info("Deleting terminal SandboxSnapshot for retry", "snapshot", snapshotName, "phase", snapshot.Status.Phase)
-		if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+	replicas := *bs.Spec.Replicas
+
+	var missing []int32
+	waitForCleanup := false
+	for idx := int32(0); idx < replicas; idx++ {
+		snapshot := &sandboxv1alpha1.SandboxSnapshot{}
+		snapshotName := internalPauseSnapshotName(bs.Name, idx)
+		err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: snapshotName}, snapshot)
+		switch {
+		case errors.IsNotFound(err):
+			missing = append(missing, idx)
+		case err != nil:
 			return ctrl.Result{}, err
+		case snapshot.DeletionTimestamp != nil:
+			log.Info("Waiting for stale SandboxSnapshot deletion before retrying pause", "snapshot", snapshotName)
+			waitForCleanup = true
+		case snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseFailed || snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseSucceed:
+			log.Info("Deleting terminal SandboxSnapshot for retry", "snapshot", snapshotName, "phase", snapshot.Status.Phase)
+			if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			waitForCleanup = true
 		}
+	}
+	if waitForCleanup {
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
-	if err != nil && !errors.IsNotFound(err) {
-		return ctrl.Result{}, err
+
+	if len(missing) > 0 {
+		msg, err := checkSnapshotQuota(ctx, r.Client, bs.Namespace, int32(len(missing)))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if msg != "" {
+			log.Info("Rejecting pause over snapshot quota", "message", msg)
+			phase := bs.Status.Phase
+			if phase == "" {
+				phase = sandboxv1alpha1.BatchSandboxPhaseSucceed
+			}
+			if err := r.ackPauseWithPhase(ctx, bs, phase, ""); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionPauseFailed, sandboxv1alpha1.ConditionTrue, EventReasonQuotaExceeded, msg); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
 	}
 
 	_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionPauseFailed, sandboxv1alpha1.ConditionFalse, "", "")
 
+	// Reset progress before entering Pausing so syncPauseOrClear never sees the replica
+	// list of an earlier pause.
+	if err := r.updatePauseReplicas(ctx, bs, newPauseReplicaStatuses(bs.Name, replicas)); err != nil {
+		return ctrl.Result{}, err
+	}
 	if err := r.ackPauseWithPhase(ctx, bs, sandboxv1alpha1.BatchSandboxPhasePausing, ""); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if errors.IsNotFound(err) {
-		if created, err := r.ensureInternalPauseSnapshot(ctx, bs, snapshotName); err != nil {
-			return ctrl.Result{}, err
-		} else if !created {
-			log.Info("Waiting for task cleanup before creating SandboxSnapshot", "snapshot", snapshotName)
-		}
+	if created, err := r.ensureInternalPauseSnapshots(ctx, bs, missing); err != nil {
+		return ctrl.Result{}, err
+	} else if !created {
+		log.Info("Waiting for task cleanup before creating SandboxSnapshots", "replicas", missing)
 	}
 
 	return ctrl.Result{RequeueAfter: time.Second}, nil
 }
 
-func (r *BatchSandboxReconciler) ensureInternalPauseSnapshot(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox, snapshotName string) (bool, error) {
+// ensureInternalPauseSnapshots creates the pause snapshots of the given replicas once
+// task cleanup has finished. It reports false while tasks are still stopping.
+func (r *BatchSandboxReconciler) ensureInternalPauseSnapshots(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox, indexes []int32) (bool, error) {
 	log := logf.FromContext(ctx)
+	if len(indexes) == 0 {
+		return true, nil
+	}
 
 	tasksStopped, err := r.stopTasksBeforePause(ctx, bs)
 	if err != nil {
@@ -289,25 +483,41 @@ func (r *BatchSandboxReconciler) ensureInternalPauseSnapshot(ctx context.Context
 		return false, nil
 	}
 
-	snapshot := &sandboxv1alpha1.SandboxSnapshot{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      snapshotName,
-			Namespace: bs.Namespace,
-		},
-		Spec: sandboxv1alpha1.SandboxSnapshotSpec{
-			SandboxName: bs.Name,
-		},
-	}
-	if err := controllerutil.SetControllerReference(bs, snapshot, r.Scheme); err != nil {
-		return false, err
-	}
-	if err := r.Create(ctx, snapshot); err != nil {
-		if errors.IsAlreadyExists(err) {
-			return true, nil
+	for _, idx := range indexes {
+		snapshot := &sandboxv1alpha1.SandboxSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      internalPauseSnapshotName(bs.Name, idx),
+				Namespace: bs.Namespace,
+			},
+			Spec: sandboxv1alpha1.SandboxSnapshotSpec{
+				SandboxName:  bs.Name,
+				ReplicaIndex: ptr.To(idx),
+			},
+		}
+		// Record the images an earlier pause pushed and this pause replaces, so the snapshot
+		// controller can delete them from the registry once the snapshot is superseded.
+		superseded, err := pausePushedImages(bs, int(idx))
+		if err != nil {
+			return false, err
 		}
-		return false, err
+		if len(superseded) > 0 {
+			supersededJSON, err := json.Marshal(superseded)
+			if err != nil {
+				return false, err
+			}
+			snapshot.Annotations = map[string]string{AnnoSupersededImagesKey: string(supersededJSON)}
+		}
+		if err := controllerutil.SetControllerReference(bs, snapshot, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Create(ctx, snapshot); err != nil {
+			if errors.IsAlreadyExists(err) {
+				continue
+			}
+			return false, err
+		}
+		log.Info("Created SandboxSnapshot", "snapshot", snapshot.Name, "replica", idx)
 	}
-	log.Info("Created SandboxSnapshot", "snapshot", snapshotName)
 	return true, nil
 }
 
@@ -361,35 +571,90 @@ func (r *BatchSandboxReconciler) handleResume(ctx context.Context, bs *sandboxv1
 	return ctrl.Result{RequeueAfter: time.Second}, nil
 }
 
-// syncPauseOrClear waits for the internal pause snapshot to finish and transitions the
-// BatchSandbox into Paused or a retryable/terminal failure state.
+// syncPauseOrClear waits for every replica's internal pause snapshot to finish and
+// transitions the BatchSandbox into Paused or a retryable/terminal failure state.
+// Failed replica snapshots are retaken up to PauseSnapshotRetries times while the
+// snapshots of the other replicas are kept.
 func (r *BatchSandboxReconciler) syncPauseOrClear(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	snapshot := &sandboxv1alpha1.SandboxSnapshot{}
-	err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: internalPauseSnapshotName(bs.Name)}, snapshot)
-	if errors.IsNotFound(err) {
-		snapshotName := internalPauseSnapshotName(bs.Name)
-		if created, createErr := r.ensureInternalPauseSnapshot(ctx, bs, snapshotName); createErr != nil {
-			return ctrl.Result{}, createErr
-		} else if !created {
-			log.Info("SandboxSnapshot not created yet; waiting for task cleanup", "snapshot", snapshotName)
+	count := pauseSnapshotCount(bs)
+	progress := make([]sandboxv1alpha1.BatchSandboxReplicaPauseStatus, 0, count)
+	var missing []int32
+	var retake []*sandboxv1alpha1.SandboxSnapshot
+	var failures []string
+	inProgress := false
+	for idx := int32(0); idx < count; idx++ {
+		status := pauseReplicaStatus(bs, idx)
+		snapshot := &sandboxv1alpha1.SandboxSnapshot{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: status.SnapshotName}, snapshot)
+		switch {
+		case errors.IsNotFound(err):
+			missing = append(missing, idx)
+			status.Phase = sandboxv1alpha1.SandboxSnapshotPhasePending
+			inProgress = true
+		case err != nil:
+			return ctrl.Result{}, err
+		case snapshot.DeletionTimestamp != nil:
+			// A failed snapshot is being cleaned up before it is retaken.
+			status.Phase = sandboxv1alpha1.SandboxSnapshotPhasePending
+			inProgress = true
+		case snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseSucceed:
+			status.Phase = sandboxv1alpha1.SandboxSnapshotPhaseSucceed
+			status.Message = ""
+		case snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseFailed:
+			status.Message = snapshotFailureMessage(snapshot)
+			if status.Retries < r.PauseSnapshotRetries {
+				retake = append(retake, snapshot)
+				status.Retries++
+				status.Phase = sandboxv1alpha1.SandboxSnapshotPhasePending
+				inProgress = true
+				break
+			}
+			status.Phase = sandboxv1alpha1.SandboxSnapshotPhaseFailed
+			if count > 1 {
+				failures = append(failures, fmt.Sprintf("replica %d: %s", idx, status.Message))
+			} else {
+				failures = append(failures, status.Message)
+			}
+		default:
+			log.Info("SandboxSnapshot in progress", "snapshot", snapshot.Name, "phase", snapshot.Status.Phase)
+			status.Phase = snapshot.Status.Phase
+			if status.Phase == "" {
+				status.Phase = sandboxv1alpha1.SandboxSnapshotPhasePending
+			}
+			inProgress = true
 		}
-		return ctrl.Result{RequeueAfter: time.Second}, nil
+		progress = append(progress, status)
 	}
-	if err != nil {
-		return ctrl.Result{}, err
+
+	// Record retries before deleting the failed snapshots so a lost status write
+	// cannot grant extra attempts.
+	if !equality.Semantic.DeepEqual(progress, bs.Status.PauseReplicas) {
+		if err := r.updatePauseReplicas(ctx, bs, progress); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	for _, snapshot := range retake {
+		log.Info("Retaking failed SandboxSnapshot", "snapshot", snapshot.Name, "message", snapshotFailureMessage(snapshot))
+		if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
 	}
 
-	switch snapshot.Status.Phase {
-	case sandboxv1alpha1.SandboxSnapshotPhaseSucceed:
-		log.Info("SandboxSnapshot Succeed, completing pause")
-		if err := r.completePause(ctx, bs); err != nil {
+	if len(missing) > 0 {
+		if created, err := r.ensureInternalPauseSnapshots(ctx, bs, missing); err != nil {
 			return ctrl.Result{}, err
+		} else if !created {
+			log.Info("SandboxSnapshots not created yet; waiting for task cleanup", "replicas", missing)
 		}
+	}
+	if inProgress {
 		return ctrl.Result{RequeueAfter: time.Second}, nil
-	case sandboxv1alpha1.SandboxSnapshotPhaseFailed:
-		msg := snapshotFailureMessage(snapshot)
+	}
+
+	if len(failures) > 0 {
+		msg := strings.Join(failures, "; ")
 		log.Info("SandboxSnapshot Failed", "message", msg)
 
 		phase := sandboxv1alpha1.BatchSandboxPhaseSucceed
@@ -406,12 +671,13 @@ func (r *BatchSandboxReconciler) syncPauseOrClear(ctx context.Context, bs *sandb
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
-	case sandboxv1alpha1.SandboxSnapshotPhasePending, sandboxv1alpha1.SandboxSnapshotPhaseCommitting:
-		log.Info("SandboxSnapshot in progress", "phase", snapshot.Status.Phase)
-		return ctrl.Result{RequeueAfter: time.Second}, nil
-	default:
-		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
+
+	log.Info("SandboxSnapshots Succeed, completing pause", "replicas", count)
+	if err := r.completePause(ctx, bs); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: time.Second}, nil
 }
 
 // completePause finalizes the pause operation:
@@ -502,47 +768,54 @@ func (r *BatchSandboxReconciler) completePause(ctx context.Context, bs *sandboxv
 }
 
 // continueResume continues the resume flow:
-//  1. Read SandboxSnapshot status for image URIs
-//  2. Replace template container images
+//  1. Read every replica's SandboxSnapshot status for image URIs
+//  2. Replace template container images with replica 0's snapshot and write the
+//     snapshots of the other replicas into their shard patches
 //  3. Pool mode: clear poolRef
 //  4. Leave spec.pause and spec.replicas untouched; normal reconciliation recreates
-//     the single supported runtime replica from the rewritten template.
+//     each replica from the rewritten template and shard patches.
 func (r *BatchSandboxReconciler) continueResume(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	snapshot := &sandboxv1alpha1.SandboxSnapshot{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: internalPauseSnapshotName(bs.Name)}, snapshot); err != nil {
-		if errors.IsNotFound(err) {
-			readyPodExists, readyErr := r.hasReadyResumePod(ctx, bs)
-			if readyErr != nil {
-				return ctrl.Result{}, readyErr
-			}
-			if readyPodExists {
-				log.Info("SandboxSnapshot missing for resume, but a ready pod already exists; treating resume as complete")
-				_ = r.ackPauseWithPhase(ctx, bs, sandboxv1alpha1.BatchSandboxPhaseSucceed, "")
-				_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionResumeFailed, sandboxv1alpha1.ConditionFalse, "", "")
-				_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionPodFailed, sandboxv1alpha1.ConditionFalse, "", "")
+	count := pauseSnapshotCount(bs)
+	replicaImages := make([]map[string]string, 0, count)
+	for idx := int32(0); idx < count; idx++ {
+		snapshotName := internalPauseSnapshotName(bs.Name, idx)
+		snapshot := &sandboxv1alpha1.SandboxSnapshot{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: snapshotName}, snapshot); err != nil {
+			if errors.IsNotFound(err) {
+				readyPodExists, readyErr := r.hasReadyResumePod(ctx, bs)
+				if readyErr != nil {
+					return ctrl.Result{}, readyErr
+				}
+				if readyPodExists {
+					log.Info("SandboxSnapshot missing for resume, but a ready pod already exists; treating resume as complete", "snapshot", snapshotName)
+					_ = r.ackPauseWithPhase(ctx, bs, sandboxv1alpha1.BatchSandboxPhaseSucceed, "")
+					_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionResumeFailed, sandboxv1alpha1.ConditionFalse, "", "")
+					_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionPodFailed, sandboxv1alpha1.ConditionFalse, "", "")
+					return ctrl.Result{}, nil
+				}
+				log.Info("SandboxSnapshot not found for resume, rolling back to Paused", "snapshot", snapshotName)
+				_ = r.ackPauseWithPhase(ctx, bs, sandboxv1alpha1.BatchSandboxPhasePaused, "")
+				_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionResumeFailed, sandboxv1alpha1.ConditionTrue, "SnapshotNotFound", fmt.Sprintf("SandboxSnapshot %s not found", snapshotName))
 				return ctrl.Result{}, nil
 			}
-			log.Info("SandboxSnapshot not found for resume, rolling back to Paused")
+			return ctrl.Result{}, err
+		}
+
+		if snapshot.Status.Phase != sandboxv1alpha1.SandboxSnapshotPhaseSucceed {
+			msg := fmt.Sprintf("snapshot not ready: phase=%s, snapshot=%s", snapshot.Status.Phase, snapshotName)
+			log.Error(nil, msg)
 			_ = r.ackPauseWithPhase(ctx, bs, sandboxv1alpha1.BatchSandboxPhasePaused, "")
-			_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionResumeFailed, sandboxv1alpha1.ConditionTrue, "SnapshotNotFound", "SandboxSnapshot not found")
+			_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionResumeFailed, sandboxv1alpha1.ConditionTrue, "SnapshotNotReady", msg)
 			return ctrl.Result{}, nil
 		}
-		return ctrl.Result{}, err
-	}
 
-	if snapshot.Status.Phase != sandboxv1alpha1.SandboxSnapshotPhaseSucceed {
-		msg := fmt.Sprintf("snapshot not ready: phase=%s", snapshot.Status.Phase)
-		log.Error(nil, msg)
-		_ = r.ackPauseWithPhase(ctx, bs, sandboxv1alpha1.BatchSandboxPhasePaused, "")
-		_ = r.setCondition(ctx, bs, sandboxv1alpha1.BatchSandboxConditionResumeFailed, sandboxv1alpha1.ConditionTrue, "SnapshotNotReady", msg)
-		return ctrl.Result{}, nil
-	}
-
-	imageMap := make(map[string]string)
-	for _, c := range snapshot.Status.Containers {
-		imageMap[c.ContainerName] = c.ImageURI
+		imageMap := make(map[string]string)
+		for _, c := range snapshot.Status.Containers {
+			imageMap[c.ContainerName] = c.ImageURI
+		}
+		replicaImages = append(replicaImages, imageMap)
 	}
 
 	var patched *sandboxv1alpha1.BatchSandbox
@@ -555,11 +828,18 @@ func (r *BatchSandboxReconciler) continueResume(ctx context.Context, bs *sandbox
 
 		if latest.Spec.Template != nil {
 			for i := range latest.Spec.Template.Spec.Containers {
-				if img, ok := imageMap[latest.Spec.Template.Spec.Containers[i].Name]; ok {
+				if img, ok := replicaImages[0][latest.Spec.Template.Spec.Containers[i].Name]; ok {
 					latest.Spec.Template.Spec.Containers[i].Image = img
 				}
 			}
 			ensureImagePullSecret(latest.Spec.Template, r.ResumePullSecret)
+			// Replica 0 goes through its shard patch too, so an image override there
+			// cannot win over the resumed image.
+			for idx := 0; idx < len(replicaImages); idx++ {
+				if err := setShardPatchImages(latest, idx, replicaImages[idx]); err != nil {
+					return err
+				}
+			}
 		}
 
 		if latest.Spec.PoolRef != "" {
@@ -603,6 +883,24 @@ func (r *BatchSandboxReconciler) ackPauseGeneration(ctx context.Context, bs *san
 	return nil
 }
 
+// updatePauseReplicas replaces the per-replica pause progress in status.
+func (r *BatchSandboxReconciler) updatePauseReplicas(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox, replicas []sandboxv1alpha1.BatchSandboxReplicaPauseStatus) error {
+	var latest *sandboxv1alpha1.BatchSandbox
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest = &sandboxv1alpha1.BatchSandbox{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: bs.Name}, latest); err != nil {
+			return err
+		}
+		latest.Status.PauseReplicas = replicas
+		return r.Status().Update(ctx, latest)
+	}); err != nil {
+		return err
+	}
+	r.StatusRVExpectation.Expect(latest)
+	bs.Status.PauseReplicas = replicas
+	return nil
+}
+
 func (r *BatchSandboxReconciler) ackPauseWithPhase(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox, phase sandboxv1alpha1.BatchSandboxPhase, _ string) error {
 	var latest *sandboxv1alpha1.BatchSandbox
 	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
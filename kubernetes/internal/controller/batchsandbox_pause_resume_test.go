@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
@@ -38,7 +39,6 @@ import (
 	taskscheduler "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/scheduler"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/expectations"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/fieldindex"
-	taskexecutor "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
 )
 
 // newTestReconciler creates a BatchSandboxReconciler with a fake client for testing.
@@ -83,7 +83,7 @@ func (f *forbiddenTaskScheduler) StopTask() []taskscheduler.Task {
 	return nil
 }
 
-func (f *forbiddenTaskScheduler) AddTasks(_ []*taskexecutor.Task) error {
+func (f *forbiddenTaskScheduler) AddTasks(_ []*taskscheduler.SandboxTasks) error {
 	f.t.Fatalf("task scheduler should not add tasks while sandbox is paused")
 	return nil
 }
@@ -107,6 +107,14 @@ func (f fakeSchedulerTask) GetPodName() string {
 	return f.podName
 }
 
+func (f fakeSchedulerTask) GetTerminationMessage() string {
+	return ""
+}
+
+func (f fakeSchedulerTask) GetStepStates() []taskscheduler.StepState {
+	return nil
+}
+
 func (f fakeSchedulerTask) IsResourceReleased() bool {
 	return f.released
 }
@@ -136,7 +144,7 @@ func (r *recordingTaskScheduler) StopTask() []taskscheduler.Task {
 	return r.tasks
 }
 
-func (r *recordingTaskScheduler) AddTasks(_ []*taskexecutor.Task) error {
+func (r *recordingTaskScheduler) AddTasks(_ []*taskscheduler.SandboxTasks) error {
 	return nil
 }
 
@@ -501,7 +509,7 @@ func TestHandlePause_RejectsUnsupportedReplicas(t *testing.T) {
 		},
 		Spec: sandboxv1alpha1.BatchSandboxSpec{
 			Pause:    ptr.To(true),
-			Replicas: ptr.To(int32(2)),
+			Replicas: ptr.To(int32(0)),
 			Template: &corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{{Name: "main", Image: "img"}},
@@ -534,13 +542,58 @@ func TestHandlePause_RejectsUnsupportedReplicas(t *testing.T) {
 	require.NotNil(t, pauseFailed)
 	assert.Equal(t, sandboxv1alpha1.ConditionTrue, pauseFailed.Status)
 	assert.Equal(t, "UnsupportedReplicas", pauseFailed.Reason)
-	assert.Contains(t, pauseFailed.Message, "spec.replicas=1")
+	assert.Contains(t, pauseFailed.Message, "spec.replicas>=1")
 
 	snap := &sandboxv1alpha1.SandboxSnapshot{}
 	err = r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs-pause"}, snap)
 	assert.True(t, apierrors.IsNotFound(err), "unsupported replicas should be rejected before creating a snapshot")
 }
 
+func TestHandlePause_MultiReplicaCreatesSnapshotPerReplica(t *testing.T) {
+	bs := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-bs",
+			Namespace:  "default",
+			Generation: 2,
+			UID:        "test-uid",
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Pause:    ptr.To(true),
+			Replicas: ptr.To(int32(3)),
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "img"}},
+				},
+			},
+		},
+		Status: sandboxv1alpha1.BatchSandboxStatus{
+			PauseObservedGeneration: 1,
+			Phase:                   sandboxv1alpha1.BatchSandboxPhaseSucceed,
+		},
+	}
+	r := newTestReconciler(bs)
+
+	result, err := r.handlePause(context.Background(), bs)
+	require.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	updated := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
+	assert.Equal(t, sandboxv1alpha1.BatchSandboxPhasePausing, updated.Status.Phase)
+	require.Len(t, updated.Status.PauseReplicas, 3)
+
+	for i, name := range []string{"test-bs-pause", "test-bs-pause-1", "test-bs-pause-2"} {
+		assert.Equal(t, name, updated.Status.PauseReplicas[i].SnapshotName)
+		assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhasePending, updated.Status.PauseReplicas[i].Phase)
+
+		snap := &sandboxv1alpha1.SandboxSnapshot{}
+		require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, snap))
+		assert.Equal(t, "test-bs", snap.Spec.SandboxName)
+		require.NotNil(t, snap.Spec.ReplicaIndex)
+		assert.Equal(t, int32(i), *snap.Spec.ReplicaIndex)
+	}
+}
+
 func TestHandlePause_PoolMode(t *testing.T) {
 	// Pool mode: pause should snapshot the allocated pod without mutating poolRef/template first.
 	pool := &sandboxv1alpha1.Pool{
@@ -830,6 +883,9 @@ func TestContinueResume_NormalFlow(t *testing.T) {
 	updated := &sandboxv1alpha1.BatchSandbox{}
 	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
 	assert.Equal(t, "registry/test-bs-main:snap-gen1", updated.Spec.Template.Spec.Containers[0].Image)
+	images, err := replicaImages(updated, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry/test-bs-main:snap-gen1"}, images)
 	// Verify replicas are preserved.
 	assert.Equal(t, int32(1), *updated.Spec.Replicas)
 	// Verify controller does not clear spec.pause
@@ -845,6 +901,98 @@ func TestContinueResume_NormalFlow(t *testing.T) {
 	assert.True(t, found, "imagePullSecrets should contain resume-pull-secret")
 }
 
+func TestContinueResume_MultiReplicaRestoresEachReplicaImage(t *testing.T) {
+	var objs []client.Object
+	for i := int32(0); i < 3; i++ {
+		snapshot := newPauseSnapshot(internalPauseSnapshotName("test-bs", i), i, sandboxv1alpha1.SandboxSnapshotPhaseSucceed)
+		snapshot.Status.Containers = []sandboxv1alpha1.ContainerSnapshot{
+			{ContainerName: "main", ImageURI: fmt.Sprintf("registry/test-bs-main:snap-gen1-r%d", i)},
+		}
+		objs = append(objs, snapshot)
+	}
+	bs := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-bs",
+			Namespace:  "default",
+			Generation: 2,
+			UID:        "test-uid",
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Pause:    ptr.To(false),
+			Replicas: ptr.To(int32(3)),
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "old-img"}},
+				},
+			},
+			ShardPatches: []runtime.RawExtension{
+				{Raw: []byte(`{}`)},
+				{Raw: []byte(`{"spec":{"containers":[{"name":"main","env":[{"name":"SHARD","value":"1"}]}]}}`)},
+			},
+		},
+		Status: sandboxv1alpha1.BatchSandboxStatus{
+			PauseObservedGeneration: 2,
+			Phase:                   sandboxv1alpha1.BatchSandboxPhaseResuming,
+			PauseReplicas:           newPauseReplicaStatuses("test-bs", 3),
+		},
+	}
+	r := newTestReconciler(append(objs, bs)...)
+
+	result, err := r.continueResume(context.Background(), bs)
+	require.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	updated := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
+	assert.Equal(t, "registry/test-bs-main:snap-gen1-r0", updated.Spec.Template.Spec.Containers[0].Image)
+	require.Len(t, updated.Spec.ShardPatches, 3)
+	assert.JSONEq(t, `{"spec":{"containers":[{"name":"main","image":"registry/test-bs-main:snap-gen1-r0"}]}}`, string(updated.Spec.ShardPatches[0].Raw))
+	assert.JSONEq(t, `{"spec":{"containers":[{"name":"main","image":"registry/test-bs-main:snap-gen1-r1","env":[{"name":"SHARD","value":"1"}]}]}}`, string(updated.Spec.ShardPatches[1].Raw))
+	assert.JSONEq(t, `{"spec":{"containers":[{"name":"main","image":"registry/test-bs-main:snap-gen1-r2"}]}}`, string(updated.Spec.ShardPatches[2].Raw))
+}
+
+func TestContinueResume_OverridesReplicaZeroShardPatchImage(t *testing.T) {
+	snapshot := newPauseSnapshot(internalPauseSnapshotName("test-bs", 0), 0, sandboxv1alpha1.SandboxSnapshotPhaseSucceed)
+	snapshot.Status.Containers = []sandboxv1alpha1.ContainerSnapshot{
+		{ContainerName: "main", ImageURI: "registry/test-bs-main:snap-gen1"},
+	}
+	bs := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-bs",
+			Namespace:  "default",
+			Generation: 2,
+			UID:        "test-uid",
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Pause:    ptr.To(false),
+			Replicas: ptr.To(int32(1)),
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "old-img"}},
+				},
+			},
+			ShardPatches: []runtime.RawExtension{
+				{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"shard0-img"}]}}`)},
+			},
+		},
+		Status: sandboxv1alpha1.BatchSandboxStatus{
+			PauseObservedGeneration: 2,
+			Phase:                   sandboxv1alpha1.BatchSandboxPhaseResuming,
+		},
+	}
+	r := newTestReconciler(snapshot, bs)
+
+	_, err := r.continueResume(context.Background(), bs)
+	require.NoError(t, err)
+
+	updated := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
+	require.Len(t, updated.Spec.ShardPatches, 1)
+	images, err := replicaImages(updated, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry/test-bs-main:snap-gen1"}, images)
+}
+
 func TestContinueResume_PreservesExistingReplicas(t *testing.T) {
 	snapshot := &sandboxv1alpha1.SandboxSnapshot{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2457,6 +2605,118 @@ func TestSyncPauseOrClear_SnapshotFailed(t *testing.T) {
 	assert.True(t, foundCondition, "PauseFailed condition should be set")
 }
 
+func newMultiReplicaPausingSandbox(replicas int32) *sandboxv1alpha1.BatchSandbox {
+	return &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-bs",
+			Namespace:  "default",
+			Generation: 2,
+			UID:        "test-uid",
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Pause:    ptr.To(true),
+			Replicas: ptr.To(replicas),
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "img"}},
+				},
+			},
+		},
+		Status: sandboxv1alpha1.BatchSandboxStatus{
+			PauseObservedGeneration: 2,
+			Phase:                   sandboxv1alpha1.BatchSandboxPhasePausing,
+			PauseReplicas:           newPauseReplicaStatuses("test-bs", replicas),
+		},
+	}
+}
+
+func newPauseSnapshot(name string, index int32, phase sandboxv1alpha1.SandboxSnapshotPhase) *sandboxv1alpha1.SandboxSnapshot {
+	return &sandboxv1alpha1.SandboxSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       sandboxv1alpha1.SandboxSnapshotSpec{SandboxName: "test-bs", ReplicaIndex: ptr.To(index)},
+		Status:     sandboxv1alpha1.SandboxSnapshotStatus{Phase: phase},
+	}
+}
+
+func TestSyncPauseOrClear_MultiReplicaRetakesFailedReplica(t *testing.T) {
+	bs := newMultiReplicaPausingSandbox(2)
+	r := newTestReconciler(bs,
+		newPauseSnapshot("test-bs-pause", 0, sandboxv1alpha1.SandboxSnapshotPhaseSucceed),
+		newPauseSnapshot("test-bs-pause-1", 1, sandboxv1alpha1.SandboxSnapshotPhaseFailed),
+	)
+	r.PauseSnapshotRetries = 1
+
+	result, err := r.syncPauseOrClear(context.Background(), bs)
+	require.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	updated := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
+	assert.Equal(t, sandboxv1alpha1.BatchSandboxPhasePausing, updated.Status.Phase)
+	require.Len(t, updated.Status.PauseReplicas, 2)
+	assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhaseSucceed, updated.Status.PauseReplicas[0].Phase)
+	assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhasePending, updated.Status.PauseReplicas[1].Phase)
+	assert.Equal(t, int32(1), updated.Status.PauseReplicas[1].Retries)
+
+	// Only the failed replica is retaken.
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs-pause"}, &sandboxv1alpha1.SandboxSnapshot{}))
+	err = r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs-pause-1"}, &sandboxv1alpha1.SandboxSnapshot{})
+	assert.True(t, apierrors.IsNotFound(err), "failed replica snapshot should be deleted for retry")
+
+	// The next pass recreates the deleted snapshot for the same replica.
+	_, err = r.syncPauseOrClear(context.Background(), updated)
+	require.NoError(t, err)
+	retaken := &sandboxv1alpha1.SandboxSnapshot{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs-pause-1"}, retaken))
+	require.NotNil(t, retaken.Spec.ReplicaIndex)
+	assert.Equal(t, int32(1), *retaken.Spec.ReplicaIndex)
+}
+
+func TestSyncPauseOrClear_MultiReplicaFailsAfterRetries(t *testing.T) {
+	bs := newMultiReplicaPausingSandbox(2)
+	bs.Status.PauseReplicas[1].Retries = 1
+	r := newTestReconciler(bs,
+		newPauseSnapshot("test-bs-pause", 0, sandboxv1alpha1.SandboxSnapshotPhaseSucceed),
+		newPauseSnapshot("test-bs-pause-1", 1, sandboxv1alpha1.SandboxSnapshotPhaseFailed),
+	)
+	r.PauseSnapshotRetries = 1
+
+	result, err := r.syncPauseOrClear(context.Background(), bs)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+
+	updated := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
+	assert.NotEqual(t, sandboxv1alpha1.BatchSandboxPhasePausing, updated.Status.Phase)
+	assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhaseFailed, updated.Status.PauseReplicas[1].Phase)
+
+	var pauseFailed *sandboxv1alpha1.BatchSandboxCondition
+	for i := range updated.Status.Conditions {
+		if updated.Status.Conditions[i].Type == sandboxv1alpha1.BatchSandboxConditionPauseFailed {
+			pauseFailed = &updated.Status.Conditions[i]
+		}
+	}
+	require.NotNil(t, pauseFailed)
+	assert.Contains(t, pauseFailed.Message, "replica 1: snapshot failed")
+}
+
+func TestSyncPauseOrClear_MultiReplicaWaitsForAllReplicas(t *testing.T) {
+	bs := newMultiReplicaPausingSandbox(2)
+	r := newTestReconciler(bs,
+		newPauseSnapshot("test-bs-pause", 0, sandboxv1alpha1.SandboxSnapshotPhaseSucceed),
+		newPauseSnapshot("test-bs-pause-1", 1, sandboxv1alpha1.SandboxSnapshotPhaseCommitting),
+	)
+
+	result, err := r.syncPauseOrClear(context.Background(), bs)
+	require.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	updated := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-bs"}, updated))
+	assert.Equal(t, sandboxv1alpha1.BatchSandboxPhasePausing, updated.Status.Phase)
+	assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhaseCommitting, updated.Status.PauseReplicas[1].Phase)
+}
+
 func TestSyncPauseOrClear_SnapshotFailedReturnsStatusUpdateError(t *testing.T) {
 	snapshot := &sandboxv1alpha1.SandboxSnapshot{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,139 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+const (
+	snapshotRefPendingRequeue = time.Second
+	snapshotRefFailedRequeue  = 10 * time.Second
+)
+
+// needsSnapshotRef reports whether spec.snapshotRef still has to be written into the template.
+// Once applied, the source-snapshot annotation records it so later template rewrites
+// (for example by resume) are not reverted.
+func needsSnapshotRef(bs *sandboxv1alpha1.BatchSandbox) bool {
+	return bs.DeletionTimestamp == nil && bs.Spec.SnapshotRef != "" &&
+		bs.Annotations[AnnoSourceSnapshotKey] != bs.Spec.SnapshotRef
+}
+
+// specConflict returns why the spec cannot be reconciled, or "". The webhook rejects these
+// combinations; this covers objects admitted while it was disabled.
+func specConflict(bs *sandboxv1alpha1.BatchSandbox) string {
+	if bs.Spec.SnapshotRef != "" && bs.Spec.PoolRef != "" {
+		return "snapshotRef and poolRef are mutually exclusive"
+	}
+	return ""
+}
+
+// rejectInvalidSpec marks the sandbox Failed with InvalidSpec=True. It gets no pods until the
+// spec is fixed.
+func (r *BatchSandboxReconciler) rejectInvalidSpec(ctx context.Context, batchSbx *sandboxv1alpha1.BatchSandbox, msg string) error {
+	if !hasConditionTrue(&batchSbx.Status, sandboxv1alpha1.BatchSandboxConditionInvalidSpec) {
+		r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonInvalidSpec, "Not reconciled: %s", msg)
+	}
+	status := batchSbx.Status.DeepCopy()
+	status.ObservedGeneration = batchSbx.Generation
+	status.Phase = sandboxv1alpha1.BatchSandboxPhaseFailed
+	setConditionInStatus(status, sandboxv1alpha1.BatchSandboxConditionInvalidSpec, sandboxv1alpha1.ConditionTrue, EventReasonInvalidSpec, msg)
+	if equality.Semantic.DeepEqual(*status, batchSbx.Status) {
+		return nil
+	}
+	return r.updateStatus(ctx, batchSbx, status)
+}
+
+// templateFromSnapshot returns a copy of template with the snapshot's container images applied.
+// Containers present in the snapshot but missing from template are appended, so a sandbox
+// without a template is started from the snapshot alone.
+func templateFromSnapshot(template *corev1.PodTemplateSpec, snapshot *sandboxv1alpha1.SandboxSnapshot, pullSecret string) *corev1.PodTemplateSpec {
+	out := &corev1.PodTemplateSpec{}
+	if template != nil {
+		out = template.DeepCopy()
+	}
+	for _, c := range snapshot.Status.Containers {
+		found := false
+		for i := range out.Spec.Containers {
+			if out.Spec.Containers[i].Name == c.ContainerName {
+				out.Spec.Containers[i].Image = c.ImageURI
+				found = true
+				break
+			}
+		}
+		if !found {
+			out.Spec.Containers = append(out.Spec.Containers, corev1.Container{Name: c.ContainerName, Image: c.ImageURI})
+		}
+	}
+	ensureImagePullSecret(out, pullSecret)
+	return out
+}
+
+// applySnapshotRef writes the images of the referenced SandboxSnapshot into spec.template.
+// Returns (true, nil) when the BatchSandbox was patched, which triggers a new reconcile with
+// the materialized template. A non-zero result means the snapshot is not usable yet.
+func (r *BatchSandboxReconciler) applySnapshotRef(ctx context.Context, batchSbx *sandboxv1alpha1.BatchSandbox) (bool, ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	snapshot := &sandboxv1alpha1.SandboxSnapshot{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: batchSbx.Namespace, Name: batchSbx.Spec.SnapshotRef}, snapshot); err != nil {
+		if errors.IsNotFound(err) {
+			r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonSnapshotNotReady, "SandboxSnapshot %s not found", batchSbx.Spec.SnapshotRef)
+			return false, ctrl.Result{RequeueAfter: snapshotRefFailedRequeue}, nil
+		}
+		return false, ctrl.Result{}, fmt.Errorf("failed to get snapshot %s: %w", batchSbx.Spec.SnapshotRef, err)
+	}
+
+	switch snapshot.Status.Phase {
+	case sandboxv1alpha1.SandboxSnapshotPhaseSucceed:
+	case sandboxv1alpha1.SandboxSnapshotPhaseFailed:
+		r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonSnapshotNotReady,
+			"SandboxSnapshot %s failed: %s", snapshot.Name, snapshotFailureMessage(snapshot))
+		return false, ctrl.Result{RequeueAfter: snapshotRefFailedRequeue}, nil
+	default:
+		log.Info("waiting for snapshot", "snapshot", snapshot.Name, "phase", snapshot.Status.Phase)
+		return false, ctrl.Result{RequeueAfter: snapshotRefPendingRequeue}, nil
+	}
+	if len(snapshot.Status.Containers) == 0 {
+		r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonSnapshotNotReady, "SandboxSnapshot %s has no container images", snapshot.Name)
+		return false, ctrl.Result{RequeueAfter: snapshotRefFailedRequeue}, nil
+	}
+
+	oldSbx := batchSbx.DeepCopy()
+	batchSbx.Spec.Template = templateFromSnapshot(batchSbx.Spec.Template, snapshot, r.ResumePullSecret)
+	if batchSbx.Annotations == nil {
+		batchSbx.Annotations = map[string]string{}
+	}
+	batchSbx.Annotations[AnnoSourceSnapshotKey] = snapshot.Name
+	if err := r.Patch(ctx, batchSbx, client.MergeFrom(oldSbx)); err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("failed to patch template from snapshot: %w", err)
+	}
+
+	log.Info("applied snapshot to template", "snapshot", snapshot.Name)
+	r.Recorder.Eventf(batchSbx, corev1.EventTypeNormal, EventReasonSnapshotApplied, "Applied SandboxSnapshot %s to template", snapshot.Name)
+	return true, ctrl.Result{}, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func newSourceSnapshot(phase sandboxv1alpha1.SandboxSnapshotPhase) *sandboxv1alpha1.SandboxSnapshot {
+	return &sandboxv1alpha1.SandboxSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "golden", Namespace: "default"},
+		Spec:       sandboxv1alpha1.SandboxSnapshotSpec{SandboxName: "builder"},
+		Status: sandboxv1alpha1.SandboxSnapshotStatus{
+			Phase: phase,
+			Containers: []sandboxv1alpha1.ContainerSnapshot{
+				{ContainerName: "main", ImageURI: "registry/builder-main:snap-gen1"},
+				{ContainerName: "sidecar", ImageURI: "registry/builder-sidecar:snap-gen1"},
+			},
+		},
+	}
+}
+
+func newSnapshotRefSandbox(template *corev1.PodTemplateSpec) *sandboxv1alpha1.BatchSandbox {
+	return &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "fork", Namespace: "default"},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Replicas:    ptr.To[int32](4),
+			SnapshotRef: "golden",
+			Template:    template,
+		},
+	}
+}
+
+func TestApplySnapshotRef_BuildsTemplateFromSnapshot(t *testing.T) {
+	bs := newSnapshotRefSandbox(nil)
+	r := newTestReconciler(bs, newSourceSnapshot(sandboxv1alpha1.SandboxSnapshotPhaseSucceed))
+	r.ResumePullSecret = "snapshot-pull"
+
+	require.True(t, needsSnapshotRef(bs))
+	updated, result, err := r.applySnapshotRef(context.Background(), bs)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Zero(t, result.RequeueAfter)
+
+	latest := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "fork"}, latest))
+	require.NotNil(t, latest.Spec.Template)
+	assert.Equal(t, []corev1.Container{
+		{Name: "main", Image: "registry/builder-main:snap-gen1"},
+		{Name: "sidecar", Image: "registry/builder-sidecar:snap-gen1"},
+	}, latest.Spec.Template.Spec.Containers)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "snapshot-pull"}}, latest.Spec.Template.Spec.ImagePullSecrets)
+	assert.Equal(t, "golden", latest.Annotations[AnnoSourceSnapshotKey])
+	assert.Equal(t, int32(4), *latest.Spec.Replicas)
+	assert.False(t, needsSnapshotRef(latest))
+}
+
+func TestApplySnapshotRef_OverridesTemplateImages(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    "main",
+				Image:   "python:3.11",
+				Command: []string{"sleep", "infinity"},
+			}},
+		},
+	}
+	bs := newSnapshotRefSandbox(template)
+	r := newTestReconciler(bs, newSourceSnapshot(sandboxv1alpha1.SandboxSnapshotPhaseSucceed))
+
+	updated, _, err := r.applySnapshotRef(context.Background(), bs)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	latest := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "fork"}, latest))
+	containers := latest.Spec.Template.Spec.Containers
+	require.Len(t, containers, 2)
+	assert.Equal(t, "registry/builder-main:snap-gen1", containers[0].Image)
+	assert.Equal(t, []string{"sleep", "infinity"}, containers[0].Command)
+	assert.Equal(t, "sidecar", containers[1].Name)
+	assert.Empty(t, latest.Spec.Template.Spec.ImagePullSecrets)
+}
+
+func TestApplySnapshotRef_WaitsForSnapshot(t *testing.T) {
+	for _, phase := range []sandboxv1alpha1.SandboxSnapshotPhase{
+		sandboxv1alpha1.SandboxSnapshotPhasePending,
+		sandboxv1alpha1.SandboxSnapshotPhaseCommitting,
+		sandboxv1alpha1.SandboxSnapshotPhaseFailed,
+	} {
+		t.Run(string(phase), func(t *testing.T) {
+			bs := newSnapshotRefSandbox(nil)
+			r := newTestReconciler(bs, newSourceSnapshot(phase))
+
+			updated, result, err := r.applySnapshotRef(context.Background(), bs)
+			require.NoError(t, err)
+			assert.False(t, updated)
+			assert.True(t, result.RequeueAfter > 0)
+
+			latest := &sandboxv1alpha1.BatchSandbox{}
+			require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "fork"}, latest))
+			assert.Nil(t, latest.Spec.Template)
+			assert.Empty(t, latest.Annotations[AnnoSourceSnapshotKey])
+		})
+	}
+}
+
+func TestApplySnapshotRef_MissingSnapshotRequeues(t *testing.T) {
+	bs := newSnapshotRefSandbox(nil)
+	r := newTestReconciler(bs)
+
+	updated, result, err := r.applySnapshotRef(context.Background(), bs)
+	require.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, snapshotRefFailedRequeue, result.RequeueAfter)
+}
+
+func TestNeedsSnapshotRef(t *testing.T) {
+	bs := newSnapshotRefSandbox(nil)
+	assert.True(t, needsSnapshotRef(bs))
+
+	bs.Annotations = map[string]string{AnnoSourceSnapshotKey: "golden"}
+	assert.False(t, needsSnapshotRef(bs), "applied snapshot must not be re-applied")
+
+	bs.Spec.SnapshotRef = "golden-v2"
+	assert.True(t, needsSnapshotRef(bs), "changed snapshotRef must be applied again")
+
+	bs.Spec.SnapshotRef = ""
+	assert.False(t, needsSnapshotRef(bs))
+}
+
+func TestReconcile_RejectsSnapshotRefWithPoolRef(t *testing.T) {
+	bs := newSnapshotRefSandbox(nil)
+	bs.Spec.PoolRef = "idle"
+	r := newTestReconciler(bs, newSourceSnapshot(sandboxv1alpha1.SandboxSnapshotPhaseSucceed))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "fork"}}
+
+	for i := 0; i < 2; i++ {
+		result, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	}
+
+	latest := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, latest))
+	assert.Equal(t, sandboxv1alpha1.BatchSandboxPhaseFailed, latest.Status.Phase)
+	assert.True(t, hasConditionTrue(&latest.Status, sandboxv1alpha1.BatchSandboxConditionInvalidSpec))
+	assert.Nil(t, latest.Spec.Template, "the snapshot must not be applied")
+	assert.Empty(t, latest.Annotations[AnnoSourceSnapshotKey])
+	assert.Len(t, r.Recorder.(*record.FakeRecorder).Events, 1, "the rejection is reported once")
+}
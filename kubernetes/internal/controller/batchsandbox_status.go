@@ -236,9 +236,42 @@ func applySteadyRuntimePhase(batchSbx *sandboxv1alpha1.BatchSandbox, status *san
 // isInitialUnallocatedSandbox returns true when the sandbox has just been created
 // and no pods have been allocated yet. In this case we skip writing the initial
 // Pending status — the next reconcile after allocation will write Succeed directly.
+// A sandbox rejected by quota is never allocated, so its status is always written.
 func isInitialUnallocatedSandbox(batchSbx *sandboxv1alpha1.BatchSandbox, view runtimeView) bool {
 	return view.status.Replicas == 0 && batchSbx.Status.Phase == "" &&
-		batchSbx.Spec.Replicas != nil && *batchSbx.Spec.Replicas > 0
+		batchSbx.Spec.Replicas != nil && *batchSbx.Spec.Replicas > 0 &&
+		!hasConditionTrue(view.status, sandboxv1alpha1.BatchSandboxConditionQuotaExceeded)
+}
+
+func hasConditionTrue(status *sandboxv1alpha1.BatchSandboxStatus, conditionType sandboxv1alpha1.BatchSandboxConditionType) bool {
+	for _, cond := range status.Conditions {
+		if cond.Type == conditionType && cond.Status == sandboxv1alpha1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// applyQuotaCondition sets QuotaExceeded while the sandbox is rejected by a SandboxQuota and
+// clears it once the sandbox is admitted.
+func (r *BatchSandboxReconciler) applyQuotaCondition(batchSbx *sandboxv1alpha1.BatchSandbox, status *sandboxv1alpha1.BatchSandboxStatus, rejection string) {
+	if rejection == "" {
+		setConditionInStatus(status, sandboxv1alpha1.BatchSandboxConditionQuotaExceeded, sandboxv1alpha1.ConditionFalse, "", "")
+		return
+	}
+	if !hasConditionTrue(&batchSbx.Status, sandboxv1alpha1.BatchSandboxConditionQuotaExceeded) {
+		r.Recorder.Eventf(batchSbx, corev1.EventTypeWarning, EventReasonQuotaExceeded, "Not admitted: %s", rejection)
+	}
+	setConditionInStatus(status, sandboxv1alpha1.BatchSandboxConditionQuotaExceeded, sandboxv1alpha1.ConditionTrue, EventReasonQuotaExceeded, rejection)
+}
+
+// applyTaskFailedCondition sets TaskFailed while any task has failed.
+func applyTaskFailedCondition(status *sandboxv1alpha1.BatchSandboxStatus, ts *taskScheduleResult) {
+	if ts.Failed == 0 {
+		setConditionInStatus(status, sandboxv1alpha1.BatchSandboxConditionTaskFailed, sandboxv1alpha1.ConditionFalse, "", "")
+		return
+	}
+	setConditionInStatus(status, sandboxv1alpha1.BatchSandboxConditionTaskFailed, sandboxv1alpha1.ConditionTrue, "TaskFailed", ts.FailureMessage)
 }
 
 func (r *BatchSandboxReconciler) persistRuntimeView(
@@ -278,7 +311,7 @@ func (r *BatchSandboxReconciler) persistRuntimeView(
 	}
 
 	if view.status.Phase == sandboxv1alpha1.BatchSandboxPhaseSucceed {
-		if err := r.deleteInternalPauseSnapshot(ctx, batchSbx); err != nil {
+		if err := r.deleteInternalPauseSnapshots(ctx, batchSbx); err != nil {
 			log.Error(err, "Failed to delete SandboxSnapshot after successful resume")
 			aggErrors = append(aggErrors, err)
 		}
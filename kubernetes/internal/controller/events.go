@@ -15,6 +15,10 @@ const (
 	EventReasonPoolAssigned     = "PoolAssigned"
 	EventReasonFailedPoolAssign = "FailedPoolAssign"
 
+	// Snapshot reference — recorded on BatchSandbox by batchsandbox-controller
+	EventReasonSnapshotApplied  = "SnapshotApplied"
+	EventReasonSnapshotNotReady = "SnapshotNotReady"
+
 	// Pod release — recorded on BatchSandbox
 	EventReasonPodReleased   = "PodReleased"
 	EventReasonFailedRelease = "FailedRelease"
@@ -28,8 +32,20 @@ const (
 	// Allocation result — recorded on Pool
 	EventReasonAllocationSucceeded = "AllocationSucceeded"
 	EventReasonAllocationFailed    = "AllocationFailed"
+	EventReasonAllocationDrift     = "AllocationDrift"
 
 	// Pod recycle — recorded on Pool
 	EventReasonPodRecycled      = "PodRecycled"
 	EventReasonFailedRecyclePod = "FailedRecyclePod"
+
+	// Priority preemption — Preempting on the preemptor and Pool, Preempted on the victim BatchSandbox
+	EventReasonPreempting    = "Preempting"
+	EventReasonPreempted     = "Preempted"
+	EventReasonFailedPreempt = "FailedPreempt"
+
+	// Namespace quota — recorded on BatchSandbox by batchsandbox-controller
+	EventReasonQuotaExceeded = "QuotaExceeded"
+
+	// Spec validation the webhook would have done — recorded on BatchSandbox by batchsandbox-controller
+	EventReasonInvalidSpec = "InvalidSpec"
 )
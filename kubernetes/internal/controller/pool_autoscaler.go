@@ -0,0 +1,218 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+const (
+	defaultAutoscalingWindowSeconds     = 300
+	defaultAutoscalingLeadSeconds       = 60
+	defaultAutoscalingTargetWaitSeconds = 1
+
+	// autoscalingResyncInterval re-evaluates autoscaled pools without any event,
+	// so the buffer target decays once allocations stop.
+	autoscalingResyncInterval = 15 * time.Second
+)
+
+// PoolDemand records pod allocations of autoscaled pools. Like PoolScaleExpectations it is
+// in-memory only: after a controller restart the window refills from new allocations.
+var PoolDemand = newPoolDemandTracker()
+
+type allocationSample struct {
+	at   time.Time
+	pods int32
+	wait time.Duration
+}
+
+type poolDemandTracker struct {
+	mu      sync.Mutex
+	samples map[string][]allocationSample
+}
+
+func newPoolDemandTracker() *poolDemandTracker {
+	return &poolDemandTracker{samples: map[string][]allocationSample{}}
+}
+
+// Record adds an allocation of pods that waited wait since their BatchSandbox was created.
+func (t *poolDemandTracker) Record(key string, at time.Time, pods int32, wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples[key] = append(t.samples[key], allocationSample{at: at, pods: pods, wait: max(wait, 0)})
+}
+
+// Observe drops samples older than window and returns the number of pods allocated
+// within it and their average wait.
+func (t *poolDemandTracker) Observe(key string, now time.Time, window time.Duration) (int32, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	samples := t.samples[key]
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	samples = samples[i:]
+	if len(samples) == 0 {
+		delete(t.samples, key)
+		return 0, 0
+	}
+	t.samples[key] = samples
+
+	var pods int32
+	var totalWait time.Duration
+	for _, s := range samples {
+		pods += s.pods
+		totalWait += s.wait * time.Duration(s.pods)
+	}
+	if pods == 0 {
+		return 0, 0
+	}
+	return pods, totalWait / time.Duration(pods)
+}
+
+// Delete forgets all samples of a pool.
+func (t *poolDemandTracker) Delete(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.samples, key)
+}
+
+// autoscaleBuffer computes the effective buffer target of an autoscaled pool at now.
+// An active schedule overrides the demand target; the result is always bounded by
+// bufferMin and bufferMax.
+func autoscaleBuffer(ctx context.Context, pool *sandboxv1alpha1.Pool, tracker *poolDemandTracker, key string, now time.Time) *sandboxv1alpha1.PoolAutoscalingStatus {
+	spec := pool.Spec.Autoscaling
+	window := secondsOrDefault(spec.WindowSeconds, defaultAutoscalingWindowSeconds)
+	lead := secondsOrDefault(spec.LeadSeconds, defaultAutoscalingLeadSeconds)
+	targetWait := secondsOrDefault(spec.TargetWaitSeconds, defaultAutoscalingTargetWaitSeconds)
+
+	allocations, avgWait := tracker.Observe(key, now, window)
+	status := &sandboxv1alpha1.PoolAutoscalingStatus{
+		AllocationsInWindow:     allocations,
+		AverageWaitMilliseconds: avgWait.Milliseconds(),
+	}
+	target := demandBufferTarget(allocations, avgWait, window, lead, targetWait)
+	for i := range spec.Schedules {
+		schedule := &spec.Schedules[i]
+		active, err := scheduleActive(schedule, now)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Ignoring invalid autoscaling schedule", "pool", pool.Name, "schedule", schedule.Name)
+			continue
+		}
+		if active {
+			target = schedule.BufferTarget
+			status.ActiveSchedule = schedule.Name
+			break
+		}
+	}
+	status.DesiredBuffer = min(max(target, pool.Spec.CapacitySpec.BufferMin), pool.Spec.CapacitySpec.BufferMax)
+	return status
+}
+
+// demandBufferTarget sizes the buffer to absorb lead worth of allocations at the observed rate,
+// scaled up by how far the average wait exceeds targetWait.
+func demandBufferTarget(allocations int32, avgWait, window, lead, targetWait time.Duration) int32 {
+	if allocations == 0 {
+		return 0
+	}
+	demand := float64(allocations) * lead.Seconds() / window.Seconds()
+	if avgWait > targetWait {
+		demand *= float64(avgWait) / float64(targetWait)
+	}
+	return int32(min(math.Ceil(demand), math.MaxInt32))
+}
+
+func secondsOrDefault(seconds, def int32) time.Duration {
+	if seconds <= 0 {
+		seconds = def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ValidateAutoscalingSchedule reports whether the times, time zone and days of s can be evaluated.
+func ValidateAutoscalingSchedule(s *sandboxv1alpha1.PoolAutoscalingSchedule) error {
+	_, err := scheduleActive(s, time.Now())
+	return err
+}
+
+// scheduleActive reports whether now falls into the daily window of s. For a window that
+// spans midnight, Days refers to the day the window starts.
+func scheduleActive(s *sandboxv1alpha1.PoolAutoscalingSchedule, now time.Time) (bool, error) {
+	loc := time.UTC
+	if s.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(s.TimeZone); err != nil {
+			return false, fmt.Errorf("invalid timeZone %q: %w", s.TimeZone, err)
+		}
+	}
+	start, err := parseClock(s.Start)
+	if err != nil {
+		return false, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseClock(s.End)
+	if err != nil {
+		return false, fmt.Errorf("invalid end: %w", err)
+	}
+	days := make(map[time.Weekday]bool, len(s.Days))
+	for _, d := range s.Days {
+		day, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return false, fmt.Errorf("invalid day %q: must be one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", d)
+		}
+		days[day] = true
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+	var active bool
+	switch {
+	case start < end:
+		active = minute >= start && minute < end
+	case start > end:
+		if minute < end {
+			day = (day + 6) % 7
+		}
+		active = minute >= start || minute < end
+	default:
+		active = true
+	}
+	return active && (len(days) == 0 || days[day]), nil
+}
+
+// parseClock parses HH:MM into minutes after midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	controllerutils "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/controller"
+)
+
+func newAutoscaledPool(name string, bufferMin, bufferMax int32) *sandboxv1alpha1.Pool {
+	return &sandboxv1alpha1.Pool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: sandboxv1alpha1.PoolSpec{
+			CapacitySpec: sandboxv1alpha1.CapacitySpec{BufferMin: bufferMin, BufferMax: bufferMax, PoolMax: 20},
+			Autoscaling:  &sandboxv1alpha1.PoolAutoscaling{WindowSeconds: 300, LeadSeconds: 60, TargetWaitSeconds: 1},
+		},
+	}
+}
+
+func TestPoolDemandTracker(t *testing.T) {
+	tracker := newPoolDemandTracker()
+	now := time.Now()
+	tracker.Record("default/pool", now.Add(-10*time.Minute), 5, time.Second)
+	tracker.Record("default/pool", now.Add(-time.Minute), 1, 4*time.Second)
+	tracker.Record("default/pool", now, 3, 0)
+
+	allocations, avgWait := tracker.Observe("default/pool", now, 5*time.Minute)
+	assert.Equal(t, int32(4), allocations)
+	assert.Equal(t, time.Second, avgWait)
+
+	allocations, _ = tracker.Observe("default/pool", now.Add(2*time.Minute), time.Minute)
+	assert.Zero(t, allocations)
+	assert.Empty(t, tracker.samples)
+
+	tracker.Record("default/pool", now, 1, -time.Second)
+	_, avgWait = tracker.Observe("default/pool", now, time.Minute)
+	assert.Zero(t, avgWait)
+	tracker.Delete("default/pool")
+	assert.Empty(t, tracker.samples)
+}
+
+func TestDemandBufferTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		allocations int32
+		avgWait     time.Duration
+		want        int32
+	}{
+		{name: "no demand", allocations: 0, want: 0},
+		{name: "one per minute covers lead", allocations: 5, want: 1},
+		{name: "rounds up", allocations: 11, want: 3},
+		{name: "wait within target", allocations: 10, avgWait: 500 * time.Millisecond, want: 2},
+		{name: "wait above target scales up", allocations: 10, avgWait: 3 * time.Second, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := demandBufferTarget(tt.allocations, tt.avgWait, 5*time.Minute, time.Minute, time.Second)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheduleActive(t *testing.T) {
+	// 2026-10-19 is a Monday.
+	monday := func(hour, minute int) time.Time { return time.Date(2026, 10, 19, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		schedule sandboxv1alpha1.PoolAutoscalingSchedule
+		now      time.Time
+		want     bool
+	}{
+		{name: "inside window", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "08:00", End: "10:00"}, now: monday(9, 0), want: true},
+		{name: "end is exclusive", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "08:00", End: "10:00"}, now: monday(10, 0), want: false},
+		{name: "weekday filter", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "08:00", End: "10:00", Days: []string{"Tue"}}, now: monday(9, 0), want: false},
+		{name: "overnight before midnight", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "22:00", End: "02:00", Days: []string{"Mon"}}, now: monday(23, 0), want: true},
+		{name: "overnight counts the start day", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "22:00", End: "02:00", Days: []string{"sun"}}, now: monday(1, 0), want: true},
+		{name: "overnight outside", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "22:00", End: "02:00"}, now: monday(12, 0), want: false},
+		{name: "time zone", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "08:00", End: "10:00", TimeZone: "Asia/Shanghai"}, now: monday(1, 0), want: true},
+		{name: "equal start and end is all day", schedule: sandboxv1alpha1.PoolAutoscalingSchedule{Start: "00:00", End: "00:00"}, now: monday(15, 30), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scheduleActive(&tt.schedule, tt.now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := scheduleActive(&sandboxv1alpha1.PoolAutoscalingSchedule{Start: "8am", End: "10:00"}, monday(9, 0))
+	assert.ErrorContains(t, err, "invalid start")
+}
+
+func TestAutoscaleBuffer(t *testing.T) {
+	pool := newAutoscaledPool("autoscale-buffer", 1, 8)
+	key := controllerutils.GetControllerKey(pool)
+	tracker := newPoolDemandTracker()
+	now := time.Now()
+
+	status := autoscaleBuffer(context.Background(), pool, tracker, key, now)
+	assert.Equal(t, sandboxv1alpha1.PoolAutoscalingStatus{DesiredBuffer: 1}, *status, "idle pool falls back to bufferMin")
+
+	for i := range 20 {
+		tracker.Record(key, now.Add(-time.Duration(i)*time.Second), 1, 2*time.Second)
+	}
+	status = autoscaleBuffer(context.Background(), pool, tracker, key, now)
+	assert.Equal(t, sandboxv1alpha1.PoolAutoscalingStatus{DesiredBuffer: 8, AllocationsInWindow: 20, AverageWaitMilliseconds: 2000}, *status,
+		"demand target of 8 is capped at bufferMax")
+
+	pool.Spec.Autoscaling.Schedules = []sandboxv1alpha1.PoolAutoscalingSchedule{
+		{Name: "broken", Start: "00:00", End: "00:00", TimeZone: "Mars/Olympus", BufferTarget: 6},
+		{Name: "night", Start: "00:00", End: "00:00", BufferTarget: 2},
+	}
+	status = autoscaleBuffer(context.Background(), pool, tracker, key, now)
+	assert.Equal(t, int32(2), status.DesiredBuffer)
+	assert.Equal(t, "night", status.ActiveSchedule)
+}
+
+func TestScalePool_AutoscalingShrinksIdleBuffer(t *testing.T) {
+	pool := newAutoscaledPool("autoscale-shrink", 1, 8)
+	key := controllerutils.GetControllerKey(pool)
+	t.Cleanup(func() {
+		PoolDemand.Delete(key)
+		PoolScaleExpectations.DeleteExpectations(key)
+	})
+
+	var pods []*corev1.Pod
+	var objs []runtime.Object
+	var idle []string
+	for i := range 6 {
+		pod := newEvictionTestPod(fmt.Sprintf("pod-%d", i), map[string]string{LabelPoolName: pool.Name}, false)
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		pods = append(pods, pod)
+		objs = append(objs, pod)
+		idle = append(idle, pod.Name)
+	}
+	r := newEvictionTestReconciler(map[string]string{}, objs...)
+	args := &scaleArgs{pods: pods, totalPodCnt: 6, idlePods: idle}
+
+	// Without demand the buffer shrinks to bufferMin, even though 6 is within [bufferMin, bufferMax].
+	status, err := r.scalePool(context.Background(), pool, args)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), status.DesiredBuffer)
+	podList := &corev1.PodList{}
+	require.NoError(t, r.List(context.Background(), podList))
+	assert.Len(t, podList.Items, 1)
+
+	// The static policy keeps a buffer that is already within bounds.
+	pool.Spec.Autoscaling = nil
+	r = newEvictionTestReconciler(map[string]string{}, objs...)
+	status, err = r.scalePool(context.Background(), pool, args)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+	require.NoError(t, r.List(context.Background(), podList))
+	assert.Len(t, podList.Items, 6)
+}
@@ -21,6 +21,7 @@ import (
 	gerrors "errors"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"sync"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -50,6 +52,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/algorithm"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/eviction"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/recycle"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils"
@@ -109,9 +112,11 @@ type PoolReconciler struct {
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=pools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=pools/finalizers,verbs=update
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=batchsandboxes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxquotas,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
 func (r *PoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
@@ -127,6 +132,7 @@ func (r *PoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 			// Pool resource not found, could have been deleted
 			controllerKey := req.NamespacedName.String()
 			PoolScaleExpectations.DeleteExpectations(controllerKey)
+			PoolDemand.Delete(controllerKey)
 			r.Allocator.ClearPoolAllocation(ctx, req.Namespace, req.Name)
 			log.Info("Pool resource not found, cleaned up scale expectations", "pool", controllerKey)
 			return ctrl.Result{}, nil
@@ -138,6 +144,7 @@ func (r *PoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 	if !pool.DeletionTimestamp.IsZero() {
 		controllerKey := controllerutils.GetControllerKey(pool)
 		PoolScaleExpectations.DeleteExpectations(controllerKey)
+		PoolDemand.Delete(controllerKey)
 		r.Allocator.ClearPoolAllocation(ctx, req.Namespace, req.Name)
 		log.Info("Pool resource is being deleted, cleaned up scale expectations", "pool", controllerKey)
 		return ctrl.Result{}, nil
@@ -173,7 +180,8 @@ func (r *PoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 	batchSandboxes := make([]*sandboxv1alpha1.BatchSandbox, 0, len(batchSandboxList.Items))
 	for i := range batchSandboxList.Items {
 		batchSandbox := batchSandboxList.Items[i]
-		if batchSandbox.Spec.Template != nil {
+		// snapshotRef with poolRef is rejected by the BatchSandbox controller; allocate nothing.
+		if batchSandbox.Spec.Template != nil || batchSandbox.Spec.SnapshotRef != "" {
 			continue
 		}
 		batchSandboxes = append(batchSandboxes, &batchSandbox)
@@ -204,8 +212,8 @@ func (r *PoolReconciler) reconcilePool(ctx context.Context, pool *sandboxv1alpha
 		if err != nil {
 			return err
 		}
-		// Requeue if there are pending sandboxes waiting for scheduling
-		if schedResult.SupplyCnt > 0 {
+		// Requeue if there are pending sandboxes waiting for scheduling or pods still being recycled
+		if schedResult.SupplyCnt > 0 || schedResult.Recycling {
 			result = ctrl.Result{RequeueAfter: defaultRetryTime}
 		}
 
@@ -227,12 +235,17 @@ func (r *PoolReconciler) reconcilePool(ctx context.Context, pool *sandboxv1alpha
 			supplyCnt:      schedResult.SupplyCnt + updateResult.SupplyUpdateRevision,
 		}
 
-		if err := r.scalePool(ctx, latestPool, args); err != nil {
+		autoscaling, err := r.scalePool(ctx, latestPool, args)
+		if err != nil {
 			return err
 		}
+		// Re-evaluate autoscaled pools periodically so the observation window slides.
+		if autoscaling != nil && (result.RequeueAfter == 0 || result.RequeueAfter > autoscalingResyncInterval) {
+			result = ctrl.Result{RequeueAfter: autoscalingResyncInterval}
+		}
 
 		// 6. Update pool status
-		if err := r.updatePoolStatus(ctx, updateResult.UpdateRevision, latestPool, pods, schedulePods, schedResult.LatestAllocation); err != nil {
+		if err := r.updatePoolStatus(ctx, updateResult.UpdateRevision, latestPool, pods, schedulePods, schedResult.LatestAllocation, autoscaling); err != nil {
 			return err
 		}
 
@@ -280,6 +293,11 @@ func (r *PoolReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconci
 			if oldVal != newVal {
 				return true
 			}
+			// Recycled preempted pods are pruned by the next scheduling round.
+			if newObj.Annotations[AnnoAllocPreemptedKey] != "" &&
+				oldObj.Annotations[AnnoAllocReleasedKey] != newObj.Annotations[AnnoAllocReleasedKey] {
+				return true
+			}
 			if oldObj.Spec.Replicas != newObj.Spec.Replicas {
 				return true
 			}
@@ -361,11 +379,30 @@ func (r *PoolReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconci
 			enqueueOldPoolForDetachedBatchSandbox,
 			builder.WithPredicates(filterBatchSandboxDetached),
 		).
+		Watches(
+			&sandboxv1alpha1.SandboxQuota{},
+			handler.EnqueueRequestsFromMapFunc(r.poolsForQuota),
+		).
 		Named("pool").
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		Complete(r)
 }
 
+// poolsForQuota enqueues every Pool in the namespace of a SandboxQuota so allocation is
+// re-evaluated against the changed limits or usage.
+func (r *PoolReconciler) poolsForQuota(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &sandboxv1alpha1.PoolList{}
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list pools for SandboxQuota", "sandboxQuota", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name}})
+	}
+	return requests
+}
+
 func (r *PoolReconciler) doAllocate(ctx context.Context, pool *sandboxv1alpha1.Pool, batchSandboxes []*sandboxv1alpha1.BatchSandbox, pods []*corev1.Pod, toAllocate map[string][]string) error {
 	// 1. Compute latest allocated pods per sandbox (merge current + newly allocated).
 	toSyncMap := r.getLatestAllocated(ctx, pool, batchSandboxes, toAllocate)
@@ -595,20 +632,141 @@ func (r *PoolReconciler) getLatestReleased(ctx context.Context, batchSandboxes [
 	return toSyncMap, orphanPods
 }
 
+// doPreempt marks victim pods as preempted and requests their release. The victim's BatchSandbox
+// controller drops release-requested pods from its pod list on its next reconcile, and the next
+// scheduling round recycles them like any other released pod. Once recycled they are pruned by
+// pruneRecycledPreemptions. Failures are reported as events and retried by the next reconcile.
+func (r *PoolReconciler) doPreempt(ctx context.Context, pool *sandboxv1alpha1.Pool, sandboxByName map[string]*sandboxv1alpha1.BatchSandbox, preemptions []algorithm.Preemption) {
+	log := logf.FromContext(ctx)
+	for _, p := range preemptions {
+		victim, ok := sandboxByName[p.Victim]
+		if !ok {
+			continue
+		}
+		if err := r.preemptSandboxPods(ctx, victim, p.Pods); err != nil {
+			log.Error(err, "Failed to preempt sandbox pods", "victim", p.Victim, "preemptor", p.Preemptor, "pods", p.Pods)
+			r.Recorder.Eventf(pool, corev1.EventTypeWarning, EventReasonFailedPreempt,
+				"Failed to preempt %d pod(s) of sandbox %s for sandbox %s: %v", len(p.Pods), p.Victim, p.Preemptor, err)
+			continue
+		}
+		r.Recorder.Eventf(pool, corev1.EventTypeNormal, EventReasonPreempting,
+			"Preempting %d pod(s) of sandbox %s for higher-priority sandbox %s: %v", len(p.Pods), p.Victim, p.Preemptor, p.Pods)
+		r.Recorder.Eventf(victim, corev1.EventTypeWarning, EventReasonPreempted,
+			"Preempted %d pod(s) by higher-priority sandbox %s: %v", len(p.Pods), p.Preemptor, p.Pods)
+		if preemptor, ok := sandboxByName[p.Preemptor]; ok {
+			r.Recorder.Eventf(preemptor, corev1.EventTypeNormal, EventReasonPreempting,
+				"Preempting %d pod(s) of lower-priority sandbox %s", len(p.Pods), p.Victim)
+		}
+	}
+}
+
+// preemptSandboxPods appends pods to the sandbox's preempted and release annotations. The patch
+// carries the observed resourceVersion so a concurrent release by the BatchSandbox controller is not lost.
+func (r *PoolReconciler) preemptSandboxPods(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox, pods []string) error {
+	preempted, err := parseSandboxPreempted(sandbox)
+	if err != nil {
+		return err
+	}
+	release, err := parseSandboxReleased(sandbox)
+	if err != nil {
+		return err
+	}
+	body := utils.DumpJSON(struct {
+		MetaData metav1.ObjectMeta `json:"metadata"`
+	}{
+		MetaData: metav1.ObjectMeta{
+			ResourceVersion: sandbox.ResourceVersion,
+			Annotations: map[string]string{
+				AnnoAllocPreemptedKey: utils.DumpJSON(AllocationPreempted{Pods: sets.List(sets.New(preempted.Pods...).Insert(pods...))}),
+				AnnoAllocReleaseKey:   utils.DumpJSON(AllocationRelease{Pods: sets.List(sets.New(release.Pods...).Insert(pods...))}),
+			},
+		},
+	})
+	obj := &sandboxv1alpha1.BatchSandbox{ObjectMeta: metav1.ObjectMeta{Namespace: sandbox.Namespace, Name: sandbox.Name}}
+	return r.Patch(ctx, obj, client.RawPatch(types.MergePatchType, []byte(body)))
+}
+
+// pruneRecycledPreemptions drops preempted pods whose recycle has completed from the sandbox's
+// allocation annotations. Removing them from alloc-status and alloc-preempted together keeps the
+// supplement (replicas - (allocated - preempted)) unchanged, and removing them from alloc-release
+// and alloc-released lets the pool hand the same pods back to this sandbox later. The patch
+// carries the observed resourceVersion; on conflict the next reconcile retries.
+func (r *PoolReconciler) pruneRecycledPreemptions(ctx context.Context, sandbox *sandboxv1alpha1.BatchSandbox) error {
+	preempted, err := parseSandboxPreempted(sandbox)
+	if err != nil || len(preempted.Pods) == 0 {
+		return err
+	}
+	released, err := r.Allocator.GetSandboxReleased(ctx, sandbox)
+	if err != nil {
+		return err
+	}
+	recycled := sets.New(preempted.Pods...).Intersection(sets.New(released...))
+	if recycled.Len() == 0 {
+		return nil
+	}
+	alloc, err := parseSandboxAllocation(sandbox)
+	if err != nil {
+		return err
+	}
+	release, err := parseSandboxReleased(sandbox)
+	if err != nil {
+		return err
+	}
+	alloc.Pods = slices.DeleteFunc(slices.Clone(alloc.Pods), recycled.Has)
+	annotations := map[string]any{
+		AnnoAllocStatusKey:    utils.DumpJSON(alloc),
+		AnnoAllocReleaseKey:   utils.DumpJSON(AllocationRelease{Pods: slices.DeleteFunc(release.Pods, recycled.Has)}),
+		AnnoAllocReleasedKey:  utils.DumpJSON(AllocationReleased{Pods: slices.DeleteFunc(slices.Clone(released), recycled.Has)}),
+		AnnoAllocPreemptedKey: nil,
+	}
+	if remaining := slices.DeleteFunc(preempted.Pods, recycled.Has); len(remaining) > 0 {
+		annotations[AnnoAllocPreemptedKey] = utils.DumpJSON(AllocationPreempted{Pods: remaining})
+	}
+	body := utils.DumpJSON(map[string]any{
+		"metadata": map[string]any{
+			"resourceVersion": sandbox.ResourceVersion,
+			"annotations":     annotations,
+		},
+	})
+	if err := r.Patch(ctx, sandbox, client.RawPatch(types.MergePatchType, []byte(body))); err != nil {
+		return err
+	}
+	logf.FromContext(ctx).Info("Pruned recycled preempted pods", "sandbox", sandbox.Name, "pods", sets.List(recycled))
+	return nil
+}
+
 func (r *PoolReconciler) scheduleSandbox(ctx context.Context, pool *sandboxv1alpha1.Pool, batchSandboxes []*sandboxv1alpha1.BatchSandbox, pods []*corev1.Pod) (*ScheduleResult, error) {
 	log := logf.FromContext(ctx)
 	// 1. Compute scheduling actions.
+	admission, err := admitSandboxQuotas(ctx, r.Client, pool.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, sbx := range batchSandboxes {
+		if err := r.pruneRecycledPreemptions(ctx, sbx); err != nil {
+			log.Error(err, "Failed to prune recycled preempted pods", "sandbox", sbx.Name)
+		}
+	}
+	if drift, err := r.Allocator.CheckPoolAllocation(ctx, pool, batchSandboxes); err != nil {
+		log.Error(err, "Failed to check pool allocation against BatchSandbox annotations", "pool", pool.Name)
+	} else if len(drift) > 0 {
+		log.Info("Repaired allocation drift", "pool", pool.Name, "drift", drift)
+		r.Recorder.Eventf(pool, corev1.EventTypeWarning, EventReasonAllocationDrift,
+			"Repaired allocation of %d pod(s) that disagreed with BatchSandbox annotations: %v", len(drift), drift)
+	}
 	spec := &AllocSpec{
-		Sandboxes: batchSandboxes,
-		Pool:      pool,
-		Pods:      pods,
+		Sandboxes:     batchSandboxes,
+		Pool:          pool,
+		Pods:          pods,
+		QuotaRejected: admission.Rejected,
+		MaxPods:       poolPodLimit(admission.Quotas, pool.Name),
 	}
 	allocAction, err := r.Allocator.Schedule(ctx, spec)
 	if err != nil {
 		r.Recorder.Eventf(pool, corev1.EventTypeWarning, EventReasonAllocationFailed, "Failed to schedule sandboxes: %v", err)
 		return nil, err
 	}
-	log.Info("Allocate action", "pool", pool.Name, "toAllocate", allocAction.ToAllocate, "toRelease", allocAction.ToRelease)
+	log.Info("Allocate action", "pool", pool.Name, "toAllocate", allocAction.ToAllocate, "toRelease", allocAction.ToRelease, "preemptions", allocAction.Preemptions)
 
 	// 2. Execute scheduling actions.
 	// 2.1 Execute ToAllocate / update in-memory store.
@@ -622,6 +780,7 @@ func (r *PoolReconciler) scheduleSandbox(ctx context.Context, pool *sandboxv1alp
 	for _, bs := range batchSandboxes {
 		sandboxByName[bs.Name] = bs
 	}
+	now := time.Now()
 	for sandboxName, allocPods := range allocAction.ToAllocate {
 		if len(allocPods) == 0 {
 			continue
@@ -631,6 +790,9 @@ func (r *PoolReconciler) scheduleSandbox(ctx context.Context, pool *sandboxv1alp
 		if sbx, ok := sandboxByName[sandboxName]; ok {
 			r.Recorder.Eventf(sbx, corev1.EventTypeNormal, EventReasonScheduled,
 				"Successfully assigned %d pod(s) from pool %s: %v", len(allocPods), pool.Name, allocPods)
+			if pool.Spec.Autoscaling != nil {
+				PoolDemand.Record(controllerutils.GetControllerKey(pool), now, int32(len(allocPods)), now.Sub(sbx.CreationTimestamp.Time))
+			}
 		}
 	}
 
@@ -640,6 +802,9 @@ func (r *PoolReconciler) scheduleSandbox(ctx context.Context, pool *sandboxv1alp
 		return nil, err
 	}
 
+	// 2.3 Execute Preemptions / request release of victim pods; they are recycled in a later round.
+	r.doPreempt(ctx, pool, sandboxByName, allocAction.Preemptions)
+
 	// 3. Return schedule result
 	latestAllocation, err := r.Allocator.GetPoolAllocation(ctx, pool)
 	if err != nil {
@@ -651,14 +816,25 @@ func (r *PoolReconciler) scheduleSandbox(ctx context.Context, pool *sandboxv1alp
 			idlePods = append(idlePods, pod.Name)
 		}
 	}
+	// Pods whose recycle is still in progress stay allocated; in-place recycles such as Reset
+	// do not touch the pod while they wait, so they are driven forward by requeueing.
+	recycling := false
+	for _, podNames := range allocAction.ToRelease {
+		for _, podName := range podNames {
+			if _, ok := latestAllocation[podName]; ok && !slices.Contains(toDeletePods, podName) {
+				recycling = true
+			}
+		}
+	}
 	result := &ScheduleResult{
 		LatestAllocation: latestAllocation,
 		IdlePods:         idlePods,
 		ToDelete:         toDeletePods,
 		SupplyCnt:        allocAction.PodSupplement,
+		Recycling:        recycling,
 	}
 
-	log.Info("Schedule result", "pool", pool.Name, "toDeletePods", toDeletePods, "supplyCnt", allocAction.PodSupplement)
+	log.Info("Schedule result", "pool", pool.Name, "toDeletePods", toDeletePods, "supplyCnt", allocAction.PodSupplement, "recycling", recycling)
 	return result, nil
 }
 
@@ -699,6 +875,8 @@ type ScheduleResult struct {
 	ToDelete []string
 	// SupplyCnt is the number of additional pods the allocator needs but are not yet available.
 	SupplyCnt int32
+	// Recycling reports whether some released pods are still being recycled.
+	Recycling bool
 }
 
 type UpdateResult struct {
@@ -709,7 +887,9 @@ type UpdateResult struct {
 	SupplyUpdateRevision int32
 }
 
-func (r *PoolReconciler) scalePool(ctx context.Context, pool *sandboxv1alpha1.Pool, args *scaleArgs) error {
+// scalePool creates or deletes pool pods to reach the desired buffer. With spec.autoscaling set the
+// buffer target follows observed demand and the decision is returned for status.
+func (r *PoolReconciler) scalePool(ctx context.Context, pool *sandboxv1alpha1.Pool, args *scaleArgs) (*sandboxv1alpha1.PoolAutoscalingStatus, error) {
 	log := logf.FromContext(ctx)
 	errs := make([]error, 0)
 	pods := args.pods
@@ -720,7 +900,7 @@ func (r *PoolReconciler) scalePool(ctx context.Context, pool *sandboxv1alpha1.Po
 			PoolScaleExpectations.DeleteExpectations(controllerutils.GetControllerKey(pool))
 		} else {
 			log.Info("Pool scale is not ready, requeue", "unsatisfiedDuration", unsatisfiedDuration, "dirtyPods", dirtyPods)
-			return fmt.Errorf("pool scale is not ready, %v", pool.Name)
+			return nil, fmt.Errorf("pool scale is not ready, %v", pool.Name)
 		}
 	}
 	schedulableCnt := int32(len(args.pods))
@@ -732,7 +912,14 @@ func (r *PoolReconciler) scalePool(ctx context.Context, pool *sandboxv1alpha1.Po
 
 	// Calculate desired buffer cnt.
 	desiredBufferCnt := bufferCnt
-	if bufferCnt < pool.Spec.CapacitySpec.BufferMin || bufferCnt > pool.Spec.CapacitySpec.BufferMax {
+	var autoscaling *sandboxv1alpha1.PoolAutoscalingStatus
+	if pool.Spec.Autoscaling != nil {
+		autoscaling = autoscaleBuffer(ctx, pool, PoolDemand, controllerutils.GetControllerKey(pool), time.Now())
+		desiredBufferCnt = autoscaling.DesiredBuffer
+		log.Info("Autoscaling buffer target", "pool", pool.Name, "desiredBuffer", autoscaling.DesiredBuffer,
+			"allocationsInWindow", autoscaling.AllocationsInWindow, "averageWaitMilliseconds", autoscaling.AverageWaitMilliseconds,
+			"activeSchedule", autoscaling.ActiveSchedule)
+	} else if bufferCnt < pool.Spec.CapacitySpec.BufferMin || bufferCnt > pool.Spec.CapacitySpec.BufferMax {
 		desiredBufferCnt = (pool.Spec.CapacitySpec.BufferMin + pool.Spec.CapacitySpec.BufferMax) / 2
 	}
 
@@ -787,10 +974,10 @@ func (r *PoolReconciler) scalePool(ctx context.Context, pool *sandboxv1alpha1.Po
 			}
 		}
 	}
-	return gerrors.Join(errs...)
+	return autoscaling, gerrors.Join(errs...)
 }
 
-func (r *PoolReconciler) updatePoolStatus(ctx context.Context, updateRevision string, pool *sandboxv1alpha1.Pool, pods []*corev1.Pod, schedulePods []*corev1.Pod, podAllocation map[string]string) error {
+func (r *PoolReconciler) updatePoolStatus(ctx context.Context, updateRevision string, pool *sandboxv1alpha1.Pool, pods []*corev1.Pod, schedulePods []*corev1.Pod, podAllocation map[string]string, autoscaling *sandboxv1alpha1.PoolAutoscalingStatus) error {
 	oldStatus := pool.Status.DeepCopy()
 	availableCnt := int32(0)
 	for _, pod := range schedulePods {
@@ -814,6 +1001,7 @@ func (r *PoolReconciler) updatePoolStatus(ctx context.Context, updateRevision st
 	pool.Status.Available = availableCnt
 	pool.Status.Revision = updateRevision
 	pool.Status.Updated = updatedCnt
+	pool.Status.Autoscaling = autoscaling
 	if equality.Semantic.DeepEqual(*oldStatus, pool.Status) {
 		return nil
 	}
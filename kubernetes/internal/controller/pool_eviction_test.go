@@ -57,6 +57,9 @@ func (a *stubAllocator) GetSandboxReleased(_ context.Context, _ *sandboxv1alpha1
 	return nil, nil
 }
 func (a *stubAllocator) ReleasePodsAllocation(_ context.Context, _ string, _ string, _ []string) {}
+func (a *stubAllocator) CheckPoolAllocation(_ context.Context, _ *sandboxv1alpha1.Pool, _ []*sandboxv1alpha1.BatchSandbox) ([]AllocationDrift, error) {
+	return nil, nil
+}
 
 func newEvictionTestPod(name string, labels map[string]string, deleting bool) *corev1.Pod {
 	pod := &corev1.Pod{
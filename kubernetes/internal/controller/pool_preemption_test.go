@@ -0,0 +1,161 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/algorithm"
+)
+
+func TestDoPreempt(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = sandboxv1alpha1.AddToScheme(scheme)
+	victim := &sandboxv1alpha1.BatchSandbox{ObjectMeta: metav1.ObjectMeta{
+		Name: "batch", Namespace: "default",
+		Annotations: map[string]string{AnnoAllocReleaseKey: `{"pods":["pod0"]}`},
+	}}
+	preemptor := &sandboxv1alpha1.BatchSandbox{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(victim, preemptor).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &PoolReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"}}
+
+	ctx := context.Background()
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), victim))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(preemptor), preemptor))
+	sandboxByName := map[string]*sandboxv1alpha1.BatchSandbox{victim.Name: victim, preemptor.Name: preemptor}
+	r.doPreempt(ctx, pool, sandboxByName, []algorithm.Preemption{{Preemptor: "agent", Victim: "batch", Pods: []string{"pod2"}}})
+
+	latest := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), latest))
+	release, err := parseSandboxReleased(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod0", "pod2"}, release.Pods)
+	preempted, err := parseSandboxPreempted(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod2"}, preempted.Pods)
+
+	var reasons []string
+	for range 3 {
+		reasons = append(reasons, <-recorder.Events)
+	}
+	assert.Contains(t, reasons[0], EventReasonPreempting)
+	assert.Contains(t, reasons[1], corev1.EventTypeWarning+" "+EventReasonPreempted)
+	assert.Contains(t, reasons[2], EventReasonPreempting)
+
+	// A stale victim is not overwritten; the failure is reported on the pool.
+	r.doPreempt(ctx, pool, sandboxByName, []algorithm.Preemption{{Preemptor: "agent", Victim: "batch", Pods: []string{"pod1"}}})
+	assert.Contains(t, <-recorder.Events, EventReasonFailedPreempt)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), latest))
+	preempted, err = parseSandboxPreempted(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod2"}, preempted.Pods)
+}
+
+func TestPruneRecycledPreemptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = sandboxv1alpha1.AddToScheme(scheme)
+	victim := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "batch", Namespace: "default",
+			Annotations: map[string]string{
+				AnnoAllocStatusKey:    `{"pods":["pod0","pod1","pod2","pod3"]}`,
+				AnnoAllocReleaseKey:   `{"pods":["pod1","pod2"]}`,
+				AnnoAllocReleasedKey:  `{"pods":["pod1"]}`,
+				AnnoAllocPreemptedKey: `{"pods":["pod1","pod2"]}`,
+			},
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: ptr.To[int32](3), PoolRef: "pool"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(victim).Build()
+	allocator := NewDefaultAllocator(c).(*defaultAllocator)
+	r := &PoolReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), Allocator: allocator}
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"}}
+
+	ctx := context.Background()
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), victim))
+	before, err := allocator.getSandboxRequest(ctx, pool, victim)
+	require.NoError(t, err)
+
+	require.NoError(t, r.pruneRecycledPreemptions(ctx, victim))
+	latest := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), latest))
+	alloc, err := parseSandboxAllocation(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod0", "pod2", "pod3"}, alloc.Pods)
+	release, err := parseSandboxReleased(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod2"}, release.Pods)
+	released, err := allocator.GetSandboxReleased(ctx, latest)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+	preempted, err := parseSandboxPreempted(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod2"}, preempted.Pods)
+
+	after, err := allocator.getSandboxRequest(ctx, pool, latest)
+	require.NoError(t, err)
+	assert.Equal(t, before.PodSupplement, after.PodSupplement, "pruning must not change the supplement")
+
+	// Once the last preempted pod is recycled the annotation is removed.
+	latest.Annotations[AnnoAllocReleasedKey] = `{"pods":["pod2"]}`
+	require.NoError(t, c.Update(ctx, latest))
+	require.NoError(t, r.pruneRecycledPreemptions(ctx, latest))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), latest))
+	assert.NotContains(t, latest.Annotations, AnnoAllocPreemptedKey)
+	alloc, err = parseSandboxAllocation(latest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod0", "pod3"}, alloc.Pods)
+}
+
+func TestReleasePods_KeepsConcurrentPreemption(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = sandboxv1alpha1.AddToScheme(scheme)
+	bs := &sandboxv1alpha1.BatchSandbox{ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(bs).Build()
+	recorder := record.NewFakeRecorder(10)
+	pr := &PoolReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+	br := &BatchSandboxReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	ctx := context.Background()
+	stale := &sandboxv1alpha1.BatchSandbox{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(bs), stale))
+	current := stale.DeepCopy()
+	require.NoError(t, pr.preemptSandboxPods(ctx, current, []string{"pod2"}))
+
+	err := br.releasePods(ctx, stale, []string{"pod0"})
+	require.True(t, apierrors.IsConflict(err), "stale release must not overwrite alloc-release: %v", err)
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(bs), current))
+	require.NoError(t, br.releasePods(ctx, current, []string{"pod0"}))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(bs), current))
+	release, err := parseSandboxReleased(current)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod0", "pod2"}, release.Pods)
+}
@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"strings"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
 )
 
@@ -36,6 +38,12 @@ type Scorer interface {
 	Score(ctx context.Context, sbx *sandboxv1alpha1.BatchSandbox, pool *sandboxv1alpha1.Pool) float64
 }
 
+// ReaderAware is implemented by plugins that read cluster state such as pool pods and nodes.
+// The assigner injects its reader before the first Score call; without one the plugin scores 0.
+type ReaderAware interface {
+	InjectReader(reader client.Reader)
+}
+
 type Assigner interface {
 	AssignPool(ctx context.Context, sbx *sandboxv1alpha1.BatchSandbox, pools []*sandboxv1alpha1.Pool) (string, error)
 }
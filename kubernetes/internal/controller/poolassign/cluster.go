@@ -0,0 +1,109 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+// labelPoolName is set on every pool pod by the pool controller (controller.LabelPoolName).
+const labelPoolName = "sandbox.opensandbox.io/pool-name"
+
+// clusterView reads pool pods and nodes for ReaderAware scorers. Scorers are created per
+// AssignPool call, so lookups are memoized for the duration of one assignment.
+type clusterView struct {
+	reader   client.Reader
+	nodes    map[string]*corev1.Node
+	poolPods map[types.NamespacedName]map[string]int
+}
+
+func newClusterView(reader client.Reader) *clusterView {
+	return &clusterView{
+		reader:   reader,
+		nodes:    map[string]*corev1.Node{},
+		poolPods: map[types.NamespacedName]map[string]int{},
+	}
+}
+
+// podsPerNode returns the number of scheduled pods of pool on each node.
+func (v *clusterView) podsPerNode(ctx context.Context, pool *sandboxv1alpha1.Pool) (map[string]int, error) {
+	key := types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}
+	if counts, ok := v.poolPods[key]; ok {
+		return counts, nil
+	}
+	pods := &corev1.PodList{}
+	if err := v.reader.List(ctx, pods, client.InNamespace(pool.Namespace), client.MatchingLabels{labelPoolName: pool.Name}); err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		counts[pod.Spec.NodeName]++
+	}
+	v.poolPods[key] = counts
+	return counts, nil
+}
+
+// node returns the named node, or nil when it no longer exists.
+func (v *clusterView) node(ctx context.Context, name string) (*corev1.Node, error) {
+	if node, ok := v.nodes[name]; ok {
+		return node, nil
+	}
+	node := &corev1.Node{}
+	if err := v.reader.Get(ctx, types.NamespacedName{Name: name}, node); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		node = nil
+	}
+	v.nodes[name] = node
+	return node, nil
+}
+
+// normalizeImage expands an image reference the way container runtimes report it in
+// node status, so "nginx" matches "docker.io/library/nginx:latest".
+func normalizeImage(image string) string {
+	name, digest, hasDigest := strings.Cut(image, "@")
+	tag := ""
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i+1:], "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+	first, _, found := strings.Cut(name, "/")
+	switch {
+	case !found:
+		name = "docker.io/library/" + name
+	case first == "index.docker.io":
+		name = "docker.io" + strings.TrimPrefix(name, first)
+	case !strings.ContainsAny(first, ".:") && first != "localhost":
+		name = "docker.io/" + name
+	}
+	if hasDigest {
+		return name + "@" + digest
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return name + ":" + tag
+}
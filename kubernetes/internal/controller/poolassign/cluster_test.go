@@ -0,0 +1,67 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func newFakeReader(objs ...client.Object) client.Reader {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = sandboxv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func makePoolPod(pool, name, node string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{labelPoolName: pool}},
+		Spec:       corev1.PodSpec{NodeName: node},
+	}
+}
+
+func makeNode(name string, labels map[string]string, images ...string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	for _, image := range images {
+		node.Status.Images = append(node.Status.Images, corev1.ContainerImage{Names: []string{image}})
+	}
+	return node
+}
+
+func TestNormalizeImage(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                                  "docker.io/library/nginx:latest",
+		"nginx:1.27":                             "docker.io/library/nginx:1.27",
+		"bitnami/redis:7":                        "docker.io/bitnami/redis:7",
+		"docker.io/library/nginx:1.27":           "docker.io/library/nginx:1.27",
+		"index.docker.io/library/nginx":          "docker.io/library/nginx:latest",
+		"registry.example.com:5000/team/app:v1":  "registry.example.com:5000/team/app:v1",
+		"localhost/app":                          "localhost/app:latest",
+		"registry.example.com/app@sha256:abc":    "registry.example.com/app@sha256:abc",
+		"registry.example.com/app:v1@sha256:abc": "registry.example.com/app@sha256:abc",
+	}
+	for image, want := range tests {
+		assert.Equal(t, want, normalizeImage(image), image)
+	}
+}
@@ -17,18 +17,28 @@ package assign
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
 )
 
 type defaultAssigner struct {
 	profile *Profile
+	reader  client.Reader
 }
 
 func NewDefaultAssigner(profile *Profile) Assigner {
 	return &defaultAssigner{profile: profile}
 }
 
+// NewDefaultAssignerWithReader returns an assigner whose ReaderAware plugins can read
+// pods and nodes through reader, e.g. for image locality and topology spread.
+func NewDefaultAssignerWithReader(profile *Profile, reader client.Reader) Assigner {
+	return &defaultAssigner{profile: profile, reader: reader}
+}
+
 func (a *defaultAssigner) AssignPool(ctx context.Context, sbx *sandboxv1alpha1.BatchSandbox, pools []*sandboxv1alpha1.Pool) (string, error) {
 	predicates, err := NewPredicates(a.profile)
 	if err != nil {
@@ -38,6 +48,13 @@ func (a *defaultAssigner) AssignPool(ctx context.Context, sbx *sandboxv1alpha1.B
 	if err != nil {
 		return "", fmt.Errorf("failed to create scorers: %w", err)
 	}
+	if a.reader != nil {
+		for _, s := range scorers {
+			if ra, ok := s.Scorer.(ReaderAware); ok {
+				ra.InjectReader(a.reader)
+			}
+		}
+	}
 
 	var candidates []*sandboxv1alpha1.Pool
 	var rejections []PoolRejection
@@ -66,6 +83,7 @@ func (a *defaultAssigner) AssignPool(ctx context.Context, sbx *sandboxv1alpha1.B
 			bestScore = score
 		}
 	}
+	assignments.record(best, time.Now())
 	return best.Name, nil
 }
 
@@ -22,4 +22,7 @@ func init() {
 	registerPredicate("capacity", newCapacityPredicate)
 
 	registerScorer("resbalance", newResBalanceScorer)
+	registerScorer("imagelocality", newImageLocalityScorer)
+	registerScorer("topologyspread", newTopologySpreadScorer)
+	registerScorer("leastrecentlyassigned", newLeastRecentlyAssignedScorer)
 }
@@ -0,0 +1,82 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+// imageLocalityScorer prefers pools whose pods run on nodes that already cache the
+// sandbox images, according to node.status.images. The score is the share of sandbox
+// images present on each node, averaged over the pool pods.
+type imageLocalityScorer struct {
+	cluster *clusterView
+}
+
+func newImageLocalityScorer(_ map[string]interface{}) (Scorer, error) {
+	return &imageLocalityScorer{}, nil
+}
+
+func (s *imageLocalityScorer) InjectReader(reader client.Reader) {
+	s.cluster = newClusterView(reader)
+}
+
+func (s *imageLocalityScorer) Score(ctx context.Context, sbx *sandboxv1alpha1.BatchSandbox, pool *sandboxv1alpha1.Pool) float64 {
+	images := collectImages(sbx.Spec.Template)
+	if s.cluster == nil || len(images) == 0 {
+		return 0
+	}
+	log := logf.FromContext(ctx)
+	podsPerNode, err := s.cluster.podsPerNode(ctx, pool)
+	if err != nil {
+		log.Error(err, "imagelocality: failed to list pool pods", "pool", pool.Name)
+		return 0
+	}
+
+	var pods, cached float64
+	for nodeName, count := range podsPerNode {
+		node, err := s.cluster.node(ctx, nodeName)
+		if err != nil {
+			log.Error(err, "imagelocality: failed to get node", "node", nodeName)
+			continue
+		}
+		pods += float64(count)
+		if node == nil {
+			continue
+		}
+		present := map[string]struct{}{}
+		for _, image := range node.Status.Images {
+			for _, name := range image.Names {
+				present[normalizeImage(name)] = struct{}{}
+			}
+		}
+		found := 0
+		for _, image := range images {
+			if _, ok := present[normalizeImage(image)]; ok {
+				found++
+			}
+		}
+		cached += float64(count) * float64(found) / float64(len(images))
+	}
+	if pods == 0 {
+		return 0
+	}
+	return cached / pods
+}
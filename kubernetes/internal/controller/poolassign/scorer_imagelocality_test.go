@@ -0,0 +1,111 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func TestImageLocalityScorer(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(
+		makeNode("node-cached", nil, "docker.io/library/python:3.11", "docker.io/library/nginx:latest"),
+		makeNode("node-cold", nil),
+		makePoolPod("warm", "warm-0", "node-cached"),
+		makePoolPod("warm", "warm-1", "node-cached"),
+		makePoolPod("mixed", "mixed-0", "node-cached"),
+		makePoolPod("mixed", "mixed-1", "node-cold"),
+		makePoolPod("cold", "cold-0", "node-cold"),
+		makePoolPod("pending", "pending-0", ""),
+		makePoolPod("gone", "gone-0", "node-deleted"),
+	)
+	sbx := makeSBX("sbx", "python:3.11")
+
+	tests := []struct {
+		pool string
+		want float64
+	}{
+		{pool: "warm", want: 1},
+		{pool: "mixed", want: 0.5},
+		{pool: "cold", want: 0},
+		{pool: "pending", want: 0},
+		{pool: "gone", want: 0},
+	}
+	s, err := newImageLocalityScorer(nil)
+	require.NoError(t, err)
+	s.(ReaderAware).InjectReader(reader)
+	for _, tt := range tests {
+		t.Run(tt.pool, func(t *testing.T) {
+			pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: tt.pool, Namespace: "default"}}
+			assert.InDelta(t, tt.want, s.Score(ctx, sbx, pool), 1e-9)
+		})
+	}
+
+	t.Run("partial image match", func(t *testing.T) {
+		multi := makeSBX("sbx", "python:3.11")
+		multi.Spec.Template.Spec.Containers = append(multi.Spec.Template.Spec.Containers, multi.Spec.Template.Spec.Containers[0])
+		multi.Spec.Template.Spec.Containers[1].Image = "redis:7"
+		pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "warm", Namespace: "default"}}
+		assert.InDelta(t, 0.5, s.Score(ctx, multi, pool), 1e-9)
+	})
+
+	t.Run("without reader", func(t *testing.T) {
+		s, err := newImageLocalityScorer(nil)
+		require.NoError(t, err)
+		pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: "warm", Namespace: "default"}}
+		assert.Zero(t, s.Score(ctx, sbx, pool))
+	})
+}
+
+func TestDefaultAssignerWithReader_ProfileScorers(t *testing.T) {
+	ctx := context.Background()
+	store := NewProfileStore()
+	require.NoError(t, store.LoadFromConfigMap(&corev1.ConfigMap{Data: map[string]string{"profiles": `[{
+		"name": "locality",
+		"plugins": {
+			"predicate": ["image"],
+			"score": [{"name": "resbalance", "weight": 10}, {"name": "imagelocality", "weight": 100}]
+		}
+	}]`}}))
+
+	cold := makePool("cold", "python:3.11", 10, 0)
+	warm := makePool("warm", "python:3.11", 10, 5)
+	cold.Namespace, warm.Namespace = "default", "default"
+	reader := newFakeReader(
+		makeNode("node-cached", nil, "docker.io/library/python:3.11"),
+		makeNode("node-cold", nil),
+		makePoolPod("warm", "warm-0", "node-cached"),
+		makePoolPod("cold", "cold-0", "node-cold"),
+	)
+	sbx := makeSBX("sbx", "python:3.11")
+	pools := []*sandboxv1alpha1.Pool{cold, warm}
+
+	// resbalance alone prefers the emptier cold pool; image locality outweighs it.
+	name, err := NewDefaultAssigner(store.GetProfile("locality")).AssignPool(ctx, sbx, pools)
+	require.NoError(t, err)
+	assert.Equal(t, "cold", name)
+
+	name, err = NewDefaultAssignerWithReader(store.GetProfile("locality"), reader).AssignPool(ctx, sbx, pools)
+	require.NoError(t, err)
+	assert.Equal(t, "warm", name)
+}
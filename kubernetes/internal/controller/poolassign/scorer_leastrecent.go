@@ -0,0 +1,90 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+const defaultLeastRecentlyAssignedWindowSeconds = 60
+
+// assignments remembers when each pool last won an assignment. It lives in controller
+// memory, so after a restart every pool starts as never assigned.
+var assignments = &assignmentHistory{last: map[types.NamespacedName]time.Time{}}
+
+type assignmentHistory struct {
+	mu   sync.Mutex
+	last map[types.NamespacedName]time.Time
+}
+
+func (h *assignmentHistory) record(pool *sandboxv1alpha1.Pool, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last[types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}] = at
+}
+
+func (h *assignmentHistory) lastAssigned(pool *sandboxv1alpha1.Pool) (time.Time, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	at, ok := h.last[types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}]
+	return at, ok
+}
+
+// leastRecentlyAssignedScorer rotates assignments across otherwise equal pools. A pool that
+// was never assigned, or not within windowSeconds, scores 1; a pool assigned just now scores 0.
+type leastRecentlyAssignedScorer struct {
+	window  time.Duration
+	history *assignmentHistory
+	now     func() time.Time
+}
+
+func newLeastRecentlyAssignedScorer(args map[string]interface{}) (Scorer, error) {
+	seconds := float64(defaultLeastRecentlyAssignedWindowSeconds)
+	if raw, ok := args["windowSeconds"]; ok {
+		switch v := raw.(type) {
+		case float64:
+			seconds = v
+		case int:
+			seconds = float64(v)
+		case int64:
+			seconds = float64(v)
+		default:
+			return nil, fmt.Errorf("windowSeconds must be a number, got %T", raw)
+		}
+		if seconds <= 0 {
+			return nil, fmt.Errorf("windowSeconds must be positive, got %v", seconds)
+		}
+	}
+	return &leastRecentlyAssignedScorer{
+		window:  time.Duration(seconds * float64(time.Second)),
+		history: assignments,
+		now:     time.Now,
+	}, nil
+}
+
+func (s *leastRecentlyAssignedScorer) Score(_ context.Context, _ *sandboxv1alpha1.BatchSandbox, pool *sandboxv1alpha1.Pool) float64 {
+	last, ok := s.history.lastAssigned(pool)
+	if !ok {
+		return 1
+	}
+	return min(max(float64(s.now().Sub(last))/float64(s.window), 0), 1)
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func TestLeastRecentlyAssignedScorer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	history := &assignmentHistory{last: map[types.NamespacedName]time.Time{}}
+	fresh := makePool("fresh", "nginx", 10, 0)
+	recent := makePool("recent", "nginx", 10, 0)
+	older := makePool("older", "nginx", 10, 0)
+	stale := makePool("stale", "nginx", 10, 0)
+	history.record(recent, now)
+	history.record(older, now.Add(-30*time.Second))
+	history.record(stale, now.Add(-time.Hour))
+
+	s, err := newLeastRecentlyAssignedScorer(map[string]interface{}{"windowSeconds": float64(60)})
+	require.NoError(t, err)
+	scorer := s.(*leastRecentlyAssignedScorer)
+	scorer.history = history
+	scorer.now = func() time.Time { return now }
+
+	sbx := makeSBX("sbx", "nginx")
+	assert.Equal(t, 1.0, s.Score(ctx, sbx, fresh))
+	assert.Equal(t, 0.0, s.Score(ctx, sbx, recent))
+	assert.InDelta(t, 0.5, s.Score(ctx, sbx, older), 1e-9)
+	assert.Equal(t, 1.0, s.Score(ctx, sbx, stale))
+
+	_, err = newLeastRecentlyAssignedScorer(map[string]interface{}{"windowSeconds": "1m"})
+	assert.Error(t, err)
+	_, err = newLeastRecentlyAssignedScorer(map[string]interface{}{"windowSeconds": float64(0)})
+	assert.Error(t, err)
+}
+
+func TestDefaultAssigner_LeastRecentlyAssignedRotates(t *testing.T) {
+	ctx := context.Background()
+	profile := &Profile{
+		Name: "rotate",
+		Plugins: PluginsSpec{
+			Predicate: []string{"image"},
+			Score:     []ScoreSpec{{Name: "leastrecentlyassigned", Weight: 100}},
+		},
+	}
+	pools := []*sandboxv1alpha1.Pool{
+		makePool("rotate-a", "nginx", 10, 0),
+		makePool("rotate-b", "nginx", 10, 0),
+	}
+	assigner := NewDefaultAssigner(profile)
+
+	first, err := assigner.AssignPool(ctx, makeSBX("sbx-1", "nginx"), pools)
+	require.NoError(t, err)
+	second, err := assigner.AssignPool(ctx, makeSBX("sbx-2", "nginx"), pools)
+	require.NoError(t, err)
+	assert.Equal(t, "rotate-a", first)
+	assert.Equal(t, "rotate-b", second)
+}
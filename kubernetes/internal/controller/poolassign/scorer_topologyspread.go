@@ -0,0 +1,145 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+// topologySpreadScorer spreads sandboxes across topology domains (zones by default).
+// The load of a domain is the number of allocated pool pods in it, attributing each pool's
+// status.allocated to domains in proportion to where its pods run. A pool scores
+// 1 - (load of its domains / total load), so pools in the least loaded domains win.
+type topologySpreadScorer struct {
+	topologyKey string
+	cluster     *clusterView
+
+	// domainLoad and totalLoad are computed on first use; all candidate pools share a namespace.
+	loaded     bool
+	domainLoad map[string]float64
+	totalLoad  float64
+}
+
+func newTopologySpreadScorer(args map[string]interface{}) (Scorer, error) {
+	topologyKey := corev1.LabelTopologyZone
+	if raw, ok := args["topologyKey"]; ok {
+		key, ok := raw.(string)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("topologyKey must be a non-empty string")
+		}
+		topologyKey = key
+	}
+	return &topologySpreadScorer{topologyKey: topologyKey}, nil
+}
+
+func (s *topologySpreadScorer) InjectReader(reader client.Reader) {
+	s.cluster = newClusterView(reader)
+}
+
+func (s *topologySpreadScorer) Score(ctx context.Context, _ *sandboxv1alpha1.BatchSandbox, pool *sandboxv1alpha1.Pool) float64 {
+	if s.cluster == nil {
+		return 0
+	}
+	log := logf.FromContext(ctx)
+	if err := s.loadDomains(ctx, pool.Namespace); err != nil {
+		log.Error(err, "topologyspread: failed to compute domain load", "namespace", pool.Namespace)
+		return 0
+	}
+	shares, err := s.domainShares(ctx, pool)
+	if err != nil {
+		log.Error(err, "topologyspread: failed to resolve pool domains", "pool", pool.Name)
+		return 0
+	}
+	if len(shares) == 0 {
+		return 0
+	}
+	if s.totalLoad == 0 {
+		return 1
+	}
+	var load float64
+	for domain, share := range shares {
+		load += share * s.domainLoad[domain]
+	}
+	return 1 - load/s.totalLoad
+}
+
+func (s *topologySpreadScorer) loadDomains(ctx context.Context, namespace string) error {
+	if s.loaded {
+		return nil
+	}
+	pools := &sandboxv1alpha1.PoolList{}
+	if err := s.cluster.reader.List(ctx, pools, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	s.domainLoad = map[string]float64{}
+	s.totalLoad = 0
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		if pool.Status.Allocated == 0 {
+			continue
+		}
+		shares, err := s.domainShares(ctx, pool)
+		if err != nil {
+			return err
+		}
+		if len(shares) == 0 {
+			continue
+		}
+		for domain, share := range shares {
+			s.domainLoad[domain] += share * float64(pool.Status.Allocated)
+		}
+		s.totalLoad += float64(pool.Status.Allocated)
+	}
+	s.loaded = true
+	return nil
+}
+
+// domainShares returns the fraction of pool pods in each topology domain. Pods on nodes
+// without the topology label are ignored.
+func (s *topologySpreadScorer) domainShares(ctx context.Context, pool *sandboxv1alpha1.Pool) (map[string]float64, error) {
+	podsPerNode, err := s.cluster.podsPerNode(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	total := 0
+	for nodeName, count := range podsPerNode {
+		node, err := s.cluster.node(ctx, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			continue
+		}
+		domain, ok := node.Labels[s.topologyKey]
+		if !ok {
+			continue
+		}
+		counts[domain] += count
+		total += count
+	}
+	shares := make(map[string]float64, len(counts))
+	for domain, count := range counts {
+		shares[domain] = float64(count) / float64(total)
+	}
+	return shares, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func makeZonedPool(name string, allocated int32) *sandboxv1alpha1.Pool {
+	return &sandboxv1alpha1.Pool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     sandboxv1alpha1.PoolStatus{Allocated: allocated},
+	}
+}
+
+func TestTopologySpreadScorer(t *testing.T) {
+	ctx := context.Background()
+	zoneA := map[string]string{corev1.LabelTopologyZone: "zone-a"}
+	zoneB := map[string]string{corev1.LabelTopologyZone: "zone-b"}
+	busyA := makeZonedPool("busy-a", 6)
+	idleA := makeZonedPool("idle-a", 0)
+	quietB := makeZonedPool("quiet-b", 2)
+	both := makeZonedPool("both", 0)
+	unlabeled := makeZonedPool("unlabeled", 0)
+	reader := newFakeReader(
+		makeNode("node-a", zoneA), makeNode("node-b", zoneB), makeNode("node-x", nil),
+		busyA, idleA, quietB, both, unlabeled,
+		makePoolPod("busy-a", "busy-a-0", "node-a"),
+		makePoolPod("idle-a", "idle-a-0", "node-a"),
+		makePoolPod("quiet-b", "quiet-b-0", "node-b"),
+		makePoolPod("both", "both-0", "node-a"),
+		makePoolPod("both", "both-1", "node-b"),
+		makePoolPod("unlabeled", "unlabeled-0", "node-x"),
+	)
+
+	s, err := newTopologySpreadScorer(nil)
+	require.NoError(t, err)
+	s.(ReaderAware).InjectReader(reader)
+	sbx := makeSBX("sbx", "nginx")
+
+	// zone-a carries 6 of 8 allocated pods, zone-b carries 2.
+	assert.InDelta(t, 0.25, s.Score(ctx, sbx, idleA), 1e-9)
+	assert.InDelta(t, 0.75, s.Score(ctx, sbx, quietB), 1e-9)
+	assert.InDelta(t, 0.5, s.Score(ctx, sbx, both), 1e-9)
+	assert.Zero(t, s.Score(ctx, sbx, unlabeled))
+
+	t.Run("no load scores every placed pool equally", func(t *testing.T) {
+		s, err := newTopologySpreadScorer(nil)
+		require.NoError(t, err)
+		s.(ReaderAware).InjectReader(newFakeReader(makeNode("node-a", zoneA), idleA, makePoolPod("idle-a", "idle-a-0", "node-a")))
+		assert.Equal(t, 1.0, s.Score(ctx, sbx, idleA))
+	})
+
+	t.Run("custom topology key", func(t *testing.T) {
+		s, err := newTopologySpreadScorer(map[string]interface{}{"topologyKey": corev1.LabelHostname})
+		require.NoError(t, err)
+		assert.Equal(t, corev1.LabelHostname, s.(*topologySpreadScorer).topologyKey)
+
+		_, err = newTopologySpreadScorer(map[string]interface{}{"topologyKey": 1})
+		assert.Error(t, err)
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/recycle/reset"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/recycle/restart"
 )
 
@@ -40,6 +41,8 @@ func NewHandler(c client.Client, restConfig *rest.Config, pool *sandboxv1alpha1.
 			return nil, fmt.Errorf("failed to create restart handler: %w", err)
 		}
 		return NewRestartRecycler(h), nil
+	case sandboxv1alpha1.RecycleTypeReset:
+		return NewResetRecycler(reset.NewDefaultResetHandler(c)), nil
 	default:
 		return NewDeleteRecycler(), nil
 	}
@@ -67,6 +67,17 @@ func TestNewHandler(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Reset",
+			pool: &sandboxv1alpha1.Pool{
+				Spec: sandboxv1alpha1.PoolSpec{
+					RecycleStrategy: &sandboxv1alpha1.RecycleStrategy{
+						Type: sandboxv1alpha1.RecycleTypeReset,
+					},
+				},
+			},
+			wantHandler: &ResetRecycler{},
+		},
 		{
 			name: "UnknownType_FallbackToDelete",
 			pool: &sandboxv1alpha1.Pool{
@@ -0,0 +1,71 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recycle
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/recycle/reset"
+)
+
+// ResetRecycler is a RecycleHandler that cleans the pod in place through the agents running in it.
+type ResetRecycler struct {
+	handler reset.Handler
+}
+
+// NewResetRecycler creates a new ResetRecycler with the given reset handler.
+func NewResetRecycler(handler reset.Handler) *ResetRecycler {
+	return &ResetRecycler{handler: handler}
+}
+
+// TryRecycle initiates or drives forward the reset recycle operation.
+// It is re-entrant: delegates directly to reset.Handler.TryReset.
+// A nil pod (already deleted) is considered succeeded since the pod is gone.
+// When the reset fails (cleanup error, failed verification or timeout), it falls back to deletion via NeedDelete.
+func (r *ResetRecycler) TryRecycle(ctx context.Context, pool *sandboxv1alpha1.Pool, pod *corev1.Pod, spec *Spec) (*Status, error) {
+	if pod == nil {
+		return &Status{
+			State:   StateSucceeded,
+			Message: "reset recycler: pod is deleted",
+		}, nil
+	}
+	opts := &reset.Spec{ID: spec.ID}
+	status, err := r.handler.TryReset(ctx, pool, pod, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status.State {
+	case reset.StateSucceeded:
+		return &Status{
+			State:   StateSucceeded,
+			Message: status.Message,
+		}, nil
+	case reset.StateFailed:
+		return &Status{
+			State:      StateFailed,
+			Message:    status.Message,
+			NeedDelete: true,
+		}, nil
+	default:
+		return &Status{
+			State:   StateRecycling,
+			Message: status.Message,
+		}, nil
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reset
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+type State string
+
+const (
+	// StateResetting indicates that the reset procedure is in progress.
+	StateResetting State = "Resetting"
+	// StateSucceeded indicates that the pod has been cleaned and verified.
+	StateSucceeded State = "Succeeded"
+	// StateFailed indicates that the pod could not be reset and must be deleted.
+	StateFailed State = "Failed"
+)
+
+// Step is a stage of the reset procedure.
+type Step string
+
+const (
+	// StepCleanup kills user processes and wipes the configured directories.
+	StepCleanup Step = "Cleanup"
+	// StepEgress restores the egress policy of the pod.
+	StepEgress Step = "Egress"
+	// StepVerify probes the pod until it reports healthy.
+	StepVerify Step = "Verify"
+	// StepDone marks a completed reset.
+	StepDone Step = "Done"
+)
+
+type Status struct {
+	// StartTime is the timestamp when the reset operation was initiated.
+	StartTime *string `json:"startTime,omitempty"`
+	// Step is the stage the reset procedure is in.
+	Step Step `json:"step,omitempty"`
+	// State is the current phase of the reset operation.
+	State State `json:"state"`
+	// Message contains human-readable details about the current state.
+	Message string `json:"message,omitempty"`
+}
+
+type Spec struct {
+	// ID is the sandbox identifier used to correlate reset records.
+	ID string
+}
+
+const (
+	// AnnoResetRecordKey is the annotation key for storing reset progress on a Pod.
+	AnnoResetRecordKey = "sandbox.opensandbox.io/reset-record"
+	// AnnoResetConfigKey is the annotation key on a Pool object for reset configuration.
+	AnnoResetConfigKey = "sandbox.opensandbox.io/reset-config"
+)
+
+type Handler interface {
+	// TryReset initiates or drives forward the reset procedure for the given pool and pod.
+	// On the first call for a sandbox it initializes the reset record and starts the cleanup;
+	// subsequent calls advance the procedure one step at a time.
+	// It is re-entrant: safe to call multiple times until Succeeded or Failed is returned.
+	TryReset(ctx context.Context, pool *sandboxv1alpha1.Pool, pod *corev1.Pod, opts *Spec) (*Status, error)
+}
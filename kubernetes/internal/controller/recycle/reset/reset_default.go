@@ -0,0 +1,366 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+)
+
+// DefaultKillScript kills the user processes of the main container. The cleanup task runs in the
+// main container's mount namespace, so in a pod sharing its PID namespace every process in another
+// mount namespace belongs to the pause container or a sidecar and is left alone. Container
+// entrypoints (parent outside the namespace), the cleanup shell itself and the task-executor,
+// execd and egress agents are also kept, since the remaining steps still need them.
+const DefaultKillScript = `self=$(readlink /proc/self/ns/mnt)
+for p in /proc/[0-9]*; do
+  pid=${p#/proc/}
+  case "$pid" in 1|$$|$PPID) continue ;; esac
+  [ "$(readlink "$p/ns/mnt" 2>/dev/null)" = "$self" ] || continue
+  ppid=$(sed -n 's/^PPid:[[:space:]]*//p' "$p/status" 2>/dev/null || true)
+  case "$ppid" in ''|0) continue ;; esac
+  case "$(cat "$p/comm" 2>/dev/null)" in task-executor|execd|egress) continue ;; esac
+  kill -9 "$pid" 2>/dev/null || true
+done`
+
+const (
+	// DefaultTimeout is the default time allowed for the whole reset procedure before the pod is deleted.
+	DefaultTimeout = 2 * time.Minute
+	// DefaultTaskExecutorPort is the default port of the task-executor that runs the cleanup.
+	DefaultTaskExecutorPort int32 = 5758
+	// DefaultEgressPort is the default port of the egress sidecar policy API.
+	DefaultEgressPort int32 = 18080
+	// DefaultProbePort is the default port probed to verify the reset, execd's API port.
+	DefaultProbePort int32 = 44772
+	// DefaultProbePath is the default path probed to verify the reset.
+	DefaultProbePath = "/ping"
+	// DefaultHTTPTimeout caps each request to an agent inside the pod.
+	DefaultHTTPTimeout = 10 * time.Second
+
+	// egressTokenEnv and egressTokenHeader authenticate policy updates against the egress sidecar.
+	egressTokenEnv    = "OPENSANDBOX_EGRESS_TOKEN"
+	egressTokenHeader = "OPENSANDBOX-EGRESS-AUTH"
+)
+
+// resetConfig is the implementation-specific configuration parsed from Pool annotations.
+type resetConfig struct {
+	// KillScript is the shell script that terminates user processes. If empty, DefaultKillScript is used.
+	KillScript string `json:"killScript,omitempty"`
+	// WipeDirs lists directories whose contents are removed. The directories themselves are kept.
+	WipeDirs []string `json:"wipeDirs,omitempty"`
+	// EgressPolicy is posted to the egress sidecar to restore its policy. If empty, the step is skipped.
+	EgressPolicy json.RawMessage `json:"egressPolicy,omitempty"`
+	// TaskExecutorPort is the port of the task-executor inside the pod.
+	TaskExecutorPort int32 `json:"taskExecutorPort,omitempty"`
+	// EgressPort is the port of the egress sidecar policy API inside the pod.
+	EgressPort int32 `json:"egressPort,omitempty"`
+	// ProbePort and ProbePath locate the HTTP health probe that verifies the reset.
+	ProbePort int32  `json:"probePort,omitempty"`
+	ProbePath string `json:"probePath,omitempty"`
+	// Timeout is the time allowed for the whole procedure before the pod is deleted.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// parseConfig parses reset configuration from Pool annotations.
+// Missing or unparseable fields fall back to defaults.
+func parseConfig(ctx context.Context, annotations map[string]string) resetConfig {
+	log := logf.FromContext(ctx)
+	var cfg resetConfig
+	if raw, ok := annotations[AnnoResetConfigKey]; ok {
+		if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+			log.Error(err, "Failed to parse reset config annotation, falling back to defaults",
+				"annotation", AnnoResetConfigKey, "value", raw)
+			cfg = resetConfig{}
+		}
+	}
+	if cfg.KillScript == "" {
+		cfg.KillScript = DefaultKillScript
+	}
+	if cfg.TaskExecutorPort <= 0 {
+		cfg.TaskExecutorPort = DefaultTaskExecutorPort
+	}
+	if cfg.EgressPort <= 0 {
+		cfg.EgressPort = DefaultEgressPort
+	}
+	if cfg.ProbePort <= 0 {
+		cfg.ProbePort = DefaultProbePort
+	}
+	if cfg.ProbePath == "" {
+		cfg.ProbePath = DefaultProbePath
+	}
+	if d, err := time.ParseDuration(cfg.Timeout); err != nil || d <= 0 {
+		cfg.Timeout = DefaultTimeout.String()
+	}
+	return cfg
+}
+
+// cleanupScript builds the shell script run by the cleanup task.
+func cleanupScript(cfg resetConfig) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	b.WriteString(cfg.KillScript)
+	b.WriteString("\n")
+	for _, dir := range cfg.WipeDirs {
+		quoted := shellQuote(dir)
+		fmt.Fprintf(&b, "[ ! -d %s ] || find %s -mindepth 1 -delete\n", quoted, quoted)
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// resetInfo is stored in the pod annotation and updated by TryReset. It only contains state that
+// must survive across reconcile calls; configuration is read from the Pool on every call.
+type resetInfo struct {
+	ID        string `json:"id"`
+	StartTime string `json:"startTime"`
+	Step      Step   `json:"step"`
+	// Task is the name of the cleanup task, unique per reset so a stale task is never mistaken for it.
+	Task string `json:"task"`
+}
+
+// defaultResetHandler implements Handler by driving the task-executor, the egress sidecar and a
+// health probe inside the pod over HTTP.
+type defaultResetHandler struct {
+	client     client.Client
+	httpClient *http.Client
+}
+
+// NewDefaultResetHandler creates a reset Handler. The pod must run the task-executor in sidecar
+// mode so the cleanup task executes in the main container.
+func NewDefaultResetHandler(c client.Client) Handler {
+	return &defaultResetHandler{
+		client:     c,
+		httpClient: &http.Client{Timeout: DefaultHTTPTimeout},
+	}
+}
+
+// TryReset initiates or drives forward the reset procedure for the given pool and pod.
+// The procedure runs Cleanup, Egress and Verify in order and persists the current step on the pod,
+// so every call resumes where the previous one stopped. Transient errors leave the procedure in
+// Resetting; it fails once Timeout has elapsed since the reset started.
+func (h *defaultResetHandler) TryReset(ctx context.Context, pool *sandboxv1alpha1.Pool, pod *corev1.Pod, opts *Spec) (*Status, error) {
+	var annotations map[string]string
+	if pool != nil {
+		annotations = pool.GetAnnotations()
+	}
+	cfg := parseConfig(ctx, annotations)
+
+	info, err := h.loadInfo(pod)
+	if err != nil || info.ID != opts.ID {
+		now := time.Now()
+		info = &resetInfo{
+			ID:        opts.ID,
+			StartTime: now.Format(time.RFC3339),
+			Step:      StepCleanup,
+			Task:      fmt.Sprintf("reset-%s-%d", opts.ID, now.Unix()),
+		}
+		if err := h.persistInfo(ctx, pod, info); err != nil {
+			return nil, fmt.Errorf("failed to initialize reset record: %w", err)
+		}
+	}
+
+	if info.Step == StepDone {
+		return newStatus(info, StateSucceeded, "reset already succeeded"), nil
+	}
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+		return newStatus(info, StateFailed, fmt.Sprintf("pod is not running (phase: %s)", pod.Status.Phase)), nil
+	}
+	timeout, _ := time.ParseDuration(cfg.Timeout)
+	if start, err := time.Parse(time.RFC3339, info.StartTime); err == nil && time.Since(start) > timeout {
+		return newStatus(info, StateFailed, fmt.Sprintf("reset did not complete within %s", cfg.Timeout)), nil
+	}
+
+	for {
+		var status *Status
+		var next Step
+		switch info.Step {
+		case StepCleanup:
+			status, next = h.cleanup(ctx, pod, info, cfg), StepEgress
+		case StepEgress:
+			status, next = h.resetEgress(ctx, pod, info, cfg), StepVerify
+		case StepVerify:
+			status, next = h.verify(ctx, pod, info, cfg), StepDone
+		default:
+			return newStatus(info, StateFailed, fmt.Sprintf("unknown reset step %q", info.Step)), nil
+		}
+		if status != nil {
+			return status, nil
+		}
+		info.Step = next
+		if err := h.persistInfo(ctx, pod, info); err != nil {
+			return nil, fmt.Errorf("failed to persist reset step: %w", err)
+		}
+		if next == StepDone {
+			return newStatus(info, StateSucceeded, "pod reset and verified"), nil
+		}
+	}
+}
+
+// cleanup runs the kill and wipe script as a task-executor task. It returns nil once the task
+// has exited successfully.
+func (h *defaultResetHandler) cleanup(ctx context.Context, pod *corev1.Pod, info *resetInfo, cfg resetConfig) *Status {
+	log := logf.FromContext(ctx)
+	taskClient := api.NewClient(podURL(pod, cfg.TaskExecutorPort, ""))
+	task, err := taskClient.GetTask(ctx, info.Task)
+	if errors.Is(err, api.ErrTaskNotFound) {
+		timeout, _ := time.ParseDuration(cfg.Timeout)
+		timeoutSeconds := int64(timeout.Seconds())
+		if _, err := taskClient.CreateTask(ctx, &api.Task{
+			Name: info.Task,
+			Process: &api.Process{
+				Command:        []string{"/bin/sh", "-c", cleanupScript(cfg)},
+				TimeoutSeconds: &timeoutSeconds,
+			},
+		}); err != nil {
+			return newStatus(info, StateResetting, fmt.Sprintf("failed to start cleanup task: %v", err))
+		}
+		return newStatus(info, StateResetting, "cleanup task started")
+	}
+	if err != nil {
+		return newStatus(info, StateResetting, fmt.Sprintf("failed to get cleanup task: %v", err))
+	}
+	if task.ProcessStatus == nil || task.ProcessStatus.Terminated == nil {
+		return newStatus(info, StateResetting, "waiting for cleanup task")
+	}
+	if err := taskClient.DeleteTask(ctx, info.Task); err != nil {
+		log.Error(err, "Failed to delete reset cleanup task", "pod", pod.Name, "task", info.Task)
+	}
+	if terminated := task.ProcessStatus.Terminated; terminated.ExitCode != 0 {
+		return newStatus(info, StateFailed, fmt.Sprintf("cleanup task exited with code %d: %s", terminated.ExitCode, terminated.Message))
+	}
+	return nil
+}
+
+// resetEgress posts the configured policy to the egress sidecar. It returns nil once the policy
+// has been applied or when no policy is configured.
+func (h *defaultResetHandler) resetEgress(ctx context.Context, pod *corev1.Pod, info *resetInfo, cfg resetConfig) *Status {
+	if len(cfg.EgressPolicy) == 0 {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, podURL(pod, cfg.EgressPort, "/policy"), bytes.NewReader(cfg.EgressPolicy))
+	if err != nil {
+		return newStatus(info, StateFailed, fmt.Sprintf("invalid egress policy request: %v", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := egressToken(pod); token != "" {
+		req.Header.Set(egressTokenHeader, token)
+	}
+	if err := h.do(req); err != nil {
+		return newStatus(info, StateResetting, fmt.Sprintf("failed to reset egress policy: %v", err))
+	}
+	return nil
+}
+
+// verify probes the pod. It returns nil once the probe succeeds.
+func (h *defaultResetHandler) verify(ctx context.Context, pod *corev1.Pod, info *resetInfo, cfg resetConfig) *Status {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, podURL(pod, cfg.ProbePort, cfg.ProbePath), nil)
+	if err != nil {
+		return newStatus(info, StateFailed, fmt.Sprintf("invalid probe request: %v", err))
+	}
+	if err := h.do(req); err != nil {
+		return newStatus(info, StateResetting, fmt.Sprintf("waiting for health probe: %v", err))
+	}
+	return nil
+}
+
+func (h *defaultResetHandler) do(req *http.Request) error {
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status=%d, body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func podURL(pod *corev1.Pod, port int32, path string) string {
+	return "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))) + path
+}
+
+// egressToken returns the policy API token set literally on the egress sidecar, if any.
+func egressToken(pod *corev1.Pod) string {
+	for _, c := range pod.Spec.Containers {
+		for _, env := range c.Env {
+			if env.Name == egressTokenEnv && env.Value != "" {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+func newStatus(info *resetInfo, state State, message string) *Status {
+	return &Status{
+		StartTime: &info.StartTime,
+		Step:      info.Step,
+		State:     state,
+		Message:   message,
+	}
+}
+
+func (h *defaultResetHandler) persistInfo(ctx context.Context, pod *corev1.Pod, info *resetInfo) error {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reset info: %w", err)
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				AnnoResetRecordKey: string(raw),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	obj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name}}
+	return h.client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
+
+func (h *defaultResetHandler) loadInfo(pod *corev1.Pod) (*resetInfo, error) {
+	raw, ok := pod.Annotations[AnnoResetRecordKey]
+	if !ok {
+		return nil, fmt.Errorf("pod %s/%s has no reset info annotation", pod.Namespace, pod.Name)
+	}
+	var info resetInfo
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reset info: %w", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,338 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+)
+
+// --- parseConfig tests ---
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        resetConfig
+	}{
+		{
+			name: "NilAnnotations",
+			want: resetConfig{
+				KillScript:       DefaultKillScript,
+				TaskExecutorPort: DefaultTaskExecutorPort,
+				EgressPort:       DefaultEgressPort,
+				ProbePort:        DefaultProbePort,
+				ProbePath:        DefaultProbePath,
+				Timeout:          DefaultTimeout.String(),
+			},
+		},
+		{
+			name: "ValidConfig",
+			annotations: map[string]string{
+				AnnoResetConfigKey: `{"killScript":"pkill -u 1000","wipeDirs":["/tmp"],"egressPolicy":{"defaultAction":"deny"},"taskExecutorPort":1,"egressPort":2,"probePort":3,"probePath":"/healthz","timeout":"30s"}`,
+			},
+			want: resetConfig{
+				KillScript:       "pkill -u 1000",
+				WipeDirs:         []string{"/tmp"},
+				EgressPolicy:     json.RawMessage(`{"defaultAction":"deny"}`),
+				TaskExecutorPort: 1,
+				EgressPort:       2,
+				ProbePort:        3,
+				ProbePath:        "/healthz",
+				Timeout:          "30s",
+			},
+		},
+		{
+			name: "InvalidJSON_FallbackToDefaults",
+			annotations: map[string]string{
+				AnnoResetConfigKey: `{invalid json}`,
+			},
+			want: resetConfig{
+				KillScript:       DefaultKillScript,
+				TaskExecutorPort: DefaultTaskExecutorPort,
+				EgressPort:       DefaultEgressPort,
+				ProbePort:        DefaultProbePort,
+				ProbePath:        DefaultProbePath,
+				Timeout:          DefaultTimeout.String(),
+			},
+		},
+		{
+			name: "InvalidTimeout_FallbackToDefault",
+			annotations: map[string]string{
+				AnnoResetConfigKey: `{"timeout":"soon"}`,
+			},
+			want: resetConfig{
+				KillScript:       DefaultKillScript,
+				TaskExecutorPort: DefaultTaskExecutorPort,
+				EgressPort:       DefaultEgressPort,
+				ProbePort:        DefaultProbePort,
+				ProbePath:        DefaultProbePath,
+				Timeout:          DefaultTimeout.String(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseConfig(context.Background(), tt.annotations))
+		})
+	}
+}
+
+func TestCleanupScript(t *testing.T) {
+	script := cleanupScript(resetConfig{KillScript: "kill-all", WipeDirs: []string{"/tmp", "/home/it's"}})
+	assert.Equal(t, "set -e\nkill-all\n"+
+		"[ ! -d '/tmp' ] || find '/tmp' -mindepth 1 -delete\n"+
+		`[ ! -d '/home/it'\''s' ] || find '/home/it'\''s' -mindepth 1 -delete`+"\n", script)
+}
+
+// TestDefaultKillScript runs the default kill script against a fake /proc of a pod that shares its
+// PID namespace, with kill replaced by a function that records the pids.
+func TestDefaultKillScript(t *testing.T) {
+	proc := t.TempDir()
+	addProc := func(pid, mntNS, ppid, comm string) {
+		dir := filepath.Join(proc, pid)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "ns"), 0o755))
+		require.NoError(t, os.Symlink("mnt:["+mntNS+"]", filepath.Join(dir, "ns", "mnt")))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "status"), []byte("Name:\t"+comm+"\nPPid:\t"+ppid+"\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "self", "ns"), 0o755))
+	require.NoError(t, os.Symlink("mnt:[main]", filepath.Join(proc, "self", "ns", "mnt")))
+	addProc("1", "pause", "0", "pause")
+	addProc("7", "main", "0", "entrypoint")
+	addProc("8", "main", "7", "python")
+	addProc("9", "main", "7", "execd")
+	addProc("10", "main", "8", "sleep")
+	addProc("20", "egress", "0", "egress")
+	addProc("21", "egress", "20", "dnsproxy")
+	addProc("30", "executor", "0", "task-executor")
+	addProc("31", "executor", "30", "nsenter")
+	// A process that exits while the script runs leaves an empty directory behind.
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "40"), 0o755))
+
+	killed := filepath.Join(t.TempDir(), "killed")
+	script := strings.ReplaceAll(cleanupScript(resetConfig{KillScript: DefaultKillScript}), "/proc", proc)
+	out, err := exec.Command("/bin/sh", "-c", "kill() { echo \"$2\" >> "+shellQuote(killed)+"; }\n"+script).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	raw, err := os.ReadFile(killed)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"8", "10"}, strings.Fields(string(raw)))
+}
+
+// --- TryReset tests ---
+
+// fakeAgent serves the task-executor, egress and probe APIs of a pod on a single port.
+type fakeAgent struct {
+	mu        sync.Mutex
+	tasks     map[string]*api.Task
+	exitCode  *int32
+	policy    string
+	token     string
+	unhealthy bool
+}
+
+func (a *fakeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/tasks":
+		task := &api.Task{}
+		if err := json.NewDecoder(r.Body).Decode(task); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		a.tasks[task.Name] = task
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(task)
+	case r.URL.Path == "/policy":
+		body, _ := io.ReadAll(r.Body)
+		a.policy = string(body)
+		a.token = r.Header.Get(egressTokenHeader)
+	case r.URL.Path == "/ping":
+		if a.unhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	default:
+		name, _ := url.PathUnescape(r.URL.Path[len("/tasks/"):])
+		task, ok := a.tasks[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(a.tasks, name)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if a.exitCode != nil {
+			task.ProcessStatus = &api.ProcessStatus{Terminated: &api.Terminated{ExitCode: *a.exitCode}}
+		}
+		_ = json.NewEncoder(w).Encode(task)
+	}
+}
+
+func (a *fakeAgent) finish(code int32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.exitCode = &code
+}
+
+func newResetTest(t *testing.T, config string) (*defaultResetHandler, *fakeAgent, *sandboxv1alpha1.Pool, *corev1.Pod) {
+	agent := &fakeAgent{tasks: map[string]*api.Task{}}
+	server := httptest.NewServer(agent)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	pool := &sandboxv1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{
+		Name: "pool",
+		Annotations: map[string]string{
+			AnnoResetConfigKey: fmt.Sprintf(`{"taskExecutorPort":%d,"egressPort":%d,"probePort":%d%s}`, port, port, port, config),
+		},
+	}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "main"},
+			{Name: "egress", Env: []corev1.EnvVar{{Name: egressTokenEnv, Value: "secret"}}},
+		}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod.DeepCopy()).Build()
+	h := NewDefaultResetHandler(c).(*defaultResetHandler)
+	return h, agent, pool, pod
+}
+
+// tryReset runs TryReset and refreshes pod from the API server, like the next reconcile would.
+func tryReset(t *testing.T, h *defaultResetHandler, pool *sandboxv1alpha1.Pool, pod *corev1.Pod, id string) *Status {
+	status, err := h.TryReset(context.Background(), pool, pod, &Spec{ID: id})
+	require.NoError(t, err)
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKeyFromObject(pod), pod))
+	return status
+}
+
+func TestTryReset_Succeeded(t *testing.T) {
+	h, agent, pool, pod := newResetTest(t, `,"wipeDirs":["/workspace"],"egressPolicy":{"defaultAction":"deny"}`)
+
+	status := tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateResetting, status.State)
+	assert.Equal(t, StepCleanup, status.Step)
+	require.Len(t, agent.tasks, 1)
+	for _, task := range agent.tasks {
+		require.NotNil(t, task.Process)
+		assert.Equal(t, []string{"/bin/sh", "-c"}, task.Process.Command[:2])
+		assert.Contains(t, task.Process.Command[2], "find '/workspace' -mindepth 1 -delete")
+	}
+
+	status = tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateResetting, status.State, "cleanup task still running")
+
+	agent.finish(0)
+	status = tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateSucceeded, status.State)
+	assert.Equal(t, StepDone, status.Step)
+	assert.Empty(t, agent.tasks, "cleanup task should be deleted")
+	assert.JSONEq(t, `{"defaultAction":"deny"}`, agent.policy)
+	assert.Equal(t, "secret", agent.token)
+
+	// Re-entrant once done.
+	status = tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateSucceeded, status.State)
+}
+
+func TestTryReset_CleanupFailed(t *testing.T) {
+	h, agent, pool, pod := newResetTest(t, "")
+
+	tryReset(t, h, pool, pod, "sbx1")
+	agent.finish(1)
+	status := tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateFailed, status.State)
+	assert.Equal(t, StepCleanup, status.Step)
+}
+
+func TestTryReset_VerifyPendingUntilTimeout(t *testing.T) {
+	h, agent, pool, pod := newResetTest(t, `,"timeout":"1m"`)
+	agent.unhealthy = true
+	agent.finish(0)
+
+	tryReset(t, h, pool, pod, "sbx1")
+	status := tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateResetting, status.State)
+	assert.Equal(t, StepVerify, status.Step)
+	assert.Empty(t, agent.policy, "egress step is skipped without a policy")
+
+	info, err := h.loadInfo(pod)
+	require.NoError(t, err)
+	info.StartTime = time.Now().Add(-2 * time.Minute).Format(time.RFC3339)
+	require.NoError(t, h.persistInfo(context.Background(), pod, info))
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKeyFromObject(pod), pod))
+
+	status = tryReset(t, h, pool, pod, "sbx1")
+	assert.Equal(t, StateFailed, status.State)
+	assert.Contains(t, status.Message, "did not complete within 1m")
+}
+
+func TestTryReset_NewIDRestartsProcedure(t *testing.T) {
+	h, agent, pool, pod := newResetTest(t, "")
+	agent.finish(0)
+	tryReset(t, h, pool, pod, "sbx1")
+	status := tryReset(t, h, pool, pod, "sbx1")
+	require.Equal(t, StateSucceeded, status.State)
+
+	status = tryReset(t, h, pool, pod, "sbx2")
+	assert.Equal(t, StateResetting, status.State)
+	assert.Equal(t, StepCleanup, status.Step)
+	info, err := h.loadInfo(pod)
+	require.NoError(t, err)
+	assert.Equal(t, "sbx2", info.ID)
+}
+
+func TestTryReset_PodNotRunning(t *testing.T) {
+	h, _, pool, pod := newResetTest(t, "")
+	pod.Status.Phase = corev1.PodFailed
+	status, err := h.TryReset(context.Background(), pool, pod, &Spec{ID: "sbx1"})
+	require.NoError(t, err)
+	assert.Equal(t, StateFailed, status.State)
+}
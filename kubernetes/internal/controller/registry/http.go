@@ -0,0 +1,225 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// manifestMediaTypes are accepted when resolving a tag, so the registry
+// returns the digest of the manifest that was actually pushed.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// HTTPClient deletes images through the OCI distribution API:
+// the tag is resolved to a digest with HEAD and the manifest is deleted by digest.
+// Basic and bearer token authentication are supported.
+type HTTPClient struct {
+	// Insecure talks plain HTTP to the registry.
+	Insecure bool
+	// HTTP is the underlying client; http.DefaultClient is used when nil.
+	HTTP *http.Client
+}
+
+var _ Client = &HTTPClient{}
+
+// NewHTTPClient returns a Client for registries reachable over HTTPS, or HTTP when insecure is set.
+func NewHTTPClient(insecure bool) *HTTPClient {
+	return &HTTPClient{Insecure: insecure, HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// DeleteImage implements Client.
+func (c *HTTPClient) DeleteImage(ctx context.Context, ref Reference, auth Auth) error {
+	digest := ref.Digest
+	if digest == "" {
+		resp, err := c.do(ctx, http.MethodHead, ref, ref.Tag, auth)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return nil
+		case resp.StatusCode != http.StatusOK:
+			return fmt.Errorf("failed to resolve %s: unexpected status %s", ref, resp.Status)
+		}
+		digest = resp.Header.Get("Docker-Content-Digest")
+		if digest == "" {
+			return fmt.Errorf("failed to resolve %s: registry returned no Docker-Content-Digest", ref)
+		}
+	}
+
+	resp, err := c.do(ctx, http.MethodDelete, ref, digest, auth)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+		return nil
+	case http.StatusMethodNotAllowed:
+		return fmt.Errorf("failed to delete %s: registry does not allow deletes", ref)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to delete %s: unexpected status %s: %s", ref, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// do sends a manifest request and answers one authentication challenge if the registry asks for it.
+func (c *HTTPClient) do(ctx context.Context, method string, ref Reference, manifest string, auth Auth) (*http.Response, error) {
+	scheme := "https"
+	if c.Insecure {
+		scheme = "http"
+	}
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, ref.Registry, ref.Repository, manifest)
+
+	send := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, manifestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return c.httpClient().Do(req)
+	}
+
+	resp, err := send("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", method, manifestURL, err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authorization, err := c.authorize(ctx, challenge, ref, auth)
+	if err != nil {
+		return nil, err
+	}
+	resp, err = send(authorization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", method, manifestURL, err)
+	}
+	return resp, nil
+}
+
+// authorize turns a WWW-Authenticate challenge into an Authorization header value.
+func (c *HTTPClient) authorize(ctx context.Context, challenge string, ref Reference, auth Auth) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth.Username+":"+auth.Password)), nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, ref, auth)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported registry authentication challenge %q", challenge)
+	}
+}
+
+func (c *HTTPClient) fetchToken(ctx context.Context, params map[string]string, ref Reference, auth Auth) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge from %s has no realm", ref.Registry)
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", realm, err)
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull,delete", ref.Repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if auth.Username != "" || auth.Password != "" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch registry token: unexpected status %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry token response from %s has no token", realm)
+}
+
+func (c *HTTPClient) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// parseChallenge parses `Bearer realm="...",service="...",scope="..."`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return scheme, params
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry talks to OCI registries on behalf of the snapshot controller.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const dockerHubRegistry = "registry-1.docker.io"
+
+// Client removes snapshot images from an OCI registry.
+type Client interface {
+	// DeleteImage deletes the manifest ref points to. An image that no longer
+	// exists is not an error, so callers can retry freely.
+	DeleteImage(ctx context.Context, ref Reference, auth Auth) error
+}
+
+// Auth holds registry credentials. The zero value means anonymous access.
+type Auth struct {
+	Username string
+	Password string
+}
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Registry is the registry host, optionally with a port.
+	Registry string
+	// Repository is the repository path inside the registry.
+	Repository string
+	// Tag is the image tag; empty when only Digest is known.
+	Tag string
+	// Digest is the manifest digest; when set it takes precedence over Tag.
+	Digest string
+}
+
+// String returns the reference in registry/repository[:tag][@digest] form.
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// ParseReference splits an image reference such as registry.example.com:5000/snapshots/app:tag.
+// References without a registry host resolve to Docker Hub, like the container runtimes do.
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	rest := image
+	if i := strings.Index(rest, "@"); i >= 0 {
+		ref.Digest = rest[i+1:]
+		rest = rest[:i]
+	}
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.Contains(rest[i+1:], "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	first, remainder, found := strings.Cut(rest, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		ref.Repository = remainder
+	} else {
+		ref.Registry = dockerHubRegistry
+		ref.Repository = rest
+		if !found {
+			ref.Repository = "library/" + rest
+		}
+	}
+	if ref.Repository == "" || (ref.Tag == "" && ref.Digest == "") {
+		return Reference{}, fmt.Errorf("invalid image reference %q: repository and tag or digest are required", image)
+	}
+	return ref, nil
+}
+
+// AuthFromDockerConfig returns the credentials for registry from a
+// .dockerconfigjson payload. Missing entries yield anonymous Auth.
+func AuthFromDockerConfig(data []byte, registry string) (Auth, error) {
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Auth{}, fmt.Errorf("failed to parse docker config: %w", err)
+	}
+	for server, entry := range config.Auths {
+		if normalizeRegistryHost(server) != normalizeRegistryHost(registry) {
+			continue
+		}
+		if entry.Auth == "" {
+			return Auth{Username: entry.Username, Password: entry.Password}, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return Auth{}, fmt.Errorf("failed to decode auth for %s: %w", server, err)
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return Auth{}, fmt.Errorf("invalid auth for %s: expected username:password", server)
+		}
+		return Auth{Username: username, Password: password}, nil
+	}
+	return Auth{}, nil
+}
+
+func normalizeRegistryHost(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	server, _, _ = strings.Cut(server, "/")
+	switch server {
+	case "docker.io", "index.docker.io":
+		return dockerHubRegistry
+	}
+	return server
+}
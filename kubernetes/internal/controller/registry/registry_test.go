@@ -0,0 +1,232 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a minimal stand-in for the distribution API: it stores
+// tag -> digest per repository and serves manifest HEAD and DELETE.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	manifests map[string]map[string]string
+	// token enables bearer auth when set; requests must present it.
+	token string
+	// user and password are required by the token endpoint when token is set.
+	user, password string
+	// deleteDisabled mimics registry:2 without REGISTRY_STORAGE_DELETE_ENABLED.
+	deleteDisabled bool
+	server         *httptest.Server
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	f := &fakeRegistry{manifests: map[string]map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", f.serveToken)
+	mux.HandleFunc("/v2/", f.serveManifest)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeRegistry) host() string {
+	return strings.TrimPrefix(f.server.URL, "http://")
+}
+
+func (f *fakeRegistry) push(repo, tag, digest string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.manifests[repo] == nil {
+		f.manifests[repo] = map[string]string{}
+	}
+	f.manifests[repo][tag] = digest
+}
+
+func (f *fakeRegistry) tags(repo string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var tags []string
+	for tag := range f.manifests[repo] {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (f *fakeRegistry) serveToken(w http.ResponseWriter, r *http.Request) {
+	user, password, _ := r.BasicAuth()
+	if user != f.user || password != f.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	fmt.Fprintf(w, `{"token":%q}`, f.token)
+}
+
+func (f *fakeRegistry) serveManifest(w http.ResponseWriter, r *http.Request) {
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, f.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	repo, ref, ok := strings.Cut(path, "/manifests/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodHead:
+		digest, ok := f.manifests[repo][ref]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+	case http.MethodDelete:
+		if f.deleteDisabled {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		found := false
+		for tag, digest := range f.manifests[repo] {
+			if digest == ref {
+				delete(f.manifests[repo], tag)
+				found = true
+			}
+		}
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{"registry.example.com/snapshots/bs-main:snap-gen1", Reference{Registry: "registry.example.com", Repository: "snapshots/bs-main", Tag: "snap-gen1"}},
+		{"localhost:5000/bs-main:snap-gen2", Reference{Registry: "localhost:5000", Repository: "bs-main", Tag: "snap-gen2"}},
+		{"registry.example.com/bs@sha256:abc", Reference{Registry: "registry.example.com", Repository: "bs", Digest: "sha256:abc"}},
+		{"org/app:v1", Reference{Registry: dockerHubRegistry, Repository: "org/app", Tag: "v1"}},
+		{"app:v1", Reference{Registry: dockerHubRegistry, Repository: "library/app", Tag: "v1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := ParseReference(tt.image)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ParseReference("registry.example.com/untagged")
+	assert.Error(t, err)
+}
+
+func TestAuthFromDockerConfig(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("robot:s3cret"))
+	config := []byte(fmt.Sprintf(`{"auths":{"https://registry.example.com":{"auth":%q},"other.example.com":{"username":"u","password":"p"}}}`, encoded))
+
+	auth, err := AuthFromDockerConfig(config, "registry.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, Auth{Username: "robot", Password: "s3cret"}, auth)
+
+	auth, err = AuthFromDockerConfig(config, "other.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, Auth{Username: "u", Password: "p"}, auth)
+
+	auth, err = AuthFromDockerConfig(config, "unknown.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, Auth{}, auth)
+
+	_, err = AuthFromDockerConfig([]byte("not json"), "registry.example.com")
+	assert.Error(t, err)
+}
+
+func TestHTTPClientDeleteImage(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.push("snapshots/bs-main", "snap-gen1", "sha256:one")
+	reg.push("snapshots/bs-main", "snap-gen2", "sha256:two")
+	client := NewHTTPClient(true)
+
+	ref, err := ParseReference(reg.host() + "/snapshots/bs-main:snap-gen1")
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteImage(context.Background(), ref, Auth{}))
+	assert.Equal(t, []string{"snap-gen2"}, reg.tags("snapshots/bs-main"))
+
+	// Deleting an image that is already gone is not an error.
+	require.NoError(t, client.DeleteImage(context.Background(), ref, Auth{}))
+
+	// A known digest skips tag resolution.
+	ref.Tag, ref.Digest = "", "sha256:two"
+	require.NoError(t, client.DeleteImage(context.Background(), ref, Auth{}))
+	assert.Empty(t, reg.tags("snapshots/bs-main"))
+}
+
+func TestHTTPClientDeleteImage_BearerAuth(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.token, reg.user, reg.password = "tok", "robot", "s3cret"
+	reg.push("bs-main", "snap-gen1", "sha256:one")
+	client := NewHTTPClient(true)
+
+	ref, err := ParseReference(reg.host() + "/bs-main:snap-gen1")
+	require.NoError(t, err)
+
+	err = client.DeleteImage(context.Background(), ref, Auth{Username: "robot", Password: "wrong"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch registry token")
+
+	require.NoError(t, client.DeleteImage(context.Background(), ref, Auth{Username: "robot", Password: "s3cret"}))
+	assert.Empty(t, reg.tags("bs-main"))
+}
+
+func TestHTTPClientDeleteImage_DeleteDisabled(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.deleteDisabled = true
+	reg.push("bs-main", "snap-gen1", "sha256:one")
+
+	ref, err := ParseReference(reg.host() + "/bs-main:snap-gen1")
+	require.NoError(t, err)
+	err = NewHTTPClient(true).DeleteImage(context.Background(), ref, Auth{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registry does not allow deletes")
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:bs:pull,delete"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:bs:pull,delete",
+	}, params)
+}
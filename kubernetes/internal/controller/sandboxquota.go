@@ -0,0 +1,198 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+// sandboxQuotaRecheckInterval is how often a rejected BatchSandbox re-checks admission in case
+// the quota or sibling event that would admit it was missed.
+const sandboxQuotaRecheckInterval = 30 * time.Second
+
+// sandboxQuotaAdmission is the outcome of admitting the BatchSandboxes of a namespace against
+// its SandboxQuotas.
+type sandboxQuotaAdmission struct {
+	Quotas []sandboxv1alpha1.SandboxQuota
+	// Admitted holds the non-terminating sandboxes that fit into every quota.
+	Admitted []*sandboxv1alpha1.BatchSandbox
+	// Rejected maps the name of each sandbox that does not fit to the reason.
+	Rejected map[string]string
+}
+
+// evaluateSandboxQuotas admits sandboxes oldest first, so that a sandbox already running is never
+// displaced by a newer one. Terminating sandboxes are neither admitted nor rejected.
+func evaluateSandboxQuotas(quotas []sandboxv1alpha1.SandboxQuota, sandboxes []sandboxv1alpha1.BatchSandbox) *sandboxQuotaAdmission {
+	admission := &sandboxQuotaAdmission{Quotas: quotas, Rejected: map[string]string{}}
+	candidates := make([]*sandboxv1alpha1.BatchSandbox, 0, len(sandboxes))
+	for i := range sandboxes {
+		if sandboxes[i].DeletionTimestamp.IsZero() {
+			candidates = append(candidates, &sandboxes[i])
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b *sandboxv1alpha1.BatchSandbox) int {
+		if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var count, replicas int32
+	for _, sandbox := range candidates {
+		want := sandboxReplicas(sandbox)
+		if msg := checkSandboxQuotas(quotas, count+1, replicas+want); msg != "" {
+			admission.Rejected[sandbox.Name] = msg
+			continue
+		}
+		count++
+		replicas += want
+		admission.Admitted = append(admission.Admitted, sandbox)
+	}
+	return admission
+}
+
+// checkSandboxQuotas returns why the given totals do not fit into quotas, or "" when they fit.
+func checkSandboxQuotas(quotas []sandboxv1alpha1.SandboxQuota, sandboxes, replicas int32) string {
+	for i := range quotas {
+		quota := &quotas[i]
+		if limit := quota.Spec.MaxBatchSandboxes; limit != nil && sandboxes > *limit {
+			return fmt.Sprintf("SandboxQuota %s allows at most %d BatchSandboxes", quota.Name, *limit)
+		}
+		if limit := quota.Spec.MaxReplicas; limit != nil && replicas > *limit {
+			return fmt.Sprintf("SandboxQuota %s allows at most %d replicas", quota.Name, *limit)
+		}
+	}
+	return ""
+}
+
+// poolPodLimit returns the lowest limit quotas put on pods allocated from the pool, or nil when
+// no quota limits the pool.
+func poolPodLimit(quotas []sandboxv1alpha1.SandboxQuota, poolName string) *int32 {
+	var limit *int32
+	for i := range quotas {
+		quotaLimit := quotas[i].Spec.MaxPoolPods
+		for _, pool := range quotas[i].Spec.Pools {
+			if pool.Name == poolName {
+				quotaLimit = &pool.MaxPods
+				break
+			}
+		}
+		if quotaLimit != nil && (limit == nil || *quotaLimit < *limit) {
+			limit = quotaLimit
+		}
+	}
+	if limit == nil {
+		return nil
+	}
+	v := *limit
+	return &v
+}
+
+// snapshotQuotaLimit returns the lowest MaxSnapshots of quotas together with the quota that sets it.
+func snapshotQuotaLimit(quotas []sandboxv1alpha1.SandboxQuota) (*sandboxv1alpha1.SandboxQuota, int32) {
+	var owner *sandboxv1alpha1.SandboxQuota
+	for i := range quotas {
+		limit := quotas[i].Spec.MaxSnapshots
+		if limit != nil && (owner == nil || *limit < *owner.Spec.MaxSnapshots) {
+			owner = &quotas[i]
+		}
+	}
+	if owner == nil {
+		return nil, 0
+	}
+	return owner, *owner.Spec.MaxSnapshots
+}
+
+// checkSnapshotQuota returns why creating additional SandboxSnapshots in the namespace would
+// exceed its quotas, or "" when they fit.
+func checkSnapshotQuota(ctx context.Context, c client.Client, namespace string, additional int32) (string, error) {
+	quotaList := &sandboxv1alpha1.SandboxQuotaList{}
+	if err := c.List(ctx, quotaList, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("failed to list sandbox quotas: %w", err)
+	}
+	quota, limit := snapshotQuotaLimit(quotaList.Items)
+	if quota == nil {
+		return "", nil
+	}
+	snapshots := &sandboxv1alpha1.SandboxSnapshotList{}
+	if err := c.List(ctx, snapshots, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("failed to list sandbox snapshots: %w", err)
+	}
+	if used := int32(len(snapshots.Items)); used+additional > limit {
+		return fmt.Sprintf("SandboxQuota %s allows at most %d SandboxSnapshots, %d in use and %d needed", quota.Name, limit, used, additional), nil
+	}
+	return "", nil
+}
+
+// admitSnapshot returns why a SandboxSnapshot does not fit into the MaxSnapshots of its
+// namespace's quotas, or "" when it fits. Snapshots are admitted oldest first: only the
+// snapshots created before it count against the limit.
+func admitSnapshot(ctx context.Context, c client.Client, snapshot *sandboxv1alpha1.SandboxSnapshot) (string, error) {
+	quotaList := &sandboxv1alpha1.SandboxQuotaList{}
+	if err := c.List(ctx, quotaList, client.InNamespace(snapshot.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list sandbox quotas: %w", err)
+	}
+	quota, limit := snapshotQuotaLimit(quotaList.Items)
+	if quota == nil {
+		return "", nil
+	}
+	snapshots := &sandboxv1alpha1.SandboxSnapshotList{}
+	if err := c.List(ctx, snapshots, client.InNamespace(snapshot.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list sandbox snapshots: %w", err)
+	}
+	var older int32
+	for i := range snapshots.Items {
+		s := &snapshots.Items[i]
+		if cmp := s.CreationTimestamp.Compare(snapshot.CreationTimestamp.Time); cmp < 0 || cmp == 0 && s.Name < snapshot.Name {
+			older++
+		}
+	}
+	if older+1 > limit {
+		return fmt.Sprintf("SandboxQuota %s allows at most %d SandboxSnapshots, %d created before this one", quota.Name, limit, older), nil
+	}
+	return "", nil
+}
+
+func sandboxReplicas(sandbox *sandboxv1alpha1.BatchSandbox) int32 {
+	if sandbox.Spec.Replicas == nil {
+		return 0
+	}
+	return *sandbox.Spec.Replicas
+}
+
+// admitSandboxQuotas lists the SandboxQuotas of the namespace and evaluates its BatchSandboxes
+// against them. BatchSandboxes are only listed when the namespace has a quota.
+func admitSandboxQuotas(ctx context.Context, c client.Client, namespace string) (*sandboxQuotaAdmission, error) {
+	quotaList := &sandboxv1alpha1.SandboxQuotaList{}
+	if err := c.List(ctx, quotaList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list sandbox quotas: %w", err)
+	}
+	if len(quotaList.Items) == 0 {
+		return &sandboxQuotaAdmission{Rejected: map[string]string{}}, nil
+	}
+	sandboxList := &sandboxv1alpha1.BatchSandboxList{}
+	if err := c.List(ctx, sandboxList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list batch sandboxes: %w", err)
+	}
+	return evaluateSandboxQuotas(quotaList.Items, sandboxList.Items), nil
+}
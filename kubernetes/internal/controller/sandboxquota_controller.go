@@ -0,0 +1,166 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+// SandboxQuotaReconciler reports the usage of a namespace in its SandboxQuotas.
+// Quotas are enforced by the BatchSandbox and Pool controllers, not here.
+type SandboxQuotaReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxquotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxquotas/finalizers,verbs=update
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=batchsandboxes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxsnapshots,verbs=get;list;watch
+
+func (r *SandboxQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	log := logf.FromContext(ctx)
+	start := time.Now()
+	defer func() {
+		log.Info("Reconcile finished", "duration", time.Since(start).String(), "error", retErr)
+	}()
+
+	quota := &sandboxv1alpha1.SandboxQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !quota.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	status, err := r.computeUsage(ctx, quota)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if equality.Semantic.DeepEqual(*status, quota.Status) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest := &sandboxv1alpha1.SandboxQuota{}
+		if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+			return err
+		}
+		latest.Status = *status
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+// computeUsage evaluates the namespace against all of its quotas, so every SandboxQuota reports
+// the same admission decisions the BatchSandbox controller enforces.
+func (r *SandboxQuotaReconciler) computeUsage(ctx context.Context, quota *sandboxv1alpha1.SandboxQuota) (*sandboxv1alpha1.SandboxQuotaStatus, error) {
+	admission, err := admitSandboxQuotas(ctx, r.Client, quota.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	status := &sandboxv1alpha1.SandboxQuotaStatus{
+		ObservedGeneration: quota.Generation,
+		BatchSandboxes:     int32(len(admission.Admitted)),
+		Rejected:           int32(len(admission.Rejected)),
+	}
+	for _, sandbox := range admission.Admitted {
+		status.Replicas += sandboxReplicas(sandbox)
+	}
+
+	snapshots := &sandboxv1alpha1.SandboxSnapshotList{}
+	if err := r.List(ctx, snapshots, client.InNamespace(quota.Namespace)); err != nil {
+		return nil, err
+	}
+	status.Snapshots = int32(len(snapshots.Items))
+
+	// Terminating sandboxes keep their pods until they are recycled, so count every sandbox.
+	sandboxes := &sandboxv1alpha1.BatchSandboxList{}
+	if err := r.List(ctx, sandboxes, client.InNamespace(quota.Namespace)); err != nil {
+		return nil, err
+	}
+	allocated := map[string]int32{}
+	for _, pool := range quota.Spec.Pools {
+		allocated[pool.Name] = 0
+	}
+	for i := range sandboxes.Items {
+		sandbox := &sandboxes.Items[i]
+		if sandbox.Spec.PoolRef == "" {
+			continue
+		}
+		allocation, err := parseSandboxAllocation(sandbox)
+		if err != nil {
+			return nil, err
+		}
+		released, err := parseSandboxReleased(sandbox)
+		if err != nil {
+			return nil, err
+		}
+		active := sets.New(allocation.Pods...).Delete(released.Pods...).Len()
+		if active > 0 {
+			allocated[sandbox.Spec.PoolRef] += int32(active)
+		}
+	}
+	for name, count := range allocated {
+		status.Pools = append(status.Pools, sandboxv1alpha1.PoolQuotaUsage{Name: name, Allocated: count})
+	}
+	slices.SortFunc(status.Pools, func(a, b sandboxv1alpha1.PoolQuotaUsage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return status, nil
+}
+
+// quotasForNamespace enqueues every SandboxQuota in the namespace of obj.
+func (r *SandboxQuotaReconciler) quotasForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &sandboxv1alpha1.SandboxQuotaList{}
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list sandbox quotas", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SandboxQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sandboxv1alpha1.SandboxQuota{}).
+		Watches(&sandboxv1alpha1.BatchSandbox{}, handler.EnqueueRequestsFromMapFunc(r.quotasForNamespace)).
+		Watches(&sandboxv1alpha1.SandboxSnapshot{}, handler.EnqueueRequestsFromMapFunc(r.quotasForNamespace)).
+		Named("sandboxquota").
+		Complete(r)
+}
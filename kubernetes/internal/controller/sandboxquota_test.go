@@ -0,0 +1,304 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/algorithm"
+	controllerutils "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils/controller"
+)
+
+func quotaTestSandbox(name string, age time.Duration, replicas int32) sandboxv1alpha1.BatchSandbox {
+	return sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{Replicas: ptr.To(replicas)},
+	}
+}
+
+func TestEvaluateSandboxQuotas(t *testing.T) {
+	terminating := quotaTestSandbox("terminating", 4*time.Hour, 1)
+	terminating.DeletionTimestamp = ptr.To(metav1.Now())
+	terminating.Finalizers = []string{FinalizerTaskCleanup}
+
+	tests := []struct {
+		name         string
+		quotas       []sandboxv1alpha1.SandboxQuota
+		sandboxes    []sandboxv1alpha1.BatchSandbox
+		wantAdmitted []string
+		wantRejected []string
+	}{
+		{
+			name:         "no quotas admits everything",
+			sandboxes:    []sandboxv1alpha1.BatchSandbox{quotaTestSandbox("a", time.Hour, 5)},
+			wantAdmitted: []string{"a"},
+		},
+		{
+			name: "max sandboxes rejects newest",
+			quotas: []sandboxv1alpha1.SandboxQuota{
+				{ObjectMeta: metav1.ObjectMeta{Name: "q"}, Spec: sandboxv1alpha1.SandboxQuotaSpec{MaxBatchSandboxes: ptr.To[int32](2)}},
+			},
+			sandboxes: []sandboxv1alpha1.BatchSandbox{
+				quotaTestSandbox("new", time.Minute, 1),
+				quotaTestSandbox("old", 3*time.Hour, 1),
+				quotaTestSandbox("mid", time.Hour, 1),
+				terminating,
+			},
+			wantAdmitted: []string{"old", "mid"},
+			wantRejected: []string{"new"},
+		},
+		{
+			name: "max replicas skips oversized sandbox but admits smaller ones",
+			quotas: []sandboxv1alpha1.SandboxQuota{
+				{ObjectMeta: metav1.ObjectMeta{Name: "q"}, Spec: sandboxv1alpha1.SandboxQuotaSpec{MaxReplicas: ptr.To[int32](4)}},
+			},
+			sandboxes: []sandboxv1alpha1.BatchSandbox{
+				quotaTestSandbox("a", 3*time.Hour, 3),
+				quotaTestSandbox("b", 2*time.Hour, 2),
+				quotaTestSandbox("c", time.Hour, 1),
+			},
+			wantAdmitted: []string{"a", "c"},
+			wantRejected: []string{"b"},
+		},
+		{
+			name: "all quotas apply",
+			quotas: []sandboxv1alpha1.SandboxQuota{
+				{ObjectMeta: metav1.ObjectMeta{Name: "loose"}, Spec: sandboxv1alpha1.SandboxQuotaSpec{MaxBatchSandboxes: ptr.To[int32](10)}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "tight"}, Spec: sandboxv1alpha1.SandboxQuotaSpec{MaxBatchSandboxes: ptr.To[int32](1)}},
+			},
+			sandboxes: []sandboxv1alpha1.BatchSandbox{
+				quotaTestSandbox("a", 2*time.Hour, 1),
+				quotaTestSandbox("b", time.Hour, 1),
+			},
+			wantAdmitted: []string{"a"},
+			wantRejected: []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admission := evaluateSandboxQuotas(tt.quotas, tt.sandboxes)
+			var admitted []string
+			for _, sandbox := range admission.Admitted {
+				admitted = append(admitted, sandbox.Name)
+			}
+			assert.Equal(t, tt.wantAdmitted, admitted)
+			assert.Len(t, admission.Rejected, len(tt.wantRejected))
+			for _, name := range tt.wantRejected {
+				assert.Contains(t, admission.Rejected, name)
+			}
+		})
+	}
+}
+
+func TestPoolPodLimit(t *testing.T) {
+	quotas := []sandboxv1alpha1.SandboxQuota{
+		{Spec: sandboxv1alpha1.SandboxQuotaSpec{MaxPoolPods: ptr.To[int32](10)}},
+		{Spec: sandboxv1alpha1.SandboxQuotaSpec{Pools: []sandboxv1alpha1.PoolQuota{{Name: "gpu", MaxPods: 2}, {Name: "cpu", MaxPods: 20}}}},
+	}
+	assert.Equal(t, ptr.To[int32](2), poolPodLimit(quotas, "gpu"))
+	assert.Equal(t, ptr.To[int32](10), poolPodLimit(quotas, "cpu"))
+	assert.Equal(t, ptr.To[int32](10), poolPodLimit(quotas, "other"))
+	assert.Nil(t, poolPodLimit(quotas[1:], "other"))
+	assert.Nil(t, poolPodLimit(nil, "gpu"))
+}
+
+func TestApplySandboxQuota(t *testing.T) {
+	requests := []*algorithm.SandboxRequest{
+		{SandboxName: "low", PodSupplement: 3},
+		{SandboxName: "high", PodSupplement: 2, Priority: 10},
+		{SandboxName: "rejected", PodSupplement: 4, Priority: 100},
+	}
+	spec := &AllocSpec{
+		QuotaRejected: map[string]string{"rejected": "over quota"},
+		MaxPods:       ptr.To[int32](5),
+	}
+	applySandboxQuota(requests, spec, 2)
+	assert.Equal(t, int32(1), requests[0].PodSupplement)
+	assert.Equal(t, int32(2), requests[1].PodSupplement)
+	assert.Equal(t, int32(0), requests[2].PodSupplement)
+
+	// Already over the limit: nothing new is allocated.
+	requests[0].PodSupplement = 3
+	applySandboxQuota(requests, spec, 7)
+	assert.Equal(t, int32(0), requests[0].PodSupplement)
+	assert.Equal(t, int32(0), requests[1].PodSupplement)
+}
+
+func TestSandboxQuotaReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = sandboxv1alpha1.AddToScheme(scheme)
+
+	quota := &sandboxv1alpha1.SandboxQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default", Generation: 2},
+		Spec: sandboxv1alpha1.SandboxQuotaSpec{
+			MaxBatchSandboxes: ptr.To[int32](2),
+			Pools:             []sandboxv1alpha1.PoolQuota{{Name: "idle", MaxPods: 1}},
+		},
+	}
+	pooled := quotaTestSandbox("pooled", 2*time.Hour, 2)
+	pooled.Spec.PoolRef = "pool"
+	pooled.Annotations = map[string]string{
+		AnnoAllocStatusKey:  `{"pods":["pod0","pod1","pod2"]}`,
+		AnnoAllocReleaseKey: `{"pods":["pod0"]}`,
+	}
+	plain := quotaTestSandbox("plain", time.Hour, 3)
+	rejected := quotaTestSandbox("rejected", time.Minute, 1)
+	snapshot := &sandboxv1alpha1.SandboxSnapshot{ObjectMeta: metav1.ObjectMeta{Name: "snap", Namespace: "default"}}
+	other := quotaTestSandbox("elsewhere", time.Hour, 1)
+	other.Namespace = "other"
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(quota, &pooled, &plain, &rejected, snapshot, &other).
+		WithStatusSubresource(quota).
+		Build()
+	r := &SandboxQuotaReconciler{Client: c, Scheme: scheme}
+
+	ctx := context.Background()
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(quota)})
+	require.NoError(t, err)
+
+	latest := &sandboxv1alpha1.SandboxQuota{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(quota), latest))
+	assert.Equal(t, sandboxv1alpha1.SandboxQuotaStatus{
+		ObservedGeneration: 2,
+		BatchSandboxes:     2,
+		Replicas:           5,
+		Rejected:           1,
+		Snapshots:          1,
+		Pools: []sandboxv1alpha1.PoolQuotaUsage{
+			{Name: "idle", Allocated: 0},
+			{Name: "pool", Allocated: 2},
+		},
+	}, latest.Status)
+}
+
+func TestCheckSnapshotQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = sandboxv1alpha1.AddToScheme(scheme)
+	quota := &sandboxv1alpha1.SandboxQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"},
+		Spec:       sandboxv1alpha1.SandboxQuotaSpec{MaxSnapshots: ptr.To[int32](2)},
+	}
+	snapshot := &sandboxv1alpha1.SandboxSnapshot{ObjectMeta: metav1.ObjectMeta{Name: "snap", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(quota, snapshot).Build()
+
+	ctx := context.Background()
+	msg, err := checkSnapshotQuota(ctx, c, "default", 1)
+	require.NoError(t, err)
+	assert.Empty(t, msg)
+
+	msg, err = checkSnapshotQuota(ctx, c, "default", 2)
+	require.NoError(t, err)
+	assert.Contains(t, msg, "SandboxQuota team allows at most 2 SandboxSnapshots")
+
+	msg, err = checkSnapshotQuota(ctx, c, "other", 5)
+	require.NoError(t, err)
+	assert.Empty(t, msg)
+}
+
+func TestSandboxSnapshotHandlePending_QuotaExceeded(t *testing.T) {
+	quota := &sandboxv1alpha1.SandboxQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"},
+		Spec:       sandboxv1alpha1.SandboxQuotaSpec{MaxSnapshots: ptr.To[int32](1)},
+	}
+	older := &sandboxv1alpha1.SandboxSnapshot{ObjectMeta: metav1.ObjectMeta{
+		Name: "older", Namespace: "default", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+	}}
+	snapshot := &sandboxv1alpha1.SandboxSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snap", Namespace: "default", CreationTimestamp: metav1.Now()},
+		Spec:       sandboxv1alpha1.SandboxSnapshotSpec{SandboxName: "missing"},
+		Status:     sandboxv1alpha1.SandboxSnapshotStatus{Phase: sandboxv1alpha1.SandboxSnapshotPhasePending},
+	}
+	r := newTestSnapshotReconciler(quota, older, snapshot)
+	r.SnapshotRegistry = "registry.example.com/snapshots"
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(snapshot)
+
+	result, err := r.handlePending(ctx, snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, sandboxQuotaRecheckInterval, result.RequeueAfter)
+	require.NoError(t, r.Get(ctx, key, snapshot))
+	assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhasePending, snapshot.Status.Phase)
+	assert.True(t, hasSnapshotConditionTrue(&snapshot.Status, sandboxv1alpha1.SandboxSnapshotConditionQuotaExceeded))
+
+	// Once the older snapshot is gone the snapshot is admitted and proceeds.
+	require.NoError(t, r.Delete(ctx, older))
+	_, err = r.handlePending(ctx, snapshot)
+	require.NoError(t, err)
+	require.NoError(t, r.Get(ctx, key, snapshot))
+	assert.False(t, hasSnapshotConditionTrue(&snapshot.Status, sandboxv1alpha1.SandboxSnapshotConditionQuotaExceeded))
+	assert.Equal(t, sandboxv1alpha1.SandboxSnapshotPhaseFailed, snapshot.Status.Phase, "proceeds to the BatchSandbox lookup")
+}
+
+func TestScaleBatchSandbox_QuotaBlocksOnlyScaleUp(t *testing.T) {
+	sandbox := quotaTestSandbox("agent", time.Minute, 2)
+	bs := &sandbox
+	bs.UID = "agent-uid"
+	t.Cleanup(func() { BatchSandboxScaleExpectations.DeleteExpectations(controllerutils.GetControllerKey(bs)) })
+	bs.Spec.Template = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "img"}}}}
+	r := newTestReconciler(bs)
+	ctx := context.Background()
+
+	require.NoError(t, r.scaleBatchSandbox(ctx, bs, bs.Spec.Template, nil, false))
+	pods := &corev1.PodList{}
+	require.NoError(t, r.List(ctx, pods))
+	assert.Empty(t, pods.Items)
+
+	require.NoError(t, r.scaleBatchSandbox(ctx, bs, bs.Spec.Template, nil, true))
+	require.NoError(t, r.List(ctx, pods))
+	assert.Len(t, pods.Items, 2)
+}
+
+func TestApplyQuotaCondition(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &BatchSandboxReconciler{Recorder: recorder}
+	bs := quotaTestSandbox("agent", time.Minute, 1)
+
+	view := buildRuntimeView(&bs, nil)
+	assert.True(t, isInitialUnallocatedSandbox(&bs, view))
+
+	r.applyQuotaCondition(&bs, view.status, "SandboxQuota team allows at most 1 BatchSandboxes")
+	assert.True(t, hasConditionTrue(view.status, sandboxv1alpha1.BatchSandboxConditionQuotaExceeded))
+	assert.False(t, isInitialUnallocatedSandbox(&bs, view), "a rejected sandbox must persist its condition")
+	assert.Contains(t, <-recorder.Events, EventReasonQuotaExceeded)
+
+	// The event is only recorded when the sandbox is first rejected.
+	bs.Status = *view.status
+	r.applyQuotaCondition(&bs, view.status, "SandboxQuota team allows at most 1 BatchSandboxes")
+	assert.Empty(t, recorder.Events)
+
+	r.applyQuotaCondition(&bs, view.status, "")
+	for _, cond := range view.status.Conditions {
+		assert.NotEqual(t, sandboxv1alpha1.BatchSandboxConditionQuotaExceeded, cond.Type)
+	}
+}
@@ -23,11 +23,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/registry"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/utils"
 )
 
@@ -81,12 +86,17 @@ type SandboxSnapshotReconciler struct {
 
 	// SnapshotRegistryInsecure controls whether image-committer uses insecure registry mode.
 	SnapshotRegistryInsecure bool
+
+	// RegistryClient deletes pushed images of snapshots whose retention policy asks for it.
+	// When nil, images are always retained.
+	RegistryClient registry.Client
 }
 
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxsnapshots,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxsnapshots/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxsnapshots/finalizers,verbs=update
 // +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=batchsandboxes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sandbox.opensandbox.io,resources=sandboxquotas,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
@@ -139,23 +149,35 @@ func (r *SandboxSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return r.handlePending(ctx, snapshot)
 	case sandboxv1alpha1.SandboxSnapshotPhaseCommitting:
 		return r.handleCommitting(ctx, snapshot)
-	case sandboxv1alpha1.SandboxSnapshotPhaseSucceed:
-		// Succeed: nothing more to do, BatchSandbox Controller handles completion
-		return ctrl.Result{}, nil
-	case sandboxv1alpha1.SandboxSnapshotPhaseFailed:
-		// Failed: wait for BatchSandbox Controller to handle recovery
-		return ctrl.Result{}, nil
+	case sandboxv1alpha1.SandboxSnapshotPhaseSucceed, sandboxv1alpha1.SandboxSnapshotPhaseFailed:
+		// Terminal: BatchSandbox Controller handles completion and recovery; only retention applies here
+		return r.enforceRetention(ctx, snapshot)
 	default:
 		log.Info("Unknown phase, treating as Pending", "phase", snapshot.Status.Phase)
 		return r.handlePending(ctx, snapshot)
 	}
 }
 
+// batchSandboxDeletedPredicate passes BatchSandbox events that start or finish its deletion.
+var batchSandboxDeletedPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SandboxSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&sandboxv1alpha1.SandboxSnapshot{}).
 		Owns(&batchv1.Job{}).
+		Watches(
+			&sandboxv1alpha1.BatchSandbox{},
+			handler.EnqueueRequestsFromMapFunc(r.snapshotsForBatchSandbox),
+			builder.WithPredicates(batchSandboxDeletedPredicate),
+		).
 		Named("sandboxsnapshot").
 		Complete(r)
 }
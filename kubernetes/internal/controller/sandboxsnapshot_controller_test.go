@@ -382,6 +382,68 @@ func TestSandboxSnapshotHandlePending_UsesSourcePodContainersWhenTemplateMissing
 	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-snapshot-commit", Namespace: "default"}, job))
 }
 
+func TestSandboxSnapshotHandlePending_ReplicaIndexSelectsReplicaPod(t *testing.T) {
+	bs := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-bs",
+			Namespace:  "default",
+			Generation: 2,
+			UID:        types.UID("test-bs-uid"),
+		},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			PoolRef: "test-pool",
+		},
+	}
+	setSandboxAllocation(bs, SandboxAllocation{Pods: []string{"pool-pod-b", "pool-pod-a"}})
+	var objs []client.Object
+	for _, name := range []string{"pool-pod-a", "pool-pod-b"} {
+		objs = append(objs, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node-" + name,
+				Containers: []corev1.Container{{Name: "sandbox-container", Image: "pool-image:latest"}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		})
+	}
+	snapshot := &sandboxv1alpha1.SandboxSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-bs-pause-1",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         "sandbox.opensandbox.io/v1alpha1",
+					Kind:               "BatchSandbox",
+					Name:               "test-bs",
+					UID:                types.UID("test-bs-uid"),
+					Controller:         ptrToBool(true),
+					BlockOwnerDeletion: ptrToBool(true),
+				},
+			},
+		},
+		Spec: sandboxv1alpha1.SandboxSnapshotSpec{
+			SandboxName:  "test-bs",
+			ReplicaIndex: ptrToInt32(1),
+		},
+		Status: sandboxv1alpha1.SandboxSnapshotStatus{
+			Phase: sandboxv1alpha1.SandboxSnapshotPhasePending,
+		},
+	}
+
+	r := newTestSnapshotReconciler(append(objs, bs, snapshot)...)
+	r.SnapshotRegistry = "registry.example.com"
+
+	_, err := r.handlePending(context.Background(), snapshot)
+	require.NoError(t, err)
+
+	updated := &sandboxv1alpha1.SandboxSnapshot{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-bs-pause-1", Namespace: "default"}, updated))
+	assert.Equal(t, "pool-pod-b", updated.Status.SourcePodName)
+	assert.Equal(t, "node-pool-pod-b", updated.Status.SourceNodeName)
+	require.Len(t, updated.Status.Containers, 1)
+	assert.Equal(t, "registry.example.com/test-bs-sandbox-container:snap-gen2-r1", updated.Status.Containers[0].ImageURI)
+}
+
 func TestSandboxSnapshotHandlePending_PublicSnapshotUsesSnapshotIDTag(t *testing.T) {
 	bs := &sandboxv1alpha1.BatchSandbox{
 		ObjectMeta: metav1.ObjectMeta{
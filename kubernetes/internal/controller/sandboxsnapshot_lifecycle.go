@@ -48,6 +48,21 @@ func (r *SandboxSnapshotReconciler) handlePending(ctx context.Context, snapshot
 		return ctrl.Result{}, nil
 	}
 
+	// Pause snapshots are admitted by the BatchSandbox controller before it creates them.
+	if !hasBatchSandboxControllerOwner(snapshot) {
+		rejection, err := admitSnapshot(ctx, r.Client, snapshot)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.applySnapshotQuotaCondition(ctx, snapshot, rejection); err != nil {
+			return ctrl.Result{}, err
+		}
+		if rejection != "" {
+			log.Info("Snapshot waits for SandboxQuota admission", "reason", rejection)
+			return ctrl.Result{RequeueAfter: sandboxQuotaRecheckInterval}, nil
+		}
+	}
+
 	bs := &sandboxv1alpha1.BatchSandbox{}
 	if err := r.Get(ctx, types.NamespacedName{
 		Name:      snapshot.Spec.SandboxName,
@@ -58,7 +73,15 @@ func (r *SandboxSnapshotReconciler) handlePending(ctx context.Context, snapshot
 		return ctrl.Result{}, nil
 	}
 
-	pod, err := r.findPodForSandbox(ctx, bs, snapshot.Namespace)
+	var (
+		pod *corev1.Pod
+		err error
+	)
+	if snapshot.Spec.ReplicaIndex != nil {
+		pod, err = r.findReplicaPod(ctx, bs, *snapshot.Spec.ReplicaIndex)
+	} else {
+		pod, err = r.findPodForSandbox(ctx, bs, snapshot.Namespace)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("source pod not found: %v", err)
 		log.Error(err, msg)
@@ -174,7 +197,8 @@ func findJobCondition(conditions []batchv1.JobCondition, conditionType batchv1.J
 	return nil
 }
 
-// handleDeletion cleans up the commit job and removes the finalizer.
+// handleDeletion cleans up the commit job, deletes pushed images when retention asks for it
+// and removes the finalizer.
 func (r *SandboxSnapshotReconciler) handleDeletion(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
@@ -196,6 +220,10 @@ func (r *SandboxSnapshotReconciler) handleDeletion(ctx context.Context, snapshot
 		log.Info("Deleted unpause job", "job", unpauseJobName)
 	}
 
+	if result := r.handleImageDeletion(ctx, snapshot); result != nil {
+		return *result, nil
+	}
+
 	if controllerutil.ContainsFinalizer(snapshot, SandboxSnapshotFinalizer) {
 		if err := utils.UpdateFinalizer(r.Client, snapshot, utils.RemoveFinalizerOpType, SandboxSnapshotFinalizer); err != nil {
 			return ctrl.Result{}, err
@@ -242,6 +270,22 @@ func (r *SandboxSnapshotReconciler) findPodForSandbox(ctx context.Context, bs *s
 	return nil, fmt.Errorf("no running pod found for BatchSandbox %s", bs.Name)
 }
 
+// findReplicaPod finds the running pod serving a single replica of a BatchSandbox.
+func (r *SandboxSnapshotReconciler) findReplicaPod(ctx context.Context, bs *sandboxv1alpha1.BatchSandbox, index int32) (*corev1.Pod, error) {
+	podName, err := replicaPodName(bs, index)
+	if err != nil {
+		return nil, err
+	}
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: bs.Namespace, Name: podName}, pod); err != nil {
+		return nil, fmt.Errorf("failed to get pod %s for replica %d: %w", podName, index, err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, fmt.Errorf("pod %s for replica %d is not running: phase=%s", podName, index, pod.Status.Phase)
+	}
+	return pod, nil
+}
+
 func (r *SandboxSnapshotReconciler) snapshotImageURI(
 	snapshot *sandboxv1alpha1.SandboxSnapshot,
 	bs *sandboxv1alpha1.BatchSandbox,
@@ -258,6 +302,11 @@ func (r *SandboxSnapshotReconciler) snapshotImageURI(
 
 func snapshotImageTag(snapshot *sandboxv1alpha1.SandboxSnapshot, bs *sandboxv1alpha1.BatchSandbox) string {
 	if hasBatchSandboxControllerOwner(snapshot) {
+		// Replica 0 keeps the single-replica tag; other replicas get their own tag
+		// so that every replica of one pause generation pushes a distinct image.
+		if index := snapshot.Spec.ReplicaIndex; index != nil && *index > 0 {
+			return fmt.Sprintf("snap-gen%d-r%d", bs.Generation, *index)
+		}
 		return fmt.Sprintf("snap-gen%d", bs.Generation)
 	}
 	return publicSnapshotImageTag(snapshot.Name)
@@ -0,0 +1,287 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/registry"
+)
+
+const (
+	// snapshotImageDeleteRetryInterval is how often a failed image deletion is retried.
+	snapshotImageDeleteRetryInterval = 10 * time.Second
+
+	// snapshotImageDeleteGracePeriod bounds how long a snapshot deletion waits for the
+	// registry before the finalizer is released and the images are left behind.
+	snapshotImageDeleteGracePeriod = 10 * time.Minute
+)
+
+// enforceRetention applies spec.retention to a Succeed or Failed snapshot and prunes
+// older snapshots of the same sandbox that exceed their keepLast.
+func (r *SandboxSnapshotReconciler) enforceRetention(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot) (ctrl.Result, error) {
+	if snapshot.Status.Phase == sandboxv1alpha1.SandboxSnapshotPhaseSucceed && !hasBatchSandboxControllerOwner(snapshot) {
+		deleted, err := r.pruneSnapshots(ctx, snapshot)
+		if err != nil || deleted {
+			return ctrl.Result{}, err
+		}
+	}
+
+	retention := snapshot.Spec.Retention
+	if retention == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if retention.DeleteWithSandbox {
+		bs := &sandboxv1alpha1.BatchSandbox{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Spec.SandboxName}, bs)
+		if err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if errors.IsNotFound(err) || bs.DeletionTimestamp != nil {
+			return ctrl.Result{}, r.deleteForRetention(ctx, snapshot, "SandboxDeleted",
+				fmt.Sprintf("source BatchSandbox %s was deleted", snapshot.Spec.SandboxName))
+		}
+	}
+
+	if ttl := retention.TTLSecondsAfterCreation; ttl != nil {
+		remaining := time.Until(snapshot.CreationTimestamp.Add(time.Duration(*ttl) * time.Second))
+		if remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+		return ctrl.Result{}, r.deleteForRetention(ctx, snapshot, "TTLExpired",
+			fmt.Sprintf("snapshot expired %ds after creation", *ttl))
+	}
+	return ctrl.Result{}, nil
+}
+
+// pruneSnapshots deletes Succeed snapshots of snapshot's sandbox that fall outside their own
+// keepLast, newest first. Snapshots owned by a BatchSandbox (pause/resume) are not counted,
+// and snapshots a BatchSandbox still names in spec.snapshotRef are counted but kept.
+// Returns true when snapshot itself was deleted.
+func (r *SandboxSnapshotReconciler) pruneSnapshots(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot) (bool, error) {
+	list := &sandboxv1alpha1.SandboxSnapshotList{}
+	if err := r.List(ctx, list, client.InNamespace(snapshot.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	sandboxes := &sandboxv1alpha1.BatchSandboxList{}
+	if err := r.List(ctx, sandboxes, client.InNamespace(snapshot.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list BatchSandboxes: %w", err)
+	}
+	referenced := sets.New[string]()
+	for i := range sandboxes.Items {
+		if ref := sandboxes.Items[i].Spec.SnapshotRef; ref != "" && sandboxes.Items[i].DeletionTimestamp == nil {
+			referenced.Insert(ref)
+		}
+	}
+	var siblings []*sandboxv1alpha1.SandboxSnapshot
+	for i := range list.Items {
+		s := &list.Items[i]
+		if s.Spec.SandboxName != snapshot.Spec.SandboxName || s.DeletionTimestamp != nil ||
+			s.Status.Phase != sandboxv1alpha1.SandboxSnapshotPhaseSucceed || hasBatchSandboxControllerOwner(s) {
+			continue
+		}
+		siblings = append(siblings, s)
+	}
+	sort.SliceStable(siblings, func(i, j int) bool {
+		ti, tj := siblings[i].CreationTimestamp, siblings[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		return siblings[i].Name > siblings[j].Name
+	})
+
+	deletedSelf := false
+	for rank, s := range siblings {
+		if s.Spec.Retention == nil || s.Spec.Retention.KeepLast == nil || rank < int(*s.Spec.Retention.KeepLast) {
+			continue
+		}
+		if referenced.Has(s.Name) {
+			logf.FromContext(ctx).Info("Keeping snapshot past keepLast, still referenced by spec.snapshotRef", "snapshot", s.Name)
+			continue
+		}
+		if err := r.deleteForRetention(ctx, s, "KeepLastExceeded",
+			fmt.Sprintf("sandbox %s has %d newer snapshots, keepLast is %d", s.Spec.SandboxName, rank, *s.Spec.Retention.KeepLast)); err != nil {
+			return deletedSelf, err
+		}
+		if s.Name == snapshot.Name {
+			deletedSelf = true
+		}
+	}
+	return deletedSelf, nil
+}
+
+func (r *SandboxSnapshotReconciler) deleteForRetention(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot, reason, message string) error {
+	logf.FromContext(ctx).Info("Deleting snapshot by retention policy", "snapshot", snapshot.Name, "reason", reason)
+	if err := r.Delete(ctx, snapshot); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.Recorder.Eventf(snapshot, corev1.EventTypeNormal, reason, "Deleting snapshot: %s", message)
+	return nil
+}
+
+// shouldDeleteSnapshotImages reports whether the pushed images go away with the snapshot.
+// User snapshots opt in with a retention policy. Internal pause snapshots always do, since
+// every pause pushes a new generation and nothing else would ever remove the old one.
+func shouldDeleteSnapshotImages(snapshot *sandboxv1alpha1.SandboxSnapshot) bool {
+	retention := snapshot.Spec.Retention
+	if retention == nil {
+		return hasBatchSandboxControllerOwner(snapshot)
+	}
+	return retention.ImagePolicy != sandboxv1alpha1.SnapshotImagePolicyRetain
+}
+
+// snapshotImagesToDelete lists the snapshot's pushed images and, for an internal pause
+// snapshot, the images an earlier pause pushed that this pause replaced.
+func snapshotImagesToDelete(snapshot *sandboxv1alpha1.SandboxSnapshot) []string {
+	images := make([]string, 0, len(snapshot.Status.Containers))
+	for _, c := range snapshot.Status.Containers {
+		images = append(images, c.ImageURI)
+	}
+	if raw := snapshot.Annotations[AnnoSupersededImagesKey]; raw != "" && hasBatchSandboxControllerOwner(snapshot) {
+		var superseded []string
+		if err := json.Unmarshal([]byte(raw), &superseded); err == nil {
+			images = append(images, superseded...)
+		}
+	}
+	return sets.List(sets.New(images...))
+}
+
+// imagesInUse collects the images that live BatchSandboxes in namespace still run from or
+// will be recreated from, across the template and every shard patch.
+func (r *SandboxSnapshotReconciler) imagesInUse(ctx context.Context, namespace string) (sets.Set[string], error) {
+	list := &sandboxv1alpha1.BatchSandboxList{}
+	if err := r.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list BatchSandboxes: %w", err)
+	}
+	inUse := sets.New[string]()
+	for i := range list.Items {
+		bs := &list.Items[i]
+		if bs.DeletionTimestamp != nil {
+			continue
+		}
+		for idx := 0; idx < max(len(bs.Spec.ShardPatches), 1); idx++ {
+			images, err := replicaImages(bs, idx)
+			if err != nil {
+				return nil, err
+			}
+			inUse.Insert(images...)
+		}
+	}
+	return inUse, nil
+}
+
+// deleteSnapshotImages removes the pushed image of every container from the registry.
+// Tags are always resolved by the registry: status.containers[].imageDigest is the local
+// image ID reported by the committer, not the manifest digest. Images outside the
+// configured snapshot registry, or still used by a live BatchSandbox, are never touched.
+func (r *SandboxSnapshotReconciler) deleteSnapshotImages(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot) error {
+	log := logf.FromContext(ctx)
+	if r.RegistryClient == nil || r.SnapshotRegistry == "" {
+		return nil
+	}
+	inUse, err := r.imagesInUse(ctx, snapshot.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var dockerConfig []byte
+	if r.SnapshotPushSecret != "" {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: r.SnapshotPushSecret}, secret); err != nil {
+			return fmt.Errorf("failed to get push secret %s: %w", r.SnapshotPushSecret, err)
+		}
+		dockerConfig = secret.Data[corev1.DockerConfigJsonKey]
+	}
+
+	prefix := strings.TrimSuffix(r.SnapshotRegistry, "/") + "/"
+	for _, image := range snapshotImagesToDelete(snapshot) {
+		if !strings.HasPrefix(image, prefix) {
+			log.Info("Skipping image outside the snapshot registry", "image", image)
+			continue
+		}
+		if inUse.Has(image) {
+			log.Info("Skipping image still used by a BatchSandbox", "image", image)
+			continue
+		}
+		ref, err := registry.ParseReference(image)
+		if err != nil {
+			return err
+		}
+		auth := registry.Auth{}
+		if len(dockerConfig) > 0 {
+			if auth, err = registry.AuthFromDockerConfig(dockerConfig, ref.Registry); err != nil {
+				return err
+			}
+		}
+		if err := r.RegistryClient.DeleteImage(ctx, ref, auth); err != nil {
+			return err
+		}
+		log.Info("Deleted snapshot image", "image", image)
+	}
+	return nil
+}
+
+// handleImageDeletion runs before the finalizer is released. It returns a non-nil result
+// while the registry keeps failing, until snapshotImageDeleteGracePeriod has passed.
+func (r *SandboxSnapshotReconciler) handleImageDeletion(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot) *ctrl.Result {
+	if !shouldDeleteSnapshotImages(snapshot) {
+		return nil
+	}
+	err := r.deleteSnapshotImages(ctx, snapshot)
+	if err == nil {
+		return nil
+	}
+	if snapshot.DeletionTimestamp != nil && time.Since(snapshot.DeletionTimestamp.Time) > snapshotImageDeleteGracePeriod {
+		r.Recorder.Eventf(snapshot, corev1.EventTypeWarning, "ImageDeleteAbandoned",
+			"Giving up deleting snapshot images after %s: %v", snapshotImageDeleteGracePeriod, err)
+		return nil
+	}
+	logf.FromContext(ctx).Error(err, "Failed to delete snapshot images, will retry")
+	r.Recorder.Eventf(snapshot, corev1.EventTypeWarning, "FailedDeleteImage", "Failed to delete snapshot images: %v", err)
+	return &ctrl.Result{RequeueAfter: snapshotImageDeleteRetryInterval}
+}
+
+// snapshotsForBatchSandbox maps a BatchSandbox to the snapshots that are deleted with it.
+func (r *SandboxSnapshotReconciler) snapshotsForBatchSandbox(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &sandboxv1alpha1.SandboxSnapshotList{}
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list snapshots for BatchSandbox", "batchSandbox", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		s := &list.Items[i]
+		if s.Spec.SandboxName == obj.GetName() && s.Spec.Retention != nil && s.Spec.Retention.DeleteWithSandbox {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: s.Namespace, Name: s.Name}})
+		}
+	}
+	return requests
+}
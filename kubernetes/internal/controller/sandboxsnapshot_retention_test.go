@@ -0,0 +1,378 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/registry"
+)
+
+type fakeRegistryClient struct {
+	deleted []string
+	auths   []registry.Auth
+	err     error
+}
+
+func (f *fakeRegistryClient) DeleteImage(_ context.Context, ref registry.Reference, auth registry.Auth) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, ref.String())
+	f.auths = append(f.auths, auth)
+	return nil
+}
+
+func newRetentionSnapshot(name string, age time.Duration, retention *sandboxv1alpha1.SandboxSnapshotRetention) *sandboxv1alpha1.SandboxSnapshot {
+	return &sandboxv1alpha1.SandboxSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec: sandboxv1alpha1.SandboxSnapshotSpec{SandboxName: "builder", Retention: retention},
+		Status: sandboxv1alpha1.SandboxSnapshotStatus{
+			Phase: sandboxv1alpha1.SandboxSnapshotPhaseSucceed,
+			Containers: []sandboxv1alpha1.ContainerSnapshot{
+				{ContainerName: "main", ImageURI: "registry.example.com/snapshots/builder-main:snap-" + name},
+			},
+		},
+	}
+}
+
+func snapshotExists(t *testing.T, r *SandboxSnapshotReconciler, name string) bool {
+	err := r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &sandboxv1alpha1.SandboxSnapshot{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestEnforceRetention_TTL(t *testing.T) {
+	retention := &sandboxv1alpha1.SandboxSnapshotRetention{TTLSecondsAfterCreation: ptr.To[int32](3600)}
+	fresh := newRetentionSnapshot("fresh", time.Minute, retention)
+	expired := newRetentionSnapshot("expired", 2*time.Hour, retention)
+	r := newTestSnapshotReconciler(fresh, expired)
+
+	result, err := r.enforceRetention(context.Background(), fresh)
+	require.NoError(t, err)
+	assert.InDelta(t, 59*time.Minute, result.RequeueAfter, float64(5*time.Second))
+	assert.True(t, snapshotExists(t, r, "fresh"))
+
+	_, err = r.enforceRetention(context.Background(), expired)
+	require.NoError(t, err)
+	assert.False(t, snapshotExists(t, r, "expired"))
+}
+
+func TestEnforceRetention_KeepLast(t *testing.T) {
+	keep2 := &sandboxv1alpha1.SandboxSnapshotRetention{KeepLast: ptr.To[int32](2)}
+	newest := newRetentionSnapshot("s4", time.Minute, keep2)
+	unmanaged := newRetentionSnapshot("s3", 2*time.Minute, nil)
+	older := newRetentionSnapshot("s2", 3*time.Minute, keep2)
+	oldest := newRetentionSnapshot("s1", 4*time.Minute, keep2)
+	otherSandbox := newRetentionSnapshot("other", 5*time.Minute, keep2)
+	otherSandbox.Spec.SandboxName = "someone-else"
+	committing := newRetentionSnapshot("s5", 0, keep2)
+	committing.Status.Phase = sandboxv1alpha1.SandboxSnapshotPhaseCommitting
+	internal := newRetentionSnapshot("builder-pause", 0, nil)
+	internal.OwnerReferences = []metav1.OwnerReference{{APIVersion: "sandbox.opensandbox.io/v1alpha1", Kind: "BatchSandbox", Name: "builder", UID: "uid", Controller: ptr.To(true)}}
+	r := newTestSnapshotReconciler(newest, unmanaged, older, oldest, otherSandbox, committing, internal)
+
+	_, err := r.enforceRetention(context.Background(), newest)
+	require.NoError(t, err)
+
+	// s4 and s3 are the two newest Succeed snapshots; s3 has no keepLast and is only counted.
+	assert.True(t, snapshotExists(t, r, "s4"))
+	assert.True(t, snapshotExists(t, r, "s3"))
+	assert.False(t, snapshotExists(t, r, "s2"))
+	assert.False(t, snapshotExists(t, r, "s1"))
+	assert.True(t, snapshotExists(t, r, "other"))
+	assert.True(t, snapshotExists(t, r, "s5"))
+	assert.True(t, snapshotExists(t, r, "builder-pause"))
+}
+
+func TestEnforceRetention_KeepLastDeletesSelf(t *testing.T) {
+	keep1 := &sandboxv1alpha1.SandboxSnapshotRetention{KeepLast: ptr.To[int32](1), TTLSecondsAfterCreation: ptr.To[int32](3600)}
+	newest := newRetentionSnapshot("s2", time.Minute, nil)
+	old := newRetentionSnapshot("s1", 2*time.Minute, keep1)
+	r := newTestSnapshotReconciler(newest, old)
+
+	result, err := r.enforceRetention(context.Background(), old)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.False(t, snapshotExists(t, r, "s1"))
+}
+
+func TestEnforceRetention_DeleteWithSandbox(t *testing.T) {
+	retention := &sandboxv1alpha1.SandboxSnapshotRetention{DeleteWithSandbox: true}
+	snapshot := newRetentionSnapshot("snap", time.Minute, retention)
+	bs := &sandboxv1alpha1.BatchSandbox{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "default"}}
+	r := newTestSnapshotReconciler(snapshot, bs)
+
+	_, err := r.enforceRetention(context.Background(), snapshot)
+	require.NoError(t, err)
+	assert.True(t, snapshotExists(t, r, "snap"))
+
+	assert.Equal(t, []string{"snap"}, requestNames(r.snapshotsForBatchSandbox(context.Background(), bs)))
+
+	require.NoError(t, r.Delete(context.Background(), bs))
+	_, err = r.enforceRetention(context.Background(), snapshot)
+	require.NoError(t, err)
+	assert.False(t, snapshotExists(t, r, "snap"))
+}
+
+func TestEnforceRetention_KeepLastSkipsReferencedSnapshot(t *testing.T) {
+	keep1 := &sandboxv1alpha1.SandboxSnapshotRetention{KeepLast: ptr.To[int32](1)}
+	newest := newRetentionSnapshot("s3", time.Minute, keep1)
+	referenced := newRetentionSnapshot("s2", 2*time.Minute, keep1)
+	oldest := newRetentionSnapshot("s1", 3*time.Minute, keep1)
+	consumer := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "default"},
+		Spec:       sandboxv1alpha1.BatchSandboxSpec{SnapshotRef: "s2"},
+	}
+	r := newTestSnapshotReconciler(newest, referenced, oldest, consumer)
+
+	_, err := r.enforceRetention(context.Background(), newest)
+	require.NoError(t, err)
+	assert.True(t, snapshotExists(t, r, "s3"))
+	assert.True(t, snapshotExists(t, r, "s2"), "snapshot named by spec.snapshotRef must survive pruning")
+	assert.False(t, snapshotExists(t, r, "s1"))
+}
+
+func TestEnforceRetention_NoPolicyKeepsSnapshot(t *testing.T) {
+	snapshot := newRetentionSnapshot("snap", 24*time.Hour, nil)
+	snapshot.Status.Phase = sandboxv1alpha1.SandboxSnapshotPhaseFailed
+	r := newTestSnapshotReconciler(snapshot)
+
+	result, err := r.enforceRetention(context.Background(), snapshot)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.True(t, snapshotExists(t, r, "snap"))
+}
+
+func TestHandleDeletion_DeletesImagesWithPushSecret(t *testing.T) {
+	snapshot := newRetentionSnapshot("snap", time.Minute, &sandboxv1alpha1.SandboxSnapshotRetention{})
+	snapshot.Finalizers = []string{SandboxSnapshotFinalizer}
+	snapshot.Status.Containers = append(snapshot.Status.Containers,
+		sandboxv1alpha1.ContainerSnapshot{ContainerName: "foreign", ImageURI: "docker.io/library/python:3.11"})
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cret"))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "push-secret", Namespace: "default"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths":{"registry.example.com":{"auth":%q}}}`, auth)),
+		},
+	}
+	r := newTestSnapshotReconciler(snapshot, secret)
+	registryClient := &fakeRegistryClient{}
+	r.RegistryClient = registryClient
+	r.SnapshotRegistry = "registry.example.com/snapshots"
+	r.SnapshotPushSecret = "push-secret"
+
+	require.NoError(t, r.Delete(context.Background(), snapshot))
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "snap"}, snapshot))
+	_, err := r.handleDeletion(context.Background(), snapshot)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"registry.example.com/snapshots/builder-main:snap-snap"}, registryClient.deleted)
+	assert.Equal(t, []registry.Auth{{Username: "robot", Password: "s3cret"}}, registryClient.auths)
+	assert.False(t, snapshotExists(t, r, "snap"))
+}
+
+func TestHandleDeletion_RetainsImages(t *testing.T) {
+	for name, retention := range map[string]*sandboxv1alpha1.SandboxSnapshotRetention{
+		"no retention":  nil,
+		"retain policy": {ImagePolicy: sandboxv1alpha1.SnapshotImagePolicyRetain},
+	} {
+		t.Run(name, func(t *testing.T) {
+			snapshot := newRetentionSnapshot("snap", time.Minute, retention)
+			snapshot.Finalizers = []string{SandboxSnapshotFinalizer}
+			r := newTestSnapshotReconciler(snapshot)
+			registryClient := &fakeRegistryClient{}
+			r.RegistryClient = registryClient
+			r.SnapshotRegistry = "registry.example.com/snapshots"
+
+			require.NoError(t, r.Delete(context.Background(), snapshot))
+			require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "snap"}, snapshot))
+			_, err := r.handleDeletion(context.Background(), snapshot)
+			require.NoError(t, err)
+			assert.Empty(t, registryClient.deleted)
+			assert.False(t, snapshotExists(t, r, "snap"))
+		})
+	}
+}
+
+func TestHandleDeletion_InternalPauseSnapshotImages(t *testing.T) {
+	const (
+		gen1 = "registry.example.com/snapshots/builder-main:snap-gen1"
+		gen2 = "registry.example.com/snapshots/builder-main:snap-gen2"
+	)
+	newPauseSnapshot := func() *sandboxv1alpha1.SandboxSnapshot {
+		snapshot := newRetentionSnapshot("builder-pause", time.Minute, nil)
+		snapshot.Finalizers = []string{SandboxSnapshotFinalizer}
+		snapshot.OwnerReferences = []metav1.OwnerReference{{APIVersion: "sandbox.opensandbox.io/v1alpha1", Kind: "BatchSandbox", Name: "builder", UID: "uid", Controller: ptr.To(true)}}
+		snapshot.Annotations = map[string]string{AnnoSupersededImagesKey: `["` + gen1 + `"]`}
+		snapshot.Status.Containers = []sandboxv1alpha1.ContainerSnapshot{{ContainerName: "main", ImageURI: gen2}}
+		return snapshot
+	}
+	resumed := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "default"},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{Template: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: gen2}},
+		}}},
+	}
+
+	for name, tc := range map[string]struct {
+		objs    []client.Object
+		deleted []string
+	}{
+		"resume keeps the image the sandbox runs from": {objs: []client.Object{resumed}, deleted: []string{gen1}},
+		"sandbox gone": {deleted: []string{gen1, gen2}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			snapshot := newPauseSnapshot()
+			r := newTestSnapshotReconciler(append(tc.objs, snapshot)...)
+			registryClient := &fakeRegistryClient{}
+			r.RegistryClient = registryClient
+			r.SnapshotRegistry = "registry.example.com/snapshots"
+
+			require.NoError(t, r.Delete(context.Background(), snapshot))
+			require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: snapshot.Name}, snapshot))
+			_, err := r.handleDeletion(context.Background(), snapshot)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.deleted, registryClient.deleted)
+			assert.False(t, snapshotExists(t, r, snapshot.Name))
+		})
+	}
+}
+
+func TestReplicaImages_AppliesShardPatch(t *testing.T) {
+	bs := &sandboxv1alpha1.BatchSandbox{Spec: sandboxv1alpha1.BatchSandboxSpec{
+		Template: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "base:1"}, {Name: "sidecar", Image: "sidecar:1"}},
+		}},
+	}}
+	require.NoError(t, setShardPatchImages(bs, 1, map[string]string{"main": "snap:r1"}))
+
+	images, err := replicaImages(bs, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base:1", "sidecar:1"}, images)
+	images, err = replicaImages(bs, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snap:r1", "sidecar:1"}, images)
+}
+
+func TestPausePushedImages(t *testing.T) {
+	bs := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "builder"},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{Template: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "main", Image: "registry.example.com/snapshots/base:1"},
+				{Name: "sidecar", Image: "registry.example.com/snapshots/builder-sidecar:snap-gen1"},
+			},
+		}}},
+	}
+	require.NoError(t, setShardPatchImages(bs, 1, map[string]string{"main": "registry.example.com/snapshots/builder-main:snap-gen2-r1"}))
+
+	images, err := pausePushedImages(bs, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry.example.com/snapshots/builder-sidecar:snap-gen1"}, images)
+	images, err = pausePushedImages(bs, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"registry.example.com/snapshots/builder-main:snap-gen2-r1",
+		"registry.example.com/snapshots/builder-sidecar:snap-gen1",
+	}, images)
+}
+
+func TestPauseResume_FirstCycleKeepsBaseImage(t *testing.T) {
+	const (
+		base = "registry.example.com/snapshots/base:1"
+		gen1 = "registry.example.com/snapshots/builder-main:snap-gen1"
+	)
+	bs := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "default", UID: "uid"},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{Template: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: base}},
+		}}},
+	}
+	br := newTestReconciler(bs)
+	created, err := br.ensureInternalPauseSnapshots(context.Background(), bs, []int32{0})
+	require.NoError(t, err)
+	require.True(t, created)
+	snapshot := &sandboxv1alpha1.SandboxSnapshot{}
+	require.NoError(t, br.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: internalPauseSnapshotName("builder", 0)}, snapshot))
+	assert.NotContains(t, snapshot.Annotations, AnnoSupersededImagesKey)
+
+	// The resume switches the sandbox to gen1 and deletes the pause snapshot.
+	snapshot.Finalizers = []string{SandboxSnapshotFinalizer}
+	snapshot.Status.Containers = []sandboxv1alpha1.ContainerSnapshot{{ContainerName: "main", ImageURI: gen1}}
+	bs.Spec.Template.Spec.Containers[0].Image = gen1
+	r := newTestSnapshotReconciler(bs, snapshot)
+	registryClient := &fakeRegistryClient{}
+	r.RegistryClient = registryClient
+	r.SnapshotRegistry = "registry.example.com/snapshots"
+
+	require.NoError(t, r.Delete(context.Background(), snapshot))
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: snapshot.Name}, snapshot))
+	_, err = r.handleDeletion(context.Background(), snapshot)
+	require.NoError(t, err)
+	assert.Empty(t, registryClient.deleted)
+}
+
+func TestHandleDeletion_RegistryFailureKeepsFinalizer(t *testing.T) {
+	snapshot := newRetentionSnapshot("snap", time.Minute, &sandboxv1alpha1.SandboxSnapshotRetention{})
+	snapshot.Finalizers = []string{SandboxSnapshotFinalizer}
+	r := newTestSnapshotReconciler(snapshot)
+	r.RegistryClient = &fakeRegistryClient{err: fmt.Errorf("registry unavailable")}
+	r.SnapshotRegistry = "registry.example.com/snapshots"
+
+	require.NoError(t, r.Delete(context.Background(), snapshot))
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "snap"}, snapshot))
+	result, err := r.handleDeletion(context.Background(), snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, snapshotImageDeleteRetryInterval, result.RequeueAfter)
+	assert.True(t, snapshotExists(t, r, "snap"))
+
+	// Past the grace period the finalizer is released even though the registry still fails.
+	snapshot.DeletionTimestamp = ptr.To(metav1.NewTime(time.Now().Add(-snapshotImageDeleteGracePeriod - time.Minute)))
+	_, err = r.handleDeletion(context.Background(), snapshot)
+	require.NoError(t, err)
+	assert.False(t, snapshotExists(t, r, "snap"))
+}
+
+func requestNames(requests []reconcile.Request) []string {
+	names := make([]string, 0, len(requests))
+	for _, req := range requests {
+		names = append(names, req.Name)
+	}
+	return names
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -82,6 +83,39 @@ func isTerminalSnapshotPhase(phase sandboxv1alpha1.SandboxSnapshotPhase) bool {
 	return phase == sandboxv1alpha1.SandboxSnapshotPhaseSucceed || phase == sandboxv1alpha1.SandboxSnapshotPhaseFailed
 }
 
+// applySnapshotQuotaCondition sets QuotaExceeded while the snapshot does not fit into the
+// SandboxQuotas of its namespace, and clears it once the snapshot is admitted.
+func (r *SandboxSnapshotReconciler) applySnapshotQuotaCondition(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot, rejection string) error {
+	exceeded := hasSnapshotConditionTrue(&snapshot.Status, sandboxv1alpha1.SandboxSnapshotConditionQuotaExceeded)
+	if rejection == "" && !exceeded {
+		return nil
+	}
+	if rejection != "" && !exceeded {
+		r.Recorder.Eventf(snapshot, corev1.EventTypeWarning, EventReasonQuotaExceeded, "Not admitted: %s", rejection)
+	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest := &sandboxv1alpha1.SandboxSnapshot{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Name}, latest); err != nil {
+			return err
+		}
+		if rejection == "" {
+			setSnapshotConditionInStatus(&latest.Status, sandboxv1alpha1.SandboxSnapshotConditionQuotaExceeded, sandboxv1alpha1.ConditionFalse, "", "")
+		} else {
+			setSnapshotConditionInStatus(&latest.Status, sandboxv1alpha1.SandboxSnapshotConditionQuotaExceeded, sandboxv1alpha1.ConditionTrue, EventReasonQuotaExceeded, rejection)
+		}
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+func hasSnapshotConditionTrue(status *sandboxv1alpha1.SandboxSnapshotStatus, conditionType sandboxv1alpha1.SandboxSnapshotConditionType) bool {
+	for _, cond := range status.Conditions {
+		if cond.Type == conditionType && cond.Status == sandboxv1alpha1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *SandboxSnapshotReconciler) ackGeneration(ctx context.Context, snapshot *sandboxv1alpha1.SandboxSnapshot) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		latest := &sandboxv1alpha1.SandboxSnapshot{}
@@ -15,7 +15,7 @@
 package strategy
 
 import (
-	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/scheduler"
 )
 
 // TaskSchedulingStrategy defines the strategy interface for task scheduling.
@@ -26,5 +26,5 @@ type TaskSchedulingStrategy interface {
 	NeedTaskScheduling() bool
 
 	// GenerateTaskSpecs generates the complete list of task specifications for the BatchSandbox.
-	GenerateTaskSpecs() ([]*api.Task, error)
+	GenerateTaskSpecs() ([]*scheduler.SandboxTasks, error)
 }
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/scheduler"
 	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
 )
 
@@ -42,14 +43,19 @@ func (s *DefaultTaskSchedulingStrategy) NeedTaskScheduling() bool {
 }
 
 // GenerateTaskSpecs generates task specifications for all replicas.
-func (s *DefaultTaskSchedulingStrategy) GenerateTaskSpecs() ([]*api.Task, error) {
-	ret := make([]*api.Task, *s.Spec.Replicas)
+func (s *DefaultTaskSchedulingStrategy) GenerateTaskSpecs() ([]*scheduler.SandboxTasks, error) {
+	ret := make([]*scheduler.SandboxTasks, *s.Spec.Replicas)
 	for idx := range int(*s.Spec.Replicas) {
-		task, err := s.getTaskSpec(idx)
+		template, err := s.getTaskTemplate(idx)
 		if err != nil {
 			return ret, err
 		}
-		ret[idx] = task
+		name := s.taskName(idx)
+		if template != nil && len(template.Spec.Steps) > 0 {
+			ret[idx] = &scheduler.SandboxTasks{Name: name, Steps: s.newStepTasks(name, template)}
+		} else {
+			ret[idx] = &scheduler.SandboxTasks{Name: name, Task: s.newTask(name, template)}
+		}
 	}
 	return ret, nil
 }
@@ -57,36 +63,75 @@ func (s *DefaultTaskSchedulingStrategy) GenerateTaskSpecs() ([]*api.Task, error)
 // getTaskSpec generates a single task specification for the given index.
 // It applies ShardTaskPatches if available, otherwise uses the base TaskTemplate.
 func (s *DefaultTaskSchedulingStrategy) getTaskSpec(idx int) (*api.Task, error) {
+	template, err := s.getTaskTemplate(idx)
+	if err != nil {
+		return nil, err
+	}
+	return s.newTask(s.taskName(idx), template), nil
+}
+
+func (s *DefaultTaskSchedulingStrategy) taskName(idx int) string {
+	return fmt.Sprintf("%s-%d", s.Name, idx)
+}
+
+// getTaskTemplate returns the task template of the given index, with its ShardTaskPatch applied.
+func (s *DefaultTaskSchedulingStrategy) getTaskTemplate(idx int) (*sandboxv1alpha1.TaskTemplateSpec, error) {
+	if len(s.Spec.ShardTaskPatches) == 0 || idx >= len(s.Spec.ShardTaskPatches) {
+		return s.Spec.TaskTemplate, nil
+	}
+	taskTemplate := s.Spec.TaskTemplate.DeepCopy()
+	cloneBytes, _ := json.Marshal(taskTemplate)
+	patch := s.Spec.ShardTaskPatches[idx]
+	modified, err := strategicpatch.StrategicMergePatch(cloneBytes, patch.Raw, &sandboxv1alpha1.TaskTemplateSpec{})
+	if err != nil {
+		return nil, fmt.Errorf("batchsandbox: failed to merge patch raw %s, idx %d, err %w", patch.Raw, idx, err)
+	}
+	newTaskTemplate := &sandboxv1alpha1.TaskTemplateSpec{}
+	if err = json.Unmarshal(modified, newTaskTemplate); err != nil {
+		return nil, fmt.Errorf("batchsandbox: failed to unmarshal %s to TaskTemplateSpec, idx %d, err %w", modified, idx, err)
+	}
+	return newTaskTemplate, nil
+}
+
+func (s *DefaultTaskSchedulingStrategy) newTask(name string, template *sandboxv1alpha1.TaskTemplateSpec) *api.Task {
 	task := &api.Task{
-		Name: fmt.Sprintf("%s-%d", s.Name, idx),
+		Name: name,
 	}
-	if len(s.Spec.ShardTaskPatches) > 0 && idx < len(s.Spec.ShardTaskPatches) {
-		taskTemplate := s.Spec.TaskTemplate.DeepCopy()
-		cloneBytes, _ := json.Marshal(taskTemplate)
-		patch := s.Spec.ShardTaskPatches[idx]
-		modified, err := strategicpatch.StrategicMergePatch(cloneBytes, patch.Raw, &sandboxv1alpha1.TaskTemplateSpec{})
-		if err != nil {
-			return nil, fmt.Errorf("batchsandbox: failed to merge patch raw %s, idx %d, err %w", patch.Raw, idx, err)
-		}
-		newTaskTemplate := &sandboxv1alpha1.TaskTemplateSpec{}
-		if err = json.Unmarshal(modified, newTaskTemplate); err != nil {
-			return nil, fmt.Errorf("batchsandbox: failed to unmarshal %s to TaskTemplateSpec, idx %d, err %w", modified, idx, err)
+	if template != nil && template.Spec.Process != nil {
+		task.Process = s.newProcess(template.Spec.Process)
+		task.RestartPolicy = api.RestartPolicy(template.Spec.RestartPolicy)
+		task.MaxRetries = template.Spec.MaxRetries
+	}
+	return task
+}
+
+// newStepTasks generates a task per step. Step tasks are named after the task and the step, and
+// depend on the step tasks of the same task.
+func (s *DefaultTaskSchedulingStrategy) newStepTasks(name string, template *sandboxv1alpha1.TaskTemplateSpec) []*api.Task {
+	tasks := make([]*api.Task, len(template.Spec.Steps))
+	for i, step := range template.Spec.Steps {
+		task := &api.Task{
+			Name:          name + "-" + step.Name,
+			RestartPolicy: api.RestartPolicy(template.Spec.RestartPolicy),
+			MaxRetries:    template.Spec.MaxRetries,
 		}
-		task.Process = &api.Process{
-			Command:        newTaskTemplate.Spec.Process.Command,
-			Args:           newTaskTemplate.Spec.Process.Args,
-			Env:            newTaskTemplate.Spec.Process.Env,
-			WorkingDir:     newTaskTemplate.Spec.Process.WorkingDir,
-			TimeoutSeconds: s.Spec.TaskTemplate.Spec.TimeoutSeconds,
+		if step.Process != nil {
+			task.Process = s.newProcess(step.Process)
 		}
-	} else if s.Spec.TaskTemplate != nil && s.Spec.TaskTemplate.Spec.Process != nil {
-		task.Process = &api.Process{
-			Command:        s.Spec.TaskTemplate.Spec.Process.Command,
-			Args:           s.Spec.TaskTemplate.Spec.Process.Args,
-			Env:            s.Spec.TaskTemplate.Spec.Process.Env,
-			WorkingDir:     s.Spec.TaskTemplate.Spec.Process.WorkingDir,
-			TimeoutSeconds: s.Spec.TaskTemplate.Spec.TimeoutSeconds,
+		for _, dep := range step.DependsOn {
+			task.DependsOn = append(task.DependsOn, name+"-"+dep)
 		}
+		tasks[i] = task
+	}
+	return tasks
+}
+
+func (s *DefaultTaskSchedulingStrategy) newProcess(process *sandboxv1alpha1.ProcessTask) *api.Process {
+	return &api.Process{
+		Command:        process.Command,
+		Args:           process.Args,
+		Env:            process.Env,
+		WorkingDir:     process.WorkingDir,
+		TimeoutSeconds: s.Spec.TaskTemplate.Spec.TimeoutSeconds,
 	}
-	return task, nil
 }
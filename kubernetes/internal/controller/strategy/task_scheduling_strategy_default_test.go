@@ -20,6 +20,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
 	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
@@ -132,6 +133,43 @@ func TestDefaultTaskSchedulingStrategy_getTaskSpec(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "task spec with restart policy patched per shard",
+			args: args{
+				batchSbx: &sandboxv1alpha1.BatchSandbox{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-bs",
+						Namespace: "default",
+					},
+					Spec: sandboxv1alpha1.BatchSandboxSpec{
+						TaskTemplate: &sandboxv1alpha1.TaskTemplateSpec{
+							Spec: sandboxv1alpha1.TaskSpec{
+								Process: &sandboxv1alpha1.ProcessTask{
+									Command: []string{"echo", "hello"},
+								},
+								RestartPolicy: sandboxv1alpha1.TaskRestartPolicyOnFailure,
+								MaxRetries:    ptr.To[int32](3),
+							},
+						},
+						ShardTaskPatches: []runtime.RawExtension{
+							{
+								Raw: []byte(`{"spec":{"maxRetries":5}}`),
+							},
+						},
+					},
+				},
+				idx: 0,
+			},
+			want: &api.Task{
+				Name: "test-bs-0",
+				Process: &api.Process{
+					Command: []string{"echo", "hello"},
+				},
+				RestartPolicy: api.RestartPolicyOnFailure,
+				MaxRetries:    ptr.To[int32](5),
+			},
+			wantErr: false,
+		},
 		{
 			name: "task spec with invalid patch",
 			args: args{
@@ -213,3 +251,54 @@ func TestDefaultTaskSchedulingStrategy_getTaskSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultTaskSchedulingStrategy_GenerateTaskSpecs_Steps(t *testing.T) {
+	batchSbx := &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "sbx"},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Replicas: ptr.To[int32](2),
+			TaskTemplate: &sandboxv1alpha1.TaskTemplateSpec{Spec: sandboxv1alpha1.TaskSpec{
+				TimeoutSeconds: ptr.To[int64](60),
+				RestartPolicy:  sandboxv1alpha1.TaskRestartPolicyOnFailure,
+				Steps: []sandboxv1alpha1.TaskStep{
+					{Name: "build", Process: &sandboxv1alpha1.ProcessTask{Command: []string{"make"}}},
+					{Name: "test", DependsOn: []string{"build"}, Process: &sandboxv1alpha1.ProcessTask{Command: []string{"make", "test"}}},
+				},
+			}},
+			// The patch merges into the test step by name.
+			ShardTaskPatches: []runtime.RawExtension{
+				{Raw: []byte(`{}`)},
+				{Raw: []byte(`{"spec":{"steps":[{"name":"test","process":{"command":["make","test-1"]}}]}}`)},
+			},
+		},
+	}
+	specs, err := NewDefaultTaskSchedulingStrategy(batchSbx).GenerateTaskSpecs()
+	if err != nil {
+		t.Fatalf("GenerateTaskSpecs() error = %v", err)
+	}
+	if len(specs) != 2 {
+		t.Fatalf("GenerateTaskSpecs() returned %d specs, want 2", len(specs))
+	}
+	test := func(command ...string) *api.Task {
+		return &api.Task{
+			Name:          "sbx-1-test",
+			Process:       &api.Process{Command: command, TimeoutSeconds: ptr.To[int64](60)},
+			RestartPolicy: api.RestartPolicyOnFailure,
+			DependsOn:     []string{"sbx-1-build"},
+		}
+	}
+	want := []*api.Task{
+		{
+			Name:          "sbx-1-build",
+			Process:       &api.Process{Command: []string{"make"}, TimeoutSeconds: ptr.To[int64](60)},
+			RestartPolicy: api.RestartPolicyOnFailure,
+		},
+		test("make", "test-1"),
+	}
+	if specs[1].Name != "sbx-1" || specs[1].Task != nil || !reflect.DeepEqual(specs[1].Steps, want) {
+		t.Errorf("GenerateTaskSpecs()[1] = %+v, want steps %+v", specs[1], want)
+	}
+	if got := specs[0].Steps[1].Process.Command; !reflect.DeepEqual(got, []string{"make", "test"}) {
+		t.Errorf("GenerateTaskSpecs()[0] test command = %v", got)
+	}
+}
@@ -17,6 +17,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -41,6 +42,10 @@ var (
 type taskSpec struct {
 	Process         *api.Process
 	PodTemplateSpec *corev1.PodTemplateSpec
+	RestartPolicy   api.RestartPolicy
+	MaxRetries      *int32
+	// Steps replace the fields above for a task that runs steps.
+	Steps []*api.Task
 }
 
 type taskNode struct {
@@ -48,9 +53,10 @@ type taskNode struct {
 	Spec taskSpec
 
 	// status
-	Status  *api.Task
-	IP      string
-	PodName string
+	Status       *api.Task
+	StepStatuses []*api.Task
+	IP           string
+	PodName      string
 
 	// collect from endpoints
 	tState              TaskState
@@ -65,6 +71,110 @@ func (t *taskNode) GetPodName() string {
 	return t.PodName
 }
 
+func (t *taskNode) GetTerminationMessage() string {
+	if len(t.Spec.Steps) == 0 {
+		return terminationMessage(t.Status)
+	}
+	// The first failed step in spec order is the one that explains the failure.
+	for _, step := range t.Spec.Steps {
+		status := t.stepStatus(step.Name)
+		if status == nil || parseTaskState(status) != FailedTaskState {
+			continue
+		}
+		if msg := terminationMessage(status); msg != "" {
+			return t.stepName(step.Name) + ": " + msg
+		}
+	}
+	return ""
+}
+
+func terminationMessage(task *api.Task) string {
+	if task == nil {
+		return ""
+	}
+	if status := task.ProcessStatus; status != nil && status.Terminated != nil {
+		return status.Terminated.Message
+	}
+	if task.PodStatus != nil {
+		for _, cs := range task.PodStatus.ContainerStatuses {
+			if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 && cs.State.Terminated.Message != "" {
+				return cs.Name + ": " + cs.State.Terminated.Message
+			}
+		}
+	}
+	return ""
+}
+
+func (t *taskNode) GetStepStates() []StepState {
+	if len(t.Spec.Steps) == 0 {
+		return nil
+	}
+	ret := make([]StepState, len(t.Spec.Steps))
+	for i, step := range t.Spec.Steps {
+		ret[i] = StepState{Name: t.stepName(step.Name), State: UnknownTaskState}
+		if status := t.stepStatus(step.Name); status != nil {
+			ret[i].State = parseTaskState(status)
+		}
+	}
+	return ret
+}
+
+func (t *taskNode) stepStatus(name string) *api.Task {
+	for _, status := range t.StepStatuses {
+		if status.Name == name {
+			return status
+		}
+	}
+	return nil
+}
+
+// stepName returns the name of a step in the task template from the name of its step task.
+func (t *taskNode) stepName(taskName string) string {
+	return strings.TrimPrefix(taskName, t.Name+"-")
+}
+
+// setStatus records the tasks the task executor reported for the node and derives the node's
+// task state. The state of a node that runs steps is aggregated from its steps.
+func (t *taskNode) setStatus(tasks []*api.Task, log logr.Logger) {
+	if len(t.Spec.Steps) == 0 {
+		t.Status = nil
+		if len(tasks) > 0 {
+			t.Status = tasks[0]
+			t.transTaskState(parseTaskState(t.Status), log)
+		}
+		return
+	}
+	t.StepStatuses = tasks
+	if len(tasks) > 0 {
+		t.transTaskState(parseStepsState(t.GetStepStates()), log)
+	}
+}
+
+// parseStepsState aggregates the state of steps: succeeded once all steps succeeded, failed once
+// all steps terminated with any failed, running once any step started, and unknown otherwise.
+func parseStepsState(steps []StepState) TaskState {
+	var succeeded, failed, running int
+	for _, step := range steps {
+		switch step.State {
+		case SucceedTaskState:
+			succeeded++
+		case FailedTaskState:
+			failed++
+		case RunningTaskState:
+			running++
+		}
+	}
+	switch {
+	case succeeded == len(steps):
+		return SucceedTaskState
+	case succeeded+failed == len(steps):
+		return FailedTaskState
+	case succeeded+failed+running > 0:
+		return RunningTaskState
+	}
+	return UnknownTaskState
+}
+
 func (t *taskNode) GetState() TaskState {
 	return t.tState
 }
@@ -78,7 +188,7 @@ func (t *taskNode) isTaskCompleted() bool {
 }
 
 func (t *taskNode) isTaskDeleted() bool {
-	return t.Status == nil
+	return t.Status == nil && len(t.StepStatuses) == 0
 }
 
 func (t *taskNode) transSchState(to string, log logr.Logger) {
@@ -137,7 +247,8 @@ const (
 
 type taskClient interface {
 	Set(ctx context.Context, task *api.Task) (*api.Task, error)
-	Get(ctx context.Context) (*api.Task, error)
+	SetTasks(ctx context.Context, tasks []api.Task) ([]api.Task, error)
+	List(ctx context.Context) ([]api.Task, error)
 }
 
 const (
@@ -171,7 +282,7 @@ type defaultTaskScheduler struct {
 	logger                    logr.Logger
 }
 
-func newTaskScheduler(name string, tasks []*api.Task, pods []*corev1.Pod, resPolicyWhenTaskComplete sandboxv1alpha1.TaskResourcePolicy, logger logr.Logger) (*defaultTaskScheduler, error) {
+func newTaskScheduler(name string, tasks []*SandboxTasks, pods []*corev1.Pod, resPolicyWhenTaskComplete sandboxv1alpha1.TaskResourcePolicy, logger logr.Logger) (*defaultTaskScheduler, error) {
 	sch := &defaultTaskScheduler{
 		allPods:                   pods,
 		maxConcurrency:            defaultSchConcurrency,
@@ -218,7 +329,7 @@ func (sch *defaultTaskScheduler) UpdatePods(pods []*corev1.Pod) {
 // AddTasks registers task specs that are not yet tracked by the scheduler.
 // Tasks whose names are already tracked are silently skipped, making this
 // safe to call with the full task list during a scale-out reconciliation.
-func (sch *defaultTaskScheduler) AddTasks(tasks []*api.Task) error {
+func (sch *defaultTaskScheduler) AddTasks(tasks []*SandboxTasks) error {
 	newNodes, err := initTaskNodes(tasks)
 	if err != nil {
 		return err
@@ -252,20 +363,29 @@ func (sch *defaultTaskScheduler) StopTask() []Task {
 	return deletedTask
 }
 
-func initTaskNodes(tasks []*api.Task) ([]*taskNode, error) {
+func initTaskNodes(tasks []*SandboxTasks) ([]*taskNode, error) {
 	size := len(tasks)
 	taskNodes := make([]*taskNode, size)
 	for idx := 0; idx < size; idx++ {
-		task := tasks[idx]
+		sandboxTasks := tasks[idx]
 		tNode := &taskNode{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: task.Name,
-			},
-			Spec: taskSpec{
-				Process:         task.Process,
-				PodTemplateSpec: task.PodTemplateSpec,
+				Name: sandboxTasks.Name,
 			},
 		}
+		switch {
+		case sandboxTasks.Task != nil:
+			tNode.Spec = taskSpec{
+				Process:         sandboxTasks.Task.Process,
+				PodTemplateSpec: sandboxTasks.Task.PodTemplateSpec,
+				RestartPolicy:   sandboxTasks.Task.RestartPolicy,
+				MaxRetries:      sandboxTasks.Task.MaxRetries,
+			}
+		case len(sandboxTasks.Steps) > 0:
+			tNode.Spec = taskSpec{Steps: sandboxTasks.Steps}
+		default:
+			return nil, fmt.Errorf("no task for %s", sandboxTasks.Name)
+		}
 		taskNodes[idx] = tNode
 	}
 	return taskNodes, nil
@@ -286,11 +406,7 @@ func (sch *defaultTaskScheduler) collectTaskStatus(taskNodes []*taskNode) {
 	}
 	tasks := sch.taskStatusCollector.Collect(context.Background(), ips)
 	for _, tNode := range taskNodes {
-		task, ok := tasks[tNode.IP]
-		tNode.Status = task
-		if ok && task != nil {
-			tNode.transTaskState(parseTaskState(task), sch.logger)
-		}
+		tNode.setStatus(tasks[tNode.IP], sch.logger)
 	}
 }
 
@@ -413,11 +529,17 @@ func scheduleSingleTaskNode(tNode *taskNode, taskClientCreator func(endpoint str
 			tNode.transSchState(stateReleasing, log)
 		} else {
 			// no need to setTask if task is completed to avoid unnecessary network overhead
-			if !tNode.isTaskCompleted() {
+			if !tNode.isTaskCompleted() && len(tNode.Spec.Steps) > 0 {
+				if err := setTasks(taskClientCreator(tNode.IP), tNode.Spec.Steps, log); err != nil {
+					log.Error(err, "Failed to set step tasks", "taskName", tNode.Name, "endpoint", tNode.IP)
+				}
+			} else if !tNode.isTaskCompleted() {
 				task := &api.Task{
 					Name:            tNode.Name,
 					Process:         tNode.Spec.Process,
 					PodTemplateSpec: tNode.Spec.PodTemplateSpec,
+					RestartPolicy:   tNode.Spec.RestartPolicy,
+					MaxRetries:      tNode.Spec.MaxRetries,
 				}
 				_, err := setTask(taskClientCreator(tNode.IP), task, log)
 				if err != nil {
@@ -449,3 +571,18 @@ func setTask(client taskClient, task *api.Task, log logr.Logger) (*api.Task, err
 	}
 	return client.Set(ctx, task)
 }
+
+func setTasks(client taskClient, tasks []*api.Task, log logr.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	list := make([]api.Task, len(tasks))
+	for i := range tasks {
+		list[i] = *tasks[i]
+	}
+	verboseLog := log.V(3)
+	if verboseLog.Enabled() {
+		verboseLog.Info("client set tasks", "tasks", utils.DumpJSON(list))
+	}
+	_, err := client.SetTasks(ctx, list)
+	return err
+}
@@ -37,19 +37,19 @@ func (m *MocktaskClient) EXPECT() *MocktaskClientMockRecorder {
 	return m.recorder
 }
 
-// Get mocks base method.
-func (m *MocktaskClient) Get(ctx context.Context) (*api.Task, error) {
+// List mocks base method.
+func (m *MocktaskClient) List(ctx context.Context) ([]api.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx)
-	ret0, _ := ret[0].(*api.Task)
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]api.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MocktaskClientMockRecorder) Get(ctx interface{}) *gomock.Call {
+// List indicates an expected call of List.
+func (mr *MocktaskClientMockRecorder) List(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MocktaskClient)(nil).Get), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MocktaskClient)(nil).List), ctx)
 }
 
 // Set mocks base method.
@@ -66,3 +66,18 @@ func (mr *MocktaskClientMockRecorder) Set(ctx, task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MocktaskClient)(nil).Set), ctx, task)
 }
+
+// SetTasks mocks base method.
+func (m *MocktaskClient) SetTasks(ctx context.Context, tasks []api.Task) ([]api.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTasks", ctx, tasks)
+	ret0, _ := ret[0].([]api.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTasks indicates an expected call of SetTasks.
+func (mr *MocktaskClientMockRecorder) SetTasks(ctx, tasks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTasks", reflect.TypeOf((*MocktaskClient)(nil).SetTasks), ctx, tasks)
+}
@@ -631,7 +631,7 @@ func Test_collectTaskStatus(t *testing.T) {
 			// Create mock task status collector
 			mockCollector := NewMocktaskStatusCollector(ctl)
 			if len(tt.expectedCollectIPs) > 0 {
-				mockCollector.EXPECT().Collect(gomock.Any(), tt.expectedCollectIPs).Return(tt.mockReturnTasks).Times(1)
+				mockCollector.EXPECT().Collect(gomock.Any(), tt.expectedCollectIPs).Return(taskLists(tt.mockReturnTasks)).Times(1)
 			}
 
 			// Create scheduler with mock collector
@@ -1195,6 +1195,56 @@ func Test_parseTaskState(t *testing.T) {
 	}
 }
 
+func Test_taskNode_GetTerminationMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *api.Task
+		expected string
+	}{
+		{
+			name:     "no status",
+			expected: "",
+		},
+		{
+			name: "failed process",
+			status: &api.Task{
+				ProcessStatus: &api.ProcessStatus{
+					Terminated: &api.Terminated{ExitCode: 1, Message: "no such file"},
+				},
+			},
+			expected: "no such file",
+		},
+		{
+			name: "running process",
+			status: &api.Task{
+				ProcessStatus: &api.ProcessStatus{Running: &api.Running{}},
+			},
+			expected: "",
+		},
+		{
+			name: "failed container",
+			status: &api.Task{
+				PodStatus: &corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "sidecar", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: "done"}}},
+						{Name: "main", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Message: "bad config"}}},
+					},
+				},
+			},
+			expected: "main: bad config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tNode := &taskNode{Status: tt.status}
+			if got := tNode.GetTerminationMessage(); got != tt.expected {
+				t.Errorf("GetTerminationMessage() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func Test_initTaskNodes(t *testing.T) {
 	type args struct {
 		tasks []*api.Task
@@ -1279,7 +1329,7 @@ func Test_initTaskNodes(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := initTaskNodes(tt.args.tasks)
+			got, err := initTaskNodes(singleTasks(tt.args.tasks))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("initTaskNodes() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1336,7 +1386,7 @@ func Test_addTasks(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			initialNodes, err := initTaskNodes(tt.initial)
+			initialNodes, err := initTaskNodes(singleTasks(tt.initial))
 			if err != nil {
 				t.Fatalf("initTaskNodes() error = %v", err)
 			}
@@ -1346,7 +1396,7 @@ func Test_addTasks(t *testing.T) {
 				logger:              testLogger,
 			}
 
-			if err := sch.AddTasks(tt.addTasks); err != nil {
+			if err := sch.AddTasks(singleTasks(tt.addTasks)); err != nil {
 				t.Fatalf("AddTasks() unexpected error = %v", err)
 			}
 
@@ -1368,3 +1418,104 @@ func Test_addTasks(t *testing.T) {
 		})
 	}
 }
+
+// singleTasks wraps tasks that do not run steps.
+func singleTasks(tasks []*api.Task) []*SandboxTasks {
+	ret := make([]*SandboxTasks, len(tasks))
+	for i, task := range tasks {
+		ret[i] = &SandboxTasks{Name: task.Name, Task: task}
+	}
+	return ret
+}
+
+// taskLists converts a task per endpoint into the task lists the collector returns.
+func taskLists(tasks map[string]*api.Task) map[string][]*api.Task {
+	ret := make(map[string][]*api.Task, len(tasks))
+	for ip, task := range tasks {
+		if task != nil {
+			ret[ip] = []*api.Task{task}
+		}
+	}
+	return ret
+}
+
+func Test_parseStepsState(t *testing.T) {
+	steps := func(states ...TaskState) []StepState {
+		ret := make([]StepState, len(states))
+		for i, state := range states {
+			ret[i] = StepState{State: state}
+		}
+		return ret
+	}
+	tests := []struct {
+		name  string
+		steps []StepState
+		want  TaskState
+	}{
+		{name: "all succeeded", steps: steps(SucceedTaskState, SucceedTaskState), want: SucceedTaskState},
+		{name: "all terminated, one failed", steps: steps(SucceedTaskState, FailedTaskState), want: FailedTaskState},
+		{name: "one step running", steps: steps(SucceedTaskState, RunningTaskState), want: RunningTaskState},
+		{name: "failed with a branch still waiting", steps: steps(FailedTaskState, UnknownTaskState), want: RunningTaskState},
+		{name: "nothing started", steps: steps(UnknownTaskState, UnknownTaskState), want: UnknownTaskState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStepsState(tt.steps); got != tt.want {
+				t.Errorf("parseStepsState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskNodeSteps(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	steps := []*api.Task{
+		{Name: "sbx-0-build", Process: &api.Process{Command: []string{"make"}}},
+		{Name: "sbx-0-test", Process: &api.Process{Command: []string{"make", "test"}}, DependsOn: []string{"sbx-0-build"}},
+	}
+	nodes, err := initTaskNodes([]*SandboxTasks{{Name: "sbx-0", Steps: steps}})
+	if err != nil {
+		t.Fatalf("initTaskNodes() error = %v", err)
+	}
+	tNode := nodes[0]
+	tNode.IP = "1.2.3.4"
+
+	// Steps are set on the task executor as one list.
+	scheduleSingleTaskNode(tNode, func(endpoint string) taskClient {
+		mock := NewMocktaskClient(ctl)
+		mock.EXPECT().SetTasks(gomock.Any(), []api.Task{*steps[0], *steps[1]}).Return(nil, nil).Times(1)
+		return mock
+	}, sandboxv1alpha1.TaskResourcePolicyRetain, testLogger)
+
+	terminated := func(name string, exitCode int32, message string) *api.Task {
+		return &api.Task{Name: name, ProcessStatus: &api.ProcessStatus{
+			Terminated: &api.Terminated{ExitCode: exitCode, Message: message},
+		}}
+	}
+	tNode.setStatus([]*api.Task{
+		terminated("sbx-0-build", 0, ""),
+		{Name: "sbx-0-test", ProcessStatus: &api.ProcessStatus{Running: &api.Running{}}},
+	}, testLogger)
+	if got := tNode.GetState(); got != RunningTaskState {
+		t.Errorf("GetState() = %v, want %v", got, RunningTaskState)
+	}
+	want := []StepState{{Name: "build", State: SucceedTaskState}, {Name: "test", State: RunningTaskState}}
+	if got := tNode.GetStepStates(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStepStates() = %v, want %v", got, want)
+	}
+
+	tNode.setStatus([]*api.Task{
+		terminated("sbx-0-build", 0, ""),
+		terminated("sbx-0-test", 2, "assertion failed"),
+	}, testLogger)
+	if got := tNode.GetState(); got != FailedTaskState {
+		t.Errorf("GetState() = %v, want %v", got, FailedTaskState)
+	}
+	if got := tNode.GetTerminationMessage(); got != "test: assertion failed" {
+		t.Errorf("GetTerminationMessage() = %q", got)
+	}
+	if tNode.isTaskDeleted() {
+		t.Errorf("isTaskDeleted() = true while the executor reports steps")
+	}
+}
@@ -16,7 +16,6 @@ package scheduler
 
 import (
 	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
-	apis "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -30,9 +29,9 @@ type TaskScheduler interface {
 	// AddTasks registers task specs that are not yet tracked by the scheduler.
 	// Tasks whose names are already tracked are silently skipped, making this
 	// safe to call with the full task list during a scale-out reconciliation.
-	AddTasks(tasks []*apis.Task) error
+	AddTasks(tasks []*SandboxTasks) error
 }
 
-func NewTaskScheduler(name string, tasks []*apis.Task, pods []*corev1.Pod, resPolicyWhenTaskCompleted sandboxv1alpha1.TaskResourcePolicy, logger logr.Logger) (TaskScheduler, error) {
+func NewTaskScheduler(name string, tasks []*SandboxTasks, pods []*corev1.Pod, resPolicyWhenTaskCompleted sandboxv1alpha1.TaskResourcePolicy, logger logr.Logger) (TaskScheduler, error) {
 	return newTaskScheduler(name, tasks, pods, resPolicyWhenTaskCompleted, logger)
 }
@@ -11,7 +11,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	scheduler "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/scheduler"
-	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
 )
 
 // MockTaskScheduler is a mock of TaskScheduler interface.
@@ -38,7 +37,7 @@ func (m *MockTaskScheduler) EXPECT() *MockTaskSchedulerMockRecorder {
 }
 
 // AddTasks mocks base method.
-func (m *MockTaskScheduler) AddTasks(tasks []*api.Task) error {
+func (m *MockTaskScheduler) AddTasks(tasks []*scheduler.SandboxTasks) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddTasks", tasks)
 	ret0, _ := ret[0].(error)
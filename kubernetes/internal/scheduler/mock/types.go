@@ -63,6 +63,34 @@ func (mr *MockTaskMockRecorder) GetPodName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodName", reflect.TypeOf((*MockTask)(nil).GetPodName))
 }
 
+// GetStepStates mocks base method.
+func (m *MockTask) GetStepStates() []scheduler.StepState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStepStates")
+	ret0, _ := ret[0].([]scheduler.StepState)
+	return ret0
+}
+
+// GetStepStates indicates an expected call of GetStepStates.
+func (mr *MockTaskMockRecorder) GetStepStates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStepStates", reflect.TypeOf((*MockTask)(nil).GetStepStates))
+}
+
+// GetTerminationMessage mocks base method.
+func (m *MockTask) GetTerminationMessage() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTerminationMessage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetTerminationMessage indicates an expected call of GetTerminationMessage.
+func (mr *MockTaskMockRecorder) GetTerminationMessage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTerminationMessage", reflect.TypeOf((*MockTask)(nil).GetTerminationMessage))
+}
+
 // GetState mocks base method.
 func (m *MockTask) GetState() scheduler.TaskState {
 	m.ctrl.T.Helper()
@@ -56,25 +56,39 @@ func (sch *defaultTaskScheduler) recoverTaskNodesStatus() error {
 	for i := range ips {
 		ip := ips[i]
 		pod := pods[i]
-		task := tasks[ip]
-		if task == nil || pod == nil {
+		podTasks := tasks[ip]
+		if len(podTasks) == 0 || podTasks[0] == nil || pod == nil {
 			continue
 		}
-		if tNode := sch.taskNodeByNameIndex[task.Name]; tNode != nil {
-			recoverOneTaskNode(tNode, task, pod.Status.PodIP, pod.Name, sch.logger)
-		} else {
+		if tNode := sch.taskNodeOf(podTasks[0].Name); tNode != nil {
+			recoverOneTaskNode(tNode, podTasks, pod.Status.PodIP, pod.Name, sch.logger)
 		}
 		// TODO do we need to stop tasks not belong us? e.g users ScaleIn []*sandboxv1alpha1.Task
 	}
 	return nil
 }
 
-func recoverOneTaskNode(tNode *taskNode, currentTask *api.Task, ip string, podName string, log logr.Logger) {
-	tNode.Status = currentTask
-	tNode.transTaskState(parseTaskState(currentTask), log)
+// taskNodeOf returns the task node that owns a task, which is either the task itself or one of
+// its steps.
+func (sch *defaultTaskScheduler) taskNodeOf(taskName string) *taskNode {
+	if tNode := sch.taskNodeByNameIndex[taskName]; tNode != nil {
+		return tNode
+	}
+	for _, tNode := range sch.taskNodes {
+		for _, step := range tNode.Spec.Steps {
+			if step.Name == taskName {
+				return tNode
+			}
+		}
+	}
+	return nil
+}
+
+func recoverOneTaskNode(tNode *taskNode, currentTasks []*api.Task, ip string, podName string, log logr.Logger) {
+	tNode.setStatus(currentTasks, log)
 	tNode.IP = ip
 	tNode.PodName = podName
-	if currentTask.DeletionTimestamp != nil {
+	if currentTasks[0].DeletionTimestamp != nil {
 		tNode.transSchState(stateReleasing, log)
 	}
 }
@@ -110,7 +110,7 @@ func Test_recoverOneTaskNode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			recoverOneTaskNode(tt.args.tNode, tt.args.currentTask, tt.args.ip, tt.args.podName, testLogger)
+			recoverOneTaskNode(tt.args.tNode, []*api.Task{tt.args.currentTask}, tt.args.ip, tt.args.podName, testLogger)
 			if tt.expectTaskNode != nil {
 				if !reflect.DeepEqual(tt.expectTaskNode, tt.args.tNode) {
 					t.Errorf("recoverOneTaskNode, want %+v, got %+v", tt.expectTaskNode, tt.args.tNode)
@@ -224,7 +224,7 @@ func Test_defaultTaskScheduler_recoverTaskNodesStatus(t *testing.T) {
 				},
 				taskStatusCollector: func() taskStatusCollector {
 					mock := NewMocktaskStatusCollector(ctl)
-					mock.EXPECT().Collect(gomock.Any(), []string{"1.2.3.4"}).Return(map[string]*api.Task{"1.2.3.4": nil}).Times(1)
+					mock.EXPECT().Collect(gomock.Any(), []string{"1.2.3.4"}).Return(map[string][]*api.Task{"1.2.3.4": nil}).Times(1)
 					return mock
 				}(),
 			},
@@ -253,7 +253,7 @@ func Test_defaultTaskScheduler_recoverTaskNodesStatus(t *testing.T) {
 				},
 				taskStatusCollector: func() taskStatusCollector {
 					mock := NewMocktaskStatusCollector(ctl)
-					mock.EXPECT().Collect(gomock.Any(), []string{"1.2.3.4"}).Return(map[string]*api.Task{"1.2.3.4": testTask}).Times(1)
+					mock.EXPECT().Collect(gomock.Any(), []string{"1.2.3.4"}).Return(map[string][]*api.Task{"1.2.3.4": {testTask}}).Times(1)
 					return mock
 				}(),
 			},
@@ -32,7 +32,7 @@ func newTaskStatusCollector(creator taskClientCreator, logger logr.Logger) taskS
 
 // TODO error
 type taskStatusCollector interface {
-	Collect(ctx context.Context, ipList []string) map[string]*api.Task /*ip<->task*/
+	Collect(ctx context.Context, ipList []string) map[string][]*api.Task /*ip<->tasks*/
 }
 
 // TODO maybe cache
@@ -41,11 +41,11 @@ type defaultTaskStatusCollector struct {
 	logger  logr.Logger
 }
 
-func (s *defaultTaskStatusCollector) Collect(ctx context.Context, ipList []string) map[string]*api.Task {
+func (s *defaultTaskStatusCollector) Collect(ctx context.Context, ipList []string) map[string][]*api.Task {
 	semaphore := make(chan struct{}, len(ipList))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	ret := make(map[string]*api.Task, len(ipList))
+	ret := make(map[string][]*api.Task, len(ipList))
 	for idx := range ipList {
 		ip := ipList[idx]
 		semaphore <- struct{}{}
@@ -58,12 +58,16 @@ func (s *defaultTaskStatusCollector) Collect(ctx context.Context, ipList []strin
 			ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 			defer cancel()
 			client := s.creator(ip)
-			task, err := client.Get(ctx)
+			tasks, err := client.List(ctx)
 			if err != nil {
-				s.logger.Error(err, "failed to GetTask", "ip", ip)
-			} else if task != nil {
+				s.logger.Error(err, "failed to ListTasks", "ip", ip)
+			} else if len(tasks) > 0 {
+				list := make([]*api.Task, len(tasks))
+				for i := range tasks {
+					list[i] = &tasks[i]
+				}
 				mu.Lock()
-				ret[ip] = task
+				ret[ip] = list
 				mu.Unlock()
 			}
 		}(ip)
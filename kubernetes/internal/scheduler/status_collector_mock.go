@@ -37,10 +37,10 @@ func (m *MocktaskStatusCollector) EXPECT() *MocktaskStatusCollectorMockRecorder
 }
 
 // Collect mocks base method.
-func (m *MocktaskStatusCollector) Collect(ctx context.Context, ipList []string) map[string]*api.Task {
+func (m *MocktaskStatusCollector) Collect(ctx context.Context, ipList []string) map[string][]*api.Task {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Collect", ctx, ipList)
-	ret0, _ := ret[0].(map[string]*api.Task)
+	ret0, _ := ret[0].(map[string][]*api.Task)
 	return ret0
 }
 
@@ -14,10 +14,30 @@
 
 package scheduler
 
+import (
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+)
+
+// SandboxTasks is the work of one sandbox: either a single task, or steps that the task executor
+// starts in the order of their dependencies.
+type SandboxTasks struct {
+	// Name identifies the sandbox's work. It is the task name for a single task.
+	Name string
+	// Task is the single task of the sandbox. It is nil if the sandbox runs steps.
+	Task *api.Task
+	// Steps are the tasks of the sandbox's steps.
+	Steps []*api.Task
+}
+
 type Task interface {
 	GetName() string
 	GetState() TaskState
 	GetPodName() string
+	// GetTerminationMessage returns the message the task executor reported when the task
+	// terminated, such as the tail of a failed process's stderr. It is empty if there is none.
+	GetTerminationMessage() string
+	// GetStepStates returns the state of each step of a task that runs steps, in spec order.
+	GetStepStates() []StepState
 	// IsResourceReleased task resource is released
 	// TODO func name is strange
 	IsResourceReleased() bool
@@ -25,6 +45,12 @@ type Task interface {
 
 type TaskState string
 
+// StepState is the state of one step of a task.
+type StepState struct {
+	Name  string
+	State TaskState
+}
+
 const (
 	RunningTaskState TaskState = "RUNNING"
 	FailedTaskState  TaskState = "FAILED"
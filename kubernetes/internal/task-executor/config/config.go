@@ -25,34 +25,37 @@ import (
 )
 
 type Config struct {
-	DataDir           string
-	ListenAddr        string
-	CRISocket         string
-	ReadTimeout       time.Duration
-	WriteTimeout      time.Duration
-	ReconcileInterval time.Duration
-	EnableSidecarMode bool
-	MainContainerName string
-	LogMaxSize        int
-	LogMaxBackups     int
-	LogMaxAge         int
-	LogDir            string
+	DataDir    string
+	ListenAddr string
+	CRISocket  string
+	// ContainerdNamespace is the containerd namespace that container mode tasks run in.
+	ContainerdNamespace string
+	ReadTimeout         time.Duration
+	WriteTimeout        time.Duration
+	ReconcileInterval   time.Duration
+	EnableSidecarMode   bool
+	MainContainerName   string
+	LogMaxSize          int
+	LogMaxBackups       int
+	LogMaxAge           int
+	LogDir              string
 }
 
 func NewConfig() *Config {
 	return &Config{
-		DataDir:           "/var/lib/sandbox/tasks",
-		ListenAddr:        "0.0.0.0:5758",
-		CRISocket:         "/var/run/containerd/containerd.sock",
-		ReadTimeout:       30 * time.Second,
-		WriteTimeout:      30 * time.Second,
-		ReconcileInterval: 500 * time.Millisecond,
-		EnableSidecarMode: false,
-		MainContainerName: "main",
-		LogMaxSize:        100,
-		LogMaxBackups:     10,
-		LogMaxAge:         7,
-		LogDir:            "logs",
+		DataDir:             "/var/lib/sandbox/tasks",
+		ListenAddr:          "0.0.0.0:5758",
+		CRISocket:           "/var/run/containerd/containerd.sock",
+		ContainerdNamespace: "opensandbox",
+		ReadTimeout:         30 * time.Second,
+		WriteTimeout:        30 * time.Second,
+		ReconcileInterval:   500 * time.Millisecond,
+		EnableSidecarMode:   false,
+		MainContainerName:   "main",
+		LogMaxSize:          100,
+		LogMaxBackups:       10,
+		LogMaxAge:           7,
+		LogDir:              "logs",
 	}
 }
 
@@ -66,6 +69,9 @@ func (c *Config) LoadFromEnv() {
 	if v := os.Getenv("CRI_SOCKET"); v != "" {
 		c.CRISocket = v
 	}
+	if v := os.Getenv("CONTAINERD_NAMESPACE"); v != "" {
+		c.ContainerdNamespace = v
+	}
 	if v := os.Getenv("ENABLE_SIDECAR_MODE"); v == "true" {
 		c.EnableSidecarMode = true
 	}
@@ -78,6 +84,7 @@ func (c *Config) LoadFromFlags() {
 	flag.StringVar(&c.DataDir, "data-dir", c.DataDir, "data storage directory")
 	flag.StringVar(&c.ListenAddr, "listen-addr", c.ListenAddr, "service listen address")
 	flag.StringVar(&c.CRISocket, "cri-socket", c.CRISocket, "CRI socket path for container runner mode")
+	flag.StringVar(&c.ContainerdNamespace, "containerd-namespace", c.ContainerdNamespace, "containerd namespace for container runner mode")
 	flag.BoolVar(&c.EnableSidecarMode, "enable-sidecar-mode", c.EnableSidecarMode, "enable sidecar runner mode")
 	flag.StringVar(&c.MainContainerName, "main-container-name", c.MainContainerName, "main container name")
 	// set log flags
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,10 +29,23 @@ import (
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/runtime"
 	store "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/storage"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
 )
 
 const (
 	maxConcurrentTasks = 1
+
+	defaultRestartBackoff = 10 * time.Second
+	maxRestartBackoff     = 5 * time.Minute
+
+	// maxCleanupAttempts bounds how often the resources of a deleted task are cleaned up before
+	// the task is removed anyway, so a broken runtime cannot block the deletion forever.
+	maxCleanupAttempts = 5
+
+	reasonBackOff                = "BackOff"
+	reasonWaitingForDependencies = "WaitingForDependencies"
+	reasonDependencyFailed       = "DependencyFailed"
+	reasonQueued                 = "Queued"
 )
 
 type taskManager struct {
@@ -44,6 +58,14 @@ type taskManager struct {
 
 	stopping map[string]bool
 
+	// cleanupFailures counts the failed resource cleanups of deleted tasks.
+	cleanupFailures map[string]int
+
+	// restartBackoff is the delay before the first restart of a task. It doubles with every
+	// restart up to maxRestartBackoff.
+	restartBackoff    time.Duration
+	maxRestartBackoff time.Duration
+
 	stopCh chan struct{}
 	doneCh chan struct{}
 }
@@ -66,8 +88,14 @@ func NewTaskManager(cfg *config.Config, taskStore store.TaskStore, exec runtime.
 		executor: exec,
 		config:   cfg,
 		stopping: make(map[string]bool),
-		stopCh:   make(chan struct{}),
-		doneCh:   make(chan struct{}),
+
+		cleanupFailures: make(map[string]int),
+
+		restartBackoff:    defaultRestartBackoff,
+		maxRestartBackoff: maxRestartBackoff,
+
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
 	}, nil
 }
 
@@ -76,7 +104,7 @@ func (m *taskManager) isTaskActive(task *types.Task) bool {
 	if task == nil {
 		return false
 	}
-	if task.DeletionTimestamp != nil {
+	if task.DeletionTimestamp != nil || !hasStarted(task) {
 		return false
 	}
 	state := task.Status.State
@@ -109,6 +137,14 @@ func (m *taskManager) Create(ctx context.Context, task *types.Task) (*types.Task
 		return nil, fmt.Errorf("task %s already exists", task.Name)
 	}
 
+	if len(task.DependsOn) > 0 {
+		if err := m.createWaitingTaskLocked(ctx, task); err != nil {
+			return nil, err
+		}
+		m.resolveDependenciesLocked(ctx)
+		return task, nil
+	}
+
 	if m.countActiveTasks() >= maxConcurrentTasks {
 		return nil, fmt.Errorf("maximum concurrent tasks (%d) reached, cannot create new task", maxConcurrentTasks)
 	}
@@ -173,13 +209,20 @@ func (m *taskManager) Sync(ctx context.Context, desired []*types.Task) ([]*types
 
 	for name, task := range desiredMap {
 		if _, exists := m.tasks[name]; !exists {
-			if err := m.createTaskLocked(ctx, task); err != nil {
+			create := m.createTaskLocked
+			if len(desiredMap) > 1 && m.countActiveTasks() >= maxConcurrentTasks {
+				// Tasks synced together are queued rather than rejected while no slot is free.
+				create = m.createWaitingTaskLocked
+			}
+			if err := create(ctx, task); err != nil {
 				klog.ErrorS(err, "failed to create task during sync", "name", name)
 				syncErrors = append(syncErrors, fmt.Errorf("failed to create task %s: %w", name, err))
 			}
 		}
 	}
 
+	m.resolveDependenciesLocked(ctx)
+
 	if len(syncErrors) > 0 {
 		return m.listTasksLocked(), errors.Join(syncErrors...)
 	}
@@ -273,6 +316,10 @@ func (m *taskManager) createTaskLocked(ctx context.Context, task *types.Task) er
 		return fmt.Errorf("task %s already exists", task.Name)
 	}
 
+	if len(task.DependsOn) > 0 {
+		return m.createWaitingTaskLocked(ctx, task)
+	}
+
 	if m.countActiveTasks() >= maxConcurrentTasks {
 		return fmt.Errorf("maximum concurrent tasks (%d) reached, cannot create new task", maxConcurrentTasks)
 	}
@@ -300,6 +347,22 @@ func (m *taskManager) createTaskLocked(ctx context.Context, task *types.Task) er
 	return nil
 }
 
+// createWaitingTaskLocked persists a task without starting it. It is started by
+// resolveDependenciesLocked once its dependencies have succeeded and a slot is free.
+func (m *taskManager) createWaitingTaskLocked(ctx context.Context, task *types.Task) error {
+	if len(task.DependsOn) > 0 {
+		task.Status = waitingStatus(task, task.DependsOn)
+	} else {
+		task.Status = queuedStatus(task)
+	}
+	if err := m.store.Create(ctx, task); err != nil {
+		return fmt.Errorf("failed to persist task: %w", err)
+	}
+	m.tasks[task.Name] = task
+	klog.InfoS("task created, waiting to start", "name", task.Name, "dependsOn", task.DependsOn)
+	return nil
+}
+
 // listTasksLocked returns all tasks without acquiring the lock
 func (m *taskManager) listTasksLocked() []*types.Task {
 	tasks := make([]*types.Task, 0, len(m.tasks))
@@ -327,6 +390,13 @@ func (m *taskManager) recoverTasks(ctx context.Context) error {
 			continue
 		}
 
+		// A task that never started has no runtime state to inspect.
+		if !hasStarted(task) {
+			m.tasks[task.Name] = task
+			klog.InfoS("recovered task", "name", task.Name, "state", task.Status.State, "deleting", task.DeletionTimestamp != nil)
+			continue
+		}
+
 		persistedState := task.Status.State
 		status, err := m.executor.Inspect(ctx, task)
 		if err != nil {
@@ -343,6 +413,7 @@ func (m *taskManager) recoverTasks(ctx context.Context) error {
 			continue
 		}
 
+		keepRestartStatus(status, &task.Status)
 		task.Status = *status
 
 		m.tasks[task.Name] = task
@@ -358,6 +429,10 @@ func shouldDropRecoveredTask(task *types.Task, persistedState, recoveredState ty
 	if task == nil || task.DeletionTimestamp != nil {
 		return false
 	}
+	// A task waiting for its restart backoff has terminated on purpose.
+	if task.Status.NextRestartAt != nil {
+		return false
+	}
 	if persistedState != types.TaskStatePending && persistedState != types.TaskStateRunning {
 		return false
 	}
@@ -398,12 +473,23 @@ func (m *taskManager) reconcileTasks(ctx context.Context) {
 		if task == nil {
 			continue
 		}
+		if !hasStarted(task) {
+			if task.DeletionTimestamp != nil {
+				klog.InfoS("task never started, finalizing deletion", "name", name)
+				tasksToDelete = append(tasksToDelete, name)
+			}
+			continue
+		}
 		status, err := m.executor.Inspect(ctx, task)
 		if err != nil {
 			klog.ErrorS(err, "failed to inspect task", "name", name)
 			continue
 		}
+		keepRestartStatus(status, &task.Status)
 		state := status.State
+		if task.DeletionTimestamp == nil && !m.stopping[name] {
+			status = m.applyRestartPolicy(ctx, task, status)
+		}
 
 		shouldStop := false
 		stopReason := ""
@@ -458,10 +544,24 @@ func (m *taskManager) reconcileTasks(ctx context.Context) {
 	}
 
 	for _, name := range tasksToDelete {
-		if _, exists := m.tasks[name]; !exists {
+		task, exists := m.tasks[name]
+		if !exists {
 			continue
 		}
 
+		if cleaner, ok := m.executor.(runtime.Cleaner); ok {
+			if err := cleaner.Cleanup(ctx, task); err != nil {
+				m.cleanupFailures[name]++
+				if m.cleanupFailures[name] < maxCleanupAttempts {
+					klog.ErrorS(err, "failed to clean up task resources", "name", name,
+						"attempt", m.cleanupFailures[name])
+					continue
+				}
+				klog.ErrorS(err, "giving up cleaning up task resources, they are left behind", "name", name,
+					"attempts", m.cleanupFailures[name])
+			}
+		}
+
 		if err := m.store.Delete(ctx, name); err != nil {
 			klog.ErrorS(err, "failed to delete task from store", "name", name)
 			continue
@@ -469,8 +569,222 @@ func (m *taskManager) reconcileTasks(ctx context.Context) {
 
 		delete(m.tasks, name)
 		delete(m.stopping, name)
+		delete(m.cleanupFailures, name)
 		klog.InfoS("task deleted successfully", "name", name)
 	}
+
+	m.resolveDependenciesLocked(ctx)
+}
+
+// resolveDependenciesLocked starts the waiting tasks whose dependencies have all succeeded, as far
+// as the concurrency limit allows, and fails the waiting tasks with a failed dependency. A failure
+// propagates through a chain of waiting tasks in a single call.
+func (m *taskManager) resolveDependenciesLocked(ctx context.Context) {
+	for changed := true; changed; {
+		changed = false
+		for name, task := range m.tasks {
+			if task == nil || task.DeletionTimestamp != nil || !isWaiting(task) {
+				continue
+			}
+			var status types.Status
+			failed, pending := m.checkDependenciesLocked(task)
+			switch {
+			case failed != "":
+				klog.InfoS("dependency failed, failing task", "name", name, "dependency", failed)
+				status = notStartedStatus(task, types.TaskStateFailed, reasonDependencyFailed,
+					fmt.Sprintf("dependency %s failed", failed))
+				changed = true
+			case len(pending) > 0:
+				status = waitingStatus(task, pending)
+			case m.countActiveTasks() >= maxConcurrentTasks:
+				status = queuedStatus(task)
+			default:
+				if !m.startWaitingTaskLocked(ctx, task) {
+					continue
+				}
+				status = task.Status
+			}
+			if task.Status.State == status.State && reflect.DeepEqual(task.Status.SubStatuses, status.SubStatuses) {
+				continue
+			}
+			task.Status = status
+			if err := m.store.Update(ctx, task); err != nil {
+				klog.ErrorS(err, "failed to update task status in store", "name", name)
+			}
+		}
+	}
+}
+
+// checkDependenciesLocked returns the first failed dependency of a task, or else the dependencies
+// that have not succeeded yet.
+func (m *taskManager) checkDependenciesLocked(task *types.Task) (string, []string) {
+	var pending []string
+	for _, dep := range task.DependsOn {
+		depTask, ok := m.tasks[dep]
+		if !ok {
+			pending = append(pending, dep)
+			continue
+		}
+		switch depTask.Status.State {
+		case types.TaskStateSucceeded:
+		case types.TaskStateFailed, types.TaskStateNotFound:
+			return dep, nil
+		default:
+			pending = append(pending, dep)
+		}
+	}
+	return "", pending
+}
+
+// startWaitingTaskLocked starts a waiting task and reports whether it did. A task that fails to
+// start keeps waiting and is retried on the next reconcile.
+func (m *taskManager) startWaitingTaskLocked(ctx context.Context, task *types.Task) bool {
+	klog.InfoS("starting waiting task", "name", task.Name)
+	if err := m.executor.Start(ctx, task); err != nil {
+		klog.ErrorS(err, "failed to start task", "name", task.Name)
+		return false
+	}
+	status, err := m.executor.Inspect(ctx, task)
+	if err != nil {
+		klog.ErrorS(err, "failed to inspect task after start", "name", task.Name)
+		status = &types.Status{State: types.TaskStatePending}
+	}
+	task.Status = *status
+	return true
+}
+
+// hasStarted reports whether a task has been handed to the executor. A task that is waiting to
+// start, or failed because of a dependency, has not.
+func hasStarted(task *types.Task) bool {
+	if len(task.Status.SubStatuses) == 0 {
+		return true
+	}
+	switch task.Status.SubStatuses[0].Reason {
+	case reasonWaitingForDependencies, reasonQueued, reasonDependencyFailed:
+		return false
+	}
+	return true
+}
+
+func isWaiting(task *types.Task) bool {
+	return task.Status.State == types.TaskStatePending && !hasStarted(task)
+}
+
+func waitingStatus(task *types.Task, pending []string) types.Status {
+	return notStartedStatus(task, types.TaskStatePending, reasonWaitingForDependencies,
+		"waiting for "+strings.Join(pending, ", "))
+}
+
+func queuedStatus(task *types.Task) types.Status {
+	return notStartedStatus(task, types.TaskStatePending, reasonQueued, "waiting for a free task slot")
+}
+
+// notStartedStatus builds the status of a task that has not been started, with a sub status per
+// container for a pod task.
+func notStartedStatus(task *types.Task, state types.TaskState, reason, message string) types.Status {
+	sub := types.SubStatus{Reason: reason, Message: message}
+	if state == types.TaskStateFailed {
+		// Terminated with a non-zero exit code, so that the task is reported as failed.
+		now := time.Now()
+		sub.ExitCode = 1
+		sub.FinishedAt = &now
+	}
+	status := types.Status{State: state}
+	if task.PodTemplateSpec != nil && len(task.PodTemplateSpec.Spec.Containers) > 0 {
+		for _, c := range task.PodTemplateSpec.Spec.Containers {
+			sub.Name = c.Name
+			status.SubStatuses = append(status.SubStatuses, sub)
+		}
+	} else {
+		status.SubStatuses = []types.SubStatus{sub}
+	}
+	return status
+}
+
+// applyRestartPolicy restarts a terminated task as its restart policy requires and returns the
+// status to record. A task waiting for its backoff to expire is reported as pending.
+func (m *taskManager) applyRestartPolicy(ctx context.Context, task *types.Task, status *types.Status) *types.Status {
+	if !shouldRestart(task, status) {
+		status.NextRestartAt = nil
+		return status
+	}
+
+	now := time.Now()
+	if status.NextRestartAt == nil {
+		next := now.Add(m.backoff(status.RestartCount))
+		status.NextRestartAt = &next
+		status.LastTermination = status.SubStatuses
+		klog.InfoS("task terminated, scheduling restart", "name", task.Name, "state", status.State,
+			"restartCount", status.RestartCount, "restartAt", next)
+	}
+	if now.Before(*status.NextRestartAt) {
+		return backOffStatus(status, status.NextRestartAt.Sub(now))
+	}
+
+	klog.InfoS("restarting task", "name", task.Name, "restartCount", status.RestartCount+1)
+	// A failed start counts as an attempt: the task is inspected as terminated again and
+	// waits for a longer backoff.
+	if err := m.executor.Start(ctx, task); err != nil {
+		klog.ErrorS(err, "failed to restart task", "name", task.Name)
+	}
+	restarted, err := m.executor.Inspect(ctx, task)
+	if err != nil {
+		klog.ErrorS(err, "failed to inspect task after restart", "name", task.Name)
+		restarted = &types.Status{State: types.TaskStatePending}
+	}
+	restarted.RestartCount = status.RestartCount + 1
+	restarted.LastTermination = status.LastTermination
+	return restarted
+}
+
+// backoff returns the delay before the restart that follows restartCount restarts.
+func (m *taskManager) backoff(restartCount int32) time.Duration {
+	backoff := m.restartBackoff
+	for i := int32(0); i < restartCount && backoff < m.maxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, m.maxRestartBackoff)
+}
+
+// shouldRestart reports whether a task in the given status is restarted by its restart policy.
+func shouldRestart(task *types.Task, status *types.Status) bool {
+	if task.MaxRetries != nil && status.RestartCount >= *task.MaxRetries {
+		return false
+	}
+	switch task.RestartPolicy {
+	case api.RestartPolicyAlways:
+		return status.State == types.TaskStateSucceeded || status.State == types.TaskStateFailed
+	case api.RestartPolicyOnFailure:
+		return status.State == types.TaskStateFailed
+	default:
+		return false
+	}
+}
+
+// backOffStatus reports a terminated task that waits for its restart as pending.
+func backOffStatus(status *types.Status, remaining time.Duration) *types.Status {
+	message := fmt.Sprintf("back-off %s restarting terminated task", remaining.Round(time.Second))
+	subStatuses := make([]types.SubStatus, 0, len(status.SubStatuses))
+	for _, sub := range status.SubStatuses {
+		subStatuses = append(subStatuses, types.SubStatus{Name: sub.Name, Reason: reasonBackOff, Message: message})
+	}
+	if len(subStatuses) == 0 {
+		subStatuses = append(subStatuses, types.SubStatus{Reason: reasonBackOff, Message: message})
+	}
+	return &types.Status{
+		State:           types.TaskStatePending,
+		SubStatuses:     subStatuses,
+		RestartCount:    status.RestartCount,
+		LastTermination: status.LastTermination,
+		NextRestartAt:   status.NextRestartAt,
+	}
+}
+
+// keepRestartStatus copies the restart bookkeeping of a task into a freshly inspected status.
+func keepRestartStatus(status, previous *types.Status) {
+	status.RestartCount = previous.RestartCount
+	status.LastTermination = previous.LastTermination
+	status.NextRestartAt = previous.NextRestartAt
 }
 
 // isTerminalState returns true if the task will not transition to another state
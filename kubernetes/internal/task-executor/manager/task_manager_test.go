@@ -16,12 +16,14 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"os/exec"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/config"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/runtime"
@@ -820,3 +822,326 @@ func TestIsTerminalState(t *testing.T) {
 		})
 	}
 }
+
+// cleaningExecutor is a fakeExecutor that implements runtime.Cleaner.
+type cleaningExecutor struct {
+	*fakeExecutor
+	cleanupErr error
+	cleaned    []string
+}
+
+func (c *cleaningExecutor) Cleanup(_ context.Context, task *types.Task) error {
+	if c.cleanupErr != nil {
+		return c.cleanupErr
+	}
+	c.cleaned = append(c.cleaned, task.Name)
+	return nil
+}
+
+func TestTaskManager_CleanupBeforeFinalizingDeletion(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		ReconcileInterval: time.Hour,
+	}
+	taskStore, err := store.NewFileStore(cfg.DataDir)
+	require.NoError(t, err)
+	exec := &cleaningExecutor{fakeExecutor: newFakeExecutor(), cleanupErr: errors.New("runtime unavailable")}
+	mgrIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	mgr := mgrIface.(*taskManager)
+
+	_, err = mgr.Create(ctx, &types.Task{Name: "task", Process: &api.Process{Command: []string{"true"}}})
+	require.NoError(t, err)
+	exec.inspect["task"] = &types.Status{State: types.TaskStateSucceeded}
+	require.NoError(t, mgr.Delete(ctx, "task"))
+
+	// The task is kept until its resources are cleaned up.
+	mgr.reconcileTasks(ctx)
+	_, err = mgr.Get(ctx, "task")
+	require.NoError(t, err)
+
+	exec.cleanupErr = nil
+	mgr.reconcileTasks(ctx)
+	_, err = mgr.Get(ctx, "task")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"task"}, exec.cleaned)
+}
+
+func TestTaskManager_DeletionProceedsAfterCleanupAttempts(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		ReconcileInterval: time.Hour,
+	}
+	taskStore, err := store.NewFileStore(cfg.DataDir)
+	require.NoError(t, err)
+	exec := &cleaningExecutor{fakeExecutor: newFakeExecutor(), cleanupErr: errors.New("runtime unavailable")}
+	mgrIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	mgr := mgrIface.(*taskManager)
+
+	_, err = mgr.Create(ctx, &types.Task{Name: "task", Process: &api.Process{Command: []string{"true"}}})
+	require.NoError(t, err)
+	exec.inspect["task"] = &types.Status{State: types.TaskStateSucceeded}
+	require.NoError(t, mgr.Delete(ctx, "task"))
+
+	for i := 1; i < maxCleanupAttempts; i++ {
+		mgr.reconcileTasks(ctx)
+		_, err = mgr.Get(ctx, "task")
+		require.NoError(t, err, "attempt %d", i)
+	}
+
+	// The last failed attempt gives up on the resources and finalizes the deletion.
+	mgr.reconcileTasks(ctx)
+	_, err = mgr.Get(ctx, "task")
+	assert.Error(t, err)
+	assert.Empty(t, mgr.cleanupFailures)
+	tasks, err := taskStore.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestTaskManager_RestartOnFailure(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		ReconcileInterval: time.Hour,
+	}
+	taskStore, err := store.NewFileStore(cfg.DataDir)
+	require.NoError(t, err)
+	exec := newFakeExecutor()
+	mgrIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	mgr := mgrIface.(*taskManager)
+	mgr.restartBackoff = time.Hour
+
+	_, err = mgr.Create(ctx, &types.Task{
+		Name:          "task",
+		Process:       &api.Process{Command: []string{"false"}},
+		RestartPolicy: api.RestartPolicyOnFailure,
+		MaxRetries:    ptr.To[int32](1),
+	})
+	require.NoError(t, err)
+	failed := func() *types.Status {
+		return &types.Status{
+			State:       types.TaskStateFailed,
+			SubStatuses: []types.SubStatus{{ExitCode: 1, Reason: "Failed", Message: "boom"}},
+		}
+	}
+
+	// A failed task waits for its backoff and is reported as pending.
+	exec.inspect["task"] = failed()
+	mgr.reconcileTasks(ctx)
+	task, err := mgr.Get(ctx, "task")
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStatePending, task.Status.State)
+	assert.Equal(t, "BackOff", task.Status.SubStatuses[0].Reason)
+	require.NotNil(t, task.Status.NextRestartAt)
+	require.Len(t, task.Status.LastTermination, 1)
+	assert.Equal(t, "boom", task.Status.LastTermination[0].Message)
+	assert.Equal(t, 1, exec.starts)
+
+	// The backoff survives a restart of the task executor.
+	persisted, err := taskStore.Get(ctx, "task")
+	require.NoError(t, err)
+	assert.False(t, shouldDropRecoveredTask(persisted, persisted.Status.State, types.TaskStateFailed))
+
+	// Once the backoff expires the task is started again.
+	task.Status.NextRestartAt = ptr.To(time.Now().Add(-time.Second))
+	mgr.reconcileTasks(ctx)
+	task, err = mgr.Get(ctx, "task")
+	require.NoError(t, err)
+	assert.Equal(t, 2, exec.starts)
+	assert.Equal(t, types.TaskStateRunning, task.Status.State)
+	assert.Equal(t, int32(1), task.Status.RestartCount)
+	assert.Nil(t, task.Status.NextRestartAt)
+	assert.Equal(t, "boom", task.Status.LastTermination[0].Message)
+
+	// With its retries used up the task stays failed.
+	exec.inspect["task"] = failed()
+	mgr.reconcileTasks(ctx)
+	task, err = mgr.Get(ctx, "task")
+	require.NoError(t, err)
+	assert.Equal(t, 2, exec.starts)
+	assert.Equal(t, types.TaskStateFailed, task.Status.State)
+	assert.Equal(t, int32(1), task.Status.RestartCount)
+	assert.Nil(t, task.Status.NextRestartAt)
+}
+
+func TestTaskManager_NoRestartWhileDeleting(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		ReconcileInterval: time.Hour,
+	}
+	taskStore, err := store.NewFileStore(cfg.DataDir)
+	require.NoError(t, err)
+	exec := newFakeExecutor()
+	mgrIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	mgr := mgrIface.(*taskManager)
+	mgr.restartBackoff = 0
+
+	_, err = mgr.Create(ctx, &types.Task{
+		Name:          "task",
+		Process:       &api.Process{Command: []string{"true"}},
+		RestartPolicy: api.RestartPolicyAlways,
+	})
+	require.NoError(t, err)
+	exec.inspect["task"] = &types.Status{State: types.TaskStateSucceeded}
+	require.NoError(t, mgr.Delete(ctx, "task"))
+
+	mgr.reconcileTasks(ctx)
+	assert.Equal(t, 1, exec.starts)
+	_, err = mgr.Get(ctx, "task")
+	assert.Error(t, err, "a terminated task being deleted is finalized instead of restarted")
+}
+
+func TestShouldRestart(t *testing.T) {
+	tests := []struct {
+		policy       api.RestartPolicy
+		maxRetries   *int32
+		restartCount int32
+		state        types.TaskState
+		want         bool
+	}{
+		{policy: "", state: types.TaskStateFailed, want: false},
+		{policy: api.RestartPolicyNever, state: types.TaskStateFailed, want: false},
+		{policy: api.RestartPolicyOnFailure, state: types.TaskStateFailed, want: true},
+		{policy: api.RestartPolicyOnFailure, state: types.TaskStateSucceeded, want: false},
+		{policy: api.RestartPolicyOnFailure, state: types.TaskStateRunning, want: false},
+		{policy: api.RestartPolicyAlways, state: types.TaskStateSucceeded, want: true},
+		{policy: api.RestartPolicyAlways, state: types.TaskStateFailed, want: true},
+		{policy: api.RestartPolicyAlways, state: types.TaskStatePending, want: false},
+		{policy: api.RestartPolicyOnFailure, maxRetries: ptr.To[int32](2), restartCount: 1, state: types.TaskStateFailed, want: true},
+		{policy: api.RestartPolicyOnFailure, maxRetries: ptr.To[int32](2), restartCount: 2, state: types.TaskStateFailed, want: false},
+		{policy: api.RestartPolicyAlways, maxRetries: ptr.To[int32](0), state: types.TaskStateFailed, want: false},
+	}
+	for _, tt := range tests {
+		task := &types.Task{RestartPolicy: tt.policy, MaxRetries: tt.maxRetries}
+		status := &types.Status{State: tt.state, RestartCount: tt.restartCount}
+		assert.Equal(t, tt.want, shouldRestart(task, status), "policy=%q maxRetries=%v restartCount=%d state=%s",
+			tt.policy, tt.maxRetries, tt.restartCount, tt.state)
+	}
+}
+
+func TestTaskManager_Backoff(t *testing.T) {
+	m := &taskManager{restartBackoff: 10 * time.Second, maxRestartBackoff: time.Minute}
+	assert.Equal(t, 10*time.Second, m.backoff(0))
+	assert.Equal(t, 20*time.Second, m.backoff(1))
+	assert.Equal(t, 40*time.Second, m.backoff(2))
+	assert.Equal(t, time.Minute, m.backoff(3))
+	assert.Equal(t, time.Minute, m.backoff(100))
+}
+
+func TestTaskManager_Dependencies(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		ReconcileInterval: time.Hour,
+	}
+	taskStore, err := store.NewFileStore(cfg.DataDir)
+	require.NoError(t, err)
+	exec := newFakeExecutor()
+	mgrIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	mgr := mgrIface.(*taskManager)
+
+	newTask := func(name string, dependsOn ...string) *types.Task {
+		return &types.Task{Name: name, Process: &api.Process{Command: []string{"true"}}, DependsOn: dependsOn}
+	}
+	state := func(name string) types.TaskState {
+		task, err := mgr.Get(ctx, name)
+		require.NoError(t, err)
+		return task.Status.State
+	}
+	// build -> test -> publish, and build -> lint.
+	_, err = mgr.Sync(ctx, []*types.Task{
+		newTask("build"),
+		newTask("test", "build"),
+		newTask("lint", "build"),
+		newTask("publish", "test"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, exec.starts, "only the root is started")
+	assert.Equal(t, types.TaskStateRunning, state("build"))
+	test, err := mgr.Get(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStatePending, test.Status.State)
+	assert.Equal(t, "WaitingForDependencies", test.Status.SubStatuses[0].Reason)
+	assert.Equal(t, 1, mgr.countActiveTasks(), "waiting tasks are not active")
+
+	// The dependents of a succeeded task are started, one at a time.
+	exec.inspect["build"] = &types.Status{State: types.TaskStateSucceeded}
+	mgr.reconcileTasks(ctx)
+	assert.Equal(t, 2, exec.starts)
+	assert.Equal(t, 1, mgr.countActiveTasks())
+	assert.Equal(t, types.TaskStatePending, state("publish"))
+
+	// A failure fails its downstream tasks without starting them.
+	first, second := "test", "lint"
+	if state("lint") == types.TaskStateRunning {
+		exec.inspect["lint"] = &types.Status{State: types.TaskStateSucceeded}
+		mgr.reconcileTasks(ctx)
+		first, second = "lint", "test"
+	}
+	assert.Equal(t, types.TaskStateRunning, state("test"))
+	exec.inspect["test"] = &types.Status{
+		State:       types.TaskStateFailed,
+		SubStatuses: []types.SubStatus{{ExitCode: 1, Reason: "Failed"}},
+	}
+	mgr.reconcileTasks(ctx)
+	assert.Equal(t, 3, exec.starts, "%s and %s started in turn, publish never", first, second)
+	publish, err := mgr.Get(ctx, "publish")
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateFailed, publish.Status.State)
+	assert.Equal(t, "DependencyFailed", publish.Status.SubStatuses[0].Reason)
+	assert.Equal(t, "dependency test failed", publish.Status.SubStatuses[0].Message)
+	assert.NotEqual(t, types.TaskStatePending, state("lint"), "independent branches still run")
+
+	// An unstarted task is recovered as persisted and deleted without touching the executor.
+	recoveredIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	recovered := recoveredIface.(*taskManager)
+	require.NoError(t, recovered.recoverTasks(ctx))
+	task, err := recovered.Get(ctx, "publish")
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateFailed, task.Status.State)
+	require.NoError(t, recovered.Delete(ctx, "publish"))
+	recovered.reconcileTasks(ctx)
+	_, err = recovered.Get(ctx, "publish")
+	assert.Error(t, err)
+}
+
+func TestTaskManager_SyncQueuesTasks(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		ReconcileInterval: time.Hour,
+	}
+	taskStore, err := store.NewFileStore(cfg.DataDir)
+	require.NoError(t, err)
+	exec := newFakeExecutor()
+	mgrIface, err := NewTaskManager(cfg, taskStore, exec)
+	require.NoError(t, err)
+	mgr := mgrIface.(*taskManager)
+
+	tasks, err := mgr.Sync(ctx, []*types.Task{
+		{Name: "a", Process: &api.Process{Command: []string{"true"}}},
+		{Name: "b", Process: &api.Process{Command: []string{"true"}}},
+	})
+	require.NoError(t, err, "tasks beyond the concurrency limit are queued, not rejected")
+	require.Len(t, tasks, 2)
+	assert.Equal(t, 1, exec.starts)
+
+	for _, task := range tasks {
+		if task.Status.State == types.TaskStateRunning {
+			exec.inspect[task.Name] = &types.Status{State: types.TaskStateSucceeded}
+		} else {
+			assert.Equal(t, "Queued", task.Status.SubStatuses[0].Reason)
+		}
+	}
+	mgr.reconcileTasks(ctx)
+	assert.Equal(t, 2, exec.starts, "the queued task starts once the slot is free")
+}
@@ -89,3 +89,14 @@ func (e *compositeExecutor) Stop(ctx context.Context, task *types.Task) error {
 	}
 	return delegate.Stop(ctx, task)
 }
+
+func (e *compositeExecutor) Cleanup(ctx context.Context, task *types.Task) error {
+	delegate, err := e.getDelegate(task)
+	if err != nil {
+		return err
+	}
+	if cleaner, ok := delegate.(Cleaner); ok {
+		return cleaner.Cleanup(ctx, task)
+	}
+	return nil
+}
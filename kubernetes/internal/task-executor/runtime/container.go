@@ -16,40 +16,317 @@ package runtime
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/config"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/utils"
+)
+
+const (
+	// LabelTaskName and LabelContainerName are set on every container started for a task.
+	LabelTaskName      = "sandbox.opensandbox.io/task"
+	LabelContainerName = "sandbox.opensandbox.io/container"
+
+	defaultStopGracePeriod   = 10 * time.Second
+	defaultStopPollInterval  = 500 * time.Millisecond
+	maxContainerIDLength     = 76
+	containerLogSuffix       = ".log"
+	containerReasonCompleted = "Completed"
+	containerReasonError     = "Error"
+	containerReasonCreated   = "ContainerCreated"
+	containerReasonNotFound  = "ContainerNotFound"
 )
 
+// containerIDPattern is the identifier format accepted by containerd.
+var containerIDPattern = regexp.MustCompile(`^[A-Za-z0-9]+(?:[._-][A-Za-z0-9]+)*$`)
+
+// containerExecutor runs the containers of a task's PodTemplateSpec on a container runtime.
 type containerExecutor struct {
-	config *config.Config
+	config       *config.Config
+	rootDir      string
+	runtime      ContainerRuntime
+	pollInterval time.Duration
 }
 
-// newContainerExecutor creates a new container-based task executor.
-// This is a placeholder implementation - container mode is not yet supported.
+// newContainerExecutor creates a container-based task executor backed by containerd.
 func newContainerExecutor(cfg *config.Config) (Executor, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
+	return newContainerExecutorWithRuntime(cfg, newContainerdRuntime(cfg.CRISocket, cfg.ContainerdNamespace)), nil
+}
 
+func newContainerExecutorWithRuntime(cfg *config.Config, rt ContainerRuntime) *containerExecutor {
 	return &containerExecutor{
-		config: cfg,
-	}, nil
+		config:       cfg,
+		rootDir:      cfg.DataDir,
+		runtime:      rt,
+		pollInterval: defaultStopPollInterval,
+	}
+}
+
+// containerID returns the runtime ID of a task container. Names that containerd does not accept
+// are replaced by a hash.
+func containerID(taskName, containerName string) string {
+	id := taskName + "_" + containerName
+	if len(id) <= maxContainerIDLength && containerIDPattern.MatchString(id) {
+		return id
+	}
+	sum := sha256.Sum256([]byte(taskName + "/" + containerName))
+	return "task-" + hex.EncodeToString(sum[:16])
+}
+
+func validatePodTemplate(task *types.Task) error {
+	if task.PodTemplateSpec == nil {
+		return fmt.Errorf("podTemplateSpec is required for container executor (task name: %s)", task.Name)
+	}
+	spec := &task.PodTemplateSpec.Spec
+	if len(spec.Containers) == 0 {
+		return fmt.Errorf("no containers specified in podTemplateSpec (task name: %s)", task.Name)
+	}
+	if len(spec.InitContainers) > 0 {
+		return fmt.Errorf("init containers are not supported in container mode (task name: %s)", task.Name)
+	}
+	for _, c := range spec.Containers {
+		if c.Image == "" {
+			return fmt.Errorf("container %s has no image (task name: %s)", c.Name, task.Name)
+		}
+		if len(c.EnvFrom) > 0 {
+			return fmt.Errorf("container %s: envFrom is not supported in container mode (task name: %s)", c.Name, task.Name)
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom != nil {
+				return fmt.Errorf("container %s: env %s valueFrom is not supported in container mode (task name: %s)", c.Name, env.Name, task.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *containerExecutor) containerSpec(task *types.Task, taskDir string, c *corev1.Container) *ContainerSpec {
+	spec := &ContainerSpec{
+		ID:         containerID(task.Name, c.Name),
+		Image:      c.Image,
+		Command:    c.Command,
+		Args:       c.Args,
+		WorkingDir: c.WorkingDir,
+		Labels: map[string]string{
+			LabelTaskName:      task.Name,
+			LabelContainerName: c.Name,
+		},
+		LogPath: filepath.Join(taskDir, c.Name+containerLogSuffix),
+	}
+	for _, env := range c.Env {
+		if env.Name != "" {
+			spec.Env = append(spec.Env, fmt.Sprintf("%s=%s", env.Name, env.Value))
+		}
+	}
+	return spec
 }
 
-// Start is not implemented for container mode yet.
+// Start runs every container of the task. Containers left over from a previous start are
+// replaced. If a container fails to start, the containers already started are removed.
 func (e *containerExecutor) Start(ctx context.Context, task *types.Task) error {
-	return errors.New("container mode is not implemented yet - use process mode instead")
+	if task == nil {
+		return fmt.Errorf("task cannot be nil")
+	}
+	if err := validatePodTemplate(task); err != nil {
+		return err
+	}
+	taskDir, err := utils.SafeJoin(e.rootDir, task.Name)
+	if err != nil {
+		return fmt.Errorf("invalid task name: %w", err)
+	}
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		return fmt.Errorf("failed to create task directory: %w", err)
+	}
+
+	var started []string
+	for i := range task.PodTemplateSpec.Spec.Containers {
+		spec := e.containerSpec(task, taskDir, &task.PodTemplateSpec.Spec.Containers[i])
+		err := e.runtime.Run(ctx, spec)
+		if errors.Is(err, ErrContainerExists) {
+			klog.InfoS("Replacing stale container", "name", task.Name, "id", spec.ID)
+			if err = e.runtime.Remove(ctx, spec.ID); err == nil {
+				err = e.runtime.Run(ctx, spec)
+			}
+		}
+		if err != nil {
+			for _, id := range started {
+				if rmErr := e.runtime.Remove(ctx, id); rmErr != nil {
+					klog.ErrorS(rmErr, "failed to remove container after start failure", "name", task.Name, "id", id)
+				}
+			}
+			return fmt.Errorf("failed to start container %s: %w", spec.Labels[LabelContainerName], err)
+		}
+		klog.InfoS("Task container started", "name", task.Name, "container", spec.Labels[LabelContainerName], "id", spec.ID)
+		started = append(started, spec.ID)
+	}
+	return nil
 }
 
-// Inspect is not implemented for container mode yet.
+// Inspect reports one sub status per container. The task is Pending until a container exists,
+// Running while any container runs, and Succeeded once all containers exited with code 0.
 func (e *containerExecutor) Inspect(ctx context.Context, task *types.Task) (*types.Status, error) {
-	return nil, errors.New("container mode is not implemented yet - use process mode instead")
+	if task == nil {
+		return nil, fmt.Errorf("task cannot be nil")
+	}
+	if task.PodTemplateSpec == nil {
+		return nil, fmt.Errorf("podTemplateSpec is required for container executor (task name: %s)", task.Name)
+	}
+	taskDir, err := utils.SafeJoin(e.rootDir, task.Name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task name: %w", err)
+	}
+
+	status := &types.Status{}
+	var missing, created, running, failed int
+	var startedAt *time.Time
+	for _, c := range task.PodTemplateSpec.Spec.Containers {
+		sub := types.SubStatus{Name: c.Name}
+		state, err := e.runtime.Inspect(ctx, containerID(task.Name, c.Name))
+		if errors.Is(err, ErrContainerNotFound) {
+			missing++
+			sub.Reason = containerReasonNotFound
+			status.SubStatuses = append(status.SubStatuses, sub)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container %s: %w", c.Name, err)
+		}
+		sub.StartedAt = state.StartedAt
+		if state.StartedAt != nil && (startedAt == nil || state.StartedAt.Before(*startedAt)) {
+			startedAt = state.StartedAt
+		}
+		switch state.Status {
+		case ContainerExited:
+			sub.ExitCode = state.ExitCode
+			sub.FinishedAt = state.FinishedAt
+			if sub.FinishedAt == nil {
+				now := time.Now()
+				sub.FinishedAt = &now
+			}
+			sub.Reason = containerReasonCompleted
+			if state.ExitCode != 0 {
+				sub.Reason = containerReasonError
+				sub.Message = terminationMessage(filepath.Join(taskDir, c.Name+containerLogSuffix))
+				failed++
+			}
+		case ContainerRunning:
+			running++
+		default:
+			created++
+			sub.Reason = containerReasonCreated
+		}
+		status.SubStatuses = append(status.SubStatuses, sub)
+	}
+
+	total := len(task.PodTemplateSpec.Spec.Containers)
+	switch {
+	case missing == total:
+		status.State = types.TaskStatePending
+	case running > 0:
+		status.State = types.TaskStateRunning
+		if deadline := task.PodTemplateSpec.Spec.ActiveDeadlineSeconds; deadline != nil && startedAt != nil &&
+			time.Since(*startedAt) > time.Duration(*deadline)*time.Second {
+			status.State = types.TaskStateTimeout
+			for i := range status.SubStatuses {
+				if status.SubStatuses[i].StartedAt != nil && status.SubStatuses[i].FinishedAt == nil {
+					status.SubStatuses[i].Reason = "TaskTimeout"
+					status.SubStatuses[i].Message = fmt.Sprintf("Task exceeded active deadline of %d seconds", *deadline)
+				}
+			}
+		}
+	case created > 0:
+		status.State = types.TaskStatePending
+	case failed > 0 || missing > 0:
+		status.State = types.TaskStateFailed
+	default:
+		status.State = types.TaskStateSucceeded
+	}
+	return status, nil
 }
 
-// Stop is not implemented for container mode yet.
+// Stop sends SIGTERM to the task containers and SIGKILL to those still running after the
+// termination grace period. Containers are kept so that their exit status can be inspected.
 func (e *containerExecutor) Stop(ctx context.Context, task *types.Task) error {
-	return errors.New("container mode is not implemented yet - use process mode instead")
+	if task == nil || task.PodTemplateSpec == nil {
+		return nil
+	}
+	ids := e.containerIDs(task)
+	for _, id := range ids {
+		if err := e.runtime.Kill(ctx, id, syscall.SIGTERM); err != nil {
+			klog.ErrorS(err, "Failed to send SIGTERM to container", "name", task.Name, "id", id)
+		}
+	}
+
+	grace := defaultStopGracePeriod
+	if seconds := task.PodTemplateSpec.Spec.TerminationGracePeriodSeconds; seconds != nil {
+		grace = time.Duration(*seconds) * time.Second
+	}
+	deadline := time.Now().Add(grace)
+	for {
+		running := e.runningContainers(ctx, ids)
+		if len(running) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			klog.InfoS("Containers did not exit after grace period, sending SIGKILL", "name", task.Name, "ids", running)
+			var errs []error
+			for _, id := range running {
+				errs = append(errs, e.runtime.Kill(ctx, id, syscall.SIGKILL))
+			}
+			return errors.Join(errs...)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(e.pollInterval):
+		}
+	}
+}
+
+// Cleanup removes the task containers.
+func (e *containerExecutor) Cleanup(ctx context.Context, task *types.Task) error {
+	if task == nil || task.PodTemplateSpec == nil {
+		return nil
+	}
+	var errs []error
+	for _, id := range e.containerIDs(task) {
+		if err := e.runtime.Remove(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove container %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *containerExecutor) containerIDs(task *types.Task) []string {
+	ids := make([]string, 0, len(task.PodTemplateSpec.Spec.Containers))
+	for _, c := range task.PodTemplateSpec.Spec.Containers {
+		ids = append(ids, containerID(task.Name, c.Name))
+	}
+	return ids
+}
+
+func (e *containerExecutor) runningContainers(ctx context.Context, ids []string) []string {
+	var running []string
+	for _, id := range ids {
+		state, err := e.runtime.Inspect(ctx, id)
+		if err == nil && state.Status == ContainerRunning {
+			running = append(running, id)
+		}
+	}
+	return running
 }
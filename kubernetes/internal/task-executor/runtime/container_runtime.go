@@ -0,0 +1,72 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrContainerExists is returned by ContainerRuntime.Run when a container with the same ID exists.
+	ErrContainerExists = errors.New("container already exists")
+	// ErrContainerNotFound is returned when the container does not exist.
+	ErrContainerNotFound = errors.New("container not found")
+)
+
+// ContainerStatus is the lifecycle phase of a container.
+type ContainerStatus string
+
+const (
+	// ContainerCreated means the container exists but its process has not started.
+	ContainerCreated ContainerStatus = "Created"
+	ContainerRunning ContainerStatus = "Running"
+	ContainerExited  ContainerStatus = "Exited"
+)
+
+// ContainerSpec describes a container to run.
+type ContainerSpec struct {
+	ID    string
+	Image string
+	// Command overrides the image entrypoint, Args overrides the image cmd.
+	Command    []string
+	Args       []string
+	Env        []string
+	WorkingDir string
+	Labels     map[string]string
+	// LogPath is the file that receives the container's stdout and stderr.
+	LogPath string
+}
+
+// ContainerState is the observed state of a container.
+type ContainerState struct {
+	Status     ContainerStatus
+	ExitCode   int
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// ContainerRuntime is the subset of a container runtime used by the container executor.
+type ContainerRuntime interface {
+	// Run creates the container and starts its process.
+	Run(ctx context.Context, spec *ContainerSpec) error
+	Inspect(ctx context.Context, id string) (*ContainerState, error)
+	// Kill sends a signal to the container process. It is a no-op if the process has exited.
+	Kill(ctx context.Context, id string, signal syscall.Signal) error
+	// Remove kills the container process if needed and deletes the container.
+	Remove(ctx context.Context, id string) error
+}
@@ -0,0 +1,345 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/config"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+)
+
+type fakeContainer struct {
+	spec    ContainerSpec
+	state   ContainerState
+	signals []syscall.Signal
+	// ignoreTerm keeps the container running on SIGTERM.
+	ignoreTerm bool
+}
+
+// fakeRuntime is an in-memory ContainerRuntime.
+type fakeRuntime struct {
+	mu         sync.Mutex
+	containers map[string]*fakeContainer
+	runErr     map[string]error
+	runs       int
+}
+
+func newFakeRuntime() *fakeRuntime {
+	return &fakeRuntime{containers: map[string]*fakeContainer{}, runErr: map[string]error{}}
+}
+
+func (r *fakeRuntime) Run(_ context.Context, spec *ContainerSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs++
+	if err := r.runErr[spec.ID]; err != nil {
+		return err
+	}
+	if _, ok := r.containers[spec.ID]; ok {
+		return ErrContainerExists
+	}
+	now := time.Now()
+	r.containers[spec.ID] = &fakeContainer{spec: *spec, state: ContainerState{Status: ContainerRunning, StartedAt: &now}}
+	return nil
+}
+
+func (r *fakeRuntime) Inspect(_ context.Context, id string) (*ContainerState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.containers[id]
+	if !ok {
+		return nil, ErrContainerNotFound
+	}
+	state := c.state
+	return &state, nil
+}
+
+func (r *fakeRuntime) Kill(_ context.Context, id string, signal syscall.Signal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.containers[id]
+	if !ok || c.state.Status != ContainerRunning {
+		return nil
+	}
+	c.signals = append(c.signals, signal)
+	if signal == syscall.SIGTERM && c.ignoreTerm {
+		return nil
+	}
+	r.exitLocked(c, 128+int(signal))
+	return nil
+}
+
+func (r *fakeRuntime) Remove(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.containers, id)
+	return nil
+}
+
+func (r *fakeRuntime) exit(id string, code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exitLocked(r.containers[id], code)
+}
+
+func (r *fakeRuntime) exitLocked(c *fakeContainer, code int) {
+	now := time.Now()
+	c.state.Status = ContainerExited
+	c.state.ExitCode = code
+	c.state.FinishedAt = &now
+}
+
+func (r *fakeRuntime) get(id string) *fakeContainer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.containers[id]
+}
+
+func setupContainerExecutor(t *testing.T) (*containerExecutor, *fakeRuntime) {
+	rt := newFakeRuntime()
+	e := newContainerExecutorWithRuntime(&config.Config{DataDir: t.TempDir()}, rt)
+	e.pollInterval = 10 * time.Millisecond
+	return e, rt
+}
+
+func newContainerTask(name string, containers ...corev1.Container) *types.Task {
+	return &types.Task{
+		Name:            name,
+		PodTemplateSpec: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}},
+	}
+}
+
+func TestContainerID(t *testing.T) {
+	assert.Equal(t, "task-1_main", containerID("task-1", "main"))
+
+	long := containerID(strings.Repeat("a", 80), "main")
+	assert.Len(t, long, len("task-")+32)
+	assert.Regexp(t, containerIDPattern, long)
+	assert.NotEqual(t, long, containerID(strings.Repeat("a", 80), "sidecar"))
+	assert.Regexp(t, containerIDPattern, containerID("bad--name", "main"))
+}
+
+func TestContainerExecutor_StartAndInspect(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	task := newContainerTask("job",
+		corev1.Container{
+			Name:       "main",
+			Image:      "busybox",
+			Command:    []string{"sh", "-c"},
+			Args:       []string{"echo hi"},
+			WorkingDir: "/work",
+			Env:        []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+		},
+		corev1.Container{Name: "sidecar", Image: "nginx"},
+	)
+
+	status, err := e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStatePending, status.State)
+
+	require.NoError(t, e.Start(ctx, task))
+	main := rt.get("job_main")
+	require.NotNil(t, main)
+	assert.Equal(t, "busybox", main.spec.Image)
+	assert.Equal(t, []string{"sh", "-c"}, main.spec.Command)
+	assert.Equal(t, []string{"echo hi"}, main.spec.Args)
+	assert.Equal(t, []string{"FOO=bar"}, main.spec.Env)
+	assert.Equal(t, "/work", main.spec.WorkingDir)
+	assert.Equal(t, filepath.Join(e.rootDir, "job", "main.log"), main.spec.LogPath)
+	assert.Equal(t, map[string]string{LabelTaskName: "job", LabelContainerName: "main"}, main.spec.Labels)
+
+	status, err = e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateRunning, status.State)
+	require.Len(t, status.SubStatuses, 2)
+	assert.Equal(t, "main", status.SubStatuses[0].Name)
+	assert.NotNil(t, status.SubStatuses[0].StartedAt)
+	assert.Nil(t, status.SubStatuses[0].FinishedAt)
+
+	rt.exit("job_main", 0)
+	status, err = e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateRunning, status.State, "sidecar still running")
+	assert.Equal(t, "Completed", status.SubStatuses[0].Reason)
+	assert.NotNil(t, status.SubStatuses[0].FinishedAt)
+
+	rt.exit("job_sidecar", 0)
+	status, err = e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateSucceeded, status.State)
+}
+
+func TestContainerExecutor_InspectFailed(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	task := newContainerTask("job", corev1.Container{Name: "a", Image: "busybox"}, corev1.Container{Name: "b", Image: "busybox"})
+	require.NoError(t, e.Start(ctx, task))
+
+	require.NoError(t, os.WriteFile(rt.get("job_b").spec.LogPath, []byte("starting\nfatal: bad config\n"), 0644))
+	rt.exit("job_a", 0)
+	rt.exit("job_b", 3)
+	status, err := e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateFailed, status.State)
+	assert.Equal(t, 3, status.SubStatuses[1].ExitCode)
+	assert.Equal(t, "Error", status.SubStatuses[1].Reason)
+	assert.Equal(t, "starting\nfatal: bad config", status.SubStatuses[1].Message)
+	assert.Empty(t, status.SubStatuses[0].Message)
+
+	// A container lost after the others exited fails the task.
+	rt.exit("job_b", 0)
+	require.NoError(t, rt.Remove(ctx, "job_b"))
+	status, err = e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateFailed, status.State)
+	assert.Equal(t, "ContainerNotFound", status.SubStatuses[1].Reason)
+}
+
+func TestContainerExecutor_InspectTimeout(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	deadline := int64(60)
+	task := newContainerTask("job", corev1.Container{Name: "main", Image: "busybox"})
+	task.PodTemplateSpec.Spec.ActiveDeadlineSeconds = &deadline
+	require.NoError(t, e.Start(ctx, task))
+
+	status, err := e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateRunning, status.State)
+
+	startedAt := time.Now().Add(-2 * time.Minute)
+	rt.get("job_main").state.StartedAt = &startedAt
+	status, err = e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateTimeout, status.State)
+	assert.Equal(t, "TaskTimeout", status.SubStatuses[0].Reason)
+}
+
+func TestContainerExecutor_StartValidation(t *testing.T) {
+	e, _ := setupContainerExecutor(t)
+	ctx := context.Background()
+
+	assert.Error(t, e.Start(ctx, &types.Task{Name: "job"}))
+	assert.Error(t, e.Start(ctx, newContainerTask("job")))
+	assert.Error(t, e.Start(ctx, newContainerTask("job", corev1.Container{Name: "main"})))
+
+	task := newContainerTask("job", corev1.Container{Name: "main", Image: "busybox"})
+	task.PodTemplateSpec.Spec.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+	assert.ErrorContains(t, e.Start(ctx, task), "init containers")
+
+	task = newContainerTask("job", corev1.Container{Name: "main", Image: "busybox", Env: []corev1.EnvVar{
+		{Name: "NODE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+	}})
+	assert.ErrorContains(t, e.Start(ctx, task), "valueFrom")
+}
+
+func TestContainerExecutor_StartReplacesStaleContainer(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	task := newContainerTask("job", corev1.Container{Name: "main", Image: "busybox"})
+	require.NoError(t, e.Start(ctx, task))
+	rt.exit("job_main", 1)
+
+	require.NoError(t, e.Start(ctx, task))
+	assert.Equal(t, ContainerRunning, rt.get("job_main").state.Status)
+}
+
+func TestContainerExecutor_StartRollsBack(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	rt.runErr["job_b"] = errors.New("image not found")
+	task := newContainerTask("job", corev1.Container{Name: "a", Image: "busybox"}, corev1.Container{Name: "b", Image: "missing"})
+
+	assert.ErrorContains(t, e.Start(ctx, task), "image not found")
+	assert.Nil(t, rt.get("job_a"), "started containers are removed")
+}
+
+func TestContainerExecutor_Stop(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	grace := int64(0)
+	task := newContainerTask("job", corev1.Container{Name: "a", Image: "busybox"}, corev1.Container{Name: "b", Image: "busybox"})
+	task.PodTemplateSpec.Spec.TerminationGracePeriodSeconds = &grace
+	require.NoError(t, e.Start(ctx, task))
+	rt.get("job_b").ignoreTerm = true
+
+	require.NoError(t, e.Stop(ctx, task))
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM}, rt.get("job_a").signals)
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL}, rt.get("job_b").signals)
+
+	status, err := e.Inspect(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, types.TaskStateFailed, status.State)
+	assert.Equal(t, 143, status.SubStatuses[0].ExitCode)
+	assert.Equal(t, 137, status.SubStatuses[1].ExitCode)
+}
+
+func TestContainerExecutor_StopWaitsForGracefulExit(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	task := newContainerTask("job", corev1.Container{Name: "main", Image: "busybox"})
+	require.NoError(t, e.Start(ctx, task))
+	rt.get("job_main").ignoreTerm = true
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		rt.exit("job_main", 0)
+	}()
+	require.NoError(t, e.Stop(ctx, task))
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM}, rt.get("job_main").signals)
+}
+
+func TestContainerExecutor_Cleanup(t *testing.T) {
+	e, rt := setupContainerExecutor(t)
+	ctx := context.Background()
+	task := newContainerTask("job", corev1.Container{Name: "main", Image: "busybox"})
+	require.NoError(t, e.Start(ctx, task))
+
+	require.NoError(t, e.Cleanup(ctx, task))
+	assert.Empty(t, rt.containers)
+	require.NoError(t, e.Cleanup(ctx, task), "cleanup is idempotent")
+}
+
+func TestCompositeExecutor_RoutesContainerTasks(t *testing.T) {
+	ce, rt := setupContainerExecutor(t)
+	pe, err := NewProcessExecutor(ce.config)
+	require.NoError(t, err)
+	e := &compositeExecutor{processExec: pe, containerExec: ce}
+	ctx := context.Background()
+
+	task := newContainerTask("job", corev1.Container{Name: "main", Image: "busybox"})
+	require.NoError(t, e.Start(ctx, task))
+	assert.NotNil(t, rt.get("job_main"))
+	require.NoError(t, e.Cleanup(ctx, task))
+	assert.Nil(t, rt.get("job_main"))
+
+	// Process tasks have nothing to clean up.
+	require.NoError(t, e.Cleanup(ctx, &types.Task{Name: "proc", Process: &api.Process{Command: []string{"true"}}}))
+}
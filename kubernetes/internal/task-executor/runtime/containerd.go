@@ -0,0 +1,218 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
+	"k8s.io/klog/v2"
+)
+
+// labelStartedAt records when the container process was started, containerd does not keep it.
+const labelStartedAt = "sandbox.opensandbox.io/started-at"
+
+// containerdRuntime implements ContainerRuntime with the containerd client.
+type containerdRuntime struct {
+	address   string
+	namespace string
+
+	mu     sync.Mutex
+	client *containerd.Client
+}
+
+// newContainerdRuntime returns a runtime for the containerd socket at address. The connection
+// is made on first use so that process mode keeps working on hosts without containerd.
+func newContainerdRuntime(address, namespace string) *containerdRuntime {
+	return &containerdRuntime{address: address, namespace: namespace}
+}
+
+func (r *containerdRuntime) connect(ctx context.Context) (*containerd.Client, context.Context, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		c, err := containerd.New(r.address, containerd.WithDefaultNamespace(r.namespace))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to containerd at %s: %w", r.address, err)
+		}
+		r.client = c
+	}
+	return r.client, namespaces.WithNamespace(ctx, r.namespace), nil
+}
+
+func (r *containerdRuntime) Run(ctx context.Context, spec *ContainerSpec) error {
+	client, ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	image, err := client.GetImage(ctx, spec.Image)
+	if errdefs.IsNotFound(err) {
+		klog.InfoS("Pulling image", "image", spec.Image)
+		image, err = client.Pull(ctx, spec.Image, containerd.WithPullUnpack)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get image %s: %w", spec.Image, err)
+	}
+
+	specOpts := []oci.SpecOpts{oci.WithImageConfig(image)}
+	switch {
+	case len(spec.Command) > 0:
+		specOpts = append(specOpts, oci.WithProcessArgs(append(spec.Command, spec.Args...)...))
+	case len(spec.Args) > 0:
+		specOpts = append(specOpts, oci.WithImageConfigArgs(image, spec.Args))
+	}
+	if len(spec.Env) > 0 {
+		specOpts = append(specOpts, oci.WithEnv(spec.Env))
+	}
+	if spec.WorkingDir != "" {
+		specOpts = append(specOpts, oci.WithProcessCwd(spec.WorkingDir))
+	}
+
+	container, err := client.NewContainer(ctx, spec.ID,
+		containerd.WithImage(image),
+		containerd.WithNewSnapshot(spec.ID, image),
+		containerd.WithNewSpec(specOpts...),
+		containerd.WithContainerLabels(spec.Labels),
+	)
+	if errdefs.IsAlreadyExists(err) {
+		return ErrContainerExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create container %s: %w", spec.ID, err)
+	}
+
+	task, err := container.NewTask(ctx, cio.LogFile(spec.LogPath))
+	if err == nil {
+		if err = task.Start(ctx); err != nil {
+			_, _ = task.Delete(ctx, containerd.WithProcessKill)
+		}
+	}
+	if err != nil {
+		if delErr := container.Delete(ctx, containerd.WithSnapshotCleanup); delErr != nil {
+			klog.ErrorS(delErr, "failed to delete container after start failure", "id", spec.ID)
+		}
+		return fmt.Errorf("failed to start container %s: %w", spec.ID, err)
+	}
+	if _, err := container.SetLabels(ctx, map[string]string{labelStartedAt: time.Now().Format(time.RFC3339Nano)}); err != nil {
+		klog.ErrorS(err, "failed to record container start time", "id", spec.ID)
+	}
+	return nil
+}
+
+func (r *containerdRuntime) Inspect(ctx context.Context, id string) (*ContainerState, error) {
+	client, ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	container, err := client.LoadContainer(ctx, id)
+	if errdefs.IsNotFound(err) {
+		return nil, ErrContainerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &ContainerState{Status: ContainerCreated}
+	labels, err := container.Labels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if startedAt, err := time.Parse(time.RFC3339Nano, labels[labelStartedAt]); err == nil {
+		state.StartedAt = &startedAt
+	}
+
+	task, err := container.Task(ctx, nil)
+	if errdefs.IsNotFound(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch status.Status {
+	case containerd.Stopped:
+		state.Status = ContainerExited
+		state.ExitCode = int(status.ExitStatus)
+		if !status.ExitTime.IsZero() {
+			finishedAt := status.ExitTime
+			state.FinishedAt = &finishedAt
+		}
+	case containerd.Created:
+	default:
+		state.Status = ContainerRunning
+	}
+	return state, nil
+}
+
+func (r *containerdRuntime) Kill(ctx context.Context, id string, signal syscall.Signal) error {
+	client, ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	container, err := client.LoadContainer(ctx, id)
+	if errdefs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	task, err := container.Task(ctx, nil)
+	if errdefs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := task.Kill(ctx, signal); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to signal container %s: %w", id, err)
+	}
+	return nil
+}
+
+func (r *containerdRuntime) Remove(ctx context.Context, id string) error {
+	client, ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	container, err := client.LoadContainer(ctx, id)
+	if errdefs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	task, err := container.Task(ctx, nil)
+	if err == nil {
+		if _, err := task.Delete(ctx, containerd.WithProcessKill); err != nil && !errdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete task of container %s: %w", id, err)
+		}
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	}
+	if err := container.Delete(ctx, containerd.WithSnapshotCleanup); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to delete container %s: %w", id, err)
+	}
+	return nil
+}
@@ -28,3 +28,9 @@ type Executor interface {
 
 	Stop(ctx context.Context, task *types.Task) error
 }
+
+// Cleaner is implemented by executors that keep runtime resources after a task has terminated.
+// Cleanup is called once the task is deleted and must be idempotent.
+type Cleaner interface {
+	Cleanup(ctx context.Context, task *types.Task) error
+}
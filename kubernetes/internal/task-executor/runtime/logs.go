@@ -0,0 +1,79 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/utils"
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+)
+
+const (
+	// terminationLogTailBytes and terminationLogTailLines bound the log tail reported as the
+	// message of a failed task.
+	terminationLogTailBytes = 1024
+	terminationLogTailLines = 10
+)
+
+// LogPath returns the log file of a task. Process tasks have a file per stream; container tasks
+// have a file per container holding both streams.
+func LogPath(rootDir string, task *types.Task, stream, container string) (string, error) {
+	taskDir, err := utils.SafeJoin(rootDir, task.Name)
+	if err != nil {
+		return "", fmt.Errorf("invalid task name: %w", err)
+	}
+	if task.Process != nil {
+		if container != "" {
+			return "", fmt.Errorf("process task %s has no containers", task.Name)
+		}
+		switch stream {
+		case "", api.LogStreamStdout:
+			return filepath.Join(taskDir, StdoutFile), nil
+		case api.LogStreamStderr:
+			return filepath.Join(taskDir, StderrFile), nil
+		default:
+			return "", fmt.Errorf("unknown log stream %q", stream)
+		}
+	}
+	if task.PodTemplateSpec == nil || len(task.PodTemplateSpec.Spec.Containers) == 0 {
+		return "", fmt.Errorf("task %s has no logs", task.Name)
+	}
+	if stream != "" {
+		return "", fmt.Errorf("container logs combine stdout and stderr, stream cannot be selected")
+	}
+	if container == "" {
+		container = task.PodTemplateSpec.Spec.Containers[0].Name
+	}
+	for _, c := range task.PodTemplateSpec.Spec.Containers {
+		if c.Name == container {
+			return filepath.Join(taskDir, c.Name+containerLogSuffix), nil
+		}
+	}
+	return "", fmt.Errorf("task %s has no container %s", task.Name, container)
+}
+
+// terminationMessage returns the tail of a log file as the message of a failed task.
+func terminationMessage(path string) string {
+	tail, err := utils.ReadTail(path, terminationLogTailBytes, terminationLogTailLines)
+	if err != nil {
+		klog.ErrorS(err, "failed to read log tail", "path", path)
+	}
+	return tail
+}
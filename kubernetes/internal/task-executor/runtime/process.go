@@ -60,6 +60,10 @@ func (e *processExecutor) Start(ctx context.Context, task *types.Task) error {
 	}
 	pidPath := filepath.Join(taskDir, PidFile)
 	exitPath := filepath.Join(taskDir, ExitFile)
+	// The exit file of a previous attempt would make a restarted task look terminated.
+	if err := os.Remove(exitPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove exit file: %w", err)
+	}
 
 	var cmdList []string
 	if task.Process != nil {
@@ -239,6 +243,7 @@ func (e *processExecutor) Inspect(ctx context.Context, task *types.Task) (*types
 		} else {
 			status.State = types.TaskStateFailed
 			subStatus.Reason = "Failed"
+			subStatus.Message = terminationMessage(filepath.Join(taskDir, StderrFile))
 		}
 
 		if pidFileInfo, err := os.Stat(pidPath); err == nil {
@@ -16,6 +16,7 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -154,7 +155,7 @@ func TestProcessExecutor_Failure(t *testing.T) {
 	task := &types.Task{
 		Name: "failing-task",
 		Process: &api.Process{
-			Command: []string{"/bin/sh", "-c", "exit 1"},
+			Command: []string{"/bin/sh", "-c", "echo starting; echo 'no such file' >&2; exit 1"},
 		},
 	}
 	taskDir, err := utils.SafeJoin(pExecutor.rootDir, task.Name)
@@ -178,6 +179,41 @@ func TestProcessExecutor_Failure(t *testing.T) {
 	if status.SubStatuses[0].ExitCode != 1 {
 		t.Errorf("Exit code should be 1, got %d", status.SubStatuses[0].ExitCode)
 	}
+	assert.Equal(t, "no such file", status.SubStatuses[0].Message, "message should hold the stderr tail")
+}
+
+func TestProcessExecutor_Restart(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+
+	executor, _ := setupTestExecutor(t)
+	pExecutor := executor.(*processExecutor)
+	ctx := context.Background()
+
+	taskDir, err := utils.SafeJoin(pExecutor.rootDir, "restart-task")
+	assert.Nil(t, err)
+	os.MkdirAll(taskDir, 0755)
+	// The first attempt fails, the second one keeps running.
+	marker := filepath.Join(taskDir, "marker")
+	task := &types.Task{
+		Name: "restart-task",
+		Process: &api.Process{
+			Command: []string{"/bin/sh", "-c", fmt.Sprintf("[ -f %s ] || { touch %s; exit 1; }; sleep 10", marker, marker)},
+		},
+	}
+
+	assert.NoError(t, executor.Start(ctx, task))
+	time.Sleep(200 * time.Millisecond)
+	status, err := executor.Inspect(ctx, task)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskStateFailed, status.State)
+
+	assert.NoError(t, executor.Start(ctx, task))
+	defer executor.Stop(ctx, task)
+	status, err = executor.Inspect(ctx, task)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskStateRunning, status.State, "the exit code of the previous attempt must not be reported")
 }
 
 func TestProcessExecutor_InvalidArgs(t *testing.T) {
@@ -39,6 +39,8 @@ type ErrorResponse struct {
 type Handler struct {
 	manager manager.TaskManager
 	config  *config.Config
+	// logFollowInterval is how often a followed log is polled for new output.
+	logFollowInterval time.Duration
 }
 
 func NewHandler(mgr manager.TaskManager, cfg *config.Config) *Handler {
@@ -49,8 +51,9 @@ func NewHandler(mgr manager.TaskManager, cfg *config.Config) *Handler {
 		klog.Warning("Config is nil, handler may not work properly")
 	}
 	return &Handler{
-		manager: mgr,
-		config:  cfg,
+		manager:           mgr,
+		config:            cfg,
+		logFollowInterval: defaultLogFollowInterval,
 	}
 }
 
@@ -235,6 +238,9 @@ func (h *Handler) convertAPIToInternalTask(apiTask *api.Task) *types.Task {
 		Name:            apiTask.Name,
 		Process:         apiTask.Process,
 		PodTemplateSpec: apiTask.PodTemplateSpec,
+		RestartPolicy:   apiTask.RestartPolicy,
+		MaxRetries:      apiTask.MaxRetries,
+		DependsOn:       apiTask.DependsOn,
 	}
 	task.Status = types.Status{
 		State: types.TaskStatePending,
@@ -252,6 +258,14 @@ func convertInternalToAPITask(task *types.Task) *api.Task {
 		Name:            task.Name,
 		Process:         task.Process,
 		PodTemplateSpec: task.PodTemplateSpec,
+		RestartPolicy:   task.RestartPolicy,
+		MaxRetries:      task.MaxRetries,
+		DependsOn:       task.DependsOn,
+		RestartCount:    task.Status.RestartCount,
+	}
+	lastTermination := make(map[string]types.SubStatus, len(task.Status.LastTermination))
+	for _, sub := range task.Status.LastTermination {
+		lastTermination[sub.Name] = sub
 	}
 
 	if task.Process != nil && len(task.Status.SubStatuses) > 0 {
@@ -291,6 +305,9 @@ func convertInternalToAPITask(task *types.Task) *api.Task {
 			}
 		}
 		apiTask.ProcessStatus = apiStatus
+		if last, ok := lastTermination[""]; ok {
+			apiTask.LastTerminationState = &api.ProcessStatus{Terminated: lastTerminated(last)}
+		}
 	}
 
 	if task.PodTemplateSpec != nil {
@@ -311,7 +328,18 @@ func convertInternalToAPITask(task *types.Task) *api.Task {
 
 		for _, sub := range task.Status.SubStatuses {
 			cs := corev1.ContainerStatus{
-				Name: sub.Name,
+				Name:         sub.Name,
+				RestartCount: task.Status.RestartCount,
+			}
+			if last, ok := lastTermination[sub.Name]; ok {
+				t := lastTerminated(last)
+				cs.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+					ExitCode:   t.ExitCode,
+					Reason:     t.Reason,
+					Message:    t.Message,
+					StartedAt:  t.StartedAt,
+					FinishedAt: t.FinishedAt,
+				}
 			}
 			if sub.FinishedAt != nil {
 				cs.State.Terminated = &corev1.ContainerStateTerminated{
@@ -381,3 +409,19 @@ func convertInternalToAPITask(task *types.Task) *api.Task {
 
 	return apiTask
 }
+
+// lastTerminated converts the sub status of a previous attempt of a restarted task.
+func lastTerminated(sub types.SubStatus) *api.Terminated {
+	term := &api.Terminated{
+		ExitCode: int32(sub.ExitCode),
+		Reason:   sub.Reason,
+		Message:  sub.Message,
+	}
+	if sub.StartedAt != nil {
+		term.StartedAt = metav1.NewTime(*sub.StartedAt)
+	}
+	if sub.FinishedAt != nil {
+		term.FinishedAt = metav1.NewTime(*sub.FinishedAt)
+	}
+	return term
+}
@@ -26,7 +26,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/config"
 	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
@@ -427,3 +429,64 @@ func TestConvertInternalToAPITask_Timeout(t *testing.T) {
 		assert.Equal(t, later.Unix(), apiTask.ProcessStatus.Terminated.FinishedAt.Unix())
 	})
 }
+
+func TestConvertInternalToAPITask_Restarted(t *testing.T) {
+	now := time.Now()
+	lastFinished := now.Add(-time.Minute)
+
+	t.Run("Process Task", func(t *testing.T) {
+		task := &types.Task{
+			Name:          "proc-task",
+			Process:       &api.Process{Command: []string{"ls"}},
+			RestartPolicy: api.RestartPolicyOnFailure,
+			MaxRetries:    ptr.To[int32](3),
+			Status: types.Status{
+				State:        types.TaskStateRunning,
+				SubStatuses:  []types.SubStatus{{StartedAt: &now}},
+				RestartCount: 2,
+				LastTermination: []types.SubStatus{
+					{ExitCode: 1, Reason: "Failed", Message: "boom", FinishedAt: &lastFinished},
+				},
+			},
+		}
+
+		apiTask := convertInternalToAPITask(task)
+		assert.Equal(t, api.RestartPolicyOnFailure, apiTask.RestartPolicy)
+		assert.Equal(t, int32(3), *apiTask.MaxRetries)
+		assert.Equal(t, int32(2), apiTask.RestartCount)
+		assert.NotNil(t, apiTask.ProcessStatus.Running)
+		require.NotNil(t, apiTask.LastTerminationState)
+		require.NotNil(t, apiTask.LastTerminationState.Terminated)
+		assert.Equal(t, int32(1), apiTask.LastTerminationState.Terminated.ExitCode)
+		assert.Equal(t, "boom", apiTask.LastTerminationState.Terminated.Message)
+		assert.Equal(t, lastFinished.Unix(), apiTask.LastTerminationState.Terminated.FinishedAt.Unix())
+	})
+
+	t.Run("Pod Task", func(t *testing.T) {
+		task := &types.Task{
+			Name:            "pod-task",
+			PodTemplateSpec: &corev1.PodTemplateSpec{},
+			Status: types.Status{
+				State: types.TaskStatePending,
+				SubStatuses: []types.SubStatus{
+					{Name: "c1", Reason: "BackOff"},
+					{Name: "c2", Reason: "BackOff"},
+				},
+				RestartCount: 1,
+				LastTermination: []types.SubStatus{
+					{Name: "c1", ExitCode: 2, Reason: "Error", FinishedAt: &lastFinished},
+				},
+			},
+		}
+
+		apiTask := convertInternalToAPITask(task)
+		assert.Nil(t, apiTask.LastTerminationState)
+		require.Len(t, apiTask.PodStatus.ContainerStatuses, 2)
+		c1, c2 := apiTask.PodStatus.ContainerStatuses[0], apiTask.PodStatus.ContainerStatuses[1]
+		assert.Equal(t, int32(1), c1.RestartCount)
+		assert.Equal(t, "BackOff", c1.State.Waiting.Reason)
+		require.NotNil(t, c1.LastTerminationState.Terminated)
+		assert.Equal(t, int32(2), c1.LastTerminationState.Terminated.ExitCode)
+		assert.Nil(t, c2.LastTerminationState.Terminated)
+	})
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/runtime"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/utils"
+)
+
+const (
+	// HeaderLogOffset is the byte offset in the log file of the first byte of a logs response.
+	HeaderLogOffset = "X-Log-Offset"
+
+	defaultLogFollowInterval = 500 * time.Millisecond
+)
+
+type logRequest struct {
+	stream    string
+	container string
+	offset    int64
+	tailLines int64
+	follow    bool
+}
+
+func parseLogRequest(r *http.Request) (*logRequest, error) {
+	query := r.URL.Query()
+	req := &logRequest{
+		stream:    query.Get("stream"),
+		container: query.Get("container"),
+		tailLines: -1,
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid offset %q", v)
+		}
+		req.offset = offset
+	}
+	if v := query.Get("tail"); v != "" {
+		if query.Has("offset") {
+			return nil, fmt.Errorf("offset and tail cannot be combined")
+		}
+		tail, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || tail < 0 {
+			return nil, fmt.Errorf("invalid tail %q", v)
+		}
+		req.tailLines = tail
+	}
+	if v := query.Get("follow"); v != "" {
+		follow, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid follow %q", v)
+		}
+		req.follow = follow
+	}
+	return req, nil
+}
+
+// GetTaskLogs writes the logs of a task as plain text. The X-Log-Offset header holds the file
+// offset of the first byte returned, so that a client can resume from offset plus bytes read.
+// With follow, new output is streamed until the task finishes or the client goes away.
+func (h *Handler) GetTaskLogs(w http.ResponseWriter, r *http.Request) {
+	if h.manager == nil {
+		writeError(w, http.StatusInternalServerError, "task manager not initialized")
+		return
+	}
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		writeError(w, http.StatusBadRequest, "task id is required")
+		return
+	}
+	logReq, err := parseLogRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	task, err := h.manager.Get(r.Context(), taskID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("task not found: %v", err))
+		return
+	}
+	path, err := runtime.LogPath(h.config.DataDir, task, logReq.stream, logReq.container)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		klog.ErrorS(err, "failed to open task log", "id", taskID, "path", path)
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to open log: %v", err))
+		return
+	}
+	// A log that does not exist yet is empty.
+	offset := int64(0)
+	if f != nil {
+		defer f.Close()
+		if offset, err = seekLog(f, logReq); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read log: %v", err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set(HeaderLogOffset, strconv.FormatInt(offset, 10))
+	w.WriteHeader(http.StatusOK)
+	if !logReq.follow {
+		if f != nil {
+			_, _ = io.Copy(w, f)
+		}
+		return
+	}
+
+	// A followed stream outlives the server write timeout.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+	_ = rc.Flush()
+	if f == nil {
+		if f = h.waitForLog(r.Context(), taskID, path); f == nil {
+			return
+		}
+		defer f.Close()
+	}
+	for {
+		if _, err := io.Copy(w, f); err != nil {
+			return
+		}
+		_ = rc.Flush()
+		if h.taskFinished(r.Context(), taskID) {
+			_, _ = io.Copy(w, f)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(h.logFollowInterval):
+		}
+	}
+}
+
+// seekLog moves f to the first byte requested and returns its offset.
+func seekLog(f *os.File, logReq *logRequest) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	offset := min(logReq.offset, info.Size())
+	if logReq.tailLines >= 0 {
+		if offset, err = utils.TailOffset(f, info.Size(), logReq.tailLines); err != nil {
+			return 0, err
+		}
+	}
+	_, err = f.Seek(offset, io.SeekStart)
+	return offset, err
+}
+
+// waitForLog waits for a log file to be created. It returns nil if the task finishes first.
+func (h *Handler) waitForLog(ctx context.Context, taskID, path string) *os.File {
+	for {
+		if f, err := os.Open(path); err == nil {
+			return f
+		}
+		if h.taskFinished(ctx, taskID) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(h.logFollowInterval):
+		}
+	}
+}
+
+// taskFinished reports whether the task will not write more output.
+func (h *Handler) taskFinished(ctx context.Context, taskID string) bool {
+	task, err := h.manager.Get(ctx, taskID)
+	if err != nil {
+		return true
+	}
+	switch task.Status.State {
+	case types.TaskStateSucceeded, types.TaskStateFailed, types.TaskStateNotFound:
+		return true
+	}
+	return false
+}
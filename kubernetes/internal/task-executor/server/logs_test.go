@@ -0,0 +1,184 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/config"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/task-executor/types"
+	api "github.com/alibaba/OpenSandbox/sandbox-k8s/pkg/task-executor"
+)
+
+// lockedTaskManager guards MockTaskManager so that task state can change while a log is followed.
+type lockedTaskManager struct {
+	*MockTaskManager
+	mu sync.Mutex
+}
+
+func (m *lockedTaskManager) Get(ctx context.Context, id string) (*types.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task, err := m.MockTaskManager.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (m *lockedTaskManager) setState(id string, state types.TaskState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tasks[id].Status.State = state
+}
+
+func setupLogsTest(t *testing.T, tasks ...*types.Task) (*api.Client, *lockedTaskManager, string) {
+	mgr := &lockedTaskManager{MockTaskManager: NewMockTaskManager()}
+	for _, task := range tasks {
+		mgr.tasks[task.Name] = task
+	}
+	cfg := &config.Config{DataDir: t.TempDir()}
+	h := NewHandler(mgr, cfg)
+	h.logFollowInterval = 10 * time.Millisecond
+	server := httptest.NewServer(NewRouter(h))
+	t.Cleanup(server.Close)
+	return api.NewClient(server.URL), mgr, cfg.DataDir
+}
+
+func writeTaskLog(t *testing.T, dataDir, task, file, content string) string {
+	path := filepath.Join(dataDir, task, file)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func readLogs(t *testing.T, c *api.Client, name string, opts *api.LogOptions) string {
+	rc, err := c.GetLogs(context.Background(), name, opts)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGetTaskLogs(t *testing.T) {
+	task := &types.Task{
+		Name:    "task",
+		Process: &api.Process{Command: []string{"true"}},
+		Status:  types.Status{State: types.TaskStateFailed},
+	}
+	c, _, dataDir := setupLogsTest(t, task)
+	writeTaskLog(t, dataDir, "task", "stdout.log", "one\ntwo\nthree\n")
+	writeTaskLog(t, dataDir, "task", "stderr.log", "boom\n")
+
+	assert.Equal(t, "one\ntwo\nthree\n", readLogs(t, c, "task", nil))
+	assert.Equal(t, "boom\n", readLogs(t, c, "task", &api.LogOptions{Stream: api.LogStreamStderr}))
+	assert.Equal(t, "two\nthree\n", readLogs(t, c, "task", &api.LogOptions{TailLines: ptr.To[int64](2)}))
+	assert.Equal(t, "three\n", readLogs(t, c, "task", &api.LogOptions{Offset: ptr.To[int64](8)}))
+	assert.Empty(t, readLogs(t, c, "task", &api.LogOptions{Offset: ptr.To[int64](100)}))
+
+	_, err := c.GetLogs(context.Background(), "missing", nil)
+	assert.ErrorIs(t, err, api.ErrTaskNotFound)
+	_, err = c.GetLogs(context.Background(), "task", &api.LogOptions{Stream: "stdin"})
+	assert.ErrorContains(t, err, "status=400")
+	_, err = c.GetLogs(context.Background(), "task", &api.LogOptions{Offset: ptr.To[int64](1), TailLines: ptr.To[int64](1)})
+	assert.ErrorContains(t, err, "status=400")
+}
+
+func TestGetTaskLogs_OffsetHeader(t *testing.T) {
+	task := &types.Task{Name: "task", Process: &api.Process{Command: []string{"true"}}}
+	mgr := NewMockTaskManager()
+	mgr.tasks["task"] = task
+	h := NewHandler(mgr, &config.Config{DataDir: t.TempDir()})
+	writeTaskLog(t, h.config.DataDir, "task", "stdout.log", "one\ntwo\n")
+
+	req := httptest.NewRequest("GET", "/tasks/task/logs?tail=1", nil)
+	w := httptest.NewRecorder()
+	NewRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "4", w.Header().Get(HeaderLogOffset))
+	assert.Equal(t, "two\n", w.Body.String())
+
+	// The log of a task that has not started yet is empty.
+	require.NoError(t, os.Remove(filepath.Join(h.config.DataDir, "task", "stdout.log")))
+	w = httptest.NewRecorder()
+	NewRouter(h).ServeHTTP(w, httptest.NewRequest("GET", "/tasks/task/logs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get(HeaderLogOffset))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestGetTaskLogs_Container(t *testing.T) {
+	task := &types.Task{
+		Name: "task",
+		PodTemplateSpec: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "main", Image: "busybox"},
+			{Name: "sidecar", Image: "busybox"},
+		}}},
+	}
+	c, _, dataDir := setupLogsTest(t, task)
+	writeTaskLog(t, dataDir, "task", "main.log", "main output\n")
+	writeTaskLog(t, dataDir, "task", "sidecar.log", "sidecar output\n")
+
+	assert.Equal(t, "main output\n", readLogs(t, c, "task", nil))
+	assert.Equal(t, "sidecar output\n", readLogs(t, c, "task", &api.LogOptions{Container: "sidecar"}))
+	_, err := c.GetLogs(context.Background(), "task", &api.LogOptions{Container: "other"})
+	assert.ErrorContains(t, err, "status=400")
+}
+
+func TestGetTaskLogs_Follow(t *testing.T) {
+	task := &types.Task{
+		Name:    "task",
+		Process: &api.Process{Command: []string{"true"}},
+		Status:  types.Status{State: types.TaskStateRunning},
+	}
+	c, mgr, dataDir := setupLogsTest(t, task)
+
+	rc, err := c.GetLogs(context.Background(), "task", &api.LogOptions{Follow: true})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	// The log file is created after the request and grows while the task runs.
+	path := writeTaskLog(t, dataDir, "task", "stdout.log", "one\n")
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(rc, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "one\n", string(buf))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("two\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	mgr.setState("task", types.TaskStateSucceeded)
+
+	rest, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "two\n", string(rest), "stream ends once the task finished")
+}
@@ -26,6 +26,7 @@ func NewRouter(h *Handler) http.Handler {
 	mux.HandleFunc("POST /tasks", h.CreateTask)
 	mux.HandleFunc("GET /tasks/{id}", h.GetTask)
 	mux.HandleFunc("DELETE /tasks/{id}", h.DeleteTask)
+	mux.HandleFunc("GET /tasks/{id}/logs", h.GetTaskLogs)
 	mux.HandleFunc("GET /health", h.Health)
 
 	return mux
@@ -40,6 +40,13 @@ const (
 type Status struct {
 	State       TaskState   `json:"state"`
 	SubStatuses []SubStatus `json:"subStatuses,omitempty"`
+
+	// RestartCount is the number of times the task has been restarted.
+	RestartCount int32 `json:"restartCount,omitempty"`
+	// LastTermination holds the sub statuses of the previous attempt of a restarted task.
+	LastTermination []SubStatus `json:"lastTermination,omitempty"`
+	// NextRestartAt is the time at which a terminated task is restarted once its backoff expires.
+	NextRestartAt *time.Time `json:"nextRestartAt,omitempty"`
 }
 
 type SubStatus struct {
@@ -57,6 +64,9 @@ type Task struct {
 
 	Process         *api.Process            `json:"process"`
 	PodTemplateSpec *corev1.PodTemplateSpec `json:"podTemplateSpec"`
+	RestartPolicy   api.RestartPolicy       `json:"restartPolicy,omitempty"`
+	MaxRetries      *int32                  `json:"maxRetries,omitempty"`
+	DependsOn       []string                `json:"dependsOn,omitempty"`
 
 	// Status is now a first-class citizen and persisted.
 	Status Status `json:"status"`
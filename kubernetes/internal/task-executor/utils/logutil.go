@@ -0,0 +1,81 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+const tailChunkSize = 32 * 1024
+
+// TailOffset returns the offset of the first of the last lines lines of r, whose size is size.
+// A trailing newline does not start a new line.
+func TailOffset(r io.ReaderAt, size, lines int64) (int64, error) {
+	if lines <= 0 {
+		return size, nil
+	}
+	end := size
+	buf := make([]byte, tailChunkSize)
+	var found int64
+	for end > 0 {
+		start := max(end-tailChunkSize, 0)
+		chunk := buf[:end-start]
+		if _, err := r.ReadAt(chunk, start); err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' || start+int64(i) == size-1 {
+				continue
+			}
+			found++
+			if found == lines {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// ReadTail returns at most the last maxLines lines of the file at path, limited to its last
+// maxBytes bytes. A missing file has an empty tail.
+func ReadTail(path string, maxBytes, maxLines int64) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	start := max(info.Size()-maxBytes, 0)
+	data := make([]byte, info.Size()-start)
+	if _, err := f.ReadAt(data, start); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	offset, err := TailOffset(bytes.NewReader(data), int64(len(data)), maxLines)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data[offset:])), nil
+}
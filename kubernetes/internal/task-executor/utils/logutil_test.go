@@ -0,0 +1,65 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailOffset(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		lines int64
+		want  string
+	}{
+		{name: "Empty", data: "", lines: 3, want: ""},
+		{name: "FewerLines", data: "a\nb\n", lines: 3, want: "a\nb\n"},
+		{name: "TrailingNewline", data: "a\nb\nc\n", lines: 2, want: "b\nc\n"},
+		{name: "NoTrailingNewline", data: "a\nb\nc", lines: 2, want: "b\nc"},
+		{name: "Zero", data: "a\nb\n", lines: 0, want: ""},
+		{name: "AcrossChunks", data: strings.Repeat("x", tailChunkSize) + "\n" + strings.Repeat("y", tailChunkSize) + "\nz\n", lines: 2, want: strings.Repeat("y", tailChunkSize) + "\nz\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(tt.data)
+			offset, err := TailOffset(r, int64(len(tt.data)), tt.lines)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.data[offset:])
+		})
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stderr.log")
+	tail, err := ReadTail(path, 100, 2)
+	require.NoError(t, err)
+	assert.Empty(t, tail)
+
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0644))
+	tail, err = ReadTail(path, 100, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "two\nthree", tail)
+
+	tail, err = ReadTail(path, 4, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "ree", tail)
+}
@@ -0,0 +1,391 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	poolassign "github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller/poolassign"
+)
+
+var batchsandboxlog = logf.Log.WithName("batchsandbox-resource")
+
+// poolRefAutoAssign asks the controller to pick a pool with the default assign profile.
+const poolRefAutoAssign = "*"
+
+// templateCompatibilityPredicates are the auto-assign predicates that only
+// depend on the BatchSandbox template and the Pool spec.
+var templateCompatibilityPredicates = []string{"image", "resource", "nodeselector"}
+
+// SetupBatchSandboxWebhookWithManager registers the webhook for BatchSandbox in the manager.
+func SetupBatchSandboxWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&sandboxv1alpha1.BatchSandbox{}).
+		WithValidator(&BatchSandboxCustomValidator{Client: mgr.GetClient()}).
+		WithDefaulter(&BatchSandboxCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sandbox-opensandbox-io-v1alpha1-batchsandbox,mutating=true,failurePolicy=fail,sideEffects=None,groups=sandbox.opensandbox.io,resources=batchsandboxes,verbs=create;update,versions=v1alpha1,name=mbatchsandbox-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// BatchSandboxCustomDefaulter sets default values on BatchSandbox resources.
+type BatchSandboxCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &BatchSandboxCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *BatchSandboxCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	batchSandbox, ok := obj.(*sandboxv1alpha1.BatchSandbox)
+	if !ok {
+		return fmt.Errorf("expected a BatchSandbox object but got %T", obj)
+	}
+	batchsandboxlog.V(1).Info("Defaulting for BatchSandbox", "name", batchSandbox.GetName())
+
+	if batchSandbox.Spec.Replicas == nil {
+		batchSandbox.Spec.Replicas = ptr.To[int32](1)
+	}
+	if batchSandbox.Spec.TaskResourcePolicyWhenCompleted == nil {
+		policy := sandboxv1alpha1.TaskResourcePolicyRetain
+		batchSandbox.Spec.TaskResourcePolicyWhenCompleted = &policy
+	}
+	if batchSandbox.Spec.PreemptionPolicy == nil {
+		batchSandbox.Spec.PreemptionPolicy = ptr.To(sandboxv1alpha1.PreemptionPolicyNever)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-sandbox-opensandbox-io-v1alpha1-batchsandbox,mutating=false,failurePolicy=fail,sideEffects=None,groups=sandbox.opensandbox.io,resources=batchsandboxes,verbs=create;update,versions=v1alpha1,name=vbatchsandbox-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// BatchSandboxCustomValidator validates BatchSandbox resources on create and update.
+// Client is used to look up the referenced Pool; when nil, pool compatibility is not checked.
+type BatchSandboxCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &BatchSandboxCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *BatchSandboxCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	batchSandbox, ok := obj.(*sandboxv1alpha1.BatchSandbox)
+	if !ok {
+		return nil, fmt.Errorf("expected a BatchSandbox object but got %T", obj)
+	}
+	batchsandboxlog.V(1).Info("Validation for BatchSandbox upon creation", "name", batchSandbox.GetName())
+
+	return v.validate(ctx, nil, batchSandbox)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *BatchSandboxCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldBatchSandbox, ok := oldObj.(*sandboxv1alpha1.BatchSandbox)
+	if !ok {
+		return nil, fmt.Errorf("expected a BatchSandbox object for the oldObj but got %T", oldObj)
+	}
+	batchSandbox, ok := newObj.(*sandboxv1alpha1.BatchSandbox)
+	if !ok {
+		return nil, fmt.Errorf("expected a BatchSandbox object for the newObj but got %T", newObj)
+	}
+	batchsandboxlog.V(1).Info("Validation for BatchSandbox upon update", "name", batchSandbox.GetName())
+
+	// Metadata-only updates (finalizers, annotations) and updates of objects
+	// that are going away must never be blocked by spec validation.
+	if batchSandbox.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldBatchSandbox.Spec, batchSandbox.Spec) {
+		return nil, nil
+	}
+	return v.validate(ctx, oldBatchSandbox, batchSandbox)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (v *BatchSandboxCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks batchSandbox; old is nil on create. On update the pool
+// compatibility and pause rules only run when their inputs changed, so an
+// object that was admitted earlier stays writable.
+func (v *BatchSandboxCustomValidator) validate(ctx context.Context, old, batchSandbox *sandboxv1alpha1.BatchSandbox) (admission.Warnings, error) {
+	var allErrs field.ErrorList
+	var warnings admission.Warnings
+	specPath := field.NewPath("spec")
+	spec := &batchSandbox.Spec
+
+	if spec.PoolRef == "" && spec.SnapshotRef == "" && spec.Template == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("template"), "template is required when neither poolRef nor snapshotRef is set"))
+	}
+	if spec.SnapshotRef != "" {
+		if spec.PoolRef != "" {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("snapshotRef"), "snapshotRef and poolRef are mutually exclusive"))
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(spec.SnapshotRef) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("snapshotRef"), spec.SnapshotRef, msg))
+		}
+	}
+	if spec.Template != nil && len(spec.Template.Spec.Containers) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("template", "spec", "containers"), "template must define at least one container"))
+	}
+
+	if spec.PoolRef != "" && spec.PoolRef != poolRefAutoAssign && spec.Template != nil &&
+		(old == nil || old.Spec.PoolRef != spec.PoolRef || !equality.Semantic.DeepEqual(old.Spec.Template, spec.Template)) {
+		errs, err := v.validatePoolCompatibility(ctx, batchSandbox, specPath)
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	replicas := ptr.Deref(spec.Replicas, 1)
+	allErrs = append(allErrs, validateShardPatches(spec, specPath)...)
+	allErrs = append(allErrs, validateTaskTemplate(spec.TaskTemplate, specPath.Child("taskTemplate"))...)
+	if len(spec.ShardPatches) > int(replicas) {
+		warnings = append(warnings, fmt.Sprintf("spec.shardPatches has %d entries but spec.replicas is %d; patches beyond the last replica are ignored",
+			len(spec.ShardPatches), replicas))
+	}
+
+	if spec.PoolRef == "" && (spec.Priority != nil || ptr.Deref(spec.PreemptionPolicy, sandboxv1alpha1.PreemptionPolicyNever) != sandboxv1alpha1.PreemptionPolicyNever) {
+		warnings = append(warnings, "spec.priority and spec.preemptionPolicy only take effect for pooled sandboxes with spec.poolRef")
+	}
+
+	if ptr.Deref(spec.Pause, false) && replicas < 1 &&
+		(old == nil || !ptr.Deref(old.Spec.Pause, false) || ptr.Deref(old.Spec.Replicas, 1) != replicas) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("pause"), true,
+			fmt.Sprintf("pause/resume requires spec.replicas>=1; got spec.replicas=%d", replicas)))
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(sandboxv1alpha1.GroupVersion.WithKind("BatchSandbox").GroupKind(), batchSandbox.Name, allErrs)
+}
+
+// validatePoolCompatibility rejects a template that the referenced pool cannot
+// serve, using the same predicates as pool auto-assignment. A pool that does
+// not exist yet is not an error; the controller waits for it.
+func (v *BatchSandboxCustomValidator) validatePoolCompatibility(ctx context.Context, batchSandbox *sandboxv1alpha1.BatchSandbox, specPath *field.Path) (field.ErrorList, error) {
+	if v.Client == nil {
+		return nil, nil
+	}
+	pool := &sandboxv1alpha1.Pool{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Namespace: batchSandbox.Namespace, Name: batchSandbox.Spec.PoolRef}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pool %s: %w", batchSandbox.Spec.PoolRef, err)
+	}
+
+	predicates, err := poolassign.NewPredicates(&poolassign.Profile{
+		Plugins: poolassign.PluginsSpec{Predicate: templateCompatibilityPredicates},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allErrs field.ErrorList
+	for _, p := range predicates {
+		if p.Predicate(ctx, batchSandbox, pool) {
+			continue
+		}
+		reason := "template is not compatible with the pool template"
+		if pr, ok := p.(poolassign.PredicateWithReason); ok {
+			if r := pr.Reason(ctx, batchSandbox, pool); r != "" {
+				reason = r
+			}
+		}
+		allErrs = append(allErrs, field.Invalid(specPath.Child("template"), "(omitted)",
+			fmt.Sprintf("incompatible with pool %q: %s", pool.Name, reason)))
+	}
+	return allErrs, nil
+}
+
+// validateShardPatches checks that every shard patch is a JSON object that
+// strategic-merges onto the pod template and the task template.
+func validateShardPatches(spec *sandboxv1alpha1.BatchSandboxSpec, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	podTemplate := &corev1.PodTemplateSpec{}
+	if spec.Template != nil {
+		podTemplate = spec.Template
+	}
+	pod := &corev1.Pod{ObjectMeta: podTemplate.ObjectMeta, Spec: podTemplate.Spec}
+	for i, patch := range spec.ShardPatches {
+		if err := checkStrategicPatch(pod, patch, &corev1.Pod{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("shardPatches").Index(i), string(patch.Raw), err.Error()))
+		}
+	}
+
+	if len(spec.ShardTaskPatches) > 0 && spec.TaskTemplate == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("taskTemplate"), "taskTemplate is required when shardTaskPatches is set"))
+		return allErrs
+	}
+	for i, patch := range spec.ShardTaskPatches {
+		patched := &sandboxv1alpha1.TaskTemplateSpec{}
+		if err := checkStrategicPatch(spec.TaskTemplate, patch, patched); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("shardTaskPatches").Index(i), string(patch.Raw), err.Error()))
+			continue
+		}
+		// Patches merge steps by name, so a patch can add a step or change dependencies.
+		allErrs = append(allErrs, validateTaskSteps(&patched.Spec, specPath.Child("shardTaskPatches").Index(i).Child("spec"))...)
+	}
+	return allErrs
+}
+
+// validateTaskTemplate checks the restart settings and the steps of the task template.
+// The CRD preserves unknown fields of taskTemplate, so its schema does not enforce them.
+func validateTaskTemplate(template *sandboxv1alpha1.TaskTemplateSpec, path *field.Path) field.ErrorList {
+	if template == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	specPath := path.Child("spec")
+	switch template.Spec.RestartPolicy {
+	case "", sandboxv1alpha1.TaskRestartPolicyNever, sandboxv1alpha1.TaskRestartPolicyOnFailure, sandboxv1alpha1.TaskRestartPolicyAlways:
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("restartPolicy"), template.Spec.RestartPolicy, []string{
+			string(sandboxv1alpha1.TaskRestartPolicyNever),
+			string(sandboxv1alpha1.TaskRestartPolicyOnFailure),
+			string(sandboxv1alpha1.TaskRestartPolicyAlways),
+		}))
+	}
+	if template.Spec.MaxRetries != nil && *template.Spec.MaxRetries < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maxRetries"), *template.Spec.MaxRetries, "must be greater than or equal to 0"))
+	}
+	allErrs = append(allErrs, validateTaskSteps(&template.Spec, specPath)...)
+	return allErrs
+}
+
+// validateTaskSteps checks that steps replace the process, have unique names and
+// form a DAG of existing steps.
+func validateTaskSteps(spec *sandboxv1alpha1.TaskSpec, specPath *field.Path) field.ErrorList {
+	if len(spec.Steps) == 0 {
+		return nil
+	}
+	var allErrs field.ErrorList
+	stepsPath := specPath.Child("steps")
+	if spec.Process != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("process"), "process and steps are mutually exclusive"))
+	}
+	names := make(map[string]bool, len(spec.Steps))
+	for i, step := range spec.Steps {
+		stepPath := stepsPath.Index(i)
+		for _, msg := range validation.IsDNS1123Label(step.Name) {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("name"), step.Name, msg))
+		}
+		if names[step.Name] {
+			allErrs = append(allErrs, field.Duplicate(stepPath.Child("name"), step.Name))
+		}
+		names[step.Name] = true
+		if step.Process == nil {
+			allErrs = append(allErrs, field.Required(stepPath.Child("process"), "every step must define a process"))
+		}
+	}
+	for i, step := range spec.Steps {
+		for j, dep := range step.DependsOn {
+			depPath := stepsPath.Index(i).Child("dependsOn").Index(j)
+			switch {
+			case dep == step.Name:
+				allErrs = append(allErrs, field.Invalid(depPath, dep, "a step cannot depend on itself"))
+			case !names[dep]:
+				allErrs = append(allErrs, field.NotFound(depPath, dep))
+			}
+		}
+	}
+	if len(allErrs) == 0 {
+		if cycle := findStepCycle(spec.Steps); cycle != nil {
+			allErrs = append(allErrs, field.Invalid(stepsPath, strings.Join(cycle, " -> "), "steps must not have a dependency cycle"))
+		}
+	}
+	return allErrs
+}
+
+// findStepCycle returns the steps of a dependency cycle, starting and ending with
+// the same step, or nil if the steps form a DAG.
+func findStepCycle(steps []sandboxv1alpha1.TaskStep) []string {
+	deps := make(map[string][]string, len(steps))
+	for _, step := range steps {
+		deps[step.Name] = step.DependsOn
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(steps))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+	for _, step := range steps {
+		if cycle := visit(step.Name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// checkStrategicPatch applies patch to original the way the controller does
+// and decodes the result back into a value of dataStruct's type.
+func checkStrategicPatch(original interface{}, patch runtime.RawExtension, dataStruct interface{}) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(patch.Raw, &fields); err != nil || fields == nil {
+		return fmt.Errorf("patch must be a JSON object")
+	}
+	originalBytes, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(originalBytes, patch.Raw, dataStruct)
+	if err != nil {
+		return fmt.Errorf("failed to apply patch: %v", err)
+	}
+	if err := json.Unmarshal(patched, dataStruct); err != nil {
+		return fmt.Errorf("patched object is invalid: %v", err)
+	}
+	return nil
+}
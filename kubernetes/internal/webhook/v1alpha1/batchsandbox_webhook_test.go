@@ -0,0 +1,371 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func newTestTemplate(image string) *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: image}},
+		},
+	}
+}
+
+func newTestBatchSandbox() *sandboxv1alpha1.BatchSandbox {
+	return &sandboxv1alpha1.BatchSandbox{
+		ObjectMeta: metav1.ObjectMeta{Name: "bs", Namespace: "default"},
+		Spec: sandboxv1alpha1.BatchSandboxSpec{
+			Replicas: ptr.To[int32](1),
+			Template: newTestTemplate("nginx"),
+		},
+	}
+}
+
+func TestBatchSandboxDefault(t *testing.T) {
+	bs := newTestBatchSandbox()
+	bs.Spec.Replicas = nil
+
+	require.NoError(t, (&BatchSandboxCustomDefaulter{}).Default(context.Background(), bs))
+	assert.Equal(t, int32(1), *bs.Spec.Replicas)
+	assert.Equal(t, sandboxv1alpha1.TaskResourcePolicyRetain, *bs.Spec.TaskResourcePolicyWhenCompleted)
+	assert.Equal(t, sandboxv1alpha1.PreemptionPolicyNever, *bs.Spec.PreemptionPolicy)
+}
+
+func TestBatchSandboxValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(bs *sandboxv1alpha1.BatchSandbox)
+		wantErr string
+	}{
+		{
+			name:   "valid template",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {},
+		},
+		{
+			name: "valid pooled",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.Template = nil
+				bs.Spec.PoolRef = "pool"
+			},
+		},
+		{
+			name: "missing template and poolRef",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.Template = nil
+			},
+			wantErr: "spec.template: Required value",
+		},
+		{
+			name: "valid snapshotRef without template",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.Template = nil
+				bs.Spec.SnapshotRef = "golden"
+				bs.Spec.Replicas = ptr.To[int32](5)
+			},
+		},
+		{
+			name: "snapshotRef with poolRef",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.SnapshotRef = "golden"
+				bs.Spec.PoolRef = "pool"
+			},
+			wantErr: "spec.snapshotRef: Forbidden",
+		},
+		{
+			name: "invalid snapshotRef",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.SnapshotRef = "Not_A_Name"
+			},
+			wantErr: "spec.snapshotRef: Invalid value",
+		},
+		{
+			name: "template without containers",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.Template = &corev1.PodTemplateSpec{}
+			},
+			wantErr: "spec.template.spec.containers: Required value",
+		},
+		{
+			name: "shard patch is not an object",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.ShardPatches = []runtime.RawExtension{{Raw: []byte(`["a"]`)}}
+			},
+			wantErr: "spec.shardPatches[0]",
+		},
+		{
+			name: "shard patch with wrong field type",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.ShardPatches = []runtime.RawExtension{{Raw: []byte(`{"spec":{"containers":"oops"}}`)}}
+			},
+			wantErr: "spec.shardPatches[0]",
+		},
+		{
+			name: "valid shard patch",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.ShardPatches = []runtime.RawExtension{{Raw: []byte(`{"metadata":{"labels":{"shard":"0"}}}`)}}
+			},
+		},
+		{
+			name: "shard task patches without task template",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.ShardTaskPatches = []runtime.RawExtension{{Raw: []byte(`{"spec":{}}`)}}
+			},
+			wantErr: "spec.taskTemplate: Required value",
+		},
+		{
+			name: "task template with restart policy",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = &sandboxv1alpha1.TaskTemplateSpec{Spec: sandboxv1alpha1.TaskSpec{
+					RestartPolicy: sandboxv1alpha1.TaskRestartPolicyOnFailure,
+					MaxRetries:    ptr.To[int32](3),
+				}}
+			},
+		},
+		{
+			name: "task template with unknown restart policy",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = &sandboxv1alpha1.TaskTemplateSpec{Spec: sandboxv1alpha1.TaskSpec{RestartPolicy: "Sometimes"}}
+			},
+			wantErr: "spec.taskTemplate.spec.restartPolicy: Unsupported value",
+		},
+		{
+			name: "task template with negative max retries",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = &sandboxv1alpha1.TaskTemplateSpec{Spec: sandboxv1alpha1.TaskSpec{MaxRetries: ptr.To[int32](-1)}}
+			},
+			wantErr: "spec.taskTemplate.spec.maxRetries: Invalid value",
+		},
+		{
+			name: "task template with steps",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = stepsTemplate(
+					sandboxv1alpha1.TaskStep{Name: "build"},
+					sandboxv1alpha1.TaskStep{Name: "test", DependsOn: []string{"build"}},
+					sandboxv1alpha1.TaskStep{Name: "publish", DependsOn: []string{"build", "test"}},
+				)
+			},
+		},
+		{
+			name: "task template with steps and process",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = stepsTemplate(sandboxv1alpha1.TaskStep{Name: "build"})
+				bs.Spec.TaskTemplate.Spec.Process = &sandboxv1alpha1.ProcessTask{Command: []string{"true"}}
+			},
+			wantErr: "spec.taskTemplate.spec.process: Forbidden",
+		},
+		{
+			name: "task template with duplicate step",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = stepsTemplate(sandboxv1alpha1.TaskStep{Name: "build"}, sandboxv1alpha1.TaskStep{Name: "build"})
+			},
+			wantErr: "spec.taskTemplate.spec.steps[1].name: Duplicate value",
+		},
+		{
+			name: "task template with unknown dependency",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = stepsTemplate(sandboxv1alpha1.TaskStep{Name: "test", DependsOn: []string{"build"}})
+			},
+			wantErr: "spec.taskTemplate.spec.steps[0].dependsOn[0]: Not found",
+		},
+		{
+			name: "task template with dependency cycle",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = stepsTemplate(
+					sandboxv1alpha1.TaskStep{Name: "a", DependsOn: []string{"c"}},
+					sandboxv1alpha1.TaskStep{Name: "b", DependsOn: []string{"a"}},
+					sandboxv1alpha1.TaskStep{Name: "c", DependsOn: []string{"b"}},
+				)
+			},
+			wantErr: `spec.taskTemplate.spec.steps: Invalid value: "a -> c -> b -> a"`,
+		},
+		{
+			name: "shard task patch adding a dependency cycle",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.TaskTemplate = stepsTemplate(
+					sandboxv1alpha1.TaskStep{Name: "a"},
+					sandboxv1alpha1.TaskStep{Name: "b", DependsOn: []string{"a"}},
+				)
+				bs.Spec.ShardTaskPatches = []runtime.RawExtension{{Raw: []byte(`{"spec":{"steps":[{"name":"a","dependsOn":["b"]}]}}`)}}
+			},
+			wantErr: "spec.shardTaskPatches[0].spec.steps: Invalid value",
+		},
+		{
+			name: "pause with multiple replicas",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.Replicas = ptr.To[int32](3)
+				bs.Spec.Pause = ptr.To(true)
+			},
+		},
+		{
+			name: "pause with zero replicas",
+			mutate: func(bs *sandboxv1alpha1.BatchSandbox) {
+				bs.Spec.Replicas = ptr.To[int32](0)
+				bs.Spec.Pause = ptr.To(true)
+			},
+			wantErr: "spec.replicas>=1; got spec.replicas=0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := newTestBatchSandbox()
+			tt.mutate(bs)
+			_, err := (&BatchSandboxCustomValidator{}).ValidateCreate(context.Background(), bs)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBatchSandboxValidateCreate_ShardPatchWarning(t *testing.T) {
+	bs := newTestBatchSandbox()
+	bs.Spec.ShardPatches = []runtime.RawExtension{{Raw: []byte(`{}`)}, {Raw: []byte(`{}`)}}
+
+	warnings, err := (&BatchSandboxCustomValidator{}).ValidateCreate(context.Background(), bs)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "spec.shardPatches has 2 entries")
+}
+
+func TestBatchSandboxValidateCreate_PriorityWithoutPoolWarning(t *testing.T) {
+	bs := newTestBatchSandbox()
+	bs.Spec.Priority = ptr.To[int32](100)
+
+	warnings, err := (&BatchSandboxCustomValidator{}).ValidateCreate(context.Background(), bs)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "only take effect for pooled sandboxes")
+
+	bs.Spec.PoolRef = "pool"
+	bs.Spec.Template = nil
+	warnings, err = (&BatchSandboxCustomValidator{}).ValidateCreate(context.Background(), bs)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestBatchSandboxValidateCreate_PoolCompatibility(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, sandboxv1alpha1.AddToScheme(scheme))
+	poolTemplate := newTestTemplate("nginx")
+	poolTemplate.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+	pool := &sandboxv1alpha1.Pool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+		Spec:       sandboxv1alpha1.PoolSpec{Template: poolTemplate},
+	}
+	validator := &BatchSandboxCustomValidator{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).Build()}
+
+	t.Run("compatible", func(t *testing.T) {
+		bs := newTestBatchSandbox()
+		bs.Spec.PoolRef = "pool"
+		_, err := validator.ValidateCreate(context.Background(), bs)
+		assert.NoError(t, err)
+	})
+
+	t.Run("image not in pool", func(t *testing.T) {
+		bs := newTestBatchSandbox()
+		bs.Spec.PoolRef = "pool"
+		bs.Spec.Template = newTestTemplate("redis")
+		_, err := validator.ValidateCreate(context.Background(), bs)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `incompatible with pool "pool"`)
+		assert.Contains(t, err.Error(), "redis")
+	})
+
+	t.Run("requests exceed pool", func(t *testing.T) {
+		bs := newTestBatchSandbox()
+		bs.Spec.PoolRef = "pool"
+		bs.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+		_, err := validator.ValidateCreate(context.Background(), bs)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `incompatible with pool "pool"`)
+	})
+
+	t.Run("unknown pool", func(t *testing.T) {
+		bs := newTestBatchSandbox()
+		bs.Spec.PoolRef = "missing"
+		bs.Spec.Template = newTestTemplate("redis")
+		_, err := validator.ValidateCreate(context.Background(), bs)
+		assert.NoError(t, err)
+	})
+}
+
+func TestBatchSandboxValidateUpdate(t *testing.T) {
+	validator := &BatchSandboxCustomValidator{}
+
+	t.Run("metadata-only update of an invalid object", func(t *testing.T) {
+		old := newTestBatchSandbox()
+		old.Spec.Template = nil
+		updated := old.DeepCopy()
+		updated.Finalizers = []string{"example.com/finalizer"}
+		_, err := validator.ValidateUpdate(context.Background(), old, updated)
+		assert.NoError(t, err)
+	})
+
+	t.Run("pause requested with multiple replicas", func(t *testing.T) {
+		old := newTestBatchSandbox()
+		old.Spec.Replicas = ptr.To[int32](2)
+		updated := old.DeepCopy()
+		updated.Spec.Pause = ptr.To(true)
+		_, err := validator.ValidateUpdate(context.Background(), old, updated)
+		assert.NoError(t, err)
+	})
+
+	t.Run("pause requested with zero replicas", func(t *testing.T) {
+		old := newTestBatchSandbox()
+		old.Spec.Replicas = ptr.To[int32](0)
+		updated := old.DeepCopy()
+		updated.Spec.Pause = ptr.To(true)
+		_, err := validator.ValidateUpdate(context.Background(), old, updated)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.pause")
+	})
+
+	t.Run("already paused object keeps unrelated updates", func(t *testing.T) {
+		old := newTestBatchSandbox()
+		old.Spec.Replicas = ptr.To[int32](0)
+		old.Spec.Pause = ptr.To(true)
+		updated := old.DeepCopy()
+		updated.Spec.ExpireTime = &metav1.Time{}
+		_, err := validator.ValidateUpdate(context.Background(), old, updated)
+		assert.NoError(t, err)
+	})
+}
+
+func stepsTemplate(steps ...sandboxv1alpha1.TaskStep) *sandboxv1alpha1.TaskTemplateSpec {
+	for i := range steps {
+		steps[i].Process = &sandboxv1alpha1.ProcessTask{Command: []string{"make", steps[i].Name}}
+	}
+	return &sandboxv1alpha1.TaskTemplateSpec{Spec: sandboxv1alpha1.TaskSpec{Steps: steps}}
+}
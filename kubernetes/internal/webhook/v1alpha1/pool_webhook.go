@@ -0,0 +1,204 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller"
+)
+
+var poollog = logf.Log.WithName("pool-resource")
+
+// SetupPoolWebhookWithManager registers the webhook for Pool in the manager.
+func SetupPoolWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&sandboxv1alpha1.Pool{}).
+		WithValidator(&PoolCustomValidator{}).
+		WithDefaulter(&PoolCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sandbox-opensandbox-io-v1alpha1-pool,mutating=true,failurePolicy=fail,sideEffects=None,groups=sandbox.opensandbox.io,resources=pools,verbs=create;update,versions=v1alpha1,name=mpool-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PoolCustomDefaulter sets default values on Pool resources.
+type PoolCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &PoolCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *PoolCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	pool, ok := obj.(*sandboxv1alpha1.Pool)
+	if !ok {
+		return fmt.Errorf("expected a Pool object but got %T", obj)
+	}
+	poollog.V(1).Info("Defaulting for Pool", "name", pool.GetName())
+
+	if pool.Spec.RecycleStrategy == nil {
+		pool.Spec.RecycleStrategy = &sandboxv1alpha1.RecycleStrategy{}
+	}
+	if pool.Spec.RecycleStrategy.Type == "" {
+		pool.Spec.RecycleStrategy.Type = sandboxv1alpha1.RecycleTypeDelete
+	}
+	if a := pool.Spec.Autoscaling; a != nil {
+		if a.WindowSeconds == 0 {
+			a.WindowSeconds = 300
+		}
+		if a.LeadSeconds == 0 {
+			a.LeadSeconds = 60
+		}
+		if a.TargetWaitSeconds == 0 {
+			a.TargetWaitSeconds = 1
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-sandbox-opensandbox-io-v1alpha1-pool,mutating=false,failurePolicy=fail,sideEffects=None,groups=sandbox.opensandbox.io,resources=pools,verbs=create;update,versions=v1alpha1,name=vpool-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PoolCustomValidator validates Pool resources on create and update.
+type PoolCustomValidator struct{}
+
+var _ webhook.CustomValidator = &PoolCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *PoolCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pool, ok := obj.(*sandboxv1alpha1.Pool)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pool object but got %T", obj)
+	}
+	poollog.V(1).Info("Validation for Pool upon creation", "name", pool.GetName())
+
+	return nil, validatePool(pool)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *PoolCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldPool, ok := oldObj.(*sandboxv1alpha1.Pool)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pool object for the oldObj but got %T", oldObj)
+	}
+	pool, ok := newObj.(*sandboxv1alpha1.Pool)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pool object for the newObj but got %T", newObj)
+	}
+	poollog.V(1).Info("Validation for Pool upon update", "name", pool.GetName())
+
+	if pool.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldPool.Spec, pool.Spec) {
+		return nil, nil
+	}
+	return nil, validatePool(pool)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (v *PoolCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validatePool(pool *sandboxv1alpha1.Pool) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if pool.Spec.Template == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("template"), "pool pods are created from the template"))
+	} else if len(pool.Spec.Template.Spec.Containers) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("template", "spec", "containers"), "template must define at least one container"))
+	}
+
+	capacity := pool.Spec.CapacitySpec
+	capacityPath := specPath.Child("capacitySpec")
+	if capacity.BufferMin > capacity.BufferMax {
+		allErrs = append(allErrs, field.Invalid(capacityPath.Child("bufferMin"), capacity.BufferMin,
+			fmt.Sprintf("must be less than or equal to bufferMax (%d)", capacity.BufferMax)))
+	}
+	if capacity.PoolMin > capacity.PoolMax {
+		allErrs = append(allErrs, field.Invalid(capacityPath.Child("poolMin"), capacity.PoolMin,
+			fmt.Sprintf("must be less than or equal to poolMax (%d)", capacity.PoolMax)))
+	}
+	if capacity.BufferMin > capacity.PoolMax {
+		allErrs = append(allErrs, field.Invalid(capacityPath.Child("bufferMin"), capacity.BufferMin,
+			fmt.Sprintf("must be less than or equal to poolMax (%d)", capacity.PoolMax)))
+	}
+
+	if s := pool.Spec.ScaleStrategy; s != nil {
+		allErrs = append(allErrs, validateMaxUnavailable(s.MaxUnavailable, specPath.Child("scaleStrategy", "maxUnavailable"))...)
+	}
+	if s := pool.Spec.UpdateStrategy; s != nil {
+		allErrs = append(allErrs, validateMaxUnavailable(s.MaxUnavailable, specPath.Child("updateStrategy", "maxUnavailable"))...)
+	}
+	if a := pool.Spec.Autoscaling; a != nil {
+		allErrs = append(allErrs, validateAutoscaling(a, capacity, specPath.Child("autoscaling"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(sandboxv1alpha1.GroupVersion.WithKind("Pool").GroupKind(), pool.Name, allErrs)
+}
+
+// validateAutoscaling checks that every schedule can be evaluated and targets a buffer
+// the capacity bounds allow, so a schedule is never silently clamped.
+func validateAutoscaling(a *sandboxv1alpha1.PoolAutoscaling, capacity sandboxv1alpha1.CapacitySpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool, len(a.Schedules))
+	for i := range a.Schedules {
+		schedule := &a.Schedules[i]
+		schedulePath := fldPath.Child("schedules").Index(i)
+		if names[schedule.Name] {
+			allErrs = append(allErrs, field.Duplicate(schedulePath.Child("name"), schedule.Name))
+		}
+		names[schedule.Name] = true
+		if err := controller.ValidateAutoscalingSchedule(schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath, schedule.Name, err.Error()))
+		}
+		if schedule.BufferTarget < capacity.BufferMin || schedule.BufferTarget > capacity.BufferMax {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("bufferTarget"), schedule.BufferTarget,
+				fmt.Sprintf("must be between bufferMin (%d) and bufferMax (%d)", capacity.BufferMin, capacity.BufferMax)))
+		}
+	}
+	return allErrs
+}
+
+// validateMaxUnavailable accepts a non-negative integer or a percentage between 0% and 100%.
+func validateMaxUnavailable(v *intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	if v == nil {
+		return nil
+	}
+	if v.Type == intstr.Int {
+		if v.IntVal < 0 {
+			return field.ErrorList{field.Invalid(fldPath, v.IntVal, "must be greater than or equal to 0")}
+		}
+		return nil
+	}
+	percent, err := intstr.GetScaledValueFromIntOrPercent(v, 100, false)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, v.StrVal, "must be an integer or a percentage such as \"25%\"")}
+	}
+	if percent < 0 || percent > 100 {
+		return field.ErrorList{field.Invalid(fldPath, v.StrVal, "must be between 0% and 100%")}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+)
+
+func newTestPool() *sandboxv1alpha1.Pool {
+	return &sandboxv1alpha1.Pool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+		Spec: sandboxv1alpha1.PoolSpec{
+			Template: newTestTemplate("nginx"),
+			CapacitySpec: sandboxv1alpha1.CapacitySpec{
+				BufferMin: 1,
+				BufferMax: 2,
+				PoolMin:   0,
+				PoolMax:   5,
+			},
+		},
+	}
+}
+
+func TestPoolDefault(t *testing.T) {
+	pool := newTestPool()
+	require.NoError(t, (&PoolCustomDefaulter{}).Default(context.Background(), pool))
+	require.NotNil(t, pool.Spec.RecycleStrategy)
+	assert.Equal(t, sandboxv1alpha1.RecycleTypeDelete, pool.Spec.RecycleStrategy.Type)
+
+	pool.Spec.RecycleStrategy.Type = sandboxv1alpha1.RecycleTypeRestart
+	require.NoError(t, (&PoolCustomDefaulter{}).Default(context.Background(), pool))
+	assert.Equal(t, sandboxv1alpha1.RecycleTypeRestart, pool.Spec.RecycleStrategy.Type)
+
+	pool.Spec.Autoscaling = &sandboxv1alpha1.PoolAutoscaling{LeadSeconds: 30}
+	require.NoError(t, (&PoolCustomDefaulter{}).Default(context.Background(), pool))
+	assert.Equal(t, sandboxv1alpha1.PoolAutoscaling{WindowSeconds: 300, LeadSeconds: 30, TargetWaitSeconds: 1}, *pool.Spec.Autoscaling)
+}
+
+func TestPoolValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(pool *sandboxv1alpha1.Pool)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			mutate: func(pool *sandboxv1alpha1.Pool) {},
+		},
+		{
+			name:    "missing template",
+			mutate:  func(pool *sandboxv1alpha1.Pool) { pool.Spec.Template = nil },
+			wantErr: "spec.template: Required value",
+		},
+		{
+			name:    "bufferMin greater than bufferMax",
+			mutate:  func(pool *sandboxv1alpha1.Pool) { pool.Spec.CapacitySpec.BufferMin = 3 },
+			wantErr: "spec.capacitySpec.bufferMin: Invalid value: 3: must be less than or equal to bufferMax (2)",
+		},
+		{
+			name:    "poolMin greater than poolMax",
+			mutate:  func(pool *sandboxv1alpha1.Pool) { pool.Spec.CapacitySpec.PoolMin = 6 },
+			wantErr: "spec.capacitySpec.poolMin",
+		},
+		{
+			name: "bufferMin greater than poolMax",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				pool.Spec.CapacitySpec.BufferMin = 2
+				pool.Spec.CapacitySpec.PoolMax = 1
+			},
+			wantErr: "must be less than or equal to poolMax (1)",
+		},
+		{
+			name: "valid percentage maxUnavailable",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				v := intstr.FromString("50%")
+				pool.Spec.UpdateStrategy = &sandboxv1alpha1.UpdateStrategy{MaxUnavailable: &v}
+			},
+		},
+		{
+			name: "malformed maxUnavailable",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				v := intstr.FromString("half")
+				pool.Spec.ScaleStrategy = &sandboxv1alpha1.ScaleStrategy{MaxUnavailable: &v}
+			},
+			wantErr: "spec.scaleStrategy.maxUnavailable",
+		},
+		{
+			name: "maxUnavailable above 100%",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				v := intstr.FromString("150%")
+				pool.Spec.UpdateStrategy = &sandboxv1alpha1.UpdateStrategy{MaxUnavailable: &v}
+			},
+			wantErr: "must be between 0% and 100%",
+		},
+		{
+			name: "negative maxUnavailable",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				v := intstr.FromInt32(-1)
+				pool.Spec.ScaleStrategy = &sandboxv1alpha1.ScaleStrategy{MaxUnavailable: &v}
+			},
+			wantErr: "must be greater than or equal to 0",
+		},
+		{
+			name: "valid autoscaling schedule",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				pool.Spec.Autoscaling = &sandboxv1alpha1.PoolAutoscaling{Schedules: []sandboxv1alpha1.PoolAutoscalingSchedule{
+					{Name: "morning", Start: "08:30", End: "10:00", Days: []string{"Mon", "Fri"}, TimeZone: "Asia/Shanghai", BufferTarget: 2},
+				}}
+			},
+		},
+		{
+			name: "autoscaling schedule with unknown time zone",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				pool.Spec.Autoscaling = &sandboxv1alpha1.PoolAutoscaling{Schedules: []sandboxv1alpha1.PoolAutoscalingSchedule{
+					{Name: "morning", Start: "08:30", End: "10:00", TimeZone: "Mars/Olympus", BufferTarget: 2},
+				}}
+			},
+			wantErr: "spec.autoscaling.schedules[0]: Invalid value: \"morning\": invalid timeZone",
+		},
+		{
+			name: "autoscaling schedule with unknown day",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				pool.Spec.Autoscaling = &sandboxv1alpha1.PoolAutoscaling{Schedules: []sandboxv1alpha1.PoolAutoscalingSchedule{
+					{Name: "morning", Start: "08:30", End: "10:00", Days: []string{"Funday"}, BufferTarget: 2},
+				}}
+			},
+			wantErr: "invalid day \"Funday\"",
+		},
+		{
+			name: "autoscaling schedule target above bufferMax",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				pool.Spec.Autoscaling = &sandboxv1alpha1.PoolAutoscaling{Schedules: []sandboxv1alpha1.PoolAutoscalingSchedule{
+					{Name: "morning", Start: "08:30", End: "10:00", BufferTarget: 3},
+				}}
+			},
+			wantErr: "spec.autoscaling.schedules[0].bufferTarget: Invalid value: 3: must be between bufferMin (1) and bufferMax (2)",
+		},
+		{
+			name: "duplicate autoscaling schedule names",
+			mutate: func(pool *sandboxv1alpha1.Pool) {
+				pool.Spec.Autoscaling = &sandboxv1alpha1.PoolAutoscaling{Schedules: []sandboxv1alpha1.PoolAutoscalingSchedule{
+					{Name: "peak", Start: "08:00", End: "10:00", BufferTarget: 2},
+					{Name: "peak", Start: "17:00", End: "19:00", BufferTarget: 2},
+				}}
+			},
+			wantErr: "spec.autoscaling.schedules[1].name: Duplicate value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newTestPool()
+			tt.mutate(pool)
+			_, err := (&PoolCustomValidator{}).ValidateCreate(context.Background(), pool)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPoolValidateUpdate(t *testing.T) {
+	old := newTestPool()
+	updated := old.DeepCopy()
+	updated.Spec.CapacitySpec.BufferMax = 0
+
+	_, err := (&PoolCustomValidator{}).ValidateUpdate(context.Background(), old, updated)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bufferMin")
+
+	// Metadata-only updates are never blocked, even on an invalid pool.
+	invalid := updated.DeepCopy()
+	relabeled := invalid.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "a"}
+	_, err = (&PoolCustomValidator{}).ValidateUpdate(context.Background(), invalid, relabeled)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller"
+)
+
+var sandboxsnapshotlog = logf.Log.WithName("sandboxsnapshot-resource")
+
+// SetupSandboxSnapshotWebhookWithManager registers the webhook for SandboxSnapshot in the manager.
+func SetupSandboxSnapshotWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&sandboxv1alpha1.SandboxSnapshot{}).
+		WithValidator(&SandboxSnapshotCustomValidator{}).
+		WithDefaulter(&SandboxSnapshotCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sandbox-opensandbox-io-v1alpha1-sandboxsnapshot,mutating=true,failurePolicy=fail,sideEffects=None,groups=sandbox.opensandbox.io,resources=sandboxsnapshots,verbs=create;update,versions=v1alpha1,name=msandboxsnapshot-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SandboxSnapshotCustomDefaulter sets default values on SandboxSnapshot resources.
+type SandboxSnapshotCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &SandboxSnapshotCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter. It labels the snapshot with its
+// source BatchSandbox so snapshots can be listed per sandbox.
+func (d *SandboxSnapshotCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	snapshot, ok := obj.(*sandboxv1alpha1.SandboxSnapshot)
+	if !ok {
+		return fmt.Errorf("expected a SandboxSnapshot object but got %T", obj)
+	}
+	sandboxsnapshotlog.V(1).Info("Defaulting for SandboxSnapshot", "name", snapshot.GetName())
+
+	if snapshot.Spec.SandboxName == "" {
+		return nil
+	}
+	if snapshot.Labels == nil {
+		snapshot.Labels = map[string]string{}
+	}
+	if _, ok := snapshot.Labels[controller.LabelBatchSandboxNameKey]; !ok {
+		snapshot.Labels[controller.LabelBatchSandboxNameKey] = snapshot.Spec.SandboxName
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-sandbox-opensandbox-io-v1alpha1-sandboxsnapshot,mutating=false,failurePolicy=fail,sideEffects=None,groups=sandbox.opensandbox.io,resources=sandboxsnapshots,verbs=create;update,versions=v1alpha1,name=vsandboxsnapshot-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SandboxSnapshotCustomValidator validates SandboxSnapshot resources on create and update.
+type SandboxSnapshotCustomValidator struct{}
+
+var _ webhook.CustomValidator = &SandboxSnapshotCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *SandboxSnapshotCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	snapshot, ok := obj.(*sandboxv1alpha1.SandboxSnapshot)
+	if !ok {
+		return nil, fmt.Errorf("expected a SandboxSnapshot object but got %T", obj)
+	}
+	sandboxsnapshotlog.V(1).Info("Validation for SandboxSnapshot upon creation", "name", snapshot.GetName())
+
+	var allErrs field.ErrorList
+	namePath := field.NewPath("spec", "sandboxName")
+	if snapshot.Spec.SandboxName == "" {
+		allErrs = append(allErrs, field.Required(namePath, "name of the BatchSandbox to snapshot"))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(snapshot.Spec.SandboxName) {
+			allErrs = append(allErrs, field.Invalid(namePath, snapshot.Spec.SandboxName, msg))
+		}
+	}
+	return nil, toSnapshotError(snapshot, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator. The snapshot source is
+// immutable: the controller resolves it once and records the result in status.
+func (v *SandboxSnapshotCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldSnapshot, ok := oldObj.(*sandboxv1alpha1.SandboxSnapshot)
+	if !ok {
+		return nil, fmt.Errorf("expected a SandboxSnapshot object for the oldObj but got %T", oldObj)
+	}
+	snapshot, ok := newObj.(*sandboxv1alpha1.SandboxSnapshot)
+	if !ok {
+		return nil, fmt.Errorf("expected a SandboxSnapshot object for the newObj but got %T", newObj)
+	}
+	sandboxsnapshotlog.V(1).Info("Validation for SandboxSnapshot upon update", "name", snapshot.GetName())
+
+	var allErrs field.ErrorList
+	if snapshot.Spec.SandboxName != oldSnapshot.Spec.SandboxName {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "sandboxName"), "field is immutable"))
+	}
+	return nil, toSnapshotError(snapshot, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (v *SandboxSnapshotCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func toSnapshotError(snapshot *sandboxv1alpha1.SandboxSnapshot, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(sandboxv1alpha1.GroupVersion.WithKind("SandboxSnapshot").GroupKind(), snapshot.Name, allErrs)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller"
+)
+
+func newTestSnapshot(sandboxName string) *sandboxv1alpha1.SandboxSnapshot {
+	return &sandboxv1alpha1.SandboxSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snap", Namespace: "default"},
+		Spec:       sandboxv1alpha1.SandboxSnapshotSpec{SandboxName: sandboxName},
+	}
+}
+
+func TestSandboxSnapshotDefault(t *testing.T) {
+	snapshot := newTestSnapshot("bs")
+	require.NoError(t, (&SandboxSnapshotCustomDefaulter{}).Default(context.Background(), snapshot))
+	assert.Equal(t, "bs", snapshot.Labels[controller.LabelBatchSandboxNameKey])
+}
+
+func TestSandboxSnapshotValidateCreate(t *testing.T) {
+	validator := &SandboxSnapshotCustomValidator{}
+
+	_, err := validator.ValidateCreate(context.Background(), newTestSnapshot("bs"))
+	assert.NoError(t, err)
+
+	_, err = validator.ValidateCreate(context.Background(), newTestSnapshot(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.sandboxName: Required value")
+
+	_, err = validator.ValidateCreate(context.Background(), newTestSnapshot("Not_A_Name"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.sandboxName: Invalid value")
+}
+
+func TestSandboxSnapshotValidateUpdate(t *testing.T) {
+	validator := &SandboxSnapshotCustomValidator{}
+	old := newTestSnapshot("bs")
+
+	relabeled := old.DeepCopy()
+	relabeled.Labels = map[string]string{"keep": "true"}
+	_, err := validator.ValidateUpdate(context.Background(), old, relabeled)
+	assert.NoError(t, err)
+
+	retargeted := old.DeepCopy()
+	retargeted.Spec.SandboxName = "other"
+	_, err = validator.ValidateUpdate(context.Background(), old, retargeted)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.sandboxName: Forbidden: field is immutable")
+}
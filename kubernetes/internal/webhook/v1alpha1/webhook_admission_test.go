@@ -0,0 +1,117 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sandboxv1alpha1 "github.com/alibaba/OpenSandbox/sandbox-k8s/apis/sandbox/v1alpha1"
+	"github.com/alibaba/OpenSandbox/sandbox-k8s/internal/controller"
+)
+
+var _ = Describe("Admission webhooks", func() {
+	Context("Pool", func() {
+		It("rejects bufferMin greater than bufferMax", func() {
+			pool := newTestPool()
+			pool.Name = "pool-bad-buffer"
+			pool.Spec.CapacitySpec.BufferMin = 3
+
+			err := k8sClient.Create(ctx, pool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+			Expect(err.Error()).To(ContainSubstring("spec.capacitySpec.bufferMin"))
+		})
+
+		It("defaults the recycle strategy", func() {
+			pool := newTestPool()
+			pool.Name = "pool-defaulted"
+			Expect(k8sClient.Create(ctx, pool)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pool))).To(Succeed()) })
+
+			Expect(pool.Spec.RecycleStrategy).NotTo(BeNil())
+			Expect(pool.Spec.RecycleStrategy.Type).To(Equal(sandboxv1alpha1.RecycleTypeDelete))
+		})
+	})
+
+	Context("BatchSandbox", func() {
+		It("rejects a template the referenced pool cannot serve", func() {
+			pool := newTestPool()
+			pool.Name = "pool-for-compat"
+			Expect(k8sClient.Create(ctx, pool)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pool))).To(Succeed()) })
+
+			bs := newTestBatchSandbox()
+			bs.Name = "bs-incompatible"
+			bs.Spec.PoolRef = pool.Name
+			bs.Spec.Template = newTestTemplate("redis")
+
+			// The webhook reads pools through the manager cache, which may lag the create.
+			Eventually(func() error {
+				return k8sClient.Create(ctx, bs.DeepCopy())
+			}).Should(MatchError(ContainSubstring(`incompatible with pool "pool-for-compat"`)))
+		})
+
+		It("rejects malformed shard patches", func() {
+			bs := newTestBatchSandbox()
+			bs.Name = "bs-bad-patch"
+			bs.Spec.ShardPatches = []runtime.RawExtension{{Raw: []byte(`{"spec":{"containers":"oops"}}`)}}
+
+			err := k8sClient.Create(ctx, bs)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+			Expect(err.Error()).To(ContainSubstring("spec.shardPatches[0]"))
+		})
+
+		It("rejects pause without replicas", func() {
+			bs := newTestBatchSandbox()
+			bs.Name = "bs-pause-replicas"
+			bs.Spec.Replicas = ptr.To[int32](0)
+			bs.Spec.Pause = ptr.To(true)
+
+			err := k8sClient.Create(ctx, bs)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+			Expect(err.Error()).To(ContainSubstring("spec.pause"))
+		})
+
+		It("accepts and defaults a valid sandbox", func() {
+			bs := newTestBatchSandbox()
+			bs.Name = "bs-valid"
+			bs.Spec.Replicas = nil
+			Expect(k8sClient.Create(ctx, bs)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, bs))).To(Succeed()) })
+
+			Expect(*bs.Spec.Replicas).To(Equal(int32(1)))
+			Expect(*bs.Spec.TaskResourcePolicyWhenCompleted).To(Equal(sandboxv1alpha1.TaskResourcePolicyRetain))
+		})
+	})
+
+	Context("SandboxSnapshot", func() {
+		It("labels the snapshot and keeps sandboxName immutable", func() {
+			snapshot := newTestSnapshot("bs-source")
+			Expect(k8sClient.Create(ctx, snapshot)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, snapshot))).To(Succeed()) })
+			Expect(snapshot.Labels).To(HaveKeyWithValue(controller.LabelBatchSandboxNameKey, "bs-source"))
+
+			snapshot.Spec.SandboxName = "bs-other"
+			err := k8sClient.Update(ctx, snapshot)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+			Expect(err.Error()).To(ContainSubstring("field is immutable"))
+		})
+	})
+})
@@ -18,9 +18,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -46,36 +49,46 @@ func NewClient(baseURL string) *Client {
 // Set creates or updates a task on the remote server.
 // If task is nil, it sends a delete request.
 func (c *Client) Set(ctx context.Context, task *Task) (*Task, error) {
-	if c == nil {
-		return nil, fmt.Errorf("client is nil")
+	if task == nil {
+		// Delete request - send an empty list to clear tasks
+		_, err := c.SetTasks(ctx, nil)
+		return nil, err
 	}
 
-	var req *http.Request
-	var err error
-
-	if task == nil {
-		// Delete request - send nil to clear tasks
-		req, err = http.NewRequestWithContext(ctx, "POST", c.baseURL+"/setTasks", bytes.NewReader([]byte("[]")))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-	} else {
-		// Create/Update request
-		data, err := json.Marshal([]Task{*task})
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal task: %w", err)
-		}
-		req, err = http.NewRequestWithContext(ctx, "POST", c.baseURL+"/setTasks", bytes.NewReader(data))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+	tasks, err := c.SetTasks(ctx, []Task{*task})
+	if err != nil {
+		return nil, err
+	}
+	// Find the task we just set
+	for i := range tasks {
+		if tasks[i].Name == task.Name {
+			return &tasks[i], nil
 		}
 	}
+	return task, nil
+}
+
+// SetTasks replaces the task list on the remote server: tasks not in the list are deleted and
+// new ones are created. It returns the task list after synchronization.
+func (c *Client) SetTasks(ctx context.Context, tasks []Task) ([]Task, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+	if tasks == nil {
+		tasks = []Task{}
+	}
 
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/setTasks", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
-	// Send request with retry
-	var resp *http.Response
-	resp, err = c.httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("network error after retries: %w", err)
 	}
@@ -87,30 +100,31 @@ func (c *Client) Set(ctx context.Context, task *Task) (*Task, error) {
 	}
 
 	// Parse response - expect array of tasks
-	var tasks []Task
-	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+	var current []Task
+	if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	return current, nil
+}
 
-	if task != nil && len(tasks) > 0 {
-		// Find the task we just set
-		for i := range tasks {
-			if tasks[i].Name == task.Name {
-				return &tasks[i], nil
-			}
-		}
+// Get retrieves the first task of the task list on the remote server.
+func (c *Client) Get(ctx context.Context) (*Task, error) {
+	tasks, err := c.List(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if task == nil {
-		// Delete succeeded
-		return nil, nil
+	// Return the first task (single task mode)
+	if len(tasks) > 0 {
+		return &tasks[0], nil
 	}
 
-	return task, nil
+	// No tasks
+	return nil, nil
 }
 
-// Get retrieves the current task list from the remote server.
-func (c *Client) Get(ctx context.Context) (*Task, error) {
+// List retrieves the current task list from the remote server.
+func (c *Client) List(ctx context.Context) ([]Task, error) {
 	if c == nil {
 		return nil, fmt.Errorf("client is nil")
 	}
@@ -136,12 +150,147 @@ func (c *Client) Get(ctx context.Context) (*Task, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	return tasks, nil
+}
 
-	// Return the first task (single task mode)
-	if len(tasks) > 0 {
-		return &tasks[0], nil
+// ErrTaskNotFound is returned by GetTask when the server has no task with the given name.
+var ErrTaskNotFound = errors.New("task not found")
+
+// CreateTask creates a single task on the remote server without touching other tasks.
+func (c *Client) CreateTask(ctx context.Context, task *Task) (*Task, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
 	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/tasks", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-	// No tasks
-	return nil, nil
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("server error: status=%d, body=%s", resp.StatusCode, string(body))
+	}
+
+	var created Task
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// GetTask retrieves a single task by name. It returns ErrTaskNotFound when the task does not exist.
+func (c *Client) GetTask(ctx context.Context, name string) (*Task, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/tasks/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrTaskNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("server error: status=%d, body=%s", resp.StatusCode, string(body))
+	}
+
+	var task Task
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &task, nil
+}
+
+// DeleteTask deletes a single task by name.
+func (c *Client) DeleteTask(ctx context.Context, name string) error {
+	if c == nil {
+		return fmt.Errorf("client is nil")
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/tasks/"+url.PathEscape(name), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("network error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error: status=%d, body=%s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetLogs returns the logs of a task. The caller must close the returned reader. With
+// opts.Follow the stream stays open until the task finishes or ctx is cancelled.
+func (c *Client) GetLogs(ctx context.Context, name string, opts *LogOptions) (io.ReadCloser, error) {
+	if c == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+	query := url.Values{}
+	httpClient := c.httpClient
+	if opts != nil {
+		if opts.Stream != "" {
+			query.Set("stream", opts.Stream)
+		}
+		if opts.Container != "" {
+			query.Set("container", opts.Container)
+		}
+		if opts.Offset != nil {
+			query.Set("offset", strconv.FormatInt(*opts.Offset, 10))
+		}
+		if opts.TailLines != nil {
+			query.Set("tail", strconv.FormatInt(*opts.TailLines, 10))
+		}
+		if opts.Follow {
+			query.Set("follow", "true")
+			// The request timeout would cut off a followed stream.
+			httpClient = &http.Client{Transport: c.httpClient.Transport}
+		}
+	}
+	reqURL := c.baseURL + "/tasks/" + url.PathEscape(name) + "/logs"
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrTaskNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("server error: status=%d, body=%s", resp.StatusCode, string(body))
+	}
+	return resp.Body, nil
 }
@@ -27,11 +27,32 @@ type Task struct {
 
 	Process         *Process                `json:"process,omitempty"`
 	PodTemplateSpec *corev1.PodTemplateSpec `json:"podTemplateSpec,omitempty"`
+	// RestartPolicy specifies whether the task is restarted after it terminates. Defaults to Never.
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+	// MaxRetries is the maximum number of restarts. If unset, the task is restarted without limit.
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// DependsOn lists the names of the tasks that must succeed before this task starts. If one
+	// of them fails, this task fails without running.
+	DependsOn []string `json:"dependsOn,omitempty"`
 
 	ProcessStatus *ProcessStatus    `json:"processStatus,omitempty"`
 	PodStatus     *corev1.PodStatus `json:"podStatus,omitempty"`
+	// RestartCount is the number of times the task has been restarted.
+	RestartCount int32 `json:"restartCount,omitempty"`
+	// LastTerminationState is the termination state of the previous attempt of a process task.
+	// Container tasks report it in the container statuses of PodStatus.
+	LastTerminationState *ProcessStatus `json:"lastState,omitempty"`
 }
 
+// RestartPolicy describes how a terminated task is restarted.
+type RestartPolicy string
+
+const (
+	RestartPolicyNever     RestartPolicy = "Never"
+	RestartPolicyOnFailure RestartPolicy = "OnFailure"
+	RestartPolicyAlways    RestartPolicy = "Always"
+)
+
 type Process struct {
 	// Command command
 	Command []string `json:"command"`
@@ -97,3 +118,24 @@ type Terminated struct {
 	// +optional
 	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
 }
+
+const (
+	// LogStreamStdout and LogStreamStderr select the output stream of a process task.
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+)
+
+// LogOptions selects the part of a task's logs to read.
+type LogOptions struct {
+	// Stream is the output stream of a process task, stdout if empty. Container tasks write both
+	// streams to a single log per container.
+	Stream string
+	// Container is the container of a podTemplateSpec task, the first container if empty.
+	Container string
+	// Offset is the byte offset to start reading from.
+	Offset *int64
+	// TailLines is the number of lines from the end of the log to return. It cannot be combined with Offset.
+	TailLines *int64
+	// Follow keeps the stream open and returns new output until the task finishes.
+	Follow bool
+}
@@ -298,6 +298,30 @@ func (e *ExecdClient) ReplaceInFilesDetailed(ctx context.Context, req ReplaceReq
 	return resp, nil
 }
 
+// GetSyncManifest returns the path, size, mode and SHA-256 of every regular
+// file under the sandbox directory root.
+func (e *ExecdClient) GetSyncManifest(ctx context.Context, root string) (*SyncManifest, error) {
+	var result SyncManifest
+	params := url.Values{}
+	params.Set("path", root)
+	err := e.client.doRequest(ctx, http.MethodGet, "/files/manifest?"+params.Encode(), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SyncFiles compares a manifest with a sandbox directory. The response lists
+// the files that are missing or differ; those must then be uploaded.
+func (e *ExecdClient) SyncFiles(ctx context.Context, req SyncRequest) (*SyncResponse, error) {
+	var result SyncResponse
+	err := e.client.doRequest(ctx, http.MethodPost, "/files/sync", req, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // UploadFileOptions configures the destination path and multipart filename for an upload.
 type UploadFileOptions struct {
 	FileName string
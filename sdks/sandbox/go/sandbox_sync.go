@@ -0,0 +1,330 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opensandbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultSyncUploadBatch is the number of files sent per upload request.
+const defaultSyncUploadBatch = 64
+
+// SyncOptions configures SyncDir and PullDir.
+type SyncOptions struct {
+	// Delete removes files from the destination that do not exist in the source.
+	// Directories are left in place. SyncDir rejects it for an empty source.
+	Delete bool
+	// UploadBatchSize is the number of files per upload request (default 64).
+	UploadBatchSize int
+}
+
+// SyncResult reports what a sync changed. Paths are relative to the synced directories.
+type SyncResult struct {
+	// Transferred lists files that were uploaded (SyncDir) or downloaded (PullDir).
+	Transferred []string
+	// ModeUpdated lists files whose content already matched and only had their mode changed.
+	ModeUpdated []string
+	// Deleted lists files removed from the destination.
+	Deleted []string
+	// Unchanged is the number of source files that needed no transfer.
+	Unchanged int
+}
+
+// SyncDir makes the sandbox directory remoteDir match localDir, uploading only
+// files that are missing or differ by content. Symlinks and special files in
+// localDir are skipped.
+func (s *Sandbox) SyncDir(ctx context.Context, localDir, remoteDir string, opts ...SyncOptions) (*SyncResult, error) {
+	if s.execd == nil {
+		return nil, fmt.Errorf("opensandbox: execd client not initialized")
+	}
+	return s.execd.SyncDir(ctx, localDir, remoteDir, opts...)
+}
+
+// PullDir makes localDir match the sandbox directory remoteDir, downloading
+// only files that are missing or differ by content.
+func (s *Sandbox) PullDir(ctx context.Context, remoteDir, localDir string, opts ...SyncOptions) (*SyncResult, error) {
+	if s.execd == nil {
+		return nil, fmt.Errorf("opensandbox: execd client not initialized")
+	}
+	return s.execd.PullDir(ctx, remoteDir, localDir, opts...)
+}
+
+// SyncDir makes the sandbox directory remoteDir match localDir. See Sandbox.SyncDir.
+func (e *ExecdClient) SyncDir(ctx context.Context, localDir, remoteDir string, opts ...SyncOptions) (*SyncResult, error) {
+	opt := syncOptions(opts)
+	if remoteDir == "" {
+		return nil, &InvalidArgumentError{Field: "remoteDir", Message: "remote directory is required"}
+	}
+
+	files, err := BuildSyncManifest(localDir)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Delete && len(files) == 0 {
+		return nil, &InvalidArgumentError{Field: "localDir", Message: "delete requires at least one file to sync"}
+	}
+	plan, err := e.SyncFiles(ctx, SyncRequest{Root: remoteDir, Files: files, Delete: opt.Delete})
+	if err != nil {
+		return nil, err
+	}
+
+	modes := make(map[string]int, len(files))
+	for _, f := range files {
+		modes[f.Path] = f.Mode
+	}
+	for start := 0; start < len(plan.Upload); start += opt.UploadBatchSize {
+		end := start + opt.UploadBatchSize
+		if end > len(plan.Upload) {
+			end = len(plan.Upload)
+		}
+		if err := e.uploadSyncBatch(ctx, localDir, remoteDir, plan.Upload[start:end], modes); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SyncResult{
+		Transferred: plan.Upload,
+		ModeUpdated: plan.Chmod,
+		Deleted:     plan.Deleted,
+		Unchanged:   len(files) - len(plan.Upload),
+	}, nil
+}
+
+func (e *ExecdClient) uploadSyncBatch(ctx context.Context, localDir, remoteDir string, rels []string, modes map[string]int) error {
+	entries := make([]UploadFileEntry, 0, len(rels))
+	defer func() {
+		for _, entry := range entries {
+			_ = entry.File.(io.Closer).Close()
+		}
+	}()
+
+	for _, rel := range rels {
+		mode, ok := modes[rel]
+		if !ok {
+			return fmt.Errorf("opensandbox: sync requested unknown file %q", rel)
+		}
+		f, err := os.Open(filepath.Join(localDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("opensandbox: open %s: %w", rel, err)
+		}
+		entries = append(entries, UploadFileEntry{
+			File: f,
+			Options: UploadFileOptions{
+				FileName: path.Base(rel),
+				Metadata: FileMetadata{Path: path.Join(remoteDir, rel), Mode: mode},
+			},
+		})
+	}
+	return e.UploadFiles(ctx, entries)
+}
+
+// PullDir makes localDir match the sandbox directory remoteDir. See Sandbox.PullDir.
+func (e *ExecdClient) PullDir(ctx context.Context, remoteDir, localDir string, opts ...SyncOptions) (*SyncResult, error) {
+	opt := syncOptions(opts)
+	if localDir == "" {
+		return nil, &InvalidArgumentError{Field: "localDir", Message: "local directory is required"}
+	}
+
+	remote, err := e.GetSyncManifest(ctx, remoteDir)
+	if err != nil {
+		return nil, err
+	}
+	local, err := BuildSyncManifest(localDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	have := make(map[string]SyncFileEntry, len(local))
+	for _, f := range local {
+		have[f.Path] = f
+	}
+
+	result := &SyncResult{}
+	wanted := make(map[string]struct{}, len(remote.Files))
+	for _, f := range remote.Files {
+		if err := validateSyncPath(f.Path); err != nil {
+			return nil, err
+		}
+		wanted[f.Path] = struct{}{}
+		target := filepath.Join(localDir, filepath.FromSlash(f.Path))
+
+		cur, ok := have[f.Path]
+		switch {
+		case !ok || cur.Size != f.Size || cur.SHA256 != f.SHA256:
+			if err := e.downloadSyncFile(ctx, path.Join(remote.Root, f.Path), target, f.Mode); err != nil {
+				return nil, err
+			}
+			result.Transferred = append(result.Transferred, f.Path)
+		case f.Mode != 0 && cur.Mode != 0 && cur.Mode != f.Mode:
+			if err := os.Chmod(target, syncFileMode(f.Mode)); err != nil {
+				return nil, fmt.Errorf("opensandbox: chmod %s: %w", f.Path, err)
+			}
+			result.ModeUpdated = append(result.ModeUpdated, f.Path)
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if opt.Delete {
+		for _, f := range local {
+			if _, ok := wanted[f.Path]; ok {
+				continue
+			}
+			if err := os.Remove(filepath.Join(localDir, filepath.FromSlash(f.Path))); err != nil {
+				return nil, fmt.Errorf("opensandbox: remove %s: %w", f.Path, err)
+			}
+			result.Deleted = append(result.Deleted, f.Path)
+		}
+	}
+	return result, nil
+}
+
+// downloadSyncFile writes remotePath to target through a temporary file so
+// an interrupted download never leaves a truncated file behind.
+func (e *ExecdClient) downloadSyncFile(ctx context.Context, remotePath, target string, mode int) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("opensandbox: create directory for %s: %w", target, err)
+	}
+	body, err := e.DownloadFile(ctx, remotePath, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".opensandbox-sync-*")
+	if err != nil {
+		return fmt.Errorf("opensandbox: create temp file for %s: %w", target, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("opensandbox: download %s: %w", remotePath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("opensandbox: write %s: %w", target, err)
+	}
+	if mode == 0 {
+		mode = 644
+	}
+	if err := os.Chmod(tmp.Name(), syncFileMode(mode)); err != nil {
+		return fmt.Errorf("opensandbox: chmod %s: %w", target, err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("opensandbox: rename into %s: %w", target, err)
+	}
+	return nil
+}
+
+// BuildSyncManifest hashes every regular file under dir, in lexical order.
+// Symlinks and special files are skipped. Modes are omitted on Windows,
+// whose permission bits do not map onto the sandbox's.
+func BuildSyncManifest(dir string) ([]SyncFileEntry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opensandbox: stat %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, &InvalidArgumentError{Field: "dir", Message: dir + " is not a directory"}
+	}
+
+	var files []SyncFileEntry
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sum, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		entry := SyncFileEntry{Path: filepath.ToSlash(rel), Size: fi.Size(), SHA256: sum}
+		if runtime.GOOS != "windows" {
+			entry.Mode, _ = strconv.Atoi(strconv.FormatUint(uint64(fi.Mode().Perm()), 8))
+		}
+		files = append(files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opensandbox: build manifest for %s: %w", dir, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func syncOptions(opts []SyncOptions) SyncOptions {
+	var opt SyncOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.UploadBatchSize <= 0 {
+		opt.UploadBatchSize = defaultSyncUploadBatch
+	}
+	return opt
+}
+
+// syncFileMode converts octal-digit modes such as 755 to an os.FileMode.
+func syncFileMode(mode int) os.FileMode {
+	v, err := strconv.ParseUint(strconv.Itoa(mode), 8, 32)
+	if err != nil {
+		return 0o644
+	}
+	return os.FileMode(v).Perm()
+}
+
+// validateSyncPath rejects manifest paths that would escape the local directory.
+func validateSyncPath(p string) error {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, `\`) || path.Clean(p) != p ||
+		p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Errorf("opensandbox: invalid manifest path %q", p)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opensandbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSyncTestFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBuildSyncManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeSyncTestFile(t, dir, "b.txt", "bravo")
+	writeSyncTestFile(t, dir, "a/x.txt", "xray")
+
+	files, err := BuildSyncManifest(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "a/x.txt", files[0].Path)
+	require.Equal(t, int64(4), files[0].Size)
+	require.Equal(t, sha256Hex("xray"), files[0].SHA256)
+	require.Equal(t, "b.txt", files[1].Path)
+}
+
+func TestSandbox_SyncDir_UploadsOnlyRequestedFiles(t *testing.T) {
+	local := t.TempDir()
+	writeSyncTestFile(t, local, "same.txt", "same")
+	writeSyncTestFile(t, local, "src/new.go", "package main")
+
+	var uploaded []FileMetadata
+	_, client := newExecdServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/sync":
+			var req SyncRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, "/workspace", req.Root)
+			require.True(t, req.Delete)
+			require.Len(t, req.Files, 2)
+			jsonResponse(w, http.StatusOK, SyncResponse{Upload: []string{"src/new.go"}, Deleted: []string{"old.txt"}})
+		case "/files/upload":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			for _, part := range r.MultipartForm.File["metadata"] {
+				f, err := part.Open()
+				require.NoError(t, err)
+				var meta FileMetadata
+				require.NoError(t, json.NewDecoder(f).Decode(&meta))
+				_ = f.Close()
+				uploaded = append(uploaded, meta)
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			assert.Fail(t, "unexpected request "+r.URL.Path)
+		}
+	})
+	sb := &Sandbox{id: "sbx-sync", execd: client}
+
+	result, err := sb.SyncDir(context.Background(), local, "/workspace", SyncOptions{Delete: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"src/new.go"}, result.Transferred)
+	require.Equal(t, []string{"old.txt"}, result.Deleted)
+	require.Equal(t, 1, result.Unchanged)
+	require.Len(t, uploaded, 1)
+	require.Equal(t, "/workspace/src/new.go", uploaded[0].Path)
+}
+
+func TestSandbox_PullDir(t *testing.T) {
+	local := t.TempDir()
+	writeSyncTestFile(t, local, "same.txt", "same")
+	writeSyncTestFile(t, local, "changed.txt", "old")
+	writeSyncTestFile(t, local, "extra.txt", "extra")
+
+	remote := map[string]string{"same.txt": "same", "changed.txt": "new", "dir/added.txt": "added"}
+	_, client := newExecdServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/manifest":
+			require.Equal(t, "/workspace", r.URL.Query().Get("path"))
+			manifest := SyncManifest{Root: "/workspace"}
+			for p, content := range remote {
+				manifest.Files = append(manifest.Files, SyncFileEntry{Path: p, Size: int64(len(content)), Mode: 644, SHA256: sha256Hex(content)})
+			}
+			jsonResponse(w, http.StatusOK, manifest)
+		case "/files/download":
+			rel, err := filepath.Rel("/workspace", r.URL.Query().Get("path"))
+			require.NoError(t, err)
+			content, ok := remote[filepath.ToSlash(rel)]
+			require.True(t, ok)
+			_, _ = io.WriteString(w, content)
+		default:
+			assert.Fail(t, "unexpected request "+r.URL.Path)
+		}
+	})
+	sb := &Sandbox{id: "sbx-pull", execd: client}
+
+	result, err := sb.PullDir(context.Background(), "/workspace", local, SyncOptions{Delete: true})
+	require.NoError(t, err)
+	require.Len(t, result.Transferred, 2)
+	require.Equal(t, []string{"extra.txt"}, result.Deleted)
+	require.Equal(t, 1, result.Unchanged)
+
+	for p, content := range remote {
+		data, err := os.ReadFile(filepath.Join(local, filepath.FromSlash(p)))
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+	_, err = os.Stat(filepath.Join(local, "extra.txt"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestSandbox_PullDir_RejectsEscapingPaths(t *testing.T) {
+	_, client := newExecdServer(t, func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, http.StatusOK, SyncManifest{
+			Root:  "/workspace",
+			Files: []SyncFileEntry{{Path: "../outside", Size: 1, SHA256: sha256Hex("x")}},
+		})
+	})
+	sb := &Sandbox{id: "sbx-pull", execd: client}
+
+	_, err := sb.PullDir(context.Background(), "/workspace", t.TempDir())
+	require.Error(t, err)
+}
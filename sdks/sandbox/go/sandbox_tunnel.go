@@ -0,0 +1,66 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opensandbox
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// DialPort opens a raw TCP connection to a sandbox port through the ingress
+// TCP tunnel, e.g. to reach a database or SSH server in the sandbox.
+func (s *Sandbox) DialPort(ctx context.Context, port int) (net.Conn, error) {
+	endpointURL, headers, err := s.tunnelEndpoint(ctx, port)
+	if err != nil {
+		return nil, err
+	}
+	return DialTunnel(ctx, endpointURL, headers)
+}
+
+// ForwardPort listens on localAddr (e.g. "127.0.0.1:0") and forwards each
+// accepted connection to a sandbox port through the ingress TCP tunnel. The
+// endpoint is resolved once, with ctx; call Close on the result to stop.
+func (s *Sandbox) ForwardPort(ctx context.Context, port int, localAddr string) (*PortForward, error) {
+	endpointURL, headers, err := s.tunnelEndpoint(ctx, port)
+	if err != nil {
+		return nil, err
+	}
+	return NewPortForward(localAddr, endpointURL, headers)
+}
+
+// tunnelEndpoint resolves the ingress endpoint of a sandbox port. Tunnels go
+// to the ingress directly, as the server proxy only relays HTTP.
+func (s *Sandbox) tunnelEndpoint(ctx context.Context, port int) (string, map[string]string, error) {
+	useProxy := false
+	endpoint, err := s.lifecycle.GetEndpoint(ctx, s.id, port, &useProxy)
+	if err != nil {
+		return "", nil, err
+	}
+
+	endpointURL := s.config.RewriteEndpointURL(endpoint.Endpoint)
+	if !strings.HasPrefix(endpointURL, "http") {
+		endpointURL = s.config.GetProtocol() + "://" + endpointURL
+	}
+
+	headers := make(map[string]string, len(s.config.Headers)+len(endpoint.Headers))
+	for k, v := range s.config.Headers {
+		headers[k] = v
+	}
+	for k, v := range endpoint.Headers {
+		headers[k] = v
+	}
+	return endpointURL, headers, nil
+}
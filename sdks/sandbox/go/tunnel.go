@@ -0,0 +1,293 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opensandbox
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TunnelUpgradeProtocol is the HTTP Upgrade token the ingress accepts for a
+// raw TCP tunnel to the sandbox port an endpoint routes to. The ingress must
+// run with --tcp-tunnel.
+const TunnelUpgradeProtocol = "opensandbox-tcp"
+
+// DialTunnel opens a raw TCP connection to a sandbox port through the
+// ingress. endpointURL is a sandbox endpoint with an http or https scheme, and
+// headers are sent with the upgrade request (e.g. the headers returned by
+// GetEndpoint). Cancelling ctx aborts the dial and handshake only.
+func DialTunnel(ctx context.Context, endpointURL string, headers map[string]string) (net.Conn, error) {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return nil, &InvalidArgumentError{Field: "endpointURL", Message: err.Error()}
+	}
+	addr := u.Host
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	switch u.Scheme {
+	case "http":
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	case "https":
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), "443")
+		}
+		conn, err = (&tls.Dialer{NetDialer: dialer}).DialContext(ctx, "tcp", addr)
+	default:
+		return nil, &InvalidArgumentError{Field: "endpointURL", Message: fmt.Sprintf("unsupported scheme %q", u.Scheme)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial tunnel %s: %w", addr, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", TunnelUpgradeProtocol)
+
+	br, err := tunnelHandshake(ctx, conn, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &tunnelConn{Conn: conn, r: br}, nil
+}
+
+// tunnelHandshake sends the upgrade request and reads the response, giving up
+// when ctx ends.
+func tunnelHandshake(ctx context.Context, conn net.Conn, req *http.Request) (*bufio.Reader, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			// Unblock the pending read or write.
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-stopped
+		_ = conn.SetDeadline(time.Time{})
+	}()
+
+	if err := req.Write(conn); err != nil {
+		return nil, handshakeErr(ctx, err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, handshakeErr(ctx, err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		return nil, handleError(resp)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), TunnelUpgradeProtocol) {
+		return nil, fmt.Errorf("tunnel handshake: unexpected upgrade %q", resp.Header.Get("Upgrade"))
+	}
+	return br, nil
+}
+
+func handshakeErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("tunnel handshake: %w", err)
+}
+
+// tunnelConn reads through the handshake buffer, which may already hold
+// bytes the sandbox sent right after the upgrade.
+type tunnelConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// CloseWrite half-closes the tunnel, signalling end of input to the sandbox.
+func (c *tunnelConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return c.Conn.Close()
+}
+
+// PortForward forwards connections accepted on a local listener to a sandbox
+// port, opening one tunnel per connection.
+type PortForward struct {
+	listener net.Listener
+	dial     func(ctx context.Context) (net.Conn, error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// NewPortForward listens on localAddr (e.g. "127.0.0.1:0") and forwards each
+// accepted connection through a tunnel to endpointURL. See DialTunnel.
+func NewPortForward(localAddr, endpointURL string, headers map[string]string) (*PortForward, error) {
+	return newPortForward(localAddr, func(ctx context.Context) (net.Conn, error) {
+		return DialTunnel(ctx, endpointURL, headers)
+	})
+}
+
+func newPortForward(localAddr string, dial func(ctx context.Context) (net.Conn, error)) (*PortForward, error) {
+	ln, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &PortForward{
+		listener: ln,
+		dial:     dial,
+		ctx:      ctx,
+		cancel:   cancel,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	f.wg.Add(1)
+	go f.serve()
+	return f, nil
+}
+
+// Addr returns the local address connections are accepted on.
+func (f *PortForward) Addr() net.Addr {
+	return f.listener.Addr()
+}
+
+// Close stops accepting connections, closes the forwarded ones and waits for
+// them to finish.
+func (f *PortForward) Close() error {
+	f.cancel()
+	err := f.listener.Close()
+	f.mu.Lock()
+	for conn := range f.conns {
+		conn.Close()
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (f *PortForward) serve() {
+	defer f.wg.Done()
+	for {
+		local, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			f.forward(local)
+		}()
+	}
+}
+
+func (f *PortForward) forward(local net.Conn) {
+	if !f.track(local) {
+		return
+	}
+	defer f.untrack(local)
+	remote, err := f.dial(f.ctx)
+	if err != nil {
+		return
+	}
+	if !f.track(remote) {
+		return
+	}
+	defer f.untrack(remote)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		copyAndCloseWrite(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		copyAndCloseWrite(local, remote)
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+}
+
+// track registers conn for Close, closing it right away if f is closed.
+func (f *PortForward) track(conn net.Conn) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ctx.Err() != nil {
+		conn.Close()
+		return false
+	}
+	f.conns[conn] = struct{}{}
+	return true
+}
+
+func (f *PortForward) untrack(conn net.Conn) {
+	f.mu.Lock()
+	delete(f.conns, conn)
+	f.mu.Unlock()
+	conn.Close()
+}
+
+// copyAndCloseWrite copies src to dst, then passes the end of src on as a
+// half close; on an error both connections are closed.
+func copyAndCloseWrite(dst, src net.Conn) {
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		src.Close()
+		return
+	}
+	if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+		return
+	}
+	dst.Close()
+}
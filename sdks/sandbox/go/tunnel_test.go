@@ -0,0 +1,139 @@
+// Copyright 2026 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opensandbox
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFakeTunnelIngress accepts tunnel upgrades that carry the given header and
+// upper-cases the tunnelled bytes until the client half-closes.
+func newFakeTunnelIngress(t *testing.T, header, value string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != TunnelUpgradeProtocol || r.Header.Get(header) != value {
+			jsonResponse(w, http.StatusUnauthorized, ErrorResponse{Code: "UNAUTHORIZED", Message: "no access"})
+			return
+		}
+		conn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: "+TunnelUpgradeProtocol+"\r\n\r\nhi ")
+		in, _ := io.ReadAll(brw)
+		_, _ = conn.Write([]byte(strings.ToUpper(string(in))))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func roundTrip(t *testing.T, conn net.Conn, payload string) string {
+	t.Helper()
+	_, err := io.WriteString(conn, payload)
+	require.NoError(t, err)
+	require.NoError(t, conn.(interface{ CloseWrite() error }).CloseWrite())
+	out, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestDialTunnel(t *testing.T) {
+	srv := newFakeTunnelIngress(t, "X-Route", "sbx-22")
+
+	conn, err := DialTunnel(context.Background(), srv.URL+"/sbx/22", map[string]string{"X-Route": "sbx-22"})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, "hi SSH", roundTrip(t, conn, "ssh"))
+}
+
+func TestDialTunnel_Rejected(t *testing.T) {
+	srv := newFakeTunnelIngress(t, "X-Route", "sbx-22")
+
+	_, err := DialTunnel(context.Background(), srv.URL+"/sbx/22", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	_, err = DialTunnel(context.Background(), "ftp://example.com", nil)
+	var argErr *InvalidArgumentError
+	require.ErrorAs(t, err, &argErr)
+}
+
+func TestDialTunnel_ContextCancelsHandshake(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		// Accept and never answer.
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = DialTunnel(ctx, "http://"+ln.Addr().String()+"/sbx/22", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, time.Since(start) < 2*time.Second, "handshake should stop with the context")
+}
+
+func TestSandbox_ForwardPort(t *testing.T) {
+	ingress := newFakeTunnelIngress(t, "OpenSandbox-Ingress-To", "sbx-fwd-5432")
+	var gotQuery string
+	lifecycleSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/sandboxes/sbx-fwd/endpoints/5432") {
+			gotQuery = r.URL.RawQuery
+			jsonResponse(w, http.StatusOK, Endpoint{
+				Endpoint: strings.TrimPrefix(ingress.URL, "http://"),
+				Headers:  map[string]string{"OpenSandbox-Ingress-To": "sbx-fwd-5432"},
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer lifecycleSrv.Close()
+
+	config := ConnectionConfig{Domain: lifecycleSrv.URL, Protocol: "http", UseServerProxy: true}
+	sb := &Sandbox{
+		id:        "sbx-fwd",
+		config:    &config,
+		lifecycle: config.lifecycleClient(),
+	}
+
+	fwd, err := sb.ForwardPort(context.Background(), 5432, "127.0.0.1:0")
+	require.NoError(t, err)
+	require.Equal(t, "use_server_proxy=false", gotQuery)
+
+	for _, payload := range []string{"select 1", "select 2"} {
+		conn, err := net.Dial("tcp", fwd.Addr().String())
+		require.NoError(t, err)
+		require.Equal(t, "hi "+strings.ToUpper(payload), roundTrip(t, conn, payload))
+		conn.Close()
+	}
+
+	require.NoError(t, fwd.Close())
+	_, err = net.DialTimeout("tcp", fwd.Addr().String(), time.Second)
+	require.Error(t, err)
+}
@@ -570,6 +570,37 @@ type FileMetadata struct {
 	Mode  int    `json:"mode,omitempty"`
 }
 
+// SyncFileEntry describes one regular file of a directory tree.
+type SyncFileEntry struct {
+	// Path is slash-separated and relative to the manifest root.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Mode holds the permission bits as octal digits (e.g. 644); 0 skips mode comparison.
+	Mode   int    `json:"mode,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+// SyncManifest lists the regular files under Root.
+type SyncManifest struct {
+	Root  string          `json:"root"`
+	Files []SyncFileEntry `json:"files"`
+}
+
+// SyncRequest asks execd to compare a manifest with the sandbox directory Root.
+type SyncRequest struct {
+	Root  string          `json:"root"`
+	Files []SyncFileEntry `json:"files"`
+	// Delete removes files under Root that are not in the manifest.
+	Delete bool `json:"delete,omitempty"`
+}
+
+// SyncResponse lists the files the client must upload to finish a sync.
+type SyncResponse struct {
+	Upload  []string `json:"upload"`
+	Chmod   []string `json:"chmod,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
+}
+
 // Metrics contains system resource usage metrics.
 type Metrics struct {
 	CPUCount   float64 `json:"cpu_count"`